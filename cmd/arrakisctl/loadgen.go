@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+
+	"github.com/elissonalvesilva/arrakis/pkg/loadgen"
+	"github.com/elissonalvesilva/arrakis/pkg/sqs"
+)
+
+// Load generator configuration
+const (
+	_localStackEndpoint   = "http://localhost:4566" // Endpoint used by --localstack
+	_loadgenReportEvery   = time.Second             // Interval between two progress lines
+	_defaultLoadgenRegion = "us-east-1"             // Region when none is configured
+)
+
+// patternFlags are the parameters of the traffic patterns.
+type patternFlags struct {
+	Name     string
+	Rate     float64
+	Peak     float64
+	Every    time.Duration
+	Length   time.Duration
+	Period   time.Duration
+	Messages int
+}
+
+// register defines the pattern flags on a command's flag set.
+func (f *patternFlags) register(flags *flag.FlagSet) {
+	flags.StringVar(&f.Name, "pattern", "steady", "traffic pattern: steady, burst, sine or drain")
+	flags.Float64Var(&f.Rate, "rate", 1, "messages per second (steady), base rate (burst) or minimum rate (sine)")
+	flags.Float64Var(&f.Peak, "peak", 50, "peak rate (burst) or maximum rate (sine)")
+	flags.DurationVar(&f.Every, "every", time.Minute, "time between the start of two bursts (burst)")
+	flags.DurationVar(&f.Length, "length", 10*time.Second, "duration of each burst (burst)")
+	flags.DurationVar(&f.Period, "period", 10*time.Minute, "duration of an oscillation (sine)")
+	flags.IntVar(&f.Messages, "messages", 1000, "size of the backlog (drain)")
+}
+
+// pattern builds the traffic pattern selected on the command line.
+func (f patternFlags) pattern() (loadgen.Pattern, error) {
+	switch f.Name {
+	case "steady":
+		return loadgen.Steady(f.Rate), nil
+	case "burst":
+		return loadgen.Burst(f.Rate, f.Peak, f.Every, f.Length), nil
+	case "sine":
+		return loadgen.Sine(f.Rate, f.Peak, f.Period), nil
+	case "drain":
+		return loadgen.Drain(f.Messages), nil
+	default:
+		return nil, fmt.Errorf("loadgen: unknown pattern %q (steady, burst, sine, drain)", f.Name)
+	}
+}
+
+// loadgenCommand implements the loadgen command: it sends messages to a queue following
+// a traffic pattern and prints the progress.
+func loadgenCommand(args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("loadgen", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	var pattern patternFlags
+	queueURL := flags.String("queue", "", "URL of the queue receiving the messages (required)")
+	endpoint := flags.String("endpoint", "", "custom SQS endpoint")
+	localStack := flags.Bool("localstack", false, "use LocalStack test credentials, on "+_localStackEndpoint+" unless --endpoint is set")
+	region := flags.String("region", "", "AWS region (default from the environment, or "+_defaultLoadgenRegion+")")
+	duration := flags.Duration("duration", time.Minute, "length of the run, 0 to run until interrupted")
+	groups := flags.Int("groups", 0, "spread messages over this many message groups (FIFO queues)")
+	pattern.register(flags)
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *queueURL == "" {
+		return errors.New("loadgen: --queue is required")
+	}
+
+	traffic, err := pattern.pattern()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	client, err := newClient(ctx, *region, *endpoint, *localStack)
+	if err != nil {
+		return err
+	}
+
+	options := []loadgen.Option{loadgen.WithProgress(progressPrinter(stdout))}
+	if *groups > 0 {
+		options = append(options, loadgen.WithSendOptions(func(seq int) []sqs.SendOption {
+			return []sqs.SendOption{sqs.WithMessageGroupID("group-" + strconv.Itoa(seq%*groups))}
+		}))
+	}
+
+	fmt.Fprintf(stdout, "Sending %s traffic to %s\n", pattern.Name, *queueURL)
+	report := loadgen.New(sqs.NewProducer(client, *queueURL), traffic, options...).Run(ctx, *duration)
+	fmt.Fprintf(stdout, "Done: %d sent, %d failed in %v\n", report.Sent, report.Failed, report.Elapsed.Round(time.Second))
+
+	if report.LastErr != nil {
+		return fmt.Errorf("loadgen: last send error: %w", report.LastErr)
+	}
+
+	return nil
+}
+
+// newClient creates the SQS client used by the command line tools.
+func newClient(ctx context.Context, region, endpoint string, localStack bool) (*sqs.SQS, error) {
+	var loadOptions []func(*config.LoadOptions) error
+	if region != "" {
+		loadOptions = append(loadOptions, config.WithRegion(region))
+	}
+	if localStack {
+		if endpoint == "" {
+			endpoint = _localStackEndpoint
+		}
+		loadOptions = append(loadOptions, config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider("test", "test", "")))
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, loadOptions...)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Region == "" {
+		cfg.Region = _defaultLoadgenRegion
+	}
+
+	var options []sqs.Option
+	if endpoint != "" {
+		options = append(options, sqs.WithEndpoint(endpoint))
+	}
+
+	return sqs.NewSQSWithOptions(&cfg, options...), nil
+}
+
+// progressPrinter returns a progress callback printing a line at most every second.
+func progressPrinter(w io.Writer) func(loadgen.Report) {
+	var last time.Duration
+
+	return func(report loadgen.Report) {
+		if report.Elapsed-last < _loadgenReportEvery {
+			return
+		}
+		last = report.Elapsed
+
+		fmt.Fprintf(w, "%8v  sent %d  failed %d\n", report.Elapsed.Round(time.Second), report.Sent, report.Failed)
+	}
+}
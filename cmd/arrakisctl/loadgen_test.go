@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// batchServer serves SendMessageBatch calls of the SQS JSON protocol and counts the entries.
+type batchServer struct {
+	mu      sync.Mutex
+	entries []map[string]any
+}
+
+func (b *batchServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var input struct {
+		Entries []map[string]any
+	}
+	_ = json.NewDecoder(r.Body).Decode(&input)
+
+	successful := []map[string]string{}
+	for _, entry := range input.Entries {
+		b.entries = append(b.entries, entry)
+		successful = append(successful, map[string]string{"Id": entry["Id"].(string), "MessageId": "m", "MD5OfMessageBody": fmt.Sprintf("%x", md5.Sum([]byte(entry["MessageBody"].(string))))})
+	}
+
+	w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+	_ = json.NewEncoder(w).Encode(map[string]any{"Successful": successful, "Failed": []any{}})
+}
+
+func TestPatternFlags(t *testing.T) {
+	for _, name := range []string{"steady", "burst", "sine", "drain"} {
+		if _, err := (patternFlags{Name: name}).pattern(); err != nil {
+			t.Errorf("Expected pattern %s to be known, got %v", name, err)
+		}
+	}
+
+	if _, err := (patternFlags{Name: "zigzag"}).pattern(); err == nil {
+		t.Error("Expected an error for an unknown pattern")
+	}
+}
+
+func TestLoadgenSendsToQueue(t *testing.T) {
+	server := &batchServer{}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	var stdout, stderr bytes.Buffer
+	args := []string{"loadgen", "--localstack", "--endpoint", httpServer.URL, "--queue", httpServer.URL + "/000000000000/orders.fifo",
+		"--pattern", "drain", "--messages", "12", "--groups", "3", "--duration", "200ms"}
+	if code := run(args, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+
+	if len(server.entries) != 12 {
+		t.Fatalf("Expected 12 messages sent, got %d", len(server.entries))
+	}
+	if server.entries[0]["MessageGroupId"] != "group-1" {
+		t.Errorf("Expected messages spread over groups, got %v", server.entries[0]["MessageGroupId"])
+	}
+	if !strings.Contains(stdout.String(), "Done: 12 sent, 0 failed") {
+		t.Errorf("Expected a summary, got %q", stdout.String())
+	}
+}
+
+func TestLoadgenRequiresQueue(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"loadgen", "--pattern", "drain"}, &stdout, &stderr); code != 1 {
+		t.Errorf("Expected exit code 1, got %d", code)
+	}
+}
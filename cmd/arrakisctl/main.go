@@ -0,0 +1,66 @@
+// Command arrakisctl is the command line companion of the Arrakis adaptive polling
+// library.
+//
+// Usage:
+//
+//	arrakisctl tune --trace traffic.json [--latency-weight 0.5] [--top 5]
+//	arrakisctl loadgen --queue URL [--localstack] [--pattern steady|burst|sine|drain] [--duration 1m]
+//	arrakisctl soak --queue URL|--fake [--duration 1h] [--check-interval 1m] [--dump-dir DIR]
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// usage describes the available commands.
+const usage = `Usage: arrakisctl <command> [flags]
+
+Commands:
+  tune     Recommend adaptive polling settings for recorded traffic
+  loadgen  Send messages to a queue following a traffic pattern
+  soak     Consume a queue for a long run and check the Arrakis invariants
+
+Run "arrakisctl <command> -h" for the flags of a command.
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command line and returns the process exit code.
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	var err error
+	switch args[0] {
+	case "tune":
+		err = tuneCommand(args[1:], stdout, stderr)
+	case "loadgen":
+		err = loadgenCommand(args[1:], stdout, stderr)
+	case "soak":
+		err = soakCommand(args[1:], stdout, stderr)
+	case "help", "-h", "--help":
+		fmt.Fprint(stdout, usage)
+		return 0
+	default:
+		fmt.Fprintf(stderr, "arrakisctl: unknown command %q\n\n%s", args[0], usage)
+		return 2
+	}
+
+	switch {
+	case errors.Is(err, flag.ErrHelp):
+		return 0
+	case err != nil:
+		fmt.Fprintf(stderr, "arrakisctl: %v\n", err)
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/elissonalvesilva/arrakis/pkg/arrakistest"
+	"github.com/elissonalvesilva/arrakis/pkg/soak"
+	"github.com/elissonalvesilva/arrakis/pkg/sqs"
+)
+
+// Soak configuration
+const (
+	_fakeQueueName   = "soak"                 // Queue consumed by --fake runs
+	_fakeMaxWaitTime = 200 * time.Millisecond // Long polling cap of the in-memory queue, so runs end promptly
+)
+
+// soakCommand implements the soak command: it consumes a queue with Arrakis enabled while
+// producing traffic, and reports the invariants found broken during the run.
+func soakCommand(args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("soak", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	var pattern patternFlags
+	queueURL := flags.String("queue", "", "URL of the queue to consume (required unless --fake)")
+	fake := flags.Bool("fake", false, "run against an in-memory queue instead of SQS")
+	endpoint := flags.String("endpoint", "", "custom SQS endpoint")
+	localStack := flags.Bool("localstack", false, "use LocalStack test credentials, on "+_localStackEndpoint+" unless --endpoint is set")
+	region := flags.String("region", "", "AWS region (default from the environment, or "+_defaultLoadgenRegion+")")
+	duration := flags.Duration("duration", time.Hour, "length of the run, 0 to run until interrupted")
+	checkInterval := flags.Duration("check-interval", time.Minute, "interval between two invariant checks")
+	maxHandling := flags.Duration("max-handling-time", 5*time.Minute, "time after which a message in its handler is stuck")
+	handlingTime := flags.Duration("handling-time", 0, "time the handler spends on every message")
+	workers := flags.Int("workers", 1, "number of consumer workers")
+	dumpDir := flags.String("dump-dir", "", "directory receiving a state dump on every violation")
+	failFast := flags.Bool("fail-fast", false, "stop at the first violation")
+	pattern.register(flags)
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *queueURL == "" && !*fake {
+		return errors.New("soak: --queue or --fake is required")
+	}
+
+	traffic, err := pattern.pattern()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var client *sqs.SQS
+	if *fake {
+		server := arrakistest.NewServer(arrakistest.WithMaxWaitTime(_fakeMaxWaitTime))
+		defer server.Close()
+
+		client = server.Client()
+		*queueURL = server.QueueURL(_fakeQueueName)
+	} else if client, err = newClient(ctx, *region, *endpoint, *localStack); err != nil {
+		return err
+	}
+	client.EnableArrakis()
+
+	handler := sqs.HandlerFunc(func(ctx context.Context, msg sqs.Message) error {
+		time.Sleep(*handlingTime)
+		return nil
+	})
+
+	fmt.Fprintf(stdout, "Soaking %s with %s traffic for %v\n", *queueURL, pattern.Name, *duration)
+	report, err := soak.Run(ctx, client, *queueURL, handler, soak.Config{
+		Duration:        *duration,
+		Pattern:         traffic,
+		CheckInterval:   *checkInterval,
+		MaxHandlingTime: *maxHandling,
+		DumpDir:         *dumpDir,
+		StopOnViolation: *failFast,
+		OnViolation: func(v soak.Violation) {
+			fmt.Fprintf(stdout, "%s  %s violated: %s\n", v.Time.Format(time.TimeOnly), v.Invariant, v.Detail)
+			if v.DumpPath != "" {
+				fmt.Fprintf(stdout, "          state dumped to %s\n", v.DumpPath)
+			}
+		},
+	}, sqs.WithWorkers(*workers))
+
+	fmt.Fprintf(stdout, "Done: %d checks, %d sent, %d handled, %d violations in %v\n",
+		report.Checks, report.Sent, report.Handled, len(report.Violations), report.Elapsed.Round(time.Second))
+
+	if err != nil {
+		return fmt.Errorf("soak: %d invariant violations", len(report.Violations))
+	}
+
+	return nil
+}
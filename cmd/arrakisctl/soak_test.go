@@ -0,0 +1,39 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSoakFakeQueue(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	args := []string{"soak", "--fake", "--duration", "300ms", "--check-interval", "50ms", "--pattern", "steady", "--rate", "50"}
+	if code := run(args, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s%s", code, stdout.String(), stderr.String())
+	}
+
+	if !strings.Contains(stdout.String(), "0 violations") {
+		t.Errorf("Expected a clean summary, got %q", stdout.String())
+	}
+}
+
+func TestSoakReportsViolations(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	args := []string{"soak", "--fake", "--duration", "2s", "--check-interval", "20ms", "--pattern", "drain", "--messages", "1",
+		"--handling-time", "300ms", "--max-handling-time", "100ms", "--fail-fast", "--dump-dir", t.TempDir()}
+	if code := run(args, &stdout, &stderr); code != 1 {
+		t.Fatalf("Expected exit code 1, got %d: %s", code, stderr.String())
+	}
+
+	if !strings.Contains(stdout.String(), "stuck_message violated") || !strings.Contains(stdout.String(), "state dumped to") {
+		t.Errorf("Expected the violation to be printed, got %q", stdout.String())
+	}
+}
+
+func TestSoakRequiresQueue(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"soak"}, &stdout, &stderr); code != 1 {
+		t.Errorf("Expected exit code 1, got %d", code)
+	}
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/elissonalvesilva/arrakis/pkg/sqs"
+)
+
+// Tune configuration
+const (
+	_defaultLatencyWeight = 0.5 // Equal importance of latency and cost
+	_defaultTop           = 5   // Candidates listed after the recommendation
+)
+
+// waitProfile is a set of wait times, in seconds, for each volume class.
+type waitProfile struct {
+	Name     string
+	Idle     int
+	Low      int
+	Medium   int
+	High     int
+	VeryHigh int
+}
+
+// Parameter grid explored by the tune command.
+var (
+	_waitProfiles = []waitProfile{
+		{Name: "responsive", Idle: 10, Low: 5, Medium: 2, High: 1, VeryHigh: 0},
+		{Name: "balanced", Idle: 20, Low: 15, Medium: 10, High: 5, VeryHigh: 1},
+		{Name: "economical", Idle: 20, Low: 20, Medium: 15, High: 10, VeryHigh: 5},
+	}
+	_alphas                     = []float64{0.1, 0.2, 0.3, 0.5, 0.7}
+	_dropDetectionThresholds    = []int{5, 10, 20}
+	_consecutiveEmptyThresholds = []int{1, 2, 5}
+)
+
+// candidate is a combination of settings evaluated against the trace.
+type candidate struct {
+	Alpha            float64
+	DropDetection    int
+	ConsecutiveEmpty int
+	Waits            waitProfile
+
+	Result sqs.SimulationResult
+	Score  float64
+}
+
+// options returns the client options applying the candidate settings.
+func (c candidate) options() []sqs.Option {
+	return []sqs.Option{
+		sqs.WithEwmaAlpha(c.Alpha),
+		sqs.WithDropDetectionThreshold(c.DropDetection),
+		sqs.WithConsecutiveEmptyThreshold(c.ConsecutiveEmpty),
+		sqs.WithIdleWaitTimeSeconds(c.Waits.Idle),
+		sqs.WithLowVolumeWaitTimeSeconds(c.Waits.Low),
+		sqs.WithMediumVolumeWaitTimeSeconds(c.Waits.Medium),
+		sqs.WithHighVolumeWaitTimeSeconds(c.Waits.High),
+		sqs.WithVeryHighVolumeWaitTimeSeconds(c.Waits.VeryHigh),
+	}
+}
+
+// tuneCommand implements the tune command: it replays a trace through the simulator for
+// every combination of the parameter grid and prints the settings with the best
+// latency/cost trade-off.
+func tuneCommand(args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("tune", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	tracePath := flags.String("trace", "", "JSON trace of message arrivals, or a DumpState document (required)")
+	latencyWeight := flags.Float64("latency-weight", _defaultLatencyWeight, "importance of latency versus cost, from 0 (cost only) to 1 (latency only)")
+	top := flags.Int("top", _defaultTop, "number of candidates to list")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *tracePath == "" {
+		return errors.New("tune: --trace is required")
+	}
+	if *latencyWeight < 0 || *latencyWeight > 1 {
+		return fmt.Errorf("tune: --latency-weight must be between 0 and 1, got %v", *latencyWeight)
+	}
+
+	file, err := os.Open(*tracePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	trace, err := sqs.ParseTrace(file)
+	if err != nil {
+		return fmt.Errorf("tune: %s: %w", *tracePath, err)
+	}
+
+	candidates := rank(trace, *latencyWeight)
+	report(stdout, trace, *latencyWeight, candidates[:min(max(*top, 1), len(candidates))])
+
+	return nil
+}
+
+// grid returns every combination of the explored parameters.
+func grid() []candidate {
+	var candidates []candidate
+	for _, waits := range _waitProfiles {
+		for _, alpha := range _alphas {
+			for _, dropDetection := range _dropDetectionThresholds {
+				for _, consecutiveEmpty := range _consecutiveEmptyThresholds {
+					candidates = append(candidates, candidate{
+						Alpha:            alpha,
+						DropDetection:    dropDetection,
+						ConsecutiveEmpty: consecutiveEmpty,
+						Waits:            waits,
+					})
+				}
+			}
+		}
+	}
+
+	return candidates
+}
+
+// rank simulates every candidate of the grid and sorts them by score, best first. The
+// score weighs latency and polls relative to the best value found for each, so both
+// terms are comparable whatever the traffic.
+func rank(trace sqs.Trace, latencyWeight float64) []candidate {
+	candidates := grid()
+
+	bestLatency, bestPolls := time.Duration(0), 0
+	for i := range candidates {
+		result := sqs.Simulate(trace, candidates[i].options()...)
+		candidates[i].Result = result
+
+		if i == 0 || result.MeanLatency < bestLatency {
+			bestLatency = result.MeanLatency
+		}
+		if i == 0 || result.Polls < bestPolls {
+			bestPolls = result.Polls
+		}
+	}
+
+	bestLatency = max(bestLatency, time.Millisecond)
+	bestPolls = max(bestPolls, 1)
+
+	for i := range candidates {
+		latency := float64(candidates[i].Result.MeanLatency) / float64(bestLatency)
+		polls := float64(candidates[i].Result.Polls) / float64(bestPolls)
+		candidates[i].Score = latencyWeight*latency + (1-latencyWeight)*polls
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].Score < candidates[j].Score })
+
+	return candidates
+}
+
+// report prints the recommended settings followed by the best candidates.
+func report(w io.Writer, trace sqs.Trace, latencyWeight float64, candidates []candidate) {
+	best := candidates[0]
+
+	fmt.Fprintf(w, "Trace: %d messages over %v\n\n", trace.Messages(), trace.Duration())
+	fmt.Fprintf(w, "Recommended settings (latency weight %.2f):\n\n", latencyWeight)
+	fmt.Fprintf(w, "\tsqs.WithEwmaAlpha(%v),\n", best.Alpha)
+	fmt.Fprintf(w, "\tsqs.WithDropDetectionThreshold(%d),\n", best.DropDetection)
+	fmt.Fprintf(w, "\tsqs.WithConsecutiveEmptyThreshold(%d),\n", best.ConsecutiveEmpty)
+	fmt.Fprintf(w, "\tsqs.WithIdleWaitTimeSeconds(%d),\n", best.Waits.Idle)
+	fmt.Fprintf(w, "\tsqs.WithLowVolumeWaitTimeSeconds(%d),\n", best.Waits.Low)
+	fmt.Fprintf(w, "\tsqs.WithMediumVolumeWaitTimeSeconds(%d),\n", best.Waits.Medium)
+	fmt.Fprintf(w, "\tsqs.WithHighVolumeWaitTimeSeconds(%d),\n", best.Waits.High)
+	fmt.Fprintf(w, "\tsqs.WithVeryHighVolumeWaitTimeSeconds(%d),\n\n", best.Waits.VeryHigh)
+	fmt.Fprintf(w, "Expected: %d polls (%d empty), mean latency %v, max latency %v\n\n",
+		best.Result.Polls, best.Result.EmptyPolls, best.Result.MeanLatency.Round(time.Millisecond), best.Result.MaxLatency.Round(time.Millisecond))
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "ALPHA\tDROP\tEMPTY\tWAITS\tPOLLS\tMEAN LATENCY\tMAX LATENCY\tSCORE")
+	for _, c := range candidates {
+		fmt.Fprintf(table, "%v\t%d\t%d\t%s\t%d\t%v\t%v\t%.3f\n",
+			c.Alpha, c.DropDetection, c.ConsecutiveEmpty, c.Waits.Name, c.Result.Polls,
+			c.Result.MeanLatency.Round(time.Millisecond), c.Result.MaxLatency.Round(time.Millisecond), c.Score)
+	}
+	table.Flush()
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elissonalvesilva/arrakis/pkg/sqs"
+)
+
+// burstyTrace returns ten minutes of steady traffic followed by an idle period and a burst.
+func burstyTrace() sqs.Trace {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var trace sqs.Trace
+	for i := range 300 {
+		trace.Events = append(trace.Events, sqs.TraceEvent{Time: start.Add(time.Duration(i) * 2 * time.Second), Messages: 1})
+	}
+	trace.Events = append(trace.Events, sqs.TraceEvent{Time: start.Add(30 * time.Minute), Messages: 50})
+
+	return trace
+}
+
+// writeTrace stores a trace as a JSON file and returns its path.
+func writeTrace(t *testing.T, trace sqs.Trace) string {
+	t.Helper()
+
+	data, err := json.Marshal(trace.Events)
+	if err != nil {
+		t.Fatalf("Failed to encode trace: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "trace.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("Failed to write trace: %v", err)
+	}
+
+	return path
+}
+
+func TestRankFollowsLatencyWeight(t *testing.T) {
+	trace := burstyTrace()
+
+	latency := rank(trace, 1)[0]
+	cost := rank(trace, 0)[0]
+
+	if latency.Result.MeanLatency > cost.Result.MeanLatency {
+		t.Errorf("Expected the latency-first pick to have the lower latency, got %v and %v",
+			latency.Result.MeanLatency, cost.Result.MeanLatency)
+	}
+	if cost.Result.Polls > latency.Result.Polls {
+		t.Errorf("Expected the cost-first pick to use fewer polls, got %d and %d", cost.Result.Polls, latency.Result.Polls)
+	}
+	if latency.Waits.Name != "responsive" || cost.Waits.Name != "economical" {
+		t.Errorf("Expected the responsive and economical wait times, got %s and %s", latency.Waits.Name, cost.Waits.Name)
+	}
+}
+
+func TestRankCoversGrid(t *testing.T) {
+	candidates := rank(burstyTrace(), 0.5)
+
+	expected := len(_waitProfiles) * len(_alphas) * len(_dropDetectionThresholds) * len(_consecutiveEmptyThresholds)
+	if len(candidates) != expected {
+		t.Fatalf("Expected %d candidates, got %d", expected, len(candidates))
+	}
+
+	for i := 1; i < len(candidates); i++ {
+		if candidates[i].Score < candidates[i-1].Score {
+			t.Fatal("Expected candidates sorted by score")
+		}
+	}
+}
+
+func TestTunePrintsRecommendation(t *testing.T) {
+	path := writeTrace(t, burstyTrace())
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"tune", "--trace", path, "--top", "3"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+
+	output := stdout.String()
+	for _, expected := range []string{"Trace: 350 messages", "sqs.WithEwmaAlpha(", "sqs.WithIdleWaitTimeSeconds(", "MEAN LATENCY"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+
+	if rows := strings.Count(output[strings.Index(output, "ALPHA"):], "\n"); rows != 4 {
+		t.Errorf("Expected a header and 3 candidates, got %d lines", rows)
+	}
+}
+
+func TestTuneRejectsInvalidInput(t *testing.T) {
+	path := writeTrace(t, burstyTrace())
+
+	cases := map[string][]string{
+		"missing trace":  {"tune"},
+		"unknown file":   {"tune", "--trace", filepath.Join(t.TempDir(), "missing.json")},
+		"invalid weight": {"tune", "--trace", path, "--latency-weight", "2"},
+	}
+
+	for name, args := range cases {
+		var stdout, stderr bytes.Buffer
+		if code := run(args, &stdout, &stderr); code != 1 {
+			t.Errorf("%s: expected exit code 1, got %d", name, code)
+		}
+	}
+}
+
+func TestRunUnknownCommand(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"deploy"}, &stdout, &stderr); code != 2 {
+		t.Errorf("Expected exit code 2, got %d", code)
+	}
+
+	if !strings.Contains(stderr.String(), "Usage: arrakisctl") {
+		t.Errorf("Expected usage on stderr, got %q", stderr.String())
+	}
+}
@@ -33,8 +33,8 @@ func main() {
 		log.Fatalf("Failed to create AWS config: %v", err)
 	}
 
-	// Create SQS client with Arrakis
-	sqsClient := sqs.NewSQS(&cfg)
+	// Create SQS client with Arrakis, pointed at LocalStack
+	sqsClient := sqs.NewSQSWithOptions(&cfg, sqs.WithEndpoint(localStackEndpoint))
 
 	// Enable Arrakis adaptive polling
 	sqsClient.EnableArrakis()
@@ -64,19 +64,9 @@ func main() {
 }
 
 func createLocalStackConfig() (aws.Config, error) {
-	// Custom endpoint resolver for LocalStack
-	customResolver := aws.EndpointResolverWithOptionsFunc(func(service, region string, options ...interface{}) (aws.Endpoint, error) {
-		return aws.Endpoint{
-			PartitionID:   "aws",
-			URL:           localStackEndpoint,
-			SigningRegion: region,
-		}, nil
-	})
-
-	// Load config with LocalStack settings
+	// Load config with LocalStack settings; the endpoint is set on the SQS client
 	cfg, err := config.LoadDefaultConfig(context.TODO(),
 		config.WithRegion(region),
-		config.WithEndpointResolverWithOptions(customResolver),
 		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider("test", "test", "")),
 	)
 
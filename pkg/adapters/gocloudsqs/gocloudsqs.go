@@ -0,0 +1,237 @@
+// Package gocloudsqs provides a gocloud.dev/pubsub driver backed by the arrakis client, so
+// code written against the portable *pubsub.Subscription API receives with adaptive
+// polling transparently.
+package gocloudsqs
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/aws/smithy-go"
+	"gocloud.dev/gcerrors"
+	"gocloud.dev/pubsub"
+	"gocloud.dev/pubsub/batcher"
+	"gocloud.dev/pubsub/driver"
+
+	arrakis "github.com/elissonalvesilva/arrakis/pkg/sqs"
+)
+
+// Driver configuration values
+const (
+	_maxReceiveBatch        = 10                     // SQS returns at most 10 messages per receive
+	_defaultReceiveHandlers = 4                      // Concurrent receives; they share the queue adaptive state
+	_maxAckBatch            = 10                     // Acks and nacks sent per batch
+	_defaultAckHandlers     = 100                    // Concurrent ack batches
+	_emptyReceivePause      = 250 * time.Millisecond // Pause after an empty short poll (adaptive polling disabled)
+	_allMessageAttributes   = "All"
+)
+
+// SubscriptionOptions configures a subscription.
+type SubscriptionOptions struct {
+	// NackLazy makes Nack a no-op: the message is redelivered once its visibility timeout
+	// expires instead of right away.
+	NackLazy bool
+
+	// ReceiveBatcherOptions adds constraints to the default batching of receives.
+	ReceiveBatcherOptions batcher.Options
+
+	// AckBatcherOptions adds constraints to the default batching of acks.
+	AckBatcherOptions batcher.Options
+}
+
+// OpenSubscription opens a portable subscription on an SQS queue. Receives go through the
+// arrakis client, so the queue is polled with the adaptive wait time when Arrakis is
+// enabled. Message attributes with string values become the message metadata.
+//
+// Parameters:
+//   - client: The arrakis client used for all API calls
+//   - queueURL: The URL of the queue to consume
+//   - opts: Optional settings (nil for defaults)
+//
+// Returns:
+//   - *pubsub.Subscription: The portable subscription
+//
+// Example:
+//
+//	subscription := gocloudsqs.OpenSubscription(sqsClient, queueURL, nil)
+//	defer subscription.Shutdown(ctx)
+//
+//	msg, err := subscription.Receive(ctx)
+//	if err == nil {
+//	    process(msg.Body)
+//	    msg.Ack()
+//	}
+func OpenSubscription(client *arrakis.SQS, queueURL string, opts *SubscriptionOptions) *pubsub.Subscription {
+	if opts == nil {
+		opts = &SubscriptionOptions{}
+	}
+
+	recvOpts := (&batcher.Options{MaxBatchSize: _maxReceiveBatch, MaxHandlers: _defaultReceiveHandlers}).NewMergedOptions(&opts.ReceiveBatcherOptions)
+	ackOpts := (&batcher.Options{MaxBatchSize: _maxAckBatch, MaxHandlers: _defaultAckHandlers}).NewMergedOptions(&opts.AckBatcherOptions)
+
+	return pubsub.NewSubscription(&subscription{client: client, queueURL: queueURL, opts: *opts}, recvOpts, ackOpts)
+}
+
+// subscription implements driver.Subscription.
+type subscription struct {
+	client   *arrakis.SQS
+	queueURL string
+	opts     SubscriptionOptions
+}
+
+// ReceiveBatch implements driver.Subscription.ReceiveBatch.
+func (s *subscription) ReceiveBatch(ctx context.Context, maxMessages int) ([]*driver.Message, error) {
+	output, err := s.client.ReceiveMessage(ctx, s.queueURL, int32(min(max(maxMessages, 1), _maxReceiveBatch)),
+		map[string]string{_allMessageAttributes: ""}, withSystemAttributes)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]*driver.Message, 0, len(output.Messages))
+	for _, m := range output.Messages {
+		messages = append(messages, driverMessage(m))
+	}
+
+	if len(messages) == 0 && !s.client.IsArrakisEnabledFor(s.queueURL) {
+		// Short polls return right away; the portable type calls ReceiveBatch again immediately
+		time.Sleep(_emptyReceivePause)
+	}
+
+	return messages, nil
+}
+
+// withSystemAttributes requests every system attribute, exposed through As.
+func withSystemAttributes(input *sqs.ReceiveMessageInput) {
+	input.MessageSystemAttributeNames = []types.MessageSystemAttributeName{types.MessageSystemAttributeNameAll}
+}
+
+// driverMessage converts a received SDK message into a driver message.
+func driverMessage(m types.Message) *driver.Message {
+	metadata := make(map[string]string, len(m.MessageAttributes))
+	for name, value := range m.MessageAttributes {
+		if value.StringValue != nil {
+			metadata[name] = aws.ToString(value.StringValue)
+		}
+	}
+
+	return &driver.Message{
+		LoggableID: aws.ToString(m.MessageId),
+		Body:       []byte(aws.ToString(m.Body)),
+		Metadata:   metadata,
+		AckID:      aws.ToString(m.ReceiptHandle),
+		AsFunc: func(i any) bool {
+			p, ok := i.(*types.Message)
+			if !ok {
+				return false
+			}
+			*p = m
+			return true
+		},
+	}
+}
+
+// SendAcks implements driver.Subscription.SendAcks by deleting the messages.
+func (s *subscription) SendAcks(ctx context.Context, ackIDs []driver.AckID) error {
+	var errs []error
+	for _, id := range ackIDs {
+		if _, err := s.client.DeleteMessage(ctx, s.queueURL, id.(string)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// CanNack implements driver.Subscription.CanNack.
+func (s *subscription) CanNack() bool {
+	return true
+}
+
+// SendNacks implements driver.Subscription.SendNacks by making the messages visible again
+// right away, unless NackLazy is set.
+func (s *subscription) SendNacks(ctx context.Context, ackIDs []driver.AckID) error {
+	if s.opts.NackLazy {
+		return nil
+	}
+
+	var errs []error
+	for _, id := range ackIDs {
+		_, err := s.client.ChangeMessageVisibility(ctx, s.queueURL, id.(string), 0)
+
+		// The message may have been deleted or redelivered meanwhile
+		var invalid *types.ReceiptHandleIsInvalid
+		if err != nil && !errors.As(err, &invalid) {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// IsRetryable implements driver.Subscription.IsRetryable. The SDK already retries.
+func (s *subscription) IsRetryable(err error) bool {
+	return false
+}
+
+// As implements driver.Subscription.As, exposing the arrakis client.
+func (s *subscription) As(i any) bool {
+	p, ok := i.(**arrakis.SQS)
+	if !ok {
+		return false
+	}
+	*p = s.client
+	return true
+}
+
+// ErrorAs implements driver.Subscription.ErrorAs.
+func (s *subscription) ErrorAs(err error, i any) bool {
+	return errors.As(err, i)
+}
+
+// ErrorCode implements driver.Subscription.ErrorCode.
+func (s *subscription) ErrorCode(err error) gcerrors.ErrorCode {
+	return errorCode(err)
+}
+
+// Close implements driver.Subscription.Close.
+func (s *subscription) Close() error {
+	return nil
+}
+
+// errorCode maps SQS API errors to portable error codes.
+func errorCode(err error) gcerrors.ErrorCode {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		if errors.Is(err, context.Canceled) {
+			return gcerrors.Canceled
+		}
+		if errors.Is(err, context.DeadlineExceeded) {
+			return gcerrors.DeadlineExceeded
+		}
+		return gcerrors.Unknown
+	}
+
+	if code, ok := _errorCodes[apiErr.ErrorCode()]; ok {
+		return code
+	}
+
+	return gcerrors.Unknown
+}
+
+// _errorCodes maps SQS error codes to portable error codes.
+var _errorCodes = map[string]gcerrors.ErrorCode{
+	(&types.QueueDoesNotExist{}).ErrorCode():      gcerrors.NotFound,
+	(&types.ReceiptHandleIsInvalid{}).ErrorCode(): gcerrors.FailedPrecondition,
+	(&types.MessageNotInflight{}).ErrorCode():     gcerrors.FailedPrecondition,
+	(&types.OverLimit{}).ErrorCode():              gcerrors.ResourceExhausted,
+	(&types.RequestThrottled{}).ErrorCode():       gcerrors.ResourceExhausted,
+	(&types.KmsThrottled{}).ErrorCode():           gcerrors.ResourceExhausted,
+	(&types.KmsAccessDenied{}).ErrorCode():        gcerrors.PermissionDenied,
+	(&types.InvalidAddress{}).ErrorCode():         gcerrors.InvalidArgument,
+	(&types.InvalidSecurity{}).ErrorCode():        gcerrors.PermissionDenied,
+	(&types.UnsupportedOperation{}).ErrorCode():   gcerrors.Unimplemented,
+}
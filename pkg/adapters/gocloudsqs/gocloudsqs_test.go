@@ -0,0 +1,114 @@
+package gocloudsqs
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"gocloud.dev/gcerrors"
+
+	arrakis "github.com/elissonalvesilva/arrakis/pkg/sqs"
+)
+
+// fakeServer serves the SQS JSON protocol: one message on the first receive, then nothing.
+type fakeServer struct {
+	mu      sync.Mutex
+	served  bool
+	deleted []string
+}
+
+func (f *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var input map[string]any
+	_ = json.NewDecoder(r.Body).Decode(&input)
+
+	w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+
+	switch strings.TrimPrefix(r.Header.Get("X-Amz-Target"), "AmazonSQS.") {
+	case "ReceiveMessage":
+		if f.served {
+			_, _ = w.Write([]byte(`{}`))
+			return
+		}
+		f.served = true
+
+		body := `{"order":42}`
+		sum := md5.Sum([]byte(body))
+		_ = json.NewEncoder(w).Encode(map[string]any{"Messages": []map[string]any{{
+			"MessageId":         "m1",
+			"ReceiptHandle":     "handle-1",
+			"Body":              body,
+			"MD5OfBody":         hex.EncodeToString(sum[:]),
+			"MessageAttributes": map[string]any{"tenant": map[string]any{"DataType": "String", "StringValue": "acme"}},
+		}}})
+	case "DeleteMessage":
+		f.deleted = append(f.deleted, input["ReceiptHandle"].(string))
+		_, _ = w.Write([]byte(`{}`))
+	default:
+		_, _ = w.Write([]byte(`{}`))
+	}
+}
+
+func TestSubscriptionReceiveAndAck(t *testing.T) {
+	fake := &fakeServer{}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	client := arrakis.NewSQSWithOptions(&aws.Config{
+		Region:      "us-east-1",
+		Credentials: credentials.NewStaticCredentialsProvider("key", "secret", ""),
+	}, arrakis.WithEndpoint(server.URL))
+
+	ctx := context.Background()
+	subscription := OpenSubscription(client, server.URL+"/000000000000/orders", nil)
+	defer subscription.Shutdown(ctx)
+
+	msg, err := subscription.Receive(ctx)
+	if err != nil {
+		t.Fatalf("Receive returned error: %v", err)
+	}
+
+	if string(msg.Body) != `{"order":42}` || msg.Metadata["tenant"] != "acme" {
+		t.Errorf("Expected body and metadata, got %s %v", msg.Body, msg.Metadata)
+	}
+
+	var raw types.Message
+	if !msg.As(&raw) || aws.ToString(raw.MessageId) != "m1" {
+		t.Error("Expected As to expose the SDK message")
+	}
+
+	msg.Ack()
+	if err := subscription.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown returned error: %v", err)
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if len(fake.deleted) != 1 || fake.deleted[0] != "handle-1" {
+		t.Errorf("Expected the acked message to be deleted, got %v", fake.deleted)
+	}
+}
+
+func TestErrorCode(t *testing.T) {
+	if code := errorCode(&types.QueueDoesNotExist{}); code != gcerrors.NotFound {
+		t.Errorf("Expected NotFound, got %v", code)
+	}
+	if code := errorCode(context.Canceled); code != gcerrors.Canceled {
+		t.Errorf("Expected Canceled, got %v", code)
+	}
+	if code := errorCode(errors.New("boom")); code != gcerrors.Unknown {
+		t.Errorf("Expected Unknown, got %v", code)
+	}
+}
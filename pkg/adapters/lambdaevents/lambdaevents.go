@@ -0,0 +1,183 @@
+// Package lambdaevents converts between arrakis messages and the SQS event types of
+// aws-lambda-go, so the same business handlers can run behind a Lambda SQS trigger and
+// behind a long-running arrakis Consumer.
+package lambdaevents
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+
+	arrakis "github.com/elissonalvesilva/arrakis/pkg/sqs"
+)
+
+// Queue ARN and URL layout
+const (
+	_arnPrefix        = "arn:"
+	_arnService       = "sqs"
+	_arnParts         = 6 // arn:partition:sqs:region:account:name
+	_defaultPartition = "aws"
+	_chinaPartition   = "aws-cn"
+	_defaultDomain    = "amazonaws.com"
+	_chinaDomain      = "amazonaws.com.cn"
+)
+
+// FromEvent converts a Lambda SQS record into an arrakis Message. The queue URL is
+// derived from the record's event source ARN.
+//
+// Parameters:
+//   - record: A record of an events.SQSEvent
+//
+// Returns:
+//   - arrakis.Message: The message, as a Consumer would deliver it
+//
+// Example:
+//
+//	func handle(ctx context.Context, event events.SQSEvent) error {
+//	    for _, record := range event.Records {
+//	        if err := processOrder(ctx, lambdaevents.FromEvent(record)); err != nil {
+//	            return err
+//	        }
+//	    }
+//	    return nil
+//	}
+func FromEvent(record events.SQSMessage) arrakis.Message {
+	m := types.Message{
+		MessageId:              aws.String(record.MessageId),
+		ReceiptHandle:          aws.String(record.ReceiptHandle),
+		Body:                   aws.String(record.Body),
+		MD5OfBody:              aws.String(record.Md5OfBody),
+		MD5OfMessageAttributes: aws.String(record.Md5OfMessageAttributes),
+		Attributes:             record.Attributes,
+	}
+
+	if len(record.MessageAttributes) > 0 {
+		m.MessageAttributes = make(map[string]types.MessageAttributeValue, len(record.MessageAttributes))
+		for name, value := range record.MessageAttributes {
+			m.MessageAttributes[name] = types.MessageAttributeValue{
+				DataType:         aws.String(value.DataType),
+				StringValue:      value.StringValue,
+				BinaryValue:      value.BinaryValue,
+				StringListValues: value.StringListValues,
+				BinaryListValues: value.BinaryListValues,
+			}
+		}
+	}
+
+	return arrakis.NewMessage(QueueURL(record.EventSourceARN), m)
+}
+
+// ToEvent converts an arrakis Message into a Lambda SQS record, e.g. to call a Lambda
+// handler from a Consumer. The event source ARN and region are derived from the queue URL.
+//
+// Parameters:
+//   - msg: A message received by arrakis
+//
+// Returns:
+//   - events.SQSMessage: The record, as Lambda would deliver it
+func ToEvent(msg arrakis.Message) events.SQSMessage {
+	arn := QueueARN(msg.QueueURL)
+
+	record := events.SQSMessage{
+		MessageId:      msg.ID,
+		ReceiptHandle:  msg.ReceiptHandle,
+		Body:           msg.Body,
+		Attributes:     msg.Attributes,
+		EventSource:    "aws:sqs",
+		EventSourceARN: arn,
+	}
+
+	if parts := strings.Split(arn, ":"); len(parts) == _arnParts {
+		record.AWSRegion = parts[3]
+	}
+
+	if len(msg.MessageAttributes) > 0 {
+		record.MessageAttributes = make(map[string]events.SQSMessageAttribute, len(msg.MessageAttributes))
+		for name, value := range msg.MessageAttributes {
+			record.MessageAttributes[name] = events.SQSMessageAttribute{
+				DataType:         aws.ToString(value.DataType),
+				StringValue:      value.StringValue,
+				BinaryValue:      value.BinaryValue,
+				StringListValues: value.StringListValues,
+				BinaryListValues: value.BinaryListValues,
+			}
+		}
+	}
+
+	return record
+}
+
+// Handler adapts an arrakis Handler to a Lambda SQS handler reporting partial batch
+// failures: records whose handling fails are listed in the response, so only they are
+// retried (requires ReportBatchItemFailures on the event source mapping).
+//
+// Parameters:
+//   - handler: The handler shared with arrakis consumers
+//
+// Returns:
+//   - func: A handler to pass to lambda.Start
+//
+// Example:
+//
+//	lambda.Start(lambdaevents.Handler(sqs.HandlerFunc(processOrder)))
+func Handler(handler arrakis.Handler) func(ctx context.Context, event events.SQSEvent) (events.SQSEventResponse, error) {
+	return func(ctx context.Context, event events.SQSEvent) (events.SQSEventResponse, error) {
+		var response events.SQSEventResponse
+
+		for _, record := range event.Records {
+			if err := handler.Handle(ctx, FromEvent(record)); err != nil {
+				response.BatchItemFailures = append(response.BatchItemFailures, events.SQSBatchItemFailure{ItemIdentifier: record.MessageId})
+			}
+		}
+
+		return response, nil
+	}
+}
+
+// QueueURL converts a queue ARN (arn:aws:sqs:us-east-1:123456789012:orders) into its
+// queue URL (https://sqs.us-east-1.amazonaws.com/123456789012/orders). Anything that is
+// not an SQS ARN is returned unchanged.
+func QueueURL(arn string) string {
+	parts := strings.Split(arn, ":")
+	if len(parts) != _arnParts || !strings.HasPrefix(arn, _arnPrefix) || parts[2] != _arnService {
+		return arn
+	}
+
+	domain := _defaultDomain
+	if parts[1] == _chinaPartition {
+		domain = _chinaDomain
+	}
+
+	return fmt.Sprintf("https://sqs.%s.%s/%s/%s", parts[3], domain, parts[4], parts[5])
+}
+
+// QueueARN converts a queue URL into its ARN. It is the inverse of QueueURL; anything
+// that is not an SQS queue URL is returned unchanged.
+func QueueARN(queueURL string) string {
+	rest, ok := strings.CutPrefix(queueURL, "https://sqs.")
+	if !ok {
+		return queueURL
+	}
+
+	host, path, ok := strings.Cut(rest, "/")
+	if !ok {
+		return queueURL
+	}
+
+	region, domain, ok := strings.Cut(host, ".")
+	account, name, ok2 := strings.Cut(path, "/")
+	if !ok || !ok2 || account == "" || name == "" {
+		return queueURL
+	}
+
+	partition := _defaultPartition
+	if domain == _chinaDomain {
+		partition = _chinaPartition
+	}
+
+	return fmt.Sprintf("arn:%s:sqs:%s:%s:%s", partition, region, account, name)
+}
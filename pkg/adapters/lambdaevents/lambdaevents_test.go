@@ -0,0 +1,101 @@
+package lambdaevents
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/aws"
+
+	arrakis "github.com/elissonalvesilva/arrakis/pkg/sqs"
+)
+
+func testRecord() events.SQSMessage {
+	return events.SQSMessage{
+		MessageId:      "m1",
+		ReceiptHandle:  "handle-1",
+		Body:           `{"order":42}`,
+		EventSourceARN: "arn:aws:sqs:us-east-1:123456789012:orders.fifo",
+		Attributes: map[string]string{
+			"ApproximateReceiveCount": "2",
+			"MessageGroupId":          "customer-7",
+			"SentTimestamp":           "1700000000000",
+		},
+		MessageAttributes: map[string]events.SQSMessageAttribute{
+			"type": {DataType: "String", StringValue: aws.String("order.created")},
+		},
+	}
+}
+
+func TestFromEvent(t *testing.T) {
+	msg := FromEvent(testRecord())
+
+	if msg.ID != "m1" || msg.ReceiptHandle != "handle-1" || msg.Body != `{"order":42}` {
+		t.Errorf("Expected record fields to be copied, got %+v", msg)
+	}
+	if msg.QueueURL != "https://sqs.us-east-1.amazonaws.com/123456789012/orders.fifo" {
+		t.Errorf("Expected queue URL derived from the ARN, got %s", msg.QueueURL)
+	}
+	if msg.ReceiveCount != 2 || msg.GroupID != "customer-7" || msg.SentTimestamp.IsZero() {
+		t.Errorf("Expected system attributes to be parsed, got %+v", msg)
+	}
+	if aws.ToString(msg.MessageAttributes["type"].StringValue) != "order.created" {
+		t.Error("Expected message attributes to be copied")
+	}
+}
+
+func TestToEventRoundTrip(t *testing.T) {
+	record := ToEvent(FromEvent(testRecord()))
+
+	if record.EventSourceARN != testRecord().EventSourceARN || record.AWSRegion != "us-east-1" {
+		t.Errorf("Expected ARN and region to be restored, got %s %s", record.EventSourceARN, record.AWSRegion)
+	}
+	if record.MessageAttributes["type"].DataType != "String" || record.Attributes["MessageGroupId"] != "customer-7" {
+		t.Errorf("Expected attributes to be restored, got %+v", record)
+	}
+}
+
+func TestQueueURLAndARN(t *testing.T) {
+	tests := []struct {
+		arn string
+		url string
+	}{
+		{"arn:aws:sqs:eu-west-1:1:jobs", "https://sqs.eu-west-1.amazonaws.com/1/jobs"},
+		{"arn:aws-cn:sqs:cn-north-1:1:jobs", "https://sqs.cn-north-1.amazonaws.com.cn/1/jobs"},
+	}
+
+	for _, tt := range tests {
+		if got := QueueURL(tt.arn); got != tt.url {
+			t.Errorf("Expected URL %s, got %s", tt.url, got)
+		}
+		if got := QueueARN(tt.url); got != tt.arn {
+			t.Errorf("Expected ARN %s, got %s", tt.arn, got)
+		}
+	}
+
+	if got := QueueURL("http://localhost:4566/000000000000/jobs"); got != "http://localhost:4566/000000000000/jobs" {
+		t.Errorf("Expected non-ARN input unchanged, got %s", got)
+	}
+}
+
+func TestHandlerReportsBatchItemFailures(t *testing.T) {
+	second := testRecord()
+	second.MessageId = "m2"
+
+	handler := Handler(arrakis.HandlerFunc(func(ctx context.Context, msg arrakis.Message) error {
+		if msg.ID == "m2" {
+			return errors.New("failed")
+		}
+		return nil
+	}))
+
+	response, err := handler(context.Background(), events.SQSEvent{Records: []events.SQSMessage{testRecord(), second}})
+	if err != nil {
+		t.Fatalf("Handler returned error: %v", err)
+	}
+
+	if len(response.BatchItemFailures) != 1 || response.BatchItemFailures[0].ItemIdentifier != "m2" {
+		t.Errorf("Expected m2 reported as failed, got %+v", response.BatchItemFailures)
+	}
+}
@@ -0,0 +1,73 @@
+// Package logruslogger adapts a logrus logger to the arrakis Logger interface.
+package logruslogger
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	arrakis "github.com/elissonalvesilva/arrakis/pkg/sqs"
+)
+
+// _missingValueKey holds a trailing key without value
+const _missingValueKey = "!BADKEY"
+
+// Compile-time check of the Logger interface
+var _ arrakis.Logger = (*Logger)(nil)
+
+// Logger is an arrakis Logger writing to logrus. Key/value arguments become logrus fields.
+type Logger struct {
+	logger logrus.FieldLogger
+}
+
+// New wraps a logrus logger or entry.
+//
+// Parameters:
+//   - logger: The logrus logger (or entry with preset fields) receiving the records
+//
+// Returns:
+//   - *Logger: A logger to pass to sqs.WithLogger
+//
+// Example:
+//
+//	client := sqs.NewSQSWithOptions(&cfg, sqs.WithLogger(logruslogger.New(logrus.WithField("component", "arrakis"))))
+func New(logger logrus.FieldLogger) *Logger {
+	return &Logger{logger: logger}
+}
+
+// Debug logs at debug level.
+func (l *Logger) Debug(msg string, keysAndValues ...any) {
+	l.logger.WithFields(fields(keysAndValues)).Debug(msg)
+}
+
+// Info logs at info level.
+func (l *Logger) Info(msg string, keysAndValues ...any) {
+	l.logger.WithFields(fields(keysAndValues)).Info(msg)
+}
+
+// Warn logs at warn level.
+func (l *Logger) Warn(msg string, keysAndValues ...any) {
+	l.logger.WithFields(fields(keysAndValues)).Warn(msg)
+}
+
+// Error logs at error level.
+func (l *Logger) Error(msg string, keysAndValues ...any) {
+	l.logger.WithFields(fields(keysAndValues)).Error(msg)
+}
+
+// fields converts alternating keys and values into logrus fields. A trailing key without
+// value is kept under !BADKEY, as log/slog does.
+func fields(keysAndValues []any) logrus.Fields {
+	result := make(logrus.Fields, len(keysAndValues)/2)
+
+	for i := 0; i < len(keysAndValues); i += 2 {
+		if i+1 == len(keysAndValues) {
+			result[_missingValueKey] = keysAndValues[i]
+			break
+		}
+
+		result[fmt.Sprint(keysAndValues[i])] = keysAndValues[i+1]
+	}
+
+	return result
+}
@@ -0,0 +1,39 @@
+package logruslogger
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+func TestLoggerWritesFields(t *testing.T) {
+	base, hook := test.NewNullLogger()
+	base.SetLevel(logrus.DebugLevel)
+	logger := New(base)
+
+	logger.Debug("volume class changed", "queue", "orders", "to", "high")
+	logger.Warn("receive failed", "queue", "orders", "error", errors.New("unavailable"))
+
+	entries := hook.AllEntries()
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+
+	if entries[0].Level != logrus.DebugLevel || entries[0].Data["to"] != "high" {
+		t.Errorf("Expected a debug entry with fields, got %+v", entries[0])
+	}
+
+	if entries[1].Level != logrus.WarnLevel || entries[1].Message != "receive failed" {
+		t.Errorf("Expected a warn entry, got %+v", entries[1])
+	}
+}
+
+func TestFieldsKeepsDanglingKey(t *testing.T) {
+	result := fields([]any{"queue", "orders", "dangling"})
+
+	if result["queue"] != "orders" || result[_missingValueKey] != "dangling" {
+		t.Errorf("Expected queue and dangling key, got %v", result)
+	}
+}
@@ -0,0 +1,64 @@
+// Package redisdedup implements the arrakis DedupStore interface on Redis, letting the
+// consumers of replicated queues, in any region or instance, handle each message once.
+package redisdedup
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	arrakis "github.com/elissonalvesilva/arrakis/pkg/sqs"
+)
+
+// Compile-time check of the DedupStore interface
+var _ arrakis.DedupStore = (*Store)(nil)
+
+// Store keeps each claim in its own Redis key, under a common prefix, expiring with the
+// claim.
+type Store struct {
+	client redis.Cmdable
+	prefix string
+}
+
+// New creates a dedup store keeping its claims under prefix. Consumers whose stores share
+// the prefix deduplicate against each other.
+//
+// Parameters:
+//   - client: The Redis client, such as a *redis.Client or *redis.ClusterClient
+//   - prefix: The prefix of the claim keys (e.g., "arrakis:orders")
+//
+// Returns:
+//   - *Store: A dedup store to pass to sqs.NewActiveActive or sqs.WithSharedDeduplication
+//
+// Example:
+//
+//	stream := sqs.NewActiveActive(queues, handler, redisdedup.New(redisClient, "arrakis:orders"), time.Hour)
+func New(client redis.Cmdable, prefix string) *Store {
+	return &Store{client: client, prefix: prefix}
+}
+
+// Claim sets the key of the claim unless it exists.
+func (s *Store) Claim(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	claimed, err := s.client.SetNX(ctx, s.key(key), 1, ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("redisdedup: claim: %w", err)
+	}
+
+	return claimed, nil
+}
+
+// Release deletes the key of the claim.
+func (s *Store) Release(ctx context.Context, key string) error {
+	if err := s.client.Del(ctx, s.key(key)).Err(); err != nil {
+		return fmt.Errorf("redisdedup: release: %w", err)
+	}
+
+	return nil
+}
+
+// key returns the Redis key of a claim.
+func (s *Store) key(key string) string {
+	return s.prefix + ":" + key
+}
@@ -0,0 +1,38 @@
+package redisdedup
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestStoreClaims(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	ctx := context.Background()
+	store := New(client, "orders")
+
+	if claimed, err := store.Claim(ctx, "event-1", time.Minute); err != nil || !claimed {
+		t.Fatalf("Expected the first claim to succeed, got %v, %v", claimed, err)
+	}
+	if claimed, _ := store.Claim(ctx, "event-1", time.Minute); claimed {
+		t.Error("Expected a second claim of the same key to fail")
+	}
+
+	if err := store.Release(ctx, "event-1"); err != nil {
+		t.Fatalf("Expected the claim to be released, got %v", err)
+	}
+	if claimed, _ := store.Claim(ctx, "event-1", time.Second); !claimed {
+		t.Error("Expected a released key to be claimable again")
+	}
+
+	server.FastForward(2 * time.Second)
+	if claimed, _ := store.Claim(ctx, "event-1", time.Minute); !claimed {
+		t.Error("Expected an expired claim to be claimable again")
+	}
+}
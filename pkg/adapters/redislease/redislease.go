@@ -0,0 +1,73 @@
+// Package redislease implements the arrakis LeaseStore interface on Redis, letting a
+// Coordinator spread queues over every instance sharing a key.
+package redislease
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	arrakis "github.com/elissonalvesilva/arrakis/pkg/sqs"
+)
+
+// Compile-time check of the LeaseStore interface
+var _ arrakis.LeaseStore = (*Store)(nil)
+
+// renewScript sets the lease of ARGV[1] in the sorted set KEYS[1] to expire in ARGV[2]
+// milliseconds, drops the expired leases and returns the members left. The clock of the
+// Redis server is used so that instances don't need synchronized clocks.
+var renewScript = redis.NewScript(`
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+local ttl = tonumber(ARGV[2])
+
+redis.call("ZADD", KEYS[1], now + ttl, ARGV[1])
+redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", now)
+redis.call("PEXPIRE", KEYS[1], ttl)
+
+return redis.call("ZRANGE", KEYS[1], 0, -1)
+`)
+
+// Store keeps the leases of a fleet in a Redis sorted set, scored by expiry time.
+type Store struct {
+	client redis.Cmdable
+	key    string
+}
+
+// New creates a lease store on the sorted set stored under key. Coordinators whose
+// stores share the key share the membership.
+//
+// Parameters:
+//   - client: The Redis client, such as a *redis.Client or *redis.ClusterClient
+//   - key: The Redis key holding the leases (e.g., "arrakis:leases:tenants")
+//
+// Returns:
+//   - *Store: A lease store to pass to sqs.NewCoordinator
+//
+// Example:
+//
+//	coordinator := sqs.NewCoordinator(redislease.New(redisClient, "arrakis:leases:tenants"), hostname, tenantQueueURLs)
+func New(client redis.Cmdable, key string) *Store {
+	return &Store{client: client, key: key}
+}
+
+// Renew creates or extends the lease of instanceID and returns the live members.
+func (s *Store) Renew(ctx context.Context, instanceID string, ttl time.Duration) ([]string, error) {
+	members, err := renewScript.Run(ctx, s.client, []string{s.key}, instanceID, ttl.Milliseconds()).StringSlice()
+	if err != nil {
+		return nil, fmt.Errorf("redislease: renew lease: %w", err)
+	}
+
+	return members, nil
+}
+
+// Release drops the lease of instanceID.
+func (s *Store) Release(ctx context.Context, instanceID string) error {
+	if err := s.client.ZRem(ctx, s.key, instanceID).Err(); err != nil {
+		return fmt.Errorf("redislease: release lease: %w", err)
+	}
+
+	return nil
+}
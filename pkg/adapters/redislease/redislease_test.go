@@ -0,0 +1,43 @@
+package redislease
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestStoreLeases(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	ctx := context.Background()
+	store := New(client, "leases")
+
+	if _, err := store.Renew(ctx, "instance-a", 100*time.Millisecond); err != nil {
+		t.Fatalf("Expected the lease to be renewed, got %v", err)
+	}
+	members, _ := store.Renew(ctx, "instance-b", time.Minute)
+	if !slices.Equal(members, []string{"instance-a", "instance-b"}) {
+		t.Errorf("Expected both instances to be members, got %v", members)
+	}
+
+	// miniredis reads TIME from the wall clock unless told otherwise
+	server.SetTime(time.Now().Add(time.Second))
+	members, _ = store.Renew(ctx, "instance-b", time.Minute)
+	if !slices.Equal(members, []string{"instance-b"}) {
+		t.Errorf("Expected the expired lease to be dropped, got %v", members)
+	}
+
+	if err := store.Release(ctx, "instance-b"); err != nil {
+		t.Fatalf("Expected the lease to be released, got %v", err)
+	}
+	members, _ = store.Renew(ctx, "instance-c", time.Minute)
+	if !slices.Equal(members, []string{"instance-c"}) {
+		t.Errorf("Expected the released lease to be gone, got %v", members)
+	}
+}
@@ -0,0 +1,96 @@
+// Package redisratelimit implements the arrakis RateLimiter interface on Redis, so that
+// every consumer sharing a key shares a single downstream call budget, whatever the
+// number of replicas.
+package redisratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	arrakis "github.com/elissonalvesilva/arrakis/pkg/sqs"
+)
+
+// Compile-time check of the RateLimiter interface
+var _ arrakis.RateLimiter = (*Limiter)(nil)
+
+// takeScript atomically refills the token bucket stored in the hash KEYS[1] and takes up
+// to ARGV[3] tokens from it. ARGV[1] is the rate in tokens per second and ARGV[2] the
+// burst. It returns the tokens granted and, when none was, the milliseconds until the
+// next one. The clock of the Redis server is used so that replicas don't need synchronized
+// clocks.
+var takeScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local requested = tonumber(ARGV[3])
+
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+local state = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(state[1]) or burst
+local last = tonumber(state[2]) or now
+
+tokens = math.min(burst, tokens + math.max(0, now - last) * rate / 1000)
+
+local granted = math.min(requested, math.floor(tokens))
+tokens = tokens - granted
+
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "ts", now)
+redis.call("PEXPIRE", KEYS[1], math.ceil(burst / rate * 1000) + 1000)
+
+local wait = 0
+if granted == 0 then
+	wait = math.ceil((1 - tokens) / rate * 1000)
+end
+
+return {granted, wait}
+`)
+
+// Limiter is a token bucket stored in Redis, refilling rate permits per second up to burst.
+type Limiter struct {
+	client redis.Scripter
+	key    string
+	rate   float64
+	burst  int
+}
+
+// New creates a limiter on the token bucket stored under key. Limiters created with the
+// same key, in any process, share the bucket.
+//
+// Parameters:
+//   - client: The Redis client, such as a *redis.Client or *redis.ClusterClient
+//   - key: The Redis key holding the bucket (e.g., "arrakis:ratelimit:partner-api")
+//   - rate: Permits added per second; must be positive
+//   - burst: Maximum number of permits available at once (at least 1)
+//
+// Returns:
+//   - *Limiter: A rate limiter to pass to sqs.WithRateLimiter
+//
+// Example:
+//
+//	limiter := redisratelimit.New(redisClient, "arrakis:ratelimit:partner-api", 100, 100)
+//	consumer := sqs.NewConsumer(client, queueURL, handler, sqs.WithRateLimiter(limiter))
+func New(client redis.Scripter, key string, rate float64, burst int) *Limiter {
+	return &Limiter{client: client, key: key, rate: rate, burst: max(burst, 1)}
+}
+
+// Take takes up to n permits from the shared bucket.
+func (l *Limiter) Take(ctx context.Context, n int) (int, time.Duration, error) {
+	if l.rate <= 0 {
+		return 0, 0, fmt.Errorf("redisratelimit: rate must be positive, got %g", l.rate)
+	}
+
+	result, err := takeScript.Run(ctx, l.client, []string{l.key}, l.rate, l.burst, n).Int64Slice()
+	if err != nil {
+		return 0, 0, fmt.Errorf("redisratelimit: take permits: %w", err)
+	}
+
+	if len(result) != 2 {
+		return 0, 0, fmt.Errorf("redisratelimit: unexpected script result %v", result)
+	}
+
+	return int(result[0]), time.Duration(result[1]) * time.Millisecond, nil
+}
@@ -0,0 +1,51 @@
+package redisratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestLimiterSharesBucket(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	ctx := context.Background()
+	first := New(client, "partner-api", 1, 3)
+	second := New(client, "partner-api", 1, 3)
+
+	if granted, _, err := first.Take(ctx, 2); err != nil || granted != 2 {
+		t.Fatalf("Expected 2 permits from a full bucket, got %d (%v)", granted, err)
+	}
+	if granted, _, _ := second.Take(ctx, 5); granted != 1 {
+		t.Errorf("Expected the permit left by the other limiter, got %d", granted)
+	}
+
+	granted, wait, _ := first.Take(ctx, 1)
+	if granted != 0 || wait <= 0 || wait > time.Second {
+		t.Errorf("Expected no permit and a wait of at most a second, got %d and %s", granted, wait)
+	}
+
+	if granted, _, _ := New(client, "other-api", 1, 3).Take(ctx, 3); granted != 3 {
+		t.Errorf("Expected separate keys to have separate buckets, got %d", granted)
+	}
+}
+
+func TestLimiterErrors(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	if _, _, err := New(client, "partner-api", 0, 1).Take(context.Background(), 1); err == nil {
+		t.Error("Expected an error for a zero rate")
+	}
+
+	server.Close()
+	if _, _, err := New(client, "partner-api", 1, 1).Take(context.Background(), 1); err == nil {
+		t.Error("Expected an error when Redis is unavailable")
+	}
+}
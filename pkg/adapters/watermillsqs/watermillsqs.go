@@ -0,0 +1,264 @@
+// Package watermillsqs implements Watermill's message.Publisher and message.Subscriber on
+// top of the arrakis client, so Watermill applications get adaptive polling by swapping
+// their Pub/Sub constructor. Topics are queue URLs unless a TopicResolver is configured.
+package watermillsqs
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+
+	arrakis "github.com/elissonalvesilva/arrakis/pkg/sqs"
+)
+
+// Message attribute carrying the Watermill message UUID
+const (
+	_uuidAttribute           = "_watermill_message_uuid"
+	_attributeDataTypeString = "String"
+)
+
+// MetadataGroupID is the metadata key holding the FIFO MessageGroupId. On publish it sets
+// the group of the message; on subscribe it is filled from the received message.
+const MetadataGroupID = "MessageGroupId"
+
+// Compile-time checks of the Watermill interfaces
+var (
+	_ message.Publisher  = (*Publisher)(nil)
+	_ message.Subscriber = (*Subscriber)(nil)
+)
+
+// ErrClosed is returned when publishing or subscribing after Close.
+var ErrClosed = errors.New("watermillsqs: pub/sub closed")
+
+// TopicResolver maps a Watermill topic to a queue URL.
+type TopicResolver func(topic string) (string, error)
+
+// queueURLTopic is the default TopicResolver: topics are queue URLs.
+func queueURLTopic(topic string) (string, error) {
+	return topic, nil
+}
+
+// Publisher publishes Watermill messages to SQS. Payloads become message bodies and
+// metadata becomes String message attributes (SQS allows at most 9 metadata entries,
+// one attribute being used for the message UUID).
+type Publisher struct {
+	client  *arrakis.SQS
+	resolve TopicResolver
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewPublisher creates a Publisher sending through client.
+//
+// Parameters:
+//   - client: The arrakis client used to send messages
+//   - resolve: Maps topics to queue URLs (nil treats topics as queue URLs)
+//
+// Returns:
+//   - *Publisher: A Watermill publisher
+//
+// Example:
+//
+//	publisher := watermillsqs.NewPublisher(sqsClient, nil)
+//	err := publisher.Publish(queueURL, message.NewMessage(watermill.NewUUID(), payload))
+func NewPublisher(client *arrakis.SQS, resolve TopicResolver) *Publisher {
+	if resolve == nil {
+		resolve = queueURLTopic
+	}
+
+	return &Publisher{client: client, resolve: resolve}
+}
+
+// Publish sends messages to the queue of topic, one at a time and in order. It stops at
+// the first failure.
+func (p *Publisher) Publish(topic string, messages ...*message.Message) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return ErrClosed
+	}
+
+	queueURL, err := p.resolve(topic)
+	if err != nil {
+		return err
+	}
+
+	for _, msg := range messages {
+		options := []arrakis.SendOption{arrakis.WithMessageAttributes(attributes(msg))}
+		if groupID := msg.Metadata.Get(MetadataGroupID); groupID != "" {
+			options = append(options, arrakis.WithMessageGroupID(groupID), arrakis.WithDeduplicationID(msg.UUID))
+		}
+
+		if _, err := p.client.SendMessage(msg.Context(), queueURL, string(msg.Payload), options...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Close makes subsequent Publish calls fail. Messages are sent synchronously, so there
+// is nothing to flush.
+func (p *Publisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.closed = true
+	return nil
+}
+
+// Subscriber delivers SQS messages to Watermill through arrakis consumers, one consumer
+// per subscription. Acked messages are deleted; nacked messages are left in the queue and
+// redelivered after their visibility timeout (or sooner with sqs.WithNackBackoff).
+type Subscriber struct {
+	client  *arrakis.SQS
+	resolve TopicResolver
+	options []arrakis.ConsumerOption
+
+	mu      sync.Mutex
+	closed  bool
+	closing chan struct{} // Closed by Close to stop every subscription
+	wg      sync.WaitGroup
+}
+
+// NewSubscriber creates a Subscriber receiving through client.
+//
+// Parameters:
+//   - client: The arrakis client used to receive messages (its adaptive polling applies)
+//   - resolve: Maps topics to queue URLs (nil treats topics as queue URLs)
+//   - options: Consumer options applied to every subscription (workers, nack backoff, ...)
+//
+// Returns:
+//   - *Subscriber: A Watermill subscriber
+//
+// Example:
+//
+//	subscriber := watermillsqs.NewSubscriber(sqsClient, nil, sqs.WithWorkers(4))
+//	router.AddConsumerHandler("orders", queueURL, subscriber, handleOrder)
+func NewSubscriber(client *arrakis.SQS, resolve TopicResolver, options ...arrakis.ConsumerOption) *Subscriber {
+	if resolve == nil {
+		resolve = queueURLTopic
+	}
+
+	return &Subscriber{client: client, resolve: resolve, options: options, closing: make(chan struct{})}
+}
+
+// Subscribe starts consuming the queue of topic. The returned channel is closed once ctx
+// is cancelled or the subscriber is closed, after in-flight messages are settled.
+func (s *Subscriber) Subscribe(ctx context.Context, topic string) (<-chan *message.Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil, ErrClosed
+	}
+
+	queueURL, err := s.resolve(topic)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	output := make(chan *message.Message)
+
+	consumer := arrakis.NewConsumer(s.client, queueURL, arrakis.HandlerFunc(func(handlerCtx context.Context, msg arrakis.Message) error {
+		return deliver(ctx, output, msg)
+	}), s.options...)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer close(output)
+		defer cancel()
+
+		go func() {
+			select {
+			case <-s.closing:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+
+		_ = consumer.Start(ctx)
+	}()
+
+	return output, nil
+}
+
+// Close stops every subscription and waits for their channels to be closed.
+func (s *Subscriber) Close() error {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.closing)
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return nil
+}
+
+// errNacked is returned to the consumer when Watermill nacks a message.
+var errNacked = errors.New("watermillsqs: message nacked")
+
+// deliver hands msg to Watermill and waits until it is acked, nacked, or the subscription
+// ends (in which case the message is left in the queue).
+func deliver(ctx context.Context, output chan<- *message.Message, msg arrakis.Message) error {
+	uuid := watermill.NewUUID()
+	if value, ok := msg.MessageAttributes[_uuidAttribute]; ok {
+		uuid = aws.ToString(value.StringValue)
+	}
+
+	wmMsg := message.NewMessageWithContext(ctx, uuid, message.Payload(msg.Body))
+	for name, value := range msg.MessageAttributes {
+		if name != _uuidAttribute && value.StringValue != nil {
+			wmMsg.Metadata.Set(name, aws.ToString(value.StringValue))
+		}
+	}
+	if msg.GroupID != "" {
+		wmMsg.Metadata.Set(MetadataGroupID, msg.GroupID)
+	}
+
+	select {
+	case output <- wmMsg:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case <-wmMsg.Acked():
+		return nil
+	case <-wmMsg.Nacked():
+		return arrakis.Retryable(errNacked)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// attributes converts the metadata of msg into String message attributes, adding the
+// message UUID. The group ID is sent as MessageGroupId instead of an attribute, and empty
+// values are dropped since SQS rejects them.
+func attributes(msg *message.Message) map[string]types.MessageAttributeValue {
+	result := make(map[string]types.MessageAttributeValue, len(msg.Metadata)+1)
+	result[_uuidAttribute] = stringAttribute(msg.UUID)
+
+	for name, value := range msg.Metadata {
+		if name != MetadataGroupID && value != "" {
+			result[name] = stringAttribute(value)
+		}
+	}
+
+	return result
+}
+
+// stringAttribute builds a String message attribute.
+func stringAttribute(value string) types.MessageAttributeValue {
+	return types.MessageAttributeValue{DataType: aws.String(_attributeDataTypeString), StringValue: aws.String(value)}
+}
@@ -0,0 +1,103 @@
+package watermillsqs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+
+	arrakis "github.com/elissonalvesilva/arrakis/pkg/sqs"
+)
+
+func receivedMessage() arrakis.Message {
+	return arrakis.Message{
+		ID:      "m1",
+		Body:    "payload",
+		GroupID: "customer-7",
+		MessageAttributes: map[string]types.MessageAttributeValue{
+			_uuidAttribute: stringAttribute("uuid-1"),
+			"tenant":       stringAttribute("acme"),
+		},
+	}
+}
+
+func TestDeliverAck(t *testing.T) {
+	output := make(chan *message.Message)
+	result := make(chan error, 1)
+	go func() {
+		result <- deliver(context.Background(), output, receivedMessage())
+	}()
+
+	msg := <-output
+	if msg.UUID != "uuid-1" || string(msg.Payload) != "payload" {
+		t.Errorf("Expected UUID and payload to be restored, got %s %s", msg.UUID, msg.Payload)
+	}
+	if msg.Metadata.Get("tenant") != "acme" || msg.Metadata.Get(MetadataGroupID) != "customer-7" {
+		t.Errorf("Expected metadata from attributes, got %v", msg.Metadata)
+	}
+	if _, ok := msg.Metadata[_uuidAttribute]; ok {
+		t.Error("Expected the UUID attribute not to leak into metadata")
+	}
+
+	msg.Ack()
+	if err := <-result; err != nil {
+		t.Errorf("Expected acked message to be deleted, got %v", err)
+	}
+}
+
+func TestDeliverNack(t *testing.T) {
+	output := make(chan *message.Message)
+	result := make(chan error, 1)
+	go func() {
+		result <- deliver(context.Background(), output, receivedMessage())
+	}()
+
+	(<-output).Nack()
+	if err := <-result; !arrakis.IsRetryable(err) {
+		t.Errorf("Expected a retryable error for a nacked message, got %v", err)
+	}
+}
+
+func TestDeliverStopsWithSubscription(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	// Nobody reads the output channel
+	err := deliver(ctx, make(chan *message.Message), receivedMessage())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the context error, got %v", err)
+	}
+}
+
+func TestAttributes(t *testing.T) {
+	msg := message.NewMessage("uuid-1", message.Payload("payload"))
+	msg.Metadata.Set("tenant", "acme")
+	msg.Metadata.Set("empty", "")
+	msg.Metadata.Set(MetadataGroupID, "customer-7")
+
+	attrs := attributes(msg)
+	if len(attrs) != 2 {
+		t.Errorf("Expected UUID and tenant attributes, got %v", attrs)
+	}
+	if aws.ToString(attrs[_uuidAttribute].StringValue) != "uuid-1" {
+		t.Error("Expected the UUID to be sent as an attribute")
+	}
+}
+
+func TestClosedPubSub(t *testing.T) {
+	publisher := NewPublisher(nil, nil)
+	_ = publisher.Close()
+	if err := publisher.Publish("queue", message.NewMessage("1", nil)); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed, got %v", err)
+	}
+
+	subscriber := NewSubscriber(nil, nil)
+	_ = subscriber.Close()
+	if _, err := subscriber.Subscribe(context.Background(), "queue"); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed, got %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+// Package zaplogger adapts a zap logger to the arrakis Logger interface.
+package zaplogger
+
+import (
+	"go.uber.org/zap"
+
+	arrakis "github.com/elissonalvesilva/arrakis/pkg/sqs"
+)
+
+// Compile-time check of the Logger interface
+var _ arrakis.Logger = (*Logger)(nil)
+
+// Logger is an arrakis Logger writing to zap. Key/value arguments become zap fields.
+type Logger struct {
+	sugar *zap.SugaredLogger
+}
+
+// New wraps a zap logger.
+//
+// Parameters:
+//   - logger: The zap logger receiving the records
+//
+// Returns:
+//   - *Logger: A logger to pass to sqs.WithLogger
+//
+// Example:
+//
+//	client := sqs.NewSQSWithOptions(&cfg, sqs.WithLogger(zaplogger.New(zapLogger.Named("arrakis"))))
+func New(logger *zap.Logger) *Logger {
+	return &Logger{sugar: logger.Sugar()}
+}
+
+// Debug logs at debug level.
+func (l *Logger) Debug(msg string, keysAndValues ...any) {
+	l.sugar.Debugw(msg, keysAndValues...)
+}
+
+// Info logs at info level.
+func (l *Logger) Info(msg string, keysAndValues ...any) {
+	l.sugar.Infow(msg, keysAndValues...)
+}
+
+// Warn logs at warn level.
+func (l *Logger) Warn(msg string, keysAndValues ...any) {
+	l.sugar.Warnw(msg, keysAndValues...)
+}
+
+// Error logs at error level.
+func (l *Logger) Error(msg string, keysAndValues ...any) {
+	l.sugar.Errorw(msg, keysAndValues...)
+}
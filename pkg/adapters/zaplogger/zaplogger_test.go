@@ -0,0 +1,31 @@
+package zaplogger
+
+import (
+	"errors"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLoggerWritesFields(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger := New(zap.New(core))
+
+	logger.Debug("volume class changed", "queue", "orders", "to", "high")
+	logger.Warn("receive failed", "queue", "orders", "error", errors.New("unavailable"))
+
+	entries := logs.All()
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+
+	if entries[0].Level != zapcore.DebugLevel || entries[0].ContextMap()["to"] != "high" {
+		t.Errorf("Expected a debug entry with fields, got %+v", entries[0])
+	}
+
+	if entries[1].Level != zapcore.WarnLevel || entries[1].Message != "receive failed" {
+		t.Errorf("Expected a warn entry, got %+v", entries[1])
+	}
+}
@@ -0,0 +1,305 @@
+// Package admin provides an optional HTTP server for the operational control of
+// long-running workers: it exposes the Arrakis state of a client and lets operators
+// enable or disable adaptive polling per queue, pause and resume consumers and adjust
+// wait times without restarting the process.
+package admin
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/elissonalvesilva/arrakis/pkg/sqs"
+)
+
+// Server configuration
+const (
+	_shutdownTimeout   = 5 * time.Second // Time given to in-flight requests when the server stops
+	_readHeaderTimeout = 5 * time.Second // Bound on reading request headers
+	_bearerPrefix      = "Bearer "       // Prefix of the Authorization header value
+	_queueParameter    = "queue"         // Query parameter holding the queue URL
+)
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithToken protects the server with a bearer token: every request must carry an
+// "Authorization: Bearer <token>" header. Without a token the server is read-only and
+// every mutating endpoint answers 403 Forbidden.
+//
+// Parameters:
+//   - token: The shared secret expected from operators
+//
+// Example:
+//
+//	server := admin.NewServer(sqsClient, admin.WithToken(os.Getenv("ARRAKIS_ADMIN_TOKEN")))
+func WithToken(token string) Option {
+	return func(s *Server) {
+		s.token = token
+	}
+}
+
+// WithConsumer registers a consumer under name, so it can be paused, resumed and woken
+// through the /consumers endpoints.
+//
+// Parameters:
+//   - name: Name identifying the consumer in the endpoint paths (e.g., "orders")
+//   - consumer: The consumer to control
+func WithConsumer(name string, consumer *sqs.Consumer) Option {
+	return func(s *Server) {
+		s.consumers[name] = consumer
+	}
+}
+
+// Server is an http.Handler serving the admin endpoints of an SQS client:
+//
+//	GET  /state                        Full adaptive polling state (see sqs.SQS.DumpState)
+//	GET  /config                       Running configuration (see sqs.SQS.Config)
+//	POST /config                       Apply a partial sqs.FileConfig (see sqs.SQS.UpdateConfig)
+//	POST /queues/enable?queue=<url>    Enable adaptive polling for a queue
+//	POST /queues/disable?queue=<url>   Disable adaptive polling for a queue
+//	POST /queues/reset?queue=<url>     Make a queue follow the client-wide setting again
+//	GET  /consumers                    Registered consumers and whether they are paused
+//	POST /consumers/{name}/pause       Stop a consumer from polling
+//	POST /consumers/{name}/resume      Let a paused consumer poll again
+//	POST /consumers/{name}/wake        End the idle backoff of a consumer (see sqs.Consumer.Wake)
+type Server struct {
+	client    *sqs.SQS
+	token     string
+	consumers map[string]*sqs.Consumer
+	mux       *http.ServeMux
+}
+
+// consumerStatus describes a registered consumer in the /consumers response.
+type consumerStatus struct {
+	Name     string `json:"name"`
+	QueueURL string `json:"queue_url"`
+	Paused   bool   `json:"paused"`
+	Workers  int    `json:"workers"`
+}
+
+// queueStatus is the response of the /queues endpoints.
+type queueStatus struct {
+	QueueURL       string `json:"queue_url"`
+	ArrakisEnabled bool   `json:"arrakis_enabled"`
+}
+
+// NewServer creates the admin server of a client. Serve it with ListenAndServe or mount
+// it on an existing http.ServeMux.
+//
+// Parameters:
+//   - client: The SQS client to inspect and control
+//   - options: Optional settings such as WithToken and WithConsumer
+//
+// Returns:
+//   - *Server: The admin server
+//
+// Example:
+//
+//	server := admin.NewServer(sqsClient,
+//	    admin.WithToken(os.Getenv("ARRAKIS_ADMIN_TOKEN")),
+//	    admin.WithConsumer("orders", ordersConsumer),
+//	)
+//	go server.ListenAndServe(ctx, ":9090")
+func NewServer(client *sqs.SQS, options ...Option) *Server {
+	s := &Server{
+		client:    client,
+		consumers: map[string]*sqs.Consumer{},
+		mux:       http.NewServeMux(),
+	}
+
+	for _, opt := range options {
+		opt(s)
+	}
+
+	s.mux.HandleFunc("GET /state", s.state)
+	s.mux.HandleFunc("GET /config", s.config)
+	s.mux.HandleFunc("POST /config", s.mutating(s.updateConfig))
+	s.mux.HandleFunc("POST /queues/{action}", s.mutating(s.queue))
+	s.mux.HandleFunc("GET /consumers", s.listConsumers)
+	s.mux.HandleFunc("POST /consumers/{name}/{action}", s.mutating(s.consumer))
+
+	return s
+}
+
+// ServeHTTP authenticates the request and dispatches it to its endpoint.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.token != "" && !s.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="arrakis"`)
+		writeError(w, http.StatusUnauthorized, "missing or invalid token")
+		return
+	}
+
+	s.mux.ServeHTTP(w, r)
+}
+
+// ListenAndServe serves the admin endpoints on addr until ctx is cancelled, then shuts
+// the server down gracefully.
+//
+// Parameters:
+//   - ctx: Context that stops the server when cancelled
+//   - addr: TCP address to listen on (e.g., ":9090" or "127.0.0.1:9090")
+//
+// Returns:
+//   - error: Any error starting the server; nil once stopped through ctx
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	server := &http.Server{Addr: addr, Handler: s, ReadHeaderTimeout: _readHeaderTimeout}
+
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), _shutdownTimeout)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	err := server.ListenAndServe()
+	if errors.Is(err, http.ErrServerClosed) {
+		<-stopped
+		return nil
+	}
+
+	return err
+}
+
+// authorized reports whether the request carries the configured token.
+func (s *Server) authorized(r *http.Request) bool {
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, _bearerPrefix) {
+		return false
+	}
+
+	token := strings.TrimPrefix(header, _bearerPrefix)
+	return subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) == 1
+}
+
+// mutating rejects requests to a mutating endpoint when the server has no token.
+func (s *Server) mutating(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.token == "" {
+			writeError(w, http.StatusForbidden, "mutating endpoints require a token (see WithToken)")
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// state serves the state dump of the client.
+func (s *Server) state(w http.ResponseWriter, r *http.Request) {
+	dump, err := s.client.DumpState()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(dump)
+}
+
+// config serves the running configuration.
+func (s *Server) config(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.client.Config())
+}
+
+// updateConfig applies the settings of the request body and serves the new configuration.
+func (s *Server) updateConfig(w http.ResponseWriter, r *http.Request) {
+	var update sqs.FileConfig
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid configuration: "+err.Error())
+		return
+	}
+
+	s.client.UpdateConfig(update)
+	writeJSON(w, http.StatusOK, s.client.Config())
+}
+
+// queue enables, disables or resets adaptive polling for the queue of the request.
+func (s *Server) queue(w http.ResponseWriter, r *http.Request) {
+	queueURL := r.URL.Query().Get(_queueParameter)
+	if queueURL == "" {
+		writeError(w, http.StatusBadRequest, "missing queue parameter")
+		return
+	}
+
+	switch r.PathValue("action") {
+	case "enable":
+		s.client.EnableArrakisFor(queueURL)
+	case "disable":
+		s.client.DisableArrakisFor(queueURL)
+	case "reset":
+		s.client.ResetArrakisFor(queueURL)
+	default:
+		writeError(w, http.StatusNotFound, "unknown action "+r.PathValue("action"))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, queueStatus{QueueURL: queueURL, ArrakisEnabled: s.client.IsArrakisEnabledFor(queueURL)})
+}
+
+// listConsumers serves the status of the registered consumers, sorted by name.
+func (s *Server) listConsumers(w http.ResponseWriter, r *http.Request) {
+	statuses := make([]consumerStatus, 0, len(s.consumers))
+	for name, consumer := range s.consumers {
+		statuses = append(statuses, status(name, consumer))
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+
+	writeJSON(w, http.StatusOK, statuses)
+}
+
+// consumer pauses, resumes or wakes the consumer of the request.
+func (s *Server) consumer(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	consumer, ok := s.consumers[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, "unknown consumer "+name)
+		return
+	}
+
+	switch r.PathValue("action") {
+	case "pause":
+		consumer.Pause()
+	case "resume":
+		consumer.Resume()
+	case "wake":
+		consumer.Wake()
+	default:
+		writeError(w, http.StatusNotFound, "unknown action "+r.PathValue("action"))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, status(name, consumer))
+}
+
+// status describes a registered consumer.
+func status(name string, consumer *sqs.Consumer) consumerStatus {
+	return consumerStatus{
+		Name:     name,
+		QueueURL: consumer.ActiveQueueURL(),
+		Paused:   consumer.Paused(),
+		Workers:  consumer.Workers(),
+	}
+}
+
+// writeJSON writes value as the JSON body of the response.
+func writeJSON(w http.ResponseWriter, code int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+// writeError writes an error response.
+func writeError(w http.ResponseWriter, code int, message string) {
+	writeJSON(w, code, map[string]string{"error": message})
+}
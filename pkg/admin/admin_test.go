@@ -0,0 +1,121 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/elissonalvesilva/arrakis/pkg/arrakistest"
+	"github.com/elissonalvesilva/arrakis/pkg/sqs"
+)
+
+// do sends a request to the server and returns the recorded response.
+func do(server *Server, method, target, token, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, target, strings.NewReader(body))
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, request)
+
+	return recorder
+}
+
+func TestServerAuthentication(t *testing.T) {
+	backend := arrakistest.NewServer()
+	defer backend.Close()
+
+	server := NewServer(backend.Client(), WithToken("secret"))
+
+	if code := do(server, http.MethodGet, "/config", "", "").Code; code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without a token, got %d", code)
+	}
+	if code := do(server, http.MethodGet, "/config", "wrong", "").Code; code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 with a wrong token, got %d", code)
+	}
+	if code := do(server, http.MethodGet, "/state", "secret", "").Code; code != http.StatusOK {
+		t.Errorf("Expected 200 with the token, got %d", code)
+	}
+
+	readOnly := NewServer(backend.Client())
+	if code := do(readOnly, http.MethodGet, "/config", "", "").Code; code != http.StatusOK {
+		t.Errorf("Expected reads without a configured token, got %d", code)
+	}
+	if code := do(readOnly, http.MethodPost, "/config", "", "{}").Code; code != http.StatusForbidden {
+		t.Errorf("Expected 403 for mutations without a configured token, got %d", code)
+	}
+}
+
+func TestServerQueuesAndConfig(t *testing.T) {
+	backend := arrakistest.NewServer()
+	defer backend.Close()
+
+	client := backend.Client()
+	server := NewServer(client, WithToken("secret"))
+	queueURL := backend.QueueURL("orders")
+
+	response := do(server, http.MethodPost, "/queues/enable?queue="+url.QueryEscape(queueURL), "secret", "")
+	if response.Code != http.StatusOK || !client.IsArrakisEnabledFor(queueURL) {
+		t.Fatalf("Expected adaptive polling enabled for the queue, got %d: %s", response.Code, response.Body)
+	}
+
+	do(server, http.MethodPost, "/queues/disable?queue="+url.QueryEscape(queueURL), "secret", "")
+	if client.IsArrakisEnabledFor(queueURL) {
+		t.Error("Expected adaptive polling disabled for the queue")
+	}
+
+	if code := do(server, http.MethodPost, "/queues/enable", "secret", "").Code; code != http.StatusBadRequest {
+		t.Errorf("Expected 400 without a queue, got %d", code)
+	}
+
+	response = do(server, http.MethodPost, "/config", "secret", `{"idle_wait_time_seconds": 15}`)
+	var current sqs.FileConfig
+	if err := json.Unmarshal(response.Body.Bytes(), &current); err != nil {
+		t.Fatalf("Expected a configuration in the response, got %s", response.Body)
+	}
+	if current.IdleWaitTimeSeconds != 15 || client.Config().IdleWaitTimeSeconds != 15 {
+		t.Errorf("Expected the idle wait time to be updated, got %+v", current)
+	}
+
+	if code := do(server, http.MethodPost, "/config", "secret", "{").Code; code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid body, got %d", code)
+	}
+}
+
+func TestServerConsumers(t *testing.T) {
+	backend := arrakistest.NewServer()
+	defer backend.Close()
+
+	consumer := sqs.NewConsumer(backend.Client(), backend.QueueURL("orders"), sqs.HandlerFunc(func(ctx context.Context, msg sqs.Message) error {
+		return nil
+	}))
+	server := NewServer(backend.Client(), WithToken("secret"), WithConsumer("orders", consumer))
+
+	if code := do(server, http.MethodPost, "/consumers/orders/pause", "secret", "").Code; code != http.StatusOK || !consumer.Paused() {
+		t.Fatalf("Expected the consumer to be paused, got %d", code)
+	}
+
+	response := do(server, http.MethodGet, "/consumers", "secret", "")
+	var statuses []consumerStatus
+	if err := json.Unmarshal(response.Body.Bytes(), &statuses); err != nil || len(statuses) != 1 || !statuses[0].Paused {
+		t.Errorf("Expected the paused consumer to be listed, got %s", response.Body)
+	}
+
+	do(server, http.MethodPost, "/consumers/orders/resume", "secret", "")
+	if consumer.Paused() {
+		t.Error("Expected the consumer to be resumed")
+	}
+
+	if code := do(server, http.MethodPost, "/consumers/orders/wake", "secret", "").Code; code != http.StatusOK {
+		t.Errorf("Expected the consumer to be woken, got %d", code)
+	}
+
+	if code := do(server, http.MethodPost, "/consumers/unknown/pause", "secret", "").Code; code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown consumer, got %d", code)
+	}
+}
@@ -0,0 +1,226 @@
+package arrakistest
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/elissonalvesilva/arrakis/pkg/sqs"
+)
+
+// Decision recorder configuration
+const (
+	_recorderQueue      = "decisions" // Queue polled by a DecisionRecorder
+	_maxMessagesPerPoll = 10          // SQS limit of messages returned by a receive
+)
+
+// Step is one step of a polling scenario replayed by DecisionRecorder.Run.
+type Step struct {
+	// Polls is the number of receives, each returning Messages messages.
+	Polls int
+	// Messages is the number of messages returned by each receive (at most 10).
+	Messages int
+	// Advance moves the fake clock forward before the receives.
+	Advance time.Duration
+}
+
+// EmptyPolls is a step of n receives returning no message.
+func EmptyPolls(n int) Step {
+	return Step{Polls: n}
+}
+
+// Poll is a step of one receive returning messages messages (at most 10).
+func Poll(messages int) Step {
+	return Step{Polls: 1, Messages: messages}
+}
+
+// Polls is a step of n receives returning messages messages each (at most 10).
+func Polls(n, messages int) Step {
+	return Step{Polls: n, Messages: messages}
+}
+
+// Idle is a step moving the fake clock forward by d without polling, e.g. to let the EWMA
+// average decay.
+func Idle(d time.Duration) Step {
+	return Step{Advance: d}
+}
+
+// DecisionRecorder drives the adaptive polling algorithm of a real client through polling
+// scenarios and records the decisions it takes, for table-driven tests of the wait times a
+// configuration produces. The client polls an in-memory server that never long polls and
+// the algorithm runs on a fake clock, which every receive moves forward by the wait time
+// it used, so scenarios spanning minutes run instantly.
+type DecisionRecorder struct {
+	t        testing.TB
+	server   *Server
+	client   *sqs.SQS
+	queueURL string
+
+	mu        sync.Mutex
+	now       time.Time
+	decisions []sqs.Decision
+}
+
+// NewDecisionRecorder creates a recorder on a new in-memory server, closed when the test
+// ends. Adaptive polling is enabled.
+//
+// Parameters:
+//   - t: The test, failed on unexpected client errors
+//   - options: Client options, typically the configuration under test
+//
+// Returns:
+//   - *DecisionRecorder: A recorder without decisions
+//
+// Example:
+//
+//	tests := []struct {
+//	    name     string
+//	    steps    []arrakistest.Step
+//	    expected int64
+//	}{
+//	    {"idle", []arrakistest.Step{arrakistest.EmptyPolls(5)}, 20},
+//	    {"burst after idle", []arrakistest.Step{arrakistest.EmptyPolls(5), arrakistest.Poll(10)}, 10},
+//	}
+//
+//	for _, tt := range tests {
+//	    t.Run(tt.name, func(t *testing.T) {
+//	        recorder := arrakistest.NewDecisionRecorder(t, sqs.WithIdleWaitTimeSeconds(20))
+//	        recorder.Run(tt.steps...)
+//	        if got := recorder.NextWaitTime(); got != tt.expected {
+//	            t.Errorf("Expected wait time %d, got %d", tt.expected, got)
+//	        }
+//	    })
+//	}
+func NewDecisionRecorder(t testing.TB, options ...sqs.Option) *DecisionRecorder {
+	t.Helper()
+
+	r := &DecisionRecorder{
+		t:      t,
+		server: NewServer(WithMaxWaitTime(0)),
+		now:    time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+	}
+	t.Cleanup(r.server.Close)
+
+	r.client = r.server.Client(append(options, sqs.WithClock(r.clock))...)
+	r.client.EnableArrakis()
+	r.queueURL = r.server.QueueURL(_recorderQueue)
+
+	return r
+}
+
+// Client returns the client driven by the recorder, to inspect its state.
+func (r *DecisionRecorder) Client() *sqs.SQS {
+	return r.client
+}
+
+// QueueURL returns the URL of the queue polled by the recorder.
+func (r *DecisionRecorder) QueueURL() string {
+	return r.queueURL
+}
+
+// Now returns the current time of the fake clock.
+func (r *DecisionRecorder) Now() time.Time {
+	return r.clock()
+}
+
+// Advance moves the fake clock forward by d.
+func (r *DecisionRecorder) Advance(d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.now = r.now.Add(d)
+}
+
+// Run replays the steps of a scenario in order.
+//
+// Returns:
+//   - sqs.Decision: The last decision recorded, zero if no step polled
+func (r *DecisionRecorder) Run(steps ...Step) sqs.Decision {
+	r.t.Helper()
+
+	for _, step := range steps {
+		r.Advance(step.Advance)
+
+		for range step.Polls {
+			r.poll(step.Messages)
+		}
+	}
+
+	return r.Last()
+}
+
+// Decisions returns every decision recorded, oldest first.
+func (r *DecisionRecorder) Decisions() []sqs.Decision {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]sqs.Decision(nil), r.decisions...)
+}
+
+// Last returns the latest decision recorded, zero if none was.
+func (r *DecisionRecorder) Last() sqs.Decision {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.decisions) == 0 {
+		return sqs.Decision{}
+	}
+
+	return r.decisions[len(r.decisions)-1]
+}
+
+// NextWaitTime returns the wait time, in seconds, the next receive would use after the
+// steps replayed so far (see sqs.SQS.NextWaitTime).
+func (r *DecisionRecorder) NextWaitTime() int64 {
+	return r.client.NextWaitTime(r.queueURL)
+}
+
+// poll sends messages messages to the queue, receives them and moves the fake clock
+// forward by the wait time of the receive.
+func (r *DecisionRecorder) poll(messages int) {
+	r.t.Helper()
+
+	ctx := context.Background()
+	messages = min(messages, _maxMessagesPerPoll)
+
+	for range messages {
+		if _, err := r.client.SendMessage(ctx, r.queueURL, "message"); err != nil {
+			r.t.Fatalf("arrakistest: sending message: %v", err)
+		}
+	}
+
+	issuedAt := r.clock()
+	output, err := r.client.ReceiveMessage(ctx, r.queueURL, _maxMessagesPerPoll, nil)
+	if err != nil {
+		r.t.Fatalf("arrakistest: receiving messages: %v", err)
+	}
+
+	for _, msg := range output.Messages {
+		if _, err := r.client.DeleteMessage(ctx, r.queueURL, *msg.ReceiptHandle); err != nil {
+			r.t.Fatalf("arrakistest: deleting message: %v", err)
+		}
+	}
+
+	decision := sqs.Decision{
+		Time:            issuedAt,
+		WaitTimeSeconds: r.client.LastWaitTime(r.queueURL),
+		Class:           r.client.CurrentVolumeClass(r.queueURL),
+		Average:         r.client.CurrentAverage(r.queueURL),
+		Messages:        len(output.Messages),
+	}
+
+	r.mu.Lock()
+	r.decisions = append(r.decisions, decision)
+	r.mu.Unlock()
+
+	r.Advance(time.Duration(decision.WaitTimeSeconds) * time.Second)
+}
+
+// clock returns the current time of the fake clock.
+func (r *DecisionRecorder) clock() time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.now
+}
@@ -0,0 +1,46 @@
+package arrakistest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/elissonalvesilva/arrakis/pkg/sqs"
+)
+
+func TestDecisionRecorderScenarios(t *testing.T) {
+	tests := []struct {
+		name     string
+		steps    []Step
+		expected int64
+	}{
+		{"idle", []Step{EmptyPolls(5)}, 20},
+		{"burst after idle", []Step{EmptyPolls(5), Poll(10)}, 10},
+		{"steady traffic", []Step{Polls(10, 10)}, 5},
+		{"decay after traffic", []Step{Polls(10, 10), EmptyPolls(3), Idle(10 * time.Minute), EmptyPolls(3)}, 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := NewDecisionRecorder(t, sqs.WithIdleWaitTimeSeconds(20))
+			recorder.Run(tt.steps...)
+
+			if got := recorder.NextWaitTime(); got != tt.expected {
+				t.Errorf("Expected wait time %d, got %d", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestDecisionRecorderClock(t *testing.T) {
+	recorder := NewDecisionRecorder(t, sqs.WithIdleWaitTimeSeconds(20))
+	start := recorder.Now()
+
+	last := recorder.Run(EmptyPolls(2), Poll(3))
+
+	if decisions := recorder.Decisions(); len(decisions) != 3 || decisions[1].Time != start.Add(20*time.Second) {
+		t.Fatalf("Expected 3 decisions, each receive advancing the clock by its wait time, got %+v", decisions)
+	}
+	if last.Messages != 3 || recorder.Now() != start.Add(60*time.Second) {
+		t.Errorf("Expected the last decision to receive 3 messages and the clock at +60s, got %+v at %s", last, recorder.Now().Sub(start))
+	}
+}
@@ -0,0 +1,548 @@
+// Package arrakistest provides utilities to test code built on Arrakis without AWS or
+// LocalStack: an in-memory SQS server speaking the SQS JSON protocol, and a
+// DecisionRecorder asserting the wait times adaptive polling chooses in given scenarios.
+package arrakistest
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+
+	"github.com/elissonalvesilva/arrakis/pkg/sqs"
+)
+
+// Server configuration
+const (
+	_accountID                = "000000000000"        // Account ID of the queue URLs
+	_defaultVisibilityTimeout = 30                    // Visibility timeout (seconds) when the request sets none
+	_maxWaitTimeSeconds       = 20                    // SQS long polling limit
+	_pollRecheck              = 10 * time.Millisecond // Interval between two checks of a long poll
+	_errorTypePrefix          = "com.amazonaws.sqs#"  // Prefix of the JSON protocol error types
+)
+
+// ServerOption configures a Server.
+type ServerOption func(*Server)
+
+// WithMaxWaitTime caps how long a ReceiveMessage call waits for messages, whatever its
+// WaitTimeSeconds. Tests use it to keep idle long polls short.
+//
+// Parameters:
+//   - maxWait: Longest duration of a long poll
+//
+// Example:
+//
+//	server := arrakistest.NewServer(arrakistest.WithMaxWaitTime(50 * time.Millisecond))
+func WithMaxWaitTime(maxWait time.Duration) ServerOption {
+	return func(s *Server) {
+		s.maxWait = maxWait
+	}
+}
+
+// Server is an in-memory SQS server for tests. Queues are created on first use; standard
+// queues deliver in arrival order and FIFO queues (names ending in ".fifo") hold back a
+// message group while one of its messages is in flight. Visibility timeouts, delays and
+// long polling behave as in SQS; deduplication, DLQ redrive and retention are not
+// simulated.
+type Server struct {
+	// URL is the endpoint of the server, to be used with sqs.WithEndpoint.
+	URL string
+
+	http    *httptest.Server
+	maxWait time.Duration
+
+	mu      sync.Mutex
+	queues  map[string]*queue
+	calls   map[string]int
+	nextID  int
+	arrived chan struct{} // Closed and replaced whenever messages are sent
+}
+
+// queue holds the messages of a queue, in arrival order.
+type queue struct {
+	fifo     bool
+	messages []*message
+}
+
+// message is a message stored by the server.
+type message struct {
+	ID               string
+	Body             string
+	Attributes       map[string]messageAttribute
+	GroupID          string
+	SentAt           time.Time
+	VisibleAt        time.Time
+	FirstReceivedAt  time.Time
+	ReceiveCount     int
+	ReceiptHandle    string
+	VisibilityExpiry time.Time
+}
+
+// messageAttribute is a message attribute in the SQS JSON protocol.
+type messageAttribute struct {
+	DataType    string `json:"DataType"`
+	StringValue string `json:"StringValue,omitempty"`
+	BinaryValue []byte `json:"BinaryValue,omitempty"`
+}
+
+// NewServer starts an in-memory SQS server. It must be closed with Close.
+//
+// Parameters:
+//   - options: Optional settings such as WithMaxWaitTime
+//
+// Returns:
+//   - *Server: The running server
+//
+// Example:
+//
+//	server := arrakistest.NewServer()
+//	defer server.Close()
+//	client := server.Client()
+//	client.SendMessage(ctx, server.QueueURL("orders"), "hello")
+func NewServer(options ...ServerOption) *Server {
+	s := &Server{
+		maxWait: _maxWaitTimeSeconds * time.Second,
+		queues:  map[string]*queue{},
+		calls:   map[string]int{},
+		arrived: make(chan struct{}),
+	}
+
+	for _, opt := range options {
+		opt(s)
+	}
+
+	s.http = httptest.NewServer(s)
+	s.URL = s.http.URL
+
+	return s
+}
+
+// Close shuts the server down.
+func (s *Server) Close() {
+	s.http.Close()
+}
+
+// QueueURL returns the URL of a queue of the server. The queue is created on first use.
+//
+// Parameters:
+//   - name: The queue name, ending in ".fifo" for a FIFO queue
+//
+// Returns:
+//   - string: The queue URL
+func (s *Server) QueueURL(name string) string {
+	return s.URL + "/" + _accountID + "/" + name
+}
+
+// Client returns an Arrakis client connected to the server with static credentials.
+//
+// Parameters:
+//   - options: Additional client options
+//
+// Returns:
+//   - *sqs.SQS: The client
+func (s *Server) Client(options ...sqs.Option) *sqs.SQS {
+	return sqs.NewSQSWithOptions(&aws.Config{
+		Region:      "us-east-1",
+		Credentials: credentials.NewStaticCredentialsProvider("test", "test", ""),
+	}, append([]sqs.Option{sqs.WithEndpoint(s.URL)}, options...)...)
+}
+
+// Calls returns how many times an SQS action was called, such as "ReceiveMessage".
+func (s *Server) Calls(action string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.calls[action]
+}
+
+// Depth returns the number of messages of a queue that are visible and in flight.
+//
+// Parameters:
+//   - queueURL: The URL of the queue
+//
+// Returns:
+//   - visible: Messages available for receiving, including delayed ones
+//   - inFlight: Messages received and not deleted whose visibility timeout hasn't expired
+func (s *Server) Depth(queueURL string) (visible, inFlight int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for _, m := range s.queue(queueURL).messages {
+		if m.VisibilityExpiry.After(now) {
+			inFlight++
+		} else {
+			visible++
+		}
+	}
+
+	return visible, inFlight
+}
+
+// ServeHTTP implements http.Handler for the SQS JSON protocol.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	action := strings.TrimPrefix(r.Header.Get("X-Amz-Target"), "AmazonSQS.")
+
+	var input request
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, "InvalidParameterValue", err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	s.calls[action]++
+	s.mu.Unlock()
+
+	var output any
+	switch action {
+	case "SendMessage":
+		output = s.sendMessage(input.sendEntry())
+	case "SendMessageBatch":
+		output = s.sendMessageBatch(input)
+	case "ReceiveMessage":
+		output = s.receiveMessage(r.Context(), input)
+	case "DeleteMessage":
+		if !s.deleteMessage(input.QueueURL, input.ReceiptHandle) {
+			writeError(w, "ReceiptHandleIsInvalid", "unknown receipt handle")
+			return
+		}
+		output = struct{}{}
+	case "ChangeMessageVisibility":
+		if !s.changeVisibility(input.QueueURL, input.ReceiptHandle, input.VisibilityTimeout) {
+			writeError(w, "MessageNotInflight", "message not in flight")
+			return
+		}
+		output = struct{}{}
+	case "GetQueueAttributes":
+		output = s.queueAttributes(input.QueueURL)
+	case "GetQueueUrl":
+		output = map[string]string{"QueueUrl": s.QueueURL(input.QueueName)}
+	default:
+		writeError(w, "UnsupportedOperation", "arrakistest: unsupported action "+action)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+	_ = json.NewEncoder(w).Encode(output)
+}
+
+// request holds the fields of every supported action.
+type request struct {
+	QueueURL            string `json:"QueueUrl"`
+	QueueName           string
+	Entries             []entry
+	MaxNumberOfMessages int
+	WaitTimeSeconds     *int
+	VisibilityTimeout   *int
+	ReceiptHandle       string
+	entry
+}
+
+// entry is a message to send.
+type entry struct {
+	ID                string `json:"Id"`
+	QueueURL          string `json:"QueueUrl"`
+	MessageBody       string
+	DelaySeconds      int
+	MessageAttributes map[string]messageAttribute
+	MessageGroupID    string `json:"MessageGroupId"`
+}
+
+// sendEntry returns the message of a SendMessage request.
+func (r request) sendEntry() entry {
+	e := r.entry
+	e.QueueURL = r.QueueURL
+	return e
+}
+
+// sendMessage stores a message and returns the SendMessage response.
+func (s *Server) sendMessage(e entry) map[string]any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m := s.store(e)
+
+	return map[string]any{
+		"MessageId":              m.ID,
+		"MD5OfMessageBody":       md5Hex([]byte(m.Body)),
+		"MD5OfMessageAttributes": attributesMD5(m.Attributes),
+	}
+}
+
+// sendMessageBatch stores the messages of a batch and returns the SendMessageBatch response.
+func (s *Server) sendMessageBatch(r request) map[string]any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	successful := []map[string]any{}
+	for _, e := range r.Entries {
+		e.QueueURL = r.QueueURL
+		m := s.store(e)
+
+		successful = append(successful, map[string]any{
+			"Id":                     e.ID,
+			"MessageId":              m.ID,
+			"MD5OfMessageBody":       md5Hex([]byte(m.Body)),
+			"MD5OfMessageAttributes": attributesMD5(m.Attributes),
+		})
+	}
+
+	return map[string]any{"Successful": successful, "Failed": []any{}}
+}
+
+// store appends a message to its queue and wakes long polls up. Must be called with the
+// mutex held.
+func (s *Server) store(e entry) *message {
+	s.nextID++
+	now := time.Now()
+
+	m := &message{
+		ID:         "message-" + strconv.Itoa(s.nextID),
+		Body:       e.MessageBody,
+		Attributes: e.MessageAttributes,
+		GroupID:    e.MessageGroupID,
+		SentAt:     now,
+		VisibleAt:  now.Add(time.Duration(e.DelaySeconds) * time.Second),
+	}
+
+	q := s.queue(e.QueueURL)
+	q.messages = append(q.messages, m)
+
+	close(s.arrived)
+	s.arrived = make(chan struct{})
+
+	return m
+}
+
+// receiveMessage returns the visible messages of a queue, long polling until at least one
+// is available or the wait time elapses.
+func (s *Server) receiveMessage(ctx context.Context, r request) map[string]any {
+	wait := 0
+	if r.WaitTimeSeconds != nil {
+		wait = min(*r.WaitTimeSeconds, _maxWaitTimeSeconds)
+	}
+	deadline := time.Now().Add(min(time.Duration(wait)*time.Second, s.maxWait))
+
+	visibility := _defaultVisibilityTimeout
+	if r.VisibilityTimeout != nil {
+		visibility = *r.VisibilityTimeout
+	}
+
+	for {
+		s.mu.Lock()
+		received := s.take(r.QueueURL, max(min(r.MaxNumberOfMessages, 10), 1), time.Duration(visibility)*time.Second)
+		arrived := s.arrived
+		s.mu.Unlock()
+
+		remaining := time.Until(deadline)
+		if len(received) > 0 || remaining <= 0 {
+			return map[string]any{"Messages": received}
+		}
+
+		timer := time.NewTimer(min(remaining, _pollRecheck))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return map[string]any{"Messages": received}
+		case <-arrived:
+		case <-timer.C:
+		}
+		timer.Stop()
+	}
+}
+
+// take marks up to limit visible messages of a queue as in flight and returns them in the
+// JSON protocol format. Must be called with the mutex held.
+func (s *Server) take(queueURL string, limit int, visibility time.Duration) []map[string]any {
+	q := s.queue(queueURL)
+	now := time.Now()
+
+	busyGroups := map[string]bool{}
+	if q.fifo {
+		for _, m := range q.messages {
+			if m.VisibilityExpiry.After(now) {
+				busyGroups[m.GroupID] = true
+			}
+		}
+	}
+
+	var received []map[string]any
+	for _, m := range q.messages {
+		if len(received) == limit {
+			break
+		}
+		if m.VisibleAt.After(now) || m.VisibilityExpiry.After(now) || busyGroups[m.GroupID] {
+			continue
+		}
+
+		s.nextID++
+		m.ReceiveCount++
+		m.ReceiptHandle = "handle-" + strconv.Itoa(s.nextID)
+		m.VisibilityExpiry = now.Add(visibility)
+		if m.FirstReceivedAt.IsZero() {
+			m.FirstReceivedAt = now
+		}
+
+		received = append(received, m.response())
+	}
+
+	return received
+}
+
+// response returns the message in the ReceiveMessage format.
+func (m *message) response() map[string]any {
+	attributes := map[string]string{
+		"SentTimestamp":                    strconv.FormatInt(m.SentAt.UnixMilli(), 10),
+		"ApproximateFirstReceiveTimestamp": strconv.FormatInt(m.FirstReceivedAt.UnixMilli(), 10),
+		"ApproximateReceiveCount":          strconv.Itoa(m.ReceiveCount),
+	}
+	if m.GroupID != "" {
+		attributes["MessageGroupId"] = m.GroupID
+	}
+
+	response := map[string]any{
+		"MessageId":     m.ID,
+		"ReceiptHandle": m.ReceiptHandle,
+		"Body":          m.Body,
+		"MD5OfBody":     md5Hex([]byte(m.Body)),
+		"Attributes":    attributes,
+	}
+	if len(m.Attributes) > 0 {
+		response["MessageAttributes"] = m.Attributes
+		response["MD5OfMessageAttributes"] = attributesMD5(m.Attributes)
+	}
+
+	return response
+}
+
+// deleteMessage removes the message with a receipt handle, reporting whether it exists.
+func (s *Server) deleteMessage(queueURL, receiptHandle string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q := s.queue(queueURL)
+	for i, m := range q.messages {
+		if m.ReceiptHandle == receiptHandle {
+			q.messages = append(q.messages[:i], q.messages[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+// changeVisibility sets the visibility timeout of an in-flight message, reporting whether
+// the message is in flight.
+func (s *Server) changeVisibility(queueURL, receiptHandle string, timeout *int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for _, m := range s.queue(queueURL).messages {
+		if m.ReceiptHandle == receiptHandle && m.VisibilityExpiry.After(now) {
+			seconds := 0
+			if timeout != nil {
+				seconds = *timeout
+			}
+			m.VisibilityExpiry = now.Add(time.Duration(seconds) * time.Second)
+			return true
+		}
+	}
+
+	return false
+}
+
+// queueAttributes returns the GetQueueAttributes response with the queue depth.
+func (s *Server) queueAttributes(queueURL string) map[string]any {
+	visible, inFlight := s.Depth(queueURL)
+
+	s.mu.Lock()
+	fifo := s.queue(queueURL).fifo
+	s.mu.Unlock()
+
+	attributes := map[string]string{
+		"ApproximateNumberOfMessages":           strconv.Itoa(visible),
+		"ApproximateNumberOfMessagesNotVisible": strconv.Itoa(inFlight),
+		"VisibilityTimeout":                     strconv.Itoa(_defaultVisibilityTimeout),
+	}
+	if fifo {
+		attributes["FifoQueue"] = "true"
+	}
+
+	return map[string]any{"Attributes": attributes}
+}
+
+// queue returns the queue of a URL, creating it on first use. Must be called with the
+// mutex held.
+func (s *Server) queue(queueURL string) *queue {
+	name := path.Base(queueURL)
+
+	q, ok := s.queues[name]
+	if !ok {
+		q = &queue{fifo: strings.HasSuffix(name, ".fifo")}
+		s.queues[name] = q
+	}
+
+	return q
+}
+
+// writeError writes an error response of the SQS JSON protocol.
+func writeError(w http.ResponseWriter, code, message string) {
+	w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+	w.WriteHeader(http.StatusBadRequest)
+	_ = json.NewEncoder(w).Encode(map[string]string{"__type": _errorTypePrefix + code, "message": message})
+}
+
+// md5Hex returns the hexadecimal MD5 digest of data.
+func md5Hex(data []byte) string {
+	sum := md5.Sum(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// attributesMD5 returns the MD5 digest of message attributes as computed by SQS: the
+// attributes sorted by name, each encoded as length-prefixed name, data type and value.
+func attributesMD5(attributes map[string]messageAttribute) string {
+	if len(attributes) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buffer []byte
+	appendValue := func(value []byte) {
+		buffer = binary.BigEndian.AppendUint32(buffer, uint32(len(value)))
+		buffer = append(buffer, value...)
+	}
+
+	for _, name := range names {
+		attribute := attributes[name]
+		appendValue([]byte(name))
+		appendValue([]byte(attribute.DataType))
+
+		if strings.HasPrefix(attribute.DataType, "Binary") {
+			buffer = append(buffer, 2)
+			appendValue(attribute.BinaryValue)
+		} else {
+			buffer = append(buffer, 1)
+			appendValue([]byte(attribute.StringValue))
+		}
+	}
+
+	return md5Hex(buffer)
+}
@@ -0,0 +1,163 @@
+package arrakistest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+
+	"github.com/elissonalvesilva/arrakis/pkg/sqs"
+)
+
+func TestServerSendReceiveDelete(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	ctx := context.Background()
+	client := server.Client()
+	queueURL := server.QueueURL("orders")
+
+	_, err := client.SendMessage(ctx, queueURL, "hello", sqs.WithMessageAttributes(map[string]types.MessageAttributeValue{
+		"type": {DataType: aws.String("String"), StringValue: aws.String("order.created")},
+		"blob": {DataType: aws.String("Binary"), BinaryValue: []byte{1, 2, 3}},
+	}))
+	if err != nil {
+		t.Fatalf("SendMessage returned error: %v", err)
+	}
+
+	output, err := client.ReceiveMessage(ctx, queueURL, 10, map[string]string{"All": ""})
+	if err != nil {
+		t.Fatalf("ReceiveMessage returned error: %v", err)
+	}
+	if len(output.Messages) != 1 || aws.ToString(output.Messages[0].Body) != "hello" {
+		t.Fatalf("Expected the sent message, got %+v", output.Messages)
+	}
+
+	received := output.Messages[0]
+	if aws.ToString(received.MessageAttributes["type"].StringValue) != "order.created" {
+		t.Errorf("Expected message attributes, got %v", received.MessageAttributes)
+	}
+	if received.Attributes["ApproximateReceiveCount"] != "1" {
+		t.Errorf("Expected a receive count of 1, got %v", received.Attributes)
+	}
+
+	if visible, inFlight := server.Depth(queueURL); visible != 0 || inFlight != 1 {
+		t.Errorf("Expected the message in flight, got %d visible and %d in flight", visible, inFlight)
+	}
+
+	if _, err := client.DeleteMessage(ctx, queueURL, aws.ToString(received.ReceiptHandle)); err != nil {
+		t.Fatalf("DeleteMessage returned error: %v", err)
+	}
+	if visible, inFlight := server.Depth(queueURL); visible+inFlight != 0 {
+		t.Error("Expected the queue to be empty after the delete")
+	}
+
+	if server.Calls("SendMessage") != 1 || server.Calls("ReceiveMessage") != 1 || server.Calls("DeleteMessage") != 1 {
+		t.Errorf("Expected one call of each action, got %v", server.calls)
+	}
+}
+
+func TestServerLongPollingWakesOnSend(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	ctx := context.Background()
+	client := server.Client()
+	queueURL := server.QueueURL("orders")
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_, _ = client.SendMessage(ctx, queueURL, "late")
+	}()
+
+	start := time.Now()
+	output, err := client.ReceiveMessage(ctx, queueURL, 10, nil, sqs.WithWaitTimeSeconds(5))
+	if err != nil {
+		t.Fatalf("ReceiveMessage returned error: %v", err)
+	}
+
+	if len(output.Messages) != 1 || time.Since(start) > 2*time.Second {
+		t.Errorf("Expected the long poll to return the late message early, got %d after %v", len(output.Messages), time.Since(start))
+	}
+}
+
+func TestServerMaxWaitTime(t *testing.T) {
+	server := NewServer(WithMaxWaitTime(20 * time.Millisecond))
+	defer server.Close()
+
+	start := time.Now()
+	output, err := server.Client().ReceiveMessage(context.Background(), server.QueueURL("orders"), 10, nil, sqs.WithWaitTimeSeconds(20))
+	if err != nil {
+		t.Fatalf("ReceiveMessage returned error: %v", err)
+	}
+
+	if len(output.Messages) != 0 || time.Since(start) > 2*time.Second {
+		t.Errorf("Expected an empty response after the capped wait, got %d after %v", len(output.Messages), time.Since(start))
+	}
+}
+
+func TestServerVisibilityTimeout(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	ctx := context.Background()
+	client := server.Client()
+	queueURL := server.QueueURL("orders")
+	_, _ = client.SendMessage(ctx, queueURL, "retry me")
+
+	output, _ := client.ReceiveMessage(ctx, queueURL, 10, nil)
+	if _, err := client.ChangeMessageVisibility(ctx, queueURL, aws.ToString(output.Messages[0].ReceiptHandle), 0); err != nil {
+		t.Fatalf("ChangeMessageVisibility returned error: %v", err)
+	}
+
+	output, _ = client.ReceiveMessage(ctx, queueURL, 10, nil)
+	if len(output.Messages) != 1 || output.Messages[0].Attributes["ApproximateReceiveCount"] != "2" {
+		t.Fatalf("Expected the released message to be received again, got %+v", output.Messages)
+	}
+
+	if _, err := client.DeleteMessage(ctx, queueURL, "unknown"); err == nil {
+		t.Error("Expected an error for an unknown receipt handle")
+	}
+}
+
+func TestServerFIFOHoldsGroupInFlight(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	ctx := context.Background()
+	client := server.Client()
+	queueURL := server.QueueURL("orders.fifo")
+
+	for _, group := range []string{"a", "a", "b"} {
+		if _, err := client.SendMessage(ctx, queueURL, group, sqs.WithMessageGroupID(group), sqs.WithDeduplicationID(time.Now().String())); err != nil {
+			t.Fatalf("SendMessage returned error: %v", err)
+		}
+	}
+
+	output, _ := client.ReceiveMessage(ctx, queueURL, 1, nil)
+	if len(output.Messages) != 1 || aws.ToString(output.Messages[0].Body) != "a" {
+		t.Fatalf("Expected the first message of group a, got %+v", output.Messages)
+	}
+
+	output, _ = client.ReceiveMessage(ctx, queueURL, 10, nil)
+	if len(output.Messages) != 1 || aws.ToString(output.Messages[0].Body) != "b" {
+		t.Errorf("Expected group a to be held while in flight, got %+v", output.Messages)
+	}
+}
+
+func TestServerResolvesQueueNames(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	orders := server.Client().Queue("orders")
+
+	queueURL, err := orders.URL(context.Background())
+	if err != nil {
+		t.Fatalf("URL returned error: %v", err)
+	}
+	if queueURL != server.QueueURL("orders") {
+		t.Errorf("Expected %s, got %s", server.QueueURL("orders"), queueURL)
+	}
+}
@@ -0,0 +1,31 @@
+package arrakistest
+
+import "github.com/elissonalvesilva/arrakis/pkg/sqs"
+
+// _deepIdleEmptyReceives is the count of consecutive empty receives of DeepIdle, well past
+// the decay threshold of any sensible configuration.
+const _deepIdleEmptyReceives = 100
+
+// HighVolume is the adaptive polling state of a queue returning full batches of 10
+// messages, classified as sqs.VolumeVeryHigh with the default batch size.
+func HighVolume() sqs.AdaptiveState {
+	return sqs.AdaptiveState{Average: _maxMessagesPerPoll}
+}
+
+// DeepIdle is the adaptive polling state of a queue that has been empty for a long time:
+// no average left and a long run of empty receives.
+func DeepIdle() sqs.AdaptiveState {
+	return sqs.AdaptiveState{ConsecutiveEmpty: _deepIdleEmptyReceives}
+}
+
+// Seed sets the adaptive polling state of the recorded queue, so a scenario starts from
+// it instead of replaying polls. A zero LastUpdate is the current time of the fake clock.
+//
+// Example:
+//
+//	recorder := arrakistest.NewDecisionRecorder(t)
+//	recorder.Seed(arrakistest.HighVolume())
+//	recorder.Run(arrakistest.EmptyPolls(3))
+func (r *DecisionRecorder) Seed(state sqs.AdaptiveState) {
+	r.client.SetAdaptiveState(r.queueURL, state)
+}
@@ -0,0 +1,24 @@
+package arrakistest
+
+import (
+	"testing"
+
+	"github.com/elissonalvesilva/arrakis/pkg/sqs"
+)
+
+func TestDecisionRecorderSeed(t *testing.T) {
+	recorder := NewDecisionRecorder(t, sqs.WithIdleWaitTimeSeconds(20))
+
+	recorder.Seed(HighVolume())
+	if got := recorder.Client().CurrentVolumeClass(recorder.QueueURL()); got != sqs.VolumeVeryHigh {
+		t.Fatalf("Expected a very high volume, got %v", got)
+	}
+	if got := recorder.NextWaitTime(); got >= 20 {
+		t.Errorf("Expected a short wait time from the high volume state, got %d", got)
+	}
+
+	recorder.Seed(DeepIdle())
+	if got := recorder.NextWaitTime(); got != 20 {
+		t.Errorf("Expected the idle wait time from the deep idle state, got %d", got)
+	}
+}
@@ -0,0 +1,47 @@
+package utils
+
+// Ring is a fixed-capacity buffer keeping the most recent items pushed into it.
+// Once full, every push overwrites the oldest item. It is not safe for concurrent use.
+type Ring[T any] struct {
+	items []T
+	next  int
+	full  bool
+}
+
+// NewRing creates a ring buffer holding up to size items.
+//
+// Parameters:
+//   - size: Maximum number of items kept (at least 1)
+//
+// Returns:
+//   - *Ring[T]: An empty ring buffer
+func NewRing[T any](size int) *Ring[T] {
+	return &Ring[T]{items: make([]T, max(size, 1))}
+}
+
+// Push adds an item, evicting the oldest one when the buffer is full.
+func (r *Ring[T]) Push(item T) {
+	r.items[r.next] = item
+	r.next = (r.next + 1) % len(r.items)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// Items returns a copy of the buffered items, oldest first.
+func (r *Ring[T]) Items() []T {
+	if !r.full {
+		return append([]T(nil), r.items[:r.next]...)
+	}
+
+	return append(append([]T(nil), r.items[r.next:]...), r.items[:r.next]...)
+}
+
+// Len returns the number of buffered items.
+func (r *Ring[T]) Len() int {
+	if r.full {
+		return len(r.items)
+	}
+
+	return r.next
+}
@@ -0,0 +1,30 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRingKeepsMostRecentItems(t *testing.T) {
+	ring := NewRing[int](3)
+
+	if len(ring.Items()) != 0 || ring.Len() != 0 {
+		t.Errorf("Expected empty ring, got %v", ring.Items())
+	}
+
+	ring.Push(1)
+	ring.Push(2)
+	if !reflect.DeepEqual(ring.Items(), []int{1, 2}) {
+		t.Errorf("Expected [1 2], got %v", ring.Items())
+	}
+
+	ring.Push(3)
+	ring.Push(4)
+	ring.Push(5)
+	if !reflect.DeepEqual(ring.Items(), []int{3, 4, 5}) {
+		t.Errorf("Expected [3 4 5], got %v", ring.Items())
+	}
+	if ring.Len() != 3 {
+		t.Errorf("Expected length 3, got %d", ring.Len())
+	}
+}
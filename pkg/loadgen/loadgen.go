@@ -0,0 +1,224 @@
+// Package loadgen generates SQS traffic following configurable patterns (steady, burst,
+// sine, drain), to reproduce and demonstrate how Arrakis adapts to message volume, for
+// example against LocalStack.
+package loadgen
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/elissonalvesilva/arrakis/pkg/sqs"
+)
+
+// Generator configuration
+const (
+	_defaultTick = 100 * time.Millisecond // Interval between two sends
+)
+
+// Sender sends batches of messages to a queue. *sqs.Producer implements it.
+type Sender interface {
+	SendBatch(ctx context.Context, messages []sqs.OutgoingMessage) (*sqs.BatchResult, error)
+}
+
+// Report summarizes the messages sent by a Generator.
+type Report struct {
+	// Sent is the number of messages accepted by SQS.
+	Sent int
+	// Failed is the number of messages that could not be sent.
+	Failed int
+	// Elapsed is the time since the run started.
+	Elapsed time.Duration
+	// LastErr is the most recent send error, nil if every message was sent.
+	LastErr error
+}
+
+// config holds the Generator settings.
+type config struct {
+	Tick        time.Duration
+	Body        func(seq int) string
+	SendOptions func(seq int) []sqs.SendOption
+	OnProgress  func(Report)
+}
+
+// Option configures a Generator.
+type Option func(*config)
+
+// WithTick sets the interval between two sends. Shorter ticks spread messages more
+// evenly; longer ticks send bigger batches.
+//
+// Parameters:
+//   - tick: Interval between sends (default: 100ms)
+func WithTick(tick time.Duration) Option {
+	return func(c *config) {
+		c.Tick = tick
+	}
+}
+
+// WithBody sets the function building the body of each message. The default body is a
+// JSON object with the sequence number and the time the message was generated.
+//
+// Parameters:
+//   - body: Function receiving the sequence number of the message, starting at 1
+//
+// Example:
+//
+//	option := loadgen.WithBody(func(seq int) string { return fmt.Sprintf("order-%d", seq) })
+func WithBody(body func(seq int) string) Option {
+	return func(c *config) {
+		c.Body = body
+	}
+}
+
+// WithSendOptions sets the function returning the send options of each message, such as
+// the message group of a FIFO queue.
+//
+// Parameters:
+//   - options: Function receiving the sequence number of the message, starting at 1
+//
+// Example:
+//
+//	option := loadgen.WithSendOptions(func(seq int) []sqs.SendOption {
+//	    return []sqs.SendOption{sqs.WithMessageGroupID(fmt.Sprintf("group-%d", seq%4))}
+//	})
+func WithSendOptions(options func(seq int) []sqs.SendOption) Option {
+	return func(c *config) {
+		c.SendOptions = options
+	}
+}
+
+// WithProgress registers a callback receiving the cumulative report after every send.
+//
+// Parameters:
+//   - onProgress: Function called from the Run goroutine
+func WithProgress(onProgress func(Report)) Option {
+	return func(c *config) {
+		c.OnProgress = onProgress
+	}
+}
+
+// Generator sends messages to a queue following a Pattern.
+type Generator struct {
+	sender  Sender
+	pattern Pattern
+	config  config
+}
+
+// New creates a generator sending messages through sender at the pace of pattern.
+//
+// Parameters:
+//   - sender: Destination of the messages, usually a *sqs.Producer
+//   - pattern: The traffic pattern to reproduce
+//   - options: Optional settings (tick, body, send options, progress)
+//
+// Returns:
+//   - *Generator: The generator, started with Run
+//
+// Example:
+//
+//	generator := loadgen.New(sqs.NewProducer(client, queueURL), loadgen.Burst(1, 50, time.Minute, 10*time.Second))
+//	report := generator.Run(ctx, 5*time.Minute)
+func New(sender Sender, pattern Pattern, options ...Option) *Generator {
+	config := config{
+		Tick: _defaultTick,
+		Body: defaultBody,
+	}
+
+	for _, opt := range options {
+		opt(&config)
+	}
+
+	if config.Tick <= 0 {
+		config.Tick = _defaultTick
+	}
+
+	return &Generator{sender: sender, pattern: pattern, config: config}
+}
+
+// Run sends messages until duration elapses or ctx is done, whichever comes first. A zero
+// duration runs until ctx is done. Send errors don't stop the run; they are counted in
+// the report.
+//
+// Parameters:
+//   - ctx: Context stopping the run when done
+//   - duration: Length of the run, zero for no limit
+//
+// Returns:
+//   - Report: The messages sent and failed
+func (g *Generator) Run(ctx context.Context, duration time.Duration) Report {
+	if duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, duration)
+		defer cancel()
+	}
+
+	ticker := time.NewTicker(g.config.Tick)
+	defer ticker.Stop()
+
+	var (
+		report  Report
+		start   = time.Now()
+		last    time.Duration
+		pending float64 // Messages due but not sent yet, including fractions
+		seq     int
+	)
+
+	for {
+		elapsed := time.Since(start)
+		if duration > 0 {
+			elapsed = min(elapsed, duration)
+		}
+
+		pending += g.pattern.Messages(last, elapsed)
+		last = elapsed
+
+		if due := int(pending); due > 0 {
+			pending -= float64(due)
+
+			messages := make([]sqs.OutgoingMessage, due)
+			for i := range messages {
+				seq++
+				messages[i] = sqs.OutgoingMessage{ID: strconv.Itoa(i), Body: g.config.Body(seq)}
+				if g.config.SendOptions != nil {
+					messages[i].Options = g.config.SendOptions(seq)
+				}
+			}
+
+			g.send(context.WithoutCancel(ctx), messages, &report)
+			report.Elapsed = time.Since(start)
+
+			if g.config.OnProgress != nil {
+				g.config.OnProgress(report)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			report.Elapsed = time.Since(start)
+			return report
+		case <-ticker.C:
+		}
+	}
+}
+
+// send sends a batch of messages and records the outcome in report.
+func (g *Generator) send(ctx context.Context, messages []sqs.OutgoingMessage, report *Report) {
+	result, err := g.sender.SendBatch(ctx, messages)
+	if result == nil {
+		report.Failed += len(messages)
+		report.LastErr = err
+		return
+	}
+
+	report.Sent += len(result.Successful)
+	report.Failed += len(result.Failed)
+	if len(result.Failed) > 0 {
+		report.LastErr = result.Failed[len(result.Failed)-1].Err
+	}
+}
+
+// defaultBody returns a JSON body with the sequence number and the generation time.
+func defaultBody(seq int) string {
+	return fmt.Sprintf(`{"seq":%d,"generated_at":%q}`, seq, time.Now().UTC().Format(time.RFC3339Nano))
+}
@@ -0,0 +1,110 @@
+package loadgen
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/elissonalvesilva/arrakis/pkg/sqs"
+)
+
+// fakeSender records the messages it is asked to send.
+type fakeSender struct {
+	mu       sync.Mutex
+	messages []sqs.OutgoingMessage
+	err      error
+}
+
+func (f *fakeSender) SendBatch(ctx context.Context, messages []sqs.OutgoingMessage) (*sqs.BatchResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	f.messages = append(f.messages, messages...)
+
+	result := &sqs.BatchResult{}
+	for _, m := range messages {
+		result.Successful = append(result.Successful, sqs.BatchSuccess{ID: m.ID})
+	}
+
+	return result, nil
+}
+
+func TestGeneratorSendsPattern(t *testing.T) {
+	sender := &fakeSender{}
+	generator := New(sender, Drain(25), WithTick(10*time.Millisecond))
+
+	report := generator.Run(context.Background(), 100*time.Millisecond)
+
+	if report.Sent != 25 || len(sender.messages) != 25 {
+		t.Errorf("Expected 25 messages sent, got %d (%d received)", report.Sent, len(sender.messages))
+	}
+	if report.Failed != 0 || report.LastErr != nil {
+		t.Errorf("Expected no failure, got %+v", report)
+	}
+	if !strings.Contains(sender.messages[24].Body, `"seq":25`) {
+		t.Errorf("Expected sequence numbers in the default body, got %s", sender.messages[24].Body)
+	}
+}
+
+func TestGeneratorFollowsRate(t *testing.T) {
+	sender := &fakeSender{}
+	generator := New(sender, Steady(200), WithTick(10*time.Millisecond))
+
+	report := generator.Run(context.Background(), 250*time.Millisecond)
+
+	if report.Sent < 30 || report.Sent > 55 {
+		t.Errorf("Expected about 50 messages at 200 msg/s for 250ms, got %d", report.Sent)
+	}
+}
+
+func TestGeneratorOptions(t *testing.T) {
+	sender := &fakeSender{}
+	var progress []Report
+
+	generator := New(sender, Drain(3),
+		WithBody(func(seq int) string { return "body" }),
+		WithSendOptions(func(seq int) []sqs.SendOption { return []sqs.SendOption{sqs.WithMessageGroupID("g")} }),
+		WithProgress(func(r Report) { progress = append(progress, r) }),
+	)
+
+	generator.Run(context.Background(), 50*time.Millisecond)
+
+	if sender.messages[0].Body != "body" || len(sender.messages[0].Options) != 1 {
+		t.Errorf("Expected custom body and options, got %+v", sender.messages[0])
+	}
+	if len(progress) != 1 || progress[0].Sent != 3 {
+		t.Errorf("Expected a progress report after the send, got %+v", progress)
+	}
+}
+
+func TestGeneratorCountsFailures(t *testing.T) {
+	sender := &fakeSender{err: errors.New("unavailable")}
+	generator := New(sender, Drain(4))
+
+	report := generator.Run(context.Background(), 50*time.Millisecond)
+
+	if report.Sent != 0 || report.Failed != 4 || report.LastErr == nil {
+		t.Errorf("Expected 4 failed messages with the error, got %+v", report)
+	}
+}
+
+func TestGeneratorStopsOnContext(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	done := make(chan Report)
+	go func() { done <- New(&fakeSender{}, Steady(10)).Run(ctx, 0) }()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected Run to stop when the context is done")
+	}
+}
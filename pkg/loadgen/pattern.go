@@ -0,0 +1,118 @@
+package loadgen
+
+import (
+	"math"
+	"time"
+)
+
+// Pattern describes how many messages a Generator sends over time.
+type Pattern interface {
+	// Messages returns the number of messages to send between from and to, both measured
+	// from the start of the run. Fractions are carried over to the next interval.
+	Messages(from, to time.Duration) float64
+}
+
+// rateFunc is a pattern defined by its rate, in messages per second, at each point of
+// the run. The rate is sampled in the middle of every interval.
+type rateFunc func(elapsed time.Duration) float64
+
+// Messages implements Pattern.
+func (f rateFunc) Messages(from, to time.Duration) float64 {
+	return f(from+(to-from)/2) * (to - from).Seconds()
+}
+
+// Steady sends messages at a constant rate.
+//
+// Parameters:
+//   - rate: Messages per second
+//
+// Returns:
+//   - Pattern: The steady pattern
+//
+// Example:
+//
+//	pattern := loadgen.Steady(5)
+func Steady(rate float64) Pattern {
+	return rateFunc(func(time.Duration) float64 {
+		return rate
+	})
+}
+
+// Burst sends messages at a base rate, raised to a peak rate for a while at regular
+// intervals. Each period starts with its burst.
+//
+// Parameters:
+//   - base: Messages per second between bursts
+//   - peak: Messages per second during bursts
+//   - every: Time between the start of two bursts
+//   - length: Duration of each burst
+//
+// Returns:
+//   - Pattern: The burst pattern
+//
+// Example:
+//
+//	// 1 msg/s, with 10 seconds at 100 msg/s every minute
+//	pattern := loadgen.Burst(1, 100, time.Minute, 10*time.Second)
+func Burst(base, peak float64, every, length time.Duration) Pattern {
+	return rateFunc(func(elapsed time.Duration) float64 {
+		if every > 0 && elapsed%every < length {
+			return peak
+		}
+
+		return base
+	})
+}
+
+// Sine sends messages at a rate oscillating smoothly between a minimum and a maximum,
+// starting at the minimum. It reproduces daily cycles in a compressed time frame.
+//
+// Parameters:
+//   - low: Minimum messages per second
+//   - high: Maximum messages per second
+//   - period: Duration of a full oscillation
+//
+// Returns:
+//   - Pattern: The sine pattern
+//
+// Example:
+//
+//	pattern := loadgen.Sine(0, 50, 10*time.Minute)
+func Sine(low, high float64, period time.Duration) Pattern {
+	return rateFunc(func(elapsed time.Duration) float64 {
+		if period <= 0 {
+			return low
+		}
+
+		phase := 2 * math.Pi * elapsed.Seconds() / period.Seconds()
+		return low + (high-low)*(1-math.Cos(phase))/2
+	})
+}
+
+// Drain sends a backlog of messages at once when the run starts, then nothing, so the
+// consumer side can be observed draining a full queue and going idle.
+//
+// Parameters:
+//   - messages: Size of the backlog
+//
+// Returns:
+//   - Pattern: The drain pattern
+//
+// Example:
+//
+//	pattern := loadgen.Drain(1000)
+func Drain(messages int) Pattern {
+	return drain(messages)
+}
+
+// drain implements the Drain pattern.
+type drain int
+
+// Messages implements Pattern.
+func (d drain) Messages(from, to time.Duration) float64 {
+	if from == 0 && to > 0 {
+		return float64(d)
+	}
+
+	return 0
+}
@@ -0,0 +1,62 @@
+package loadgen
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// total sums the messages of a pattern over a run, in steps of tick.
+func total(pattern Pattern, run, tick time.Duration) float64 {
+	sum := 0.0
+	for from := time.Duration(0); from < run; from += tick {
+		sum += pattern.Messages(from, min(from+tick, run))
+	}
+
+	return sum
+}
+
+func TestSteady(t *testing.T) {
+	if got := total(Steady(5), time.Minute, 100*time.Millisecond); math.Abs(got-300) > 0.001 {
+		t.Errorf("Expected 300 messages in a minute, got %v", got)
+	}
+}
+
+func TestBurst(t *testing.T) {
+	pattern := Burst(1, 10, time.Minute, 10*time.Second)
+
+	if got := pattern.Messages(0, time.Second); got != 10 {
+		t.Errorf("Expected the peak rate at the start of a period, got %v", got)
+	}
+	if got := pattern.Messages(30*time.Second, 31*time.Second); got != 1 {
+		t.Errorf("Expected the base rate between bursts, got %v", got)
+	}
+	if got := total(pattern, 2*time.Minute, time.Second); math.Abs(got-2*(100+50)) > 0.001 {
+		t.Errorf("Expected 300 messages in two periods, got %v", got)
+	}
+}
+
+func TestSine(t *testing.T) {
+	pattern := Sine(0, 20, time.Minute)
+
+	if got := pattern.Messages(0, 0); got != 0 {
+		t.Errorf("Expected no message in an empty interval, got %v", got)
+	}
+	if got := pattern.Messages(30*time.Second-50*time.Millisecond, 30*time.Second+50*time.Millisecond); math.Abs(got-2) > 0.001 {
+		t.Errorf("Expected the maximum rate at half period, got %v messages in 100ms", got)
+	}
+	if got := total(pattern, time.Minute, 100*time.Millisecond); math.Abs(got-600) > 1 {
+		t.Errorf("Expected the average rate over a period, got %v", got)
+	}
+}
+
+func TestDrain(t *testing.T) {
+	pattern := Drain(500)
+
+	if got := pattern.Messages(0, 100*time.Millisecond); got != 500 {
+		t.Errorf("Expected the backlog in the first interval, got %v", got)
+	}
+	if got := total(pattern, time.Minute, 100*time.Millisecond); got != 500 {
+		t.Errorf("Expected nothing after the backlog, got %v", got)
+	}
+}
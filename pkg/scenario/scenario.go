@@ -0,0 +1,302 @@
+// Package scenario runs end-to-end scenarios against a queue: produce a traffic pattern,
+// consume it with a configured Arrakis client, and assert on the API calls and latency
+// observed. Scenarios run against LocalStack, a real queue or an arrakistest.Server, which
+// makes them usable in downstream CI suites.
+package scenario
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+
+	"github.com/elissonalvesilva/arrakis/pkg/loadgen"
+	"github.com/elissonalvesilva/arrakis/pkg/sqs"
+)
+
+// Scenario configuration
+const (
+	_defaultDrainTimeout = 30 * time.Second       // Time allowed to receive the last messages after production
+	_receiveErrorBackoff = 100 * time.Millisecond // Pause of the consumer after a failed receive
+	_progressCheck       = 10 * time.Millisecond  // Interval between two checks of the received messages
+)
+
+// Scenario describes a traffic pattern to produce and the expectations on how the
+// consumer handles it.
+type Scenario struct {
+	// Name identifies the scenario in results and errors.
+	Name string
+	// Pattern is the traffic produced to the queue.
+	Pattern loadgen.Pattern
+	// Duration is how long the pattern is produced.
+	Duration time.Duration
+	// DrainTimeout is how long the consumer may keep receiving after production stopped
+	// (default: 30 seconds). The scenario ends as soon as every message was received.
+	DrainTimeout time.Duration
+	// Expect lists the assertions checked at the end of the scenario.
+	Expect Expectations
+}
+
+// Expectations are the assertions of a scenario. Zero values are not checked, except
+// that every produced message must always be received.
+type Expectations struct {
+	// MaxReceiveCalls is the maximum number of ReceiveMessage calls.
+	MaxReceiveCalls int
+	// MaxEmptyReceives is the maximum number of ReceiveMessage calls returning no message.
+	MaxEmptyReceives int
+	// MaxMeanLatency is the maximum average time between producing and receiving a message.
+	MaxMeanLatency time.Duration
+	// MaxLatency is the maximum time between producing and receiving any message.
+	MaxLatency time.Duration
+}
+
+// Result reports what happened during a scenario.
+type Result struct {
+	// Name is the scenario name.
+	Name string
+	// Sent is the number of messages produced.
+	Sent int
+	// Received is the number of distinct messages received.
+	Received int
+	// Duplicates is the number of messages received more than once.
+	Duplicates int
+	// ReceiveCalls is the number of ReceiveMessage calls of the consumer.
+	ReceiveCalls int
+	// EmptyReceives is the number of ReceiveMessage calls that returned no message.
+	EmptyReceives int
+	// MeanLatency is the average time between producing and receiving a message.
+	MeanLatency time.Duration
+	// MaxLatency is the longest time between producing and receiving a message.
+	MaxLatency time.Duration
+	// Elapsed is the duration of the scenario.
+	Elapsed time.Duration
+}
+
+// ErrExpectation is returned by Run when a result doesn't meet an expectation.
+type ErrExpectation struct {
+	// Scenario is the scenario name.
+	Scenario string
+	// Expectation names the failed assertion, such as "receive calls".
+	Expectation string
+	// Limit is the expected bound.
+	Limit string
+	// Observed is the value measured during the scenario.
+	Observed string
+}
+
+// Error implements the error interface.
+func (e *ErrExpectation) Error() string {
+	return fmt.Sprintf("scenario %q: %s: expected %s, got %s", e.Scenario, e.Expectation, e.Limit, e.Observed)
+}
+
+// payload is the body of the messages produced by a scenario.
+type payload struct {
+	Seq    int       `json:"seq"`
+	SentAt time.Time `json:"sent_at"`
+}
+
+// Run produces the scenario traffic to a queue while consuming it with client, then
+// checks the expectations. The client is used as configured, so enable Arrakis (or not)
+// and set the options under test before calling Run. The queue should be empty and
+// only used by the scenario.
+//
+// Parameters:
+//   - ctx: Context for cancellation of the whole scenario
+//   - client: The client consuming (and producing) the messages
+//   - queueURL: The queue to use
+//   - scenario: The traffic and expectations
+//
+// Returns:
+//   - Result: The measures of the scenario, also returned on failure
+//   - error: The failed expectations as *ErrExpectation joined together, a send error,
+//     or the context error
+//
+// Example:
+//
+//	client.EnableArrakis()
+//	result, err := scenario.Run(ctx, client, queueURL, scenario.Scenario{
+//	    Name:     "burst",
+//	    Pattern:  loadgen.Burst(1, 50, time.Minute, 10*time.Second),
+//	    Duration: 2 * time.Minute,
+//	    Expect:   scenario.Expectations{MaxReceiveCalls: 200, MaxMeanLatency: 5 * time.Second},
+//	})
+func Run(ctx context.Context, client *sqs.SQS, queueURL string, scenario Scenario) (Result, error) {
+	start := time.Now()
+
+	drainTimeout := scenario.DrainTimeout
+	if drainTimeout <= 0 {
+		drainTimeout = _defaultDrainTimeout
+	}
+
+	consumerCtx, stopConsumer := context.WithCancel(ctx)
+	defer stopConsumer()
+
+	c := &consumer{client: client, queueURL: queueURL, seen: map[int]bool{}}
+	var wg sync.WaitGroup
+	wg.Go(func() { c.run(consumerCtx) })
+
+	generator := loadgen.New(sqs.NewProducer(client, queueURL), scenario.Pattern, loadgen.WithBody(func(seq int) string {
+		body, _ := json.Marshal(payload{Seq: seq, SentAt: time.Now()})
+		return string(body)
+	}))
+	report := generator.Run(ctx, scenario.Duration)
+
+	// Keep consuming until every message was received or the drain timeout elapses
+	deadline := time.NewTimer(drainTimeout)
+	defer deadline.Stop()
+
+	ticker := time.NewTicker(_progressCheck)
+	defer ticker.Stop()
+
+waiting:
+	for c.received() < report.Sent {
+		select {
+		case <-ctx.Done():
+			break waiting
+		case <-deadline.C:
+			break waiting
+		case <-ticker.C:
+		}
+	}
+
+	stopConsumer()
+	wg.Wait()
+
+	result := c.result(scenario.Name, report.Sent)
+	result.Elapsed = time.Since(start)
+
+	switch {
+	case ctx.Err() != nil:
+		return result, ctx.Err()
+	case report.Failed > 0:
+		return result, fmt.Errorf("scenario %q: %d messages not sent: %w", scenario.Name, report.Failed, report.LastErr)
+	}
+
+	return result, scenario.Expect.check(result)
+}
+
+// check returns the failed expectations of a result.
+func (e Expectations) check(r Result) error {
+	var errs []error
+	fail := func(expectation, limit, observed string) {
+		errs = append(errs, &ErrExpectation{Scenario: r.Name, Expectation: expectation, Limit: limit, Observed: observed})
+	}
+
+	if r.Received < r.Sent {
+		fail("received messages", fmt.Sprint(r.Sent), fmt.Sprint(r.Received))
+	}
+	if e.MaxReceiveCalls > 0 && r.ReceiveCalls > e.MaxReceiveCalls {
+		fail("receive calls", fmt.Sprintf("at most %d", e.MaxReceiveCalls), fmt.Sprint(r.ReceiveCalls))
+	}
+	if e.MaxEmptyReceives > 0 && r.EmptyReceives > e.MaxEmptyReceives {
+		fail("empty receives", fmt.Sprintf("at most %d", e.MaxEmptyReceives), fmt.Sprint(r.EmptyReceives))
+	}
+	if e.MaxMeanLatency > 0 && r.MeanLatency > e.MaxMeanLatency {
+		fail("mean latency", fmt.Sprintf("at most %v", e.MaxMeanLatency), r.MeanLatency.String())
+	}
+	if e.MaxLatency > 0 && r.MaxLatency > e.MaxLatency {
+		fail("max latency", fmt.Sprintf("at most %v", e.MaxLatency), r.MaxLatency.String())
+	}
+
+	return errors.Join(errs...)
+}
+
+// consumer receives and deletes the scenario messages, measuring their latency.
+type consumer struct {
+	client   *sqs.SQS
+	queueURL string
+
+	mu            sync.Mutex
+	seen          map[int]bool
+	duplicates    int
+	receiveCalls  int
+	emptyReceives int
+	totalLatency  time.Duration
+	maxLatency    time.Duration
+}
+
+// run polls the queue until ctx is done.
+func (c *consumer) run(ctx context.Context) {
+	for ctx.Err() == nil {
+		output, err := c.client.ReceiveMessage(ctx, c.queueURL, 10, nil)
+		if err != nil {
+			select {
+			case <-ctx.Done():
+			case <-time.After(_receiveErrorBackoff):
+			}
+			continue
+		}
+
+		receivedAt := time.Now()
+		c.record(len(output.Messages) == 0)
+
+		for _, m := range output.Messages {
+			var body payload
+			if json.Unmarshal([]byte(aws.ToString(m.Body)), &body) == nil {
+				c.observe(body, receivedAt)
+			}
+
+			_, _ = c.client.DeleteMessage(context.WithoutCancel(ctx), c.queueURL, aws.ToString(m.ReceiptHandle))
+		}
+	}
+}
+
+// record counts a ReceiveMessage call.
+func (c *consumer) record(empty bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.receiveCalls++
+	if empty {
+		c.emptyReceives++
+	}
+}
+
+// observe records the reception of a scenario message.
+func (c *consumer) observe(body payload, receivedAt time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.seen[body.Seq] {
+		c.duplicates++
+		return
+	}
+	c.seen[body.Seq] = true
+
+	latency := receivedAt.Sub(body.SentAt)
+	c.totalLatency += latency
+	c.maxLatency = max(c.maxLatency, latency)
+}
+
+// received returns the number of distinct messages received so far.
+func (c *consumer) received() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.seen)
+}
+
+// result returns the measures of the consumer.
+func (c *consumer) result(name string, sent int) Result {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	result := Result{
+		Name:          name,
+		Sent:          sent,
+		Received:      len(c.seen),
+		Duplicates:    c.duplicates,
+		ReceiveCalls:  c.receiveCalls,
+		EmptyReceives: c.emptyReceives,
+		MaxLatency:    c.maxLatency,
+	}
+	if len(c.seen) > 0 {
+		result.MeanLatency = c.totalLatency / time.Duration(len(c.seen))
+	}
+
+	return result
+}
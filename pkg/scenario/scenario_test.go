@@ -0,0 +1,101 @@
+package scenario
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/elissonalvesilva/arrakis/pkg/arrakistest"
+	"github.com/elissonalvesilva/arrakis/pkg/loadgen"
+)
+
+func TestRunMeasuresScenario(t *testing.T) {
+	server := arrakistest.NewServer(arrakistest.WithMaxWaitTime(20 * time.Millisecond))
+	defer server.Close()
+
+	client := server.Client()
+	client.EnableArrakis()
+
+	result, err := Run(context.Background(), client, server.QueueURL("orders"), Scenario{
+		Name:     "drain",
+		Pattern:  loadgen.Drain(35),
+		Duration: 100 * time.Millisecond,
+		Expect:   Expectations{MaxReceiveCalls: 1000, MaxMeanLatency: 5 * time.Second},
+	})
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	if result.Sent != 35 || result.Received != 35 || result.Duplicates != 0 {
+		t.Errorf("Expected 35 messages sent and received, got %+v", result)
+	}
+	if result.ReceiveCalls < 4 || result.ReceiveCalls > server.Calls("ReceiveMessage") {
+		t.Errorf("Expected at least 4 receive calls, consistent with the server, got %d (server %d)",
+			result.ReceiveCalls, server.Calls("ReceiveMessage"))
+	}
+	if result.MeanLatency <= 0 || result.MaxLatency < result.MeanLatency {
+		t.Errorf("Expected latencies to be measured, got %+v", result)
+	}
+	if visible, inFlight := server.Depth(server.QueueURL("orders")); visible+inFlight != 0 {
+		t.Error("Expected the messages to be deleted")
+	}
+}
+
+func TestRunReportsFailedExpectations(t *testing.T) {
+	server := arrakistest.NewServer(arrakistest.WithMaxWaitTime(20 * time.Millisecond))
+	defer server.Close()
+
+	result, err := Run(context.Background(), server.Client(), server.QueueURL("orders"), Scenario{
+		Name:     "strict",
+		Pattern:  loadgen.Steady(100),
+		Duration: 200 * time.Millisecond,
+		Expect:   Expectations{MaxReceiveCalls: 1, MaxLatency: time.Nanosecond},
+	})
+
+	var expectation *ErrExpectation
+	if !errors.As(err, &expectation) || expectation.Scenario != "strict" {
+		t.Fatalf("Expected an expectation error, got %v", err)
+	}
+	if result.Received == 0 {
+		t.Error("Expected the result to be returned with the error")
+	}
+}
+
+func TestExpectationsCheck(t *testing.T) {
+	result := Result{Name: "lossy", Sent: 10, Received: 8, ReceiveCalls: 5, EmptyReceives: 3, MeanLatency: time.Second, MaxLatency: 2 * time.Second}
+
+	if err := (Expectations{}).check(Result{Sent: 10, Received: 10}); err != nil {
+		t.Errorf("Expected no error without expectations, got %v", err)
+	}
+
+	err := Expectations{MaxReceiveCalls: 4, MaxEmptyReceives: 2, MaxMeanLatency: time.Millisecond, MaxLatency: time.Second}.check(result)
+	if err == nil {
+		t.Fatal("Expected failed expectations")
+	}
+
+	for _, expected := range []string{"received messages", "receive calls", "empty receives", "mean latency", "max latency"} {
+		found := false
+		for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+			if e.(*ErrExpectation).Expectation == expected {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("Expected the %q expectation to fail, got %v", expected, err)
+		}
+	}
+}
+
+func TestRunStopsOnContext(t *testing.T) {
+	server := arrakistest.NewServer()
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err := Run(ctx, server.Client(), server.QueueURL("orders"), Scenario{Name: "cancelled", Pattern: loadgen.Steady(10), Duration: time.Hour})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the context error, got %v", err)
+	}
+}
@@ -0,0 +1,314 @@
+// Package soak runs a consumer for a long time (typically hours) against a queue fed with
+// generated traffic, periodically checking invariants that only break over time: goroutine
+// leaks, an EWMA average out of bounds, in-flight accounting drift and messages stuck in
+// their handler. The client state is dumped whenever an invariant is violated.
+package soak
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/elissonalvesilva/arrakis/pkg/loadgen"
+	"github.com/elissonalvesilva/arrakis/pkg/sqs"
+)
+
+// Soak configuration
+const (
+	_defaultCheckInterval      = time.Minute     // Interval between two invariant checks
+	_defaultMaxGoroutineGrowth = 100             // Goroutines allowed above the first check
+	_defaultMaxHandlingTime    = 5 * time.Minute // Time after which a message in its handler is stuck
+	_maxMessagesPerPoll        = 10              // Upper bound of the EWMA average of messages per poll
+)
+
+// Invariants checked by the harness.
+const (
+	// InvariantGoroutines is violated when the number of goroutines keeps growing.
+	InvariantGoroutines = "goroutines"
+	// InvariantEWMA is violated when the EWMA average is not a number of messages per poll.
+	InvariantEWMA = "ewma"
+	// InvariantInFlight is violated when the in-flight count of Arrakis drifts from the
+	// messages actually being handled.
+	InvariantInFlight = "in_flight"
+	// InvariantStuckMessage is violated when a message stays in its handler too long, which
+	// would keep its visibility extended forever.
+	InvariantStuckMessage = "stuck_message"
+)
+
+// Config describes a soak run.
+type Config struct {
+	// Duration is the length of the run. Zero runs until the context is done.
+	Duration time.Duration
+	// Pattern is the traffic produced to the queue during the run. Nil produces nothing,
+	// for queues fed by another process.
+	Pattern loadgen.Pattern
+	// CheckInterval is the interval between two invariant checks (default: 1 minute).
+	CheckInterval time.Duration
+	// MaxGoroutineGrowth is the number of goroutines allowed above the count of the first
+	// check (default: 100).
+	MaxGoroutineGrowth int
+	// MaxHandlingTime is how long a message may stay in its handler (default: 5 minutes).
+	MaxHandlingTime time.Duration
+	// DumpDir is the directory receiving a DumpState document on every violation. Empty
+	// disables dumps.
+	DumpDir string
+	// StopOnViolation ends the run at the first violation.
+	StopOnViolation bool
+	// OnViolation is notified of every violation as soon as it is detected.
+	OnViolation func(Violation)
+}
+
+// Violation is an invariant found broken during a check.
+type Violation struct {
+	// Time is when the check ran.
+	Time time.Time
+	// Invariant is the broken invariant, one of the Invariant constants.
+	Invariant string
+	// Detail describes the observed values.
+	Detail string
+	// DumpPath is the state dump written for the violation, empty if none.
+	DumpPath string
+}
+
+// Error implements the error interface.
+func (v *Violation) Error() string {
+	return fmt.Sprintf("soak: %s invariant violated: %s", v.Invariant, v.Detail)
+}
+
+// Report summarizes a soak run.
+type Report struct {
+	// Checks is the number of invariant checks performed.
+	Checks int
+	// Handled is the number of messages handled.
+	Handled int
+	// Sent is the number of messages produced by the run.
+	Sent int
+	// Violations lists every violation detected.
+	Violations []Violation
+	// Elapsed is the duration of the run.
+	Elapsed time.Duration
+}
+
+// Run consumes a queue with handler for the configured duration while producing the
+// configured traffic, and checks the invariants every CheckInterval and once more at the
+// end of the run.
+//
+// Parameters:
+//   - ctx: Context ending the run when done
+//   - client: The client under test, configured (and with Arrakis enabled) by the caller
+//   - queueURL: The queue to consume
+//   - handler: The handler of the consumer
+//   - config: The run settings
+//   - options: Options of the consumer under test
+//
+// Returns:
+//   - Report: The checks performed and the violations detected
+//   - error: The violations as *Violation joined together, nil if none
+//
+// Example:
+//
+//	report, err := soak.Run(ctx, client, queueURL, handler, soak.Config{
+//	    Duration: 4 * time.Hour,
+//	    Pattern:  loadgen.Sine(0, 30, 20*time.Minute),
+//	    DumpDir:  "soak-dumps",
+//	}, sqs.WithWorkers(8))
+func Run(ctx context.Context, client *sqs.SQS, queueURL string, handler sqs.Handler, config Config, options ...sqs.ConsumerOption) (Report, error) {
+	setDefaults(&config)
+	start := time.Now()
+
+	if config.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.Duration)
+		defer cancel()
+	}
+
+	runCtx, stop := context.WithCancel(ctx)
+	defer stop()
+
+	tracker := &handlerTracker{handler: handler, started: map[*int]time.Time{}}
+	h := &harness{client: client, queueURL: queueURL, config: config, tracker: tracker, stop: stop}
+
+	// The consumer stops polling ahead of a context deadline, so it gets a context without
+	// the deadline of the run, cancelled when the run ends
+	consumerCtx, stopConsumer := context.WithCancel(context.WithoutCancel(runCtx))
+	defer context.AfterFunc(runCtx, stopConsumer)()
+
+	var wg sync.WaitGroup
+	wg.Go(func() { _ = sqs.NewConsumer(client, queueURL, tracker, options...).Start(consumerCtx) })
+
+	var sent int
+	if config.Pattern != nil {
+		wg.Go(func() { sent = loadgen.New(sqs.NewProducer(client, queueURL), config.Pattern).Run(runCtx, 0).Sent })
+	}
+
+	ticker := time.NewTicker(config.CheckInterval)
+	defer ticker.Stop()
+
+	for runCtx.Err() == nil {
+		select {
+		case <-runCtx.Done():
+		case <-ticker.C:
+			h.check(false)
+		}
+	}
+
+	stop()
+	stopConsumer()
+	wg.Wait()
+	h.check(true)
+
+	report := h.report
+	report.Sent = sent
+	report.Handled = tracker.handledCount()
+	report.Elapsed = time.Since(start)
+
+	errs := make([]error, len(report.Violations))
+	for i := range report.Violations {
+		errs[i] = &report.Violations[i]
+	}
+
+	return report, errors.Join(errs...)
+}
+
+// setDefaults fills in the unset settings of a run.
+func setDefaults(c *Config) {
+	if c.CheckInterval <= 0 {
+		c.CheckInterval = _defaultCheckInterval
+	}
+
+	if c.MaxGoroutineGrowth <= 0 {
+		c.MaxGoroutineGrowth = _defaultMaxGoroutineGrowth
+	}
+
+	if c.MaxHandlingTime <= 0 {
+		c.MaxHandlingTime = _defaultMaxHandlingTime
+	}
+}
+
+// harness checks the invariants of a run.
+type harness struct {
+	client   *sqs.SQS
+	queueURL string
+	config   Config
+	tracker  *handlerTracker
+	stop     context.CancelFunc
+
+	baseline int // Goroutines at the first check
+	report   Report
+}
+
+// check verifies every invariant and records the violations. The final check runs once
+// the consumer stopped, when nothing may be left in flight.
+func (h *harness) check(final bool) {
+	now := time.Now()
+	h.report.Checks++
+
+	goroutines := runtime.NumGoroutine()
+	if h.report.Checks == 1 {
+		h.baseline = goroutines
+	}
+	if growth := goroutines - h.baseline; growth > h.config.MaxGoroutineGrowth {
+		h.violate(now, InvariantGoroutines, fmt.Sprintf("%d goroutines, %d more than at the first check", goroutines, growth))
+	}
+
+	stats := h.client.Stats()[h.queueURL]
+	if math.IsNaN(stats.Average) || stats.Average < 0 || stats.Average > _maxMessagesPerPoll {
+		h.violate(now, InvariantEWMA, fmt.Sprintf("average %v outside [0, %d]", stats.Average, _maxMessagesPerPoll))
+	}
+
+	handling, oldest := h.tracker.inProgress(now)
+	if stats.InFlight < int64(handling) || final && stats.InFlight != int64(handling) {
+		h.violate(now, InvariantInFlight, fmt.Sprintf("%d in flight for %d messages in their handler", stats.InFlight, handling))
+	}
+	if oldest > h.config.MaxHandlingTime {
+		h.violate(now, InvariantStuckMessage, fmt.Sprintf("a message has been in its handler for %v", oldest.Round(time.Second)))
+	}
+}
+
+// violate records a violation, dumping the client state if configured.
+func (h *harness) violate(now time.Time, invariant, detail string) {
+	violation := Violation{Time: now, Invariant: invariant, Detail: detail}
+
+	if h.config.DumpDir != "" {
+		violation.DumpPath = h.dump(now, invariant)
+	}
+
+	h.report.Violations = append(h.report.Violations, violation)
+
+	if h.config.OnViolation != nil {
+		h.config.OnViolation(violation)
+	}
+	if h.config.StopOnViolation {
+		h.stop()
+	}
+}
+
+// dump writes the client state to the dump directory and returns the file path, or an
+// empty path if it couldn't be written.
+func (h *harness) dump(now time.Time, invariant string) string {
+	state, err := h.client.DumpState()
+	if err != nil {
+		return ""
+	}
+
+	path := filepath.Join(h.config.DumpDir, fmt.Sprintf("soak-%s-%s.json", now.UTC().Format("20060102T150405.000"), invariant))
+	if os.MkdirAll(h.config.DumpDir, 0o755) != nil || os.WriteFile(path, state, 0o644) != nil {
+		return ""
+	}
+
+	return path
+}
+
+// handlerTracker wraps the handler under test to know which messages are being handled.
+type handlerTracker struct {
+	handler sqs.Handler
+
+	mu      sync.Mutex
+	started map[*int]time.Time // Start time of every message in its handler
+	handled int
+}
+
+// Handle implements sqs.Handler.
+func (t *handlerTracker) Handle(ctx context.Context, msg sqs.Message) error {
+	key := new(int)
+
+	t.mu.Lock()
+	t.started[key] = time.Now()
+	t.mu.Unlock()
+
+	defer func() {
+		t.mu.Lock()
+		delete(t.started, key)
+		t.handled++
+		t.mu.Unlock()
+	}()
+
+	return t.handler.Handle(ctx, msg)
+}
+
+// inProgress returns the number of messages in their handler and the longest time one of
+// them has spent there.
+func (t *handlerTracker) inProgress(now time.Time) (count int, oldest time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, started := range t.started {
+		oldest = max(oldest, now.Sub(started))
+	}
+
+	return len(t.started), oldest
+}
+
+// handledCount returns the number of messages handled.
+func (t *handlerTracker) handledCount() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.handled
+}
@@ -0,0 +1,129 @@
+package soak
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/elissonalvesilva/arrakis/pkg/arrakistest"
+	"github.com/elissonalvesilva/arrakis/pkg/loadgen"
+	"github.com/elissonalvesilva/arrakis/pkg/sqs"
+)
+
+// noop is a handler accepting every message.
+var noop = sqs.HandlerFunc(func(ctx context.Context, msg sqs.Message) error { return nil })
+
+func TestRunHealthyConsumer(t *testing.T) {
+	server := arrakistest.NewServer(arrakistest.WithMaxWaitTime(20 * time.Millisecond))
+	defer server.Close()
+
+	client := server.Client()
+	client.EnableArrakis()
+
+	report, err := Run(context.Background(), client, server.QueueURL("orders"), noop, Config{
+		Duration:      300 * time.Millisecond,
+		Pattern:       loadgen.Steady(100),
+		CheckInterval: 50 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("Expected no violation, got %v", err)
+	}
+
+	if report.Checks < 3 || report.Sent == 0 || report.Handled == 0 {
+		t.Errorf("Expected periodic checks and handled traffic, got %+v", report)
+	}
+}
+
+func TestRunDetectsStuckMessage(t *testing.T) {
+	server := arrakistest.NewServer(arrakistest.WithMaxWaitTime(20 * time.Millisecond))
+	defer server.Close()
+
+	client := server.Client()
+	queueURL := server.QueueURL("orders")
+	_, _ = client.SendMessage(context.Background(), queueURL, "slow")
+
+	var notified []Violation
+	slow := sqs.HandlerFunc(func(ctx context.Context, msg sqs.Message) error {
+		time.Sleep(300 * time.Millisecond)
+		return nil
+	})
+
+	report, err := Run(context.Background(), client, queueURL, slow, Config{
+		Duration:        time.Second,
+		CheckInterval:   20 * time.Millisecond,
+		MaxHandlingTime: 100 * time.Millisecond,
+		DumpDir:         t.TempDir(),
+		StopOnViolation: true,
+		OnViolation:     func(v Violation) { notified = append(notified, v) },
+	})
+
+	var violation *Violation
+	if !errors.As(err, &violation) || violation.Invariant != InvariantStuckMessage {
+		t.Fatalf("Expected a stuck message violation, got %v", err)
+	}
+	if len(notified) == 0 || len(report.Violations) < len(notified) {
+		t.Errorf("Expected violations to be notified and reported, got %d and %d", len(notified), len(report.Violations))
+	}
+	if report.Elapsed >= time.Second {
+		t.Errorf("Expected the run to stop at the first violation, took %v", report.Elapsed)
+	}
+
+	if _, err := os.Stat(violation.DumpPath); err != nil {
+		t.Errorf("Expected the state to be dumped, got %q: %v", violation.DumpPath, err)
+	}
+}
+
+func TestCheckDetectsGoroutineGrowth(t *testing.T) {
+	server := arrakistest.NewServer()
+	defer server.Close()
+
+	h := &harness{
+		client:   server.Client(),
+		queueURL: server.QueueURL("orders"),
+		config:   Config{MaxGoroutineGrowth: 2},
+		tracker:  &handlerTracker{started: map[*int]time.Time{}},
+		stop:     func() {},
+	}
+	h.check(false)
+
+	release := make(chan struct{})
+	defer close(release)
+	for range 5 {
+		go func() { <-release }()
+	}
+
+	h.check(false)
+
+	if len(h.report.Violations) != 1 || h.report.Violations[0].Invariant != InvariantGoroutines {
+		t.Errorf("Expected a goroutine violation, got %+v", h.report.Violations)
+	}
+}
+
+func TestCheckDetectsInFlightDrift(t *testing.T) {
+	server := arrakistest.NewServer()
+	defer server.Close()
+
+	client := server.Client()
+	queueURL := server.QueueURL("orders")
+	client.SetInFlight(queueURL, 3, 10)
+
+	h := &harness{
+		client:   client,
+		queueURL: queueURL,
+		config:   Config{MaxGoroutineGrowth: 1000},
+		tracker:  &handlerTracker{started: map[*int]time.Time{}},
+		stop:     func() {},
+	}
+
+	h.check(false)
+	if len(h.report.Violations) != 0 {
+		t.Errorf("Expected queued messages to be allowed during the run, got %+v", h.report.Violations)
+	}
+
+	h.check(true)
+	if len(h.report.Violations) != 1 || h.report.Violations[0].Invariant != InvariantInFlight {
+		t.Errorf("Expected an in-flight violation after the consumer stopped, got %+v", h.report.Violations)
+	}
+}
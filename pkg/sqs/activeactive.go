@@ -0,0 +1,233 @@
+package sqs
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Default shared deduplication configuration values
+const (
+	_memoryDedupSweepSize = 1024 // Claims held by a MemoryDedupStore before expired ones are swept
+)
+
+// DedupStore records which messages of a logical stream are claimed for processing, so
+// that consumers of replicated queues (see NewActiveActive) handle each event once.
+// pkg/adapters/redisdedup implements it on Redis; NewMemoryDedupStore suits consumers
+// running in a single process.
+type DedupStore interface {
+	// Claim marks key as taken for ttl and reports whether this caller took it: false
+	// means another consumer holds an unexpired claim on the key.
+	Claim(ctx context.Context, key string, ttl time.Duration) (bool, error)
+	// Release drops the claim on key, so the next delivery of the message, in any queue,
+	// can be handled again.
+	Release(ctx context.Context, key string) error
+}
+
+// MessageKey identifies the copies of a message across replicated queues. Message IDs
+// differ between queues, so it is usually derived from the body or an attribute.
+type MessageKey func(Message) string
+
+// sharedDedupConfig holds the shared deduplication settings of a Consumer.
+type sharedDedupConfig struct {
+	Store DedupStore
+	TTL   time.Duration
+	Key   MessageKey
+}
+
+// WithSharedDeduplication suppresses the double processing of messages replicated to
+// several queues, typically the same events published in two regions. Before handling a
+// message the consumer claims its key in store; a copy whose key is already claimed is
+// deleted without calling the handler (audited as DeleteDuplicate). Failed messages
+// release their claim, so whichever copy is delivered next is retried.
+//
+// Messages whose claim can't be checked because the store is unavailable are left in the
+// queue, and delivered again after their visibility timeout.
+//
+// Parameters:
+//   - store: The dedup store shared by every consumer of the stream
+//   - ttl: How long claims are kept; must cover the replication lag between queues
+//   - key: Identifies the copies of a message (nil uses ContentKey)
+//
+// Example:
+//
+//	consumer := NewConsumer(client, queueURL, handler,
+//	    WithSharedDeduplication(redisdedup.New(redisClient, "arrakis:orders"), time.Hour, nil))
+func WithSharedDeduplication(store DedupStore, ttl time.Duration, key MessageKey) ConsumerOption {
+	return func(c *consumerConfig) {
+		if key == nil {
+			key = ContentKey
+		}
+		c.SharedDedup = &sharedDedupConfig{Store: store, TTL: ttl, Key: key}
+	}
+}
+
+// ContentKey is the default MessageKey: the hex SHA-256 of the canonical body (see
+// CanonicalBody), so copies of a JSON event match even when re-encoded in transit.
+//
+// Parameters:
+//   - msg: The message
+//
+// Returns:
+//   - string: The key of the message
+func ContentKey(msg Message) string {
+	sum := sha256.Sum256([]byte(CanonicalBody(msg.Body)))
+	return hex.EncodeToString(sum[:])
+}
+
+// claim claims msg in the shared dedup store and reports whether the consumer may handle
+// it. Copies already claimed elsewhere are deleted.
+func (c *Consumer) claim(ctx context.Context, source queueSource, msg Message) bool {
+	shared := c.config.SharedDedup
+	if shared == nil {
+		return true
+	}
+
+	claimed, err := shared.Store.Claim(ctx, shared.Key(msg), shared.TTL)
+	if err != nil {
+		source.client.logger().Warn("dedup claim failed, message will be redelivered", "queue", source.queueURL, "message_id", msg.ID, "error", err)
+		return false
+	}
+
+	if !claimed {
+		if _, err := source.client.DeleteMessage(ctx, source.queueURL, msg.ReceiptHandle); err == nil {
+			c.auditDelete(source, msg, DeleteDuplicate, time.Time{})
+		}
+		return false
+	}
+
+	return true
+}
+
+// unclaim releases the claim of a message that failed.
+func (c *Consumer) unclaim(ctx context.Context, source queueSource, msg Message) {
+	shared := c.config.SharedDedup
+	if shared == nil {
+		return
+	}
+
+	if err := shared.Store.Release(ctx, shared.Key(msg)); err != nil {
+		source.client.logger().Warn("dedup release failed, retries wait for the claim to expire", "queue", source.queueURL, "message_id", msg.ID, "error", err)
+	}
+}
+
+// RegionQueue is one queue of a replicated stream and the client of its region.
+type RegionQueue struct {
+	Client   *SQS
+	QueueURL string
+}
+
+// ActiveActive consumes the same logical stream from queues in several regions at once,
+// with one Consumer per queue sharing a DedupStore, for teams replicating events
+// active-active. Unlike WithFailover, every region is polled all the time: events are
+// handled as soon as any region delivers them, and a regional outage only removes one
+// of the sources.
+type ActiveActive struct {
+	consumers []*Consumer
+}
+
+// NewActiveActive creates a consumer for each queue, all running handler and claiming
+// messages in store (see WithSharedDeduplication) before handling them.
+//
+// Parameters:
+//   - queues: The replicated queues, each with the client of its region
+//   - handler: The handler of the stream
+//   - store: The dedup store shared by the consumers, and by other instances if any
+//   - ttl: How long claims are kept; must cover the replication lag between regions
+//   - options: Consumer options applied to every consumer
+//
+// Returns:
+//   - *ActiveActive: The consumers, ready to Start
+//
+// Example:
+//
+//	stream := sqs.NewActiveActive([]sqs.RegionQueue{
+//	    {Client: usEast1, QueueURL: ordersEastURL},
+//	    {Client: euWest1, QueueURL: ordersWestURL},
+//	}, handler, redisdedup.New(redisClient, "arrakis:orders"), time.Hour, sqs.WithWorkers(8))
+//	err := stream.Start(ctx)
+func NewActiveActive(queues []RegionQueue, handler Handler, store DedupStore, ttl time.Duration, options ...ConsumerOption) *ActiveActive {
+	options = append(options[:len(options):len(options)], WithSharedDeduplication(store, ttl, nil))
+
+	a := &ActiveActive{}
+	for _, queue := range queues {
+		a.consumers = append(a.consumers, NewConsumer(queue.Client, queue.QueueURL, handler, options...))
+	}
+
+	return a
+}
+
+// Consumers returns the consumer of each queue, in the order of the queues, e.g. to
+// register them with the admin server.
+func (a *ActiveActive) Consumers() []*Consumer {
+	return a.consumers
+}
+
+// Start runs every consumer until ctx is cancelled, and returns once all of them have
+// drained (see Consumer.Start).
+//
+// Returns:
+//   - error: The errors returned by the consumers, joined
+func (a *ActiveActive) Start(ctx context.Context) error {
+	errs := make([]error, len(a.consumers))
+
+	var wg sync.WaitGroup
+	for i, consumer := range a.consumers {
+		wg.Go(func() {
+			errs[i] = consumer.Start(ctx)
+		})
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// MemoryDedupStore is a DedupStore kept in memory, for active-active consumers running in
+// a single process. Fleets of several instances need a shared store such as Redis.
+type MemoryDedupStore struct {
+	mu      sync.Mutex
+	claims  map[string]time.Time // Expiry of each claim
+	sweepAt int                  // Number of claims triggering the next sweep of expired ones
+	clock   func() time.Time
+}
+
+// NewMemoryDedupStore creates an empty in-memory dedup store.
+func NewMemoryDedupStore() *MemoryDedupStore {
+	return &MemoryDedupStore{claims: map[string]time.Time{}, sweepAt: _memoryDedupSweepSize, clock: time.Now}
+}
+
+// Claim takes key for ttl unless it holds an unexpired claim.
+func (m *MemoryDedupStore) Claim(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.clock()
+	if expiry, ok := m.claims[key]; ok && now.Before(expiry) {
+		return false, nil
+	}
+
+	// Drop the expired claims whenever the store has doubled since the last sweep
+	if len(m.claims) >= m.sweepAt {
+		for claimed, expiry := range m.claims {
+			if !now.Before(expiry) {
+				delete(m.claims, claimed)
+			}
+		}
+		m.sweepAt = max(2*len(m.claims), _memoryDedupSweepSize)
+	}
+
+	m.claims[key] = now.Add(ttl)
+	return true, nil
+}
+
+// Release drops the claim on key.
+func (m *MemoryDedupStore) Release(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.claims, key)
+	return nil
+}
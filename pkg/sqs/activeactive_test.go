@@ -0,0 +1,74 @@
+package sqs
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+func TestActiveActiveHandlesEachEventOnce(t *testing.T) {
+	east, west := &fakeSQS{}, &fakeSQS{}
+	eastCopy, westCopy := testMessage("east-1", ""), testMessage("west-1", "")
+	eastCopy.Body = aws.String(`{"order":1,"total":10}`)
+	westCopy.Body = aws.String(`{"total": 10, "order": 1}`)
+	east.push(eastCopy)
+	west.push(westCopy)
+
+	var handled atomic.Int32
+	stream := NewActiveActive([]RegionQueue{
+		{Client: newTestSQS(east), QueueURL: "orders-east"},
+		{Client: newTestSQS(west), QueueURL: "orders-west"},
+	}, HandlerFunc(func(ctx context.Context, msg Message) error {
+		handled.Add(1)
+		return nil
+	}), NewMemoryDedupStore(), time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error, 1)
+	go func() {
+		result <- stream.Start(ctx)
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(east.deletedHandles())+len(west.deletedHandles()) < 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for both copies to be deleted")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+
+	if err := <-result; err != nil {
+		t.Errorf("Expected the consumers to stop cleanly, got %v", err)
+	}
+	if handled.Load() != 1 {
+		t.Errorf("Expected the replicated event to be handled once, got %d", handled.Load())
+	}
+}
+
+func TestMemoryDedupStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryDedupStore()
+	now := time.Now()
+	store.clock = func() time.Time { return now }
+
+	if claimed, _ := store.Claim(ctx, "a", time.Minute); !claimed {
+		t.Fatal("Expected the first claim to succeed")
+	}
+	if claimed, _ := store.Claim(ctx, "a", time.Minute); claimed {
+		t.Error("Expected a held claim to be refused")
+	}
+
+	_ = store.Release(ctx, "a")
+	if claimed, _ := store.Claim(ctx, "a", time.Minute); !claimed {
+		t.Error("Expected a released claim to be taken again")
+	}
+
+	now = now.Add(2 * time.Minute)
+	if claimed, _ := store.Claim(ctx, "a", time.Minute); !claimed {
+		t.Error("Expected an expired claim to be taken again")
+	}
+}
@@ -0,0 +1,90 @@
+package sqs
+
+import "time"
+
+// ActivityChange describes a queue entering a sustained idle period or becoming active
+// again.
+type ActivityChange struct {
+	// QueueURL is the queue whose activity changed.
+	QueueURL string
+	// Idle is true when the queue became idle, false when it became active again.
+	Idle bool
+	// Since is when the EWMA average decayed to zero (idle transitions) or when messages
+	// arrived again (active transitions).
+	Since time.Time
+	// IdleFor is how long the average stayed at zero before the queue became active again
+	// (zero for idle transitions).
+	IdleFor time.Duration
+}
+
+// WithOnActivityChange registers a callback fired when a queue becomes idle, i.e. its
+// EWMA average has been decayed to zero for at least idleAfter, and again when messages
+// arrive on an idle queue. Applications can use it to scale to zero, close database pools
+// or warm caches back up.
+//
+// Activity is only tracked while adaptive polling is enabled for the queue, since the EWMA
+// is not updated otherwise. A queue that never received a message becomes idle idleAfter
+// after its first poll. The callback runs on the polling goroutine and should return
+// quickly.
+//
+// Parameters:
+//   - idleAfter: How long the average must stay at zero before the queue is idle (e.g., 15 minutes)
+//   - callback: Function notified of each transition
+//
+// Example:
+//
+//	option := WithOnActivityChange(15*time.Minute, func(change ActivityChange) {
+//	    if change.Idle {
+//	        db.SetMaxIdleConns(0)
+//	    }
+//	})
+func WithOnActivityChange(idleAfter time.Duration, callback func(ActivityChange)) Option {
+	return func(c *config) {
+		c.IdleAfter = idleAfter
+		c.OnActivityChange = callback
+	}
+}
+
+// observeActivity updates the idle tracking of the queue with its current average. It
+// reports the transition and true when the queue became idle or active.
+func (a *arrakis) observeActivity(idleAfter time.Duration) (ActivityChange, bool) {
+	now := a.now()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.average == 0 {
+		if a.zeroSince.IsZero() {
+			a.zeroSince = now
+		}
+
+		if a.idle || now.Sub(a.zeroSince) < idleAfter {
+			return ActivityChange{}, false
+		}
+
+		a.idle = true
+		return ActivityChange{Idle: true, Since: a.zeroSince}, true
+	}
+
+	zeroSince := a.zeroSince
+	a.zeroSince = time.Time{}
+
+	if !a.idle {
+		return ActivityChange{}, false
+	}
+
+	a.idle = false
+	return ActivityChange{Since: now, IdleFor: now.Sub(zeroSince)}, true
+}
+
+// detectActivityChange runs idle tracking on a poll of queueURL and notifies the callback.
+func (s *SQS) detectActivityChange(state *arrakis, queueURL string) {
+	if s.config.OnActivityChange == nil || s.config.IdleAfter <= 0 {
+		return
+	}
+
+	if change, ok := state.observeActivity(s.config.IdleAfter); ok {
+		change.QueueURL = queueURL
+		s.config.OnActivityChange(change)
+	}
+}
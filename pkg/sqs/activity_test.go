@@ -0,0 +1,77 @@
+package sqs
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestObserveActivity(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	state := newArrakis(newTestSQS(&fakeSQS{}).config)
+	state.clock = func() time.Time { return now }
+
+	if _, changed := state.observeActivity(time.Minute); changed {
+		t.Error("Expected no transition as soon as the average is zero")
+	}
+
+	now = now.Add(time.Minute)
+	change, changed := state.observeActivity(time.Minute)
+	if !changed || !change.Idle || !change.Since.Equal(now.Add(-time.Minute)) {
+		t.Fatalf("Expected an idle transition since the average decayed, got %+v (%v)", change, changed)
+	}
+
+	now = now.Add(time.Minute)
+	if _, changed := state.observeActivity(time.Minute); changed {
+		t.Error("Expected a single idle notification")
+	}
+
+	state.average = 2
+	change, changed = state.observeActivity(time.Minute)
+	if !changed || change.Idle || change.IdleFor != 2*time.Minute {
+		t.Errorf("Expected an active transition after 2 minutes idle, got %+v (%v)", change, changed)
+	}
+
+	if _, changed := state.observeActivity(time.Minute); changed {
+		t.Error("Expected a single active notification")
+	}
+}
+
+func TestObserveActivityIgnoresShortPauses(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	state := newArrakis(newTestSQS(&fakeSQS{}).config)
+	state.clock = func() time.Time { return now }
+
+	state.observeActivity(time.Minute)
+	now = now.Add(30 * time.Second)
+	state.average = 1
+	if _, changed := state.observeActivity(time.Minute); changed {
+		t.Error("Expected no transition for a queue that was never idle")
+	}
+
+	state.average = 0
+	now = now.Add(45 * time.Second)
+	if _, changed := state.observeActivity(time.Minute); changed {
+		t.Error("Expected the idle period to restart when the average decays again")
+	}
+}
+
+func TestReceiveReportsActivityChange(t *testing.T) {
+	fake := &fakeSQS{}
+	var changes []ActivityChange
+	client := newTestSQS(fake, WithOnActivityChange(time.Nanosecond, func(change ActivityChange) {
+		changes = append(changes, change)
+	}))
+	client.EnableArrakis()
+
+	ctx := context.Background()
+	_, _ = client.ReceiveMessage(ctx, "queue", 10, nil)
+	_, _ = client.ReceiveMessage(ctx, "queue", 10, nil)
+
+	fake.push(testMessage("m1", ""))
+	_, _ = client.ReceiveMessage(ctx, "queue", 10, nil)
+
+	if len(changes) != 2 || !changes[0].Idle || changes[1].Idle || changes[0].QueueURL != "queue" {
+		t.Errorf("Expected an idle then an active transition, got %+v", changes)
+	}
+}
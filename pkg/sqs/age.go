@@ -0,0 +1,104 @@
+package sqs
+
+import (
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// WithMessageAgeThreshold makes message age an additional adaptation signal: when the
+// oldest message of a poll was sent more than threshold ago, the queue has a backlog that
+// per-poll counts alone can miss, and the shortest (very high volume) wait time is used
+// until a poll returns only fresh messages or no messages at all.
+//
+// SQS only publishes ApproximateAgeOfOldestMessage as a CloudWatch metric, not as a queue
+// attribute, so the age is sampled from the SentTimestamp of received messages instead;
+// the attribute is requested automatically.
+//
+// Parameters:
+//   - threshold: Message age above which polling speeds up (0 disables the signal)
+//
+// Example:
+//
+//	option := WithMessageAgeThreshold(2 * time.Minute)
+func WithMessageAgeThreshold(threshold time.Duration) Option {
+	return func(c *config) {
+		c.AdaptivePolling.MessageAgeThreshold = threshold
+	}
+}
+
+// WithMessageAgeTarget enables the message age driven adaptation mode: the wait time is
+// chosen from how far the oldest message of a poll is over target, not only from per-poll
+// counts. Each doubling of the age beyond target shortens the wait by one volume class
+// (older than target: one class, 2x target: two classes, and so on up to very high), so a
+// slowly growing backlog is polled faster even while each poll returns few messages.
+//
+// Unlike WithMessageAgeThreshold, which jumps straight to the shortest wait time, the
+// target scales the response with the delay. SentTimestamp is requested automatically.
+//
+// Parameters:
+//   - target: Message age the queue should stay under (0 disables the mode)
+//
+// Example:
+//
+//	option := WithMessageAgeTarget(30 * time.Second)
+func WithMessageAgeTarget(target time.Duration) Option {
+	return func(c *config) {
+		c.AdaptivePolling.MessageAgeTarget = target
+	}
+}
+
+// requestSentTimestamp makes sure a receive returns the SentTimestamp system attribute.
+func requestSentTimestamp(input *sqs.ReceiveMessageInput) {
+	names := input.MessageSystemAttributeNames
+	if slices.Contains(names, types.MessageSystemAttributeNameAll) || slices.Contains(names, types.MessageSystemAttributeNameSentTimestamp) {
+		return
+	}
+
+	input.MessageSystemAttributeNames = append(slices.Clone(names), types.MessageSystemAttributeNameSentTimestamp)
+}
+
+// observeAge records the age of the oldest message of a poll. Polls without a
+// usable timestamp clear the signal.
+func (a *arrakis) observeAge(messages []types.Message, now time.Time) {
+	var oldest time.Duration
+	for _, m := range messages {
+		if sent := parseEpochMillis(m.Attributes[_attributeSentTimestamp]); !sent.IsZero() {
+			oldest = max(oldest, now.Sub(sent))
+		}
+	}
+
+	atomic.StoreInt64(&a.oldestAge, int64(oldest))
+}
+
+// oldestMessageAge returns the age of the oldest message seen by the last poll.
+func (a *arrakis) oldestMessageAge() time.Duration {
+	return time.Duration(atomic.LoadInt64(&a.oldestAge))
+}
+
+// agedClass raises a volume class to VolumeVeryHigh while messages are older than the
+// threshold (a zero threshold disables the signal).
+func agedClass(class VolumeClass, oldestAge, threshold time.Duration) VolumeClass {
+	if threshold > 0 && oldestAge > threshold {
+		return VolumeVeryHigh
+	}
+
+	return class
+}
+
+// targetClass raises a volume class by one level for each doubling of the message age
+// beyond target, up to VolumeVeryHigh (a zero target disables the mode).
+func targetClass(class VolumeClass, oldestAge, target time.Duration) VolumeClass {
+	if target <= 0 {
+		return class
+	}
+
+	for limit := target; oldestAge > limit && class < VolumeVeryHigh; limit *= 2 {
+		class++
+	}
+
+	return class
+}
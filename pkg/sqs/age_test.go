@@ -0,0 +1,97 @@
+package sqs
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+func agedMessage(id string, age time.Duration) types.Message {
+	m := testMessage(id, "")
+	m.Attributes[_attributeSentTimestamp] = strconv.FormatInt(time.Now().Add(-age).UnixMilli(), 10)
+
+	return m
+}
+
+func TestAgedClass(t *testing.T) {
+	if class := agedClass(VolumeLow, time.Minute, 0); class != VolumeLow {
+		t.Errorf("Expected a zero threshold to disable the signal, got %s", class)
+	}
+
+	if class := agedClass(VolumeLow, 30*time.Second, time.Minute); class != VolumeLow {
+		t.Errorf("Expected fresh messages to keep the class, got %s", class)
+	}
+
+	if class := agedClass(VolumeLow, 2*time.Minute, time.Minute); class != VolumeVeryHigh {
+		t.Errorf("Expected aging messages to raise the class, got %s", class)
+	}
+}
+
+func TestTargetClass(t *testing.T) {
+	cases := []struct {
+		age  time.Duration
+		want VolumeClass
+	}{
+		{age: 20 * time.Second, want: VolumeLow},
+		{age: 40 * time.Second, want: VolumeMedium},
+		{age: 90 * time.Second, want: VolumeHigh},
+		{age: 10 * time.Minute, want: VolumeVeryHigh},
+	}
+
+	for _, c := range cases {
+		if class := targetClass(VolumeLow, c.age, 30*time.Second); class != c.want {
+			t.Errorf("Expected %s for an age of %s, got %s", c.want, c.age, class)
+		}
+	}
+
+	if class := targetClass(VolumeLow, time.Hour, 0); class != VolumeLow {
+		t.Errorf("Expected a zero target to disable the mode, got %s", class)
+	}
+}
+
+func TestMessageAgeTargetShortensWaits(t *testing.T) {
+	fake := &fakeSQS{}
+	fake.push(agedMessage("m1", 45*time.Second))
+	client := newTestSQS(fake, WithMessageAgeTarget(30*time.Second))
+	client.EnableArrakis()
+
+	_, _ = client.ReceiveMessage(context.Background(), "queue", 10, nil)
+	_, _ = client.ReceiveMessage(context.Background(), "queue", 10, nil)
+
+	if !slices.Contains(fake.receiveInputs[0].MessageSystemAttributeNames, types.MessageSystemAttributeNameSentTimestamp) {
+		t.Error("Expected SentTimestamp to be requested")
+	}
+
+	// A single message keeps the queue low volume; its age raises it one class
+	if wait := fake.receiveInputs[1].WaitTimeSeconds; wait != _defaultMediumVolumeWaitTimeSeconds {
+		t.Errorf("Expected the medium volume wait time, got %d", wait)
+	}
+}
+
+func TestMessageAgeSpeedsUpPolling(t *testing.T) {
+	fake := &fakeSQS{}
+	fake.push(agedMessage("m1", 10*time.Minute))
+	client := newTestSQS(fake, WithMessageAgeThreshold(time.Minute))
+	client.EnableArrakis()
+
+	_, _ = client.ReceiveMessage(context.Background(), "queue", 10, nil)
+	_, _ = client.ReceiveMessage(context.Background(), "queue", 10, nil)
+	_, _ = client.ReceiveMessage(context.Background(), "queue", 10, nil)
+
+	if !slices.Contains(fake.receiveInputs[0].MessageSystemAttributeNames, types.MessageSystemAttributeNameSentTimestamp) {
+		t.Error("Expected SentTimestamp to be requested")
+	}
+
+	if wait := fake.receiveInputs[1].WaitTimeSeconds; wait != _defaultVeryHighVolumeWaitTimeSeconds {
+		t.Errorf("Expected the shortest wait time after an old message, got %d", wait)
+	}
+
+	// The empty second poll clears the signal
+	if wait := fake.receiveInputs[2].WaitTimeSeconds; wait == _defaultVeryHighVolumeWaitTimeSeconds {
+		t.Errorf("Expected the age signal to clear after an empty poll, got %d", wait)
+	}
+}
@@ -0,0 +1,63 @@
+package sqs
+
+import "math"
+
+// Adaptive alpha configuration values
+const (
+	_adaptiveAlphaSmoothing = 0.2 // Smoothing factor of the tracked prediction errors
+)
+
+// WithAdaptiveEwmaAlpha replaces the fixed EWMA smoothing factor (see WithEwmaAlpha) with
+// one tuned per queue from the recent prediction errors of the average: alpha moves
+// towards maxAlpha while the errors keep the same sign, i.e. traffic is trending and the
+// average lags behind, and towards minAlpha while they cancel out, i.e. traffic is stable
+// and only noise is left. This is the tracking signal of Trigg and Leach: alpha is the
+// smoothed error over the smoothed absolute error.
+//
+// Parameters:
+//   - minAlpha: Smoothing factor used while traffic is stable (typically 0.1)
+//   - maxAlpha: Smoothing factor used while traffic is changing (typically 0.8)
+//
+// Example:
+//
+//	client := sqs.NewSQSWithOptions(&cfg, sqs.WithAdaptiveEwmaAlpha(0.1, 0.8))
+func WithAdaptiveEwmaAlpha(minAlpha, maxAlpha float64) Option {
+	return func(c *config) {
+		minAlpha = math.Max(math.Min(minAlpha, 1), 0)
+		maxAlpha = math.Max(math.Min(maxAlpha, 1), minAlpha)
+		c.AdaptivePolling.AdaptiveAlphaMin = minAlpha
+		c.AdaptivePolling.AdaptiveAlphaMax = maxAlpha
+	}
+}
+
+// alphaFor returns the smoothing factor of the next EWMA update, observing count: the
+// configured one, or the one tuned from the prediction errors with WithAdaptiveEwmaAlpha.
+// Must be called with the mutex held.
+func (a *arrakis) alphaFor(count float64) float64 {
+	settings := a.config.adaptivePolling()
+	if settings.AdaptiveAlphaMax <= 0 {
+		return a.ewmaAlpha
+	}
+
+	predictionError := count - a.average
+	a.smoothedError = _adaptiveAlphaSmoothing*predictionError + (1-_adaptiveAlphaSmoothing)*a.smoothedError
+	a.smoothedAbsError = _adaptiveAlphaSmoothing*math.Abs(predictionError) + (1-_adaptiveAlphaSmoothing)*a.smoothedAbsError
+
+	alpha := settings.AdaptiveAlphaMin
+	if a.smoothedAbsError > 0 {
+		alpha = math.Abs(a.smoothedError) / a.smoothedAbsError
+	}
+	a.alpha = math.Max(settings.AdaptiveAlphaMin, math.Min(alpha, settings.AdaptiveAlphaMax))
+
+	return a.alpha
+}
+
+// currentAlpha returns the smoothing factor of the last EWMA update. Must be called with
+// the mutex held.
+func (a *arrakis) currentAlpha() float64 {
+	if a.alpha > 0 && a.config.adaptivePolling().AdaptiveAlphaMax > 0 {
+		return a.alpha
+	}
+
+	return a.ewmaAlpha
+}
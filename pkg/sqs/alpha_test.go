@@ -0,0 +1,41 @@
+package sqs
+
+import "testing"
+
+func TestAdaptiveEwmaAlpha(t *testing.T) {
+	client := newTestSQS(&fakeSQS{}, WithAdaptiveEwmaAlpha(0.1, 0.8))
+	state := client.state("queue")
+
+	// Alternating traffic: the errors cancel out, so alpha settles at its minimum
+	for i := range 20 {
+		state.updateMessageCount(4 + 2*(i%2))
+	}
+	state.mu.RLock()
+	stable := state.currentAlpha()
+	state.mu.RUnlock()
+	if stable > 0.2 {
+		t.Errorf("Expected a low alpha on stable traffic, got %v", stable)
+	}
+
+	// Rising traffic: the errors keep their sign, so alpha rises
+	for count := 6; count <= 10; count++ {
+		state.updateMessageCount(count)
+	}
+	state.mu.RLock()
+	trending := state.currentAlpha()
+	state.mu.RUnlock()
+	if trending < 0.5 || trending > 0.8 {
+		t.Errorf("Expected a high alpha, bounded by 0.8, on changing traffic, got %v", trending)
+	}
+}
+
+func TestFixedEwmaAlphaByDefault(t *testing.T) {
+	state := newTestSQS(&fakeSQS{}, WithEwmaAlpha(0.5)).state("queue")
+
+	state.updateMessageCount(4)
+	state.updateMessageCount(4)
+
+	if got := state.currentAverage(); got != 3 {
+		t.Errorf("Expected the fixed alpha to move the average halfway twice, got %v", got)
+	}
+}
@@ -0,0 +1,158 @@
+package sqs
+
+import (
+	"math"
+
+	"github.com/elissonalvesilva/arrakis/pkg/internal/infra/utils"
+)
+
+// Anomaly detection defaults
+const (
+	_defaultAnomalyWindow   = 60 // Polls kept to compute the rolling mean and variance
+	_minAnomalySamples      = 10 // Polls observed before anomalies are reported
+	_minAnomalyStdDeviation = 1  // Floor of the standard deviation, in messages per poll
+)
+
+// AnomalyKind tells whether an anomaly is an unusually high or low volume.
+type AnomalyKind int
+
+// Anomaly kinds.
+const (
+	// AnomalySurge means far more messages than usual
+	AnomalySurge AnomalyKind = iota + 1
+	// AnomalySilence means far fewer messages than usual, e.g. a producer stopped
+	AnomalySilence
+)
+
+// String returns the lowercase name of the anomaly kind.
+func (k AnomalyKind) String() string {
+	switch k {
+	case AnomalySurge:
+		return "surge"
+	case AnomalySilence:
+		return "silence"
+	default:
+		return "unknown"
+	}
+}
+
+// Anomaly describes a poll whose size is statistically unusual for its queue.
+type Anomaly struct {
+	// QueueURL is the queue the anomaly was observed on.
+	QueueURL string
+	// Kind is AnomalySurge or AnomalySilence.
+	Kind AnomalyKind
+	// Observed is the number of messages returned by the anomalous poll.
+	Observed int
+	// Mean and StdDeviation describe the recent polls of the queue.
+	Mean         float64
+	StdDeviation float64
+	// ZScore is how many standard deviations Observed is from Mean.
+	ZScore float64
+}
+
+// WithAnomalyDetection registers a hook notified when the size of a poll is statistically
+// unusual: its z-score against the rolling mean and variance of the recent polls (60 by
+// default, see WithAnomalyWindow) reaches threshold. Surges (z >= threshold) and silences
+// (z <= -threshold, e.g. a producer that died) are both reported, once per episode; the
+// volume has to return to normal before the same kind is reported again.
+//
+// Nothing is reported until 10 polls have been observed, and the standard deviation is
+// taken as at least one message per poll so perfectly steady queues don't flag every
+// small change. Detection runs whether or not adaptive polling is enabled. The hook runs
+// on the polling goroutine and should return quickly.
+//
+// Parameters:
+//   - threshold: Minimum absolute z-score of an anomaly (e.g., 3)
+//   - hook: Function notified of each anomaly
+//
+// Example:
+//
+//	option := WithAnomalyDetection(3, func(anomaly Anomaly) {
+//	    alert("%s on %s: %d messages (mean %.1f)", anomaly.Kind, anomaly.QueueURL, anomaly.Observed, anomaly.Mean)
+//	})
+func WithAnomalyDetection(threshold float64, hook func(Anomaly)) Option {
+	return func(c *config) {
+		c.AnomalyThreshold = threshold
+		c.OnAnomaly = hook
+	}
+}
+
+// WithAnomalyWindow sets how many recent polls the anomaly detection statistics cover
+// (default 60).
+func WithAnomalyWindow(polls int) Option {
+	return func(c *config) {
+		c.AnomalyWindow = polls
+	}
+}
+
+// anomalyDetector keeps the recent poll sizes of a queue (protected by the arrakis mutex).
+type anomalyDetector struct {
+	samples *utils.Ring[int]
+	active  AnomalyKind // Kind of the ongoing anomaly episode, 0 when volume is normal
+}
+
+// observeAnomaly scores a poll against the recent polls, then records it. It reports the
+// anomaly and true when the poll starts a new anomaly episode.
+func (a *arrakis) observeAnomaly(observed int, threshold float64, window int) (Anomaly, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.anomalies.samples == nil {
+		a.anomalies.samples = utils.NewRing[int](max(window, _minAnomalySamples))
+	}
+	defer a.anomalies.samples.Push(observed)
+
+	samples := a.anomalies.samples.Items()
+	if len(samples) < _minAnomalySamples {
+		return Anomaly{}, false
+	}
+
+	mean, deviation := meanAndStdDeviation(samples)
+	z := (float64(observed) - mean) / max(deviation, _minAnomalyStdDeviation)
+
+	var kind AnomalyKind
+	switch {
+	case z >= threshold:
+		kind = AnomalySurge
+	case z <= -threshold:
+		kind = AnomalySilence
+	}
+
+	previous := a.anomalies.active
+	a.anomalies.active = kind
+	if kind == 0 || kind == previous {
+		return Anomaly{}, false
+	}
+
+	return Anomaly{Kind: kind, Observed: observed, Mean: mean, StdDeviation: deviation, ZScore: z}, true
+}
+
+// meanAndStdDeviation computes the mean and population standard deviation of samples.
+func meanAndStdDeviation(samples []int) (float64, float64) {
+	var sum float64
+	for _, sample := range samples {
+		sum += float64(sample)
+	}
+	mean := sum / float64(len(samples))
+
+	var squares float64
+	for _, sample := range samples {
+		diff := float64(sample) - mean
+		squares += diff * diff
+	}
+
+	return mean, math.Sqrt(squares / float64(len(samples)))
+}
+
+// detectAnomaly runs anomaly detection on a poll of queueURL and notifies the hook.
+func (s *SQS) detectAnomaly(state *arrakis, queueURL string, observed int) {
+	if s.config.OnAnomaly == nil || s.config.AnomalyThreshold <= 0 {
+		return
+	}
+
+	if anomaly, ok := state.observeAnomaly(observed, s.config.AnomalyThreshold, s.config.AnomalyWindow); ok {
+		anomaly.QueueURL = queueURL
+		s.config.OnAnomaly(anomaly)
+	}
+}
@@ -0,0 +1,79 @@
+package sqs
+
+import (
+	"context"
+	"testing"
+)
+
+// steadyState returns a queue state that observed 20 polls of around 10 messages.
+func steadyState(t *testing.T) *arrakis {
+	state := newArrakis(newTestSQS(&fakeSQS{}).config)
+
+	for i := 0; i < 20; i++ {
+		if _, ok := state.observeAnomaly(9+i%3, 3, 60); ok {
+			t.Fatalf("Expected no anomaly on steady traffic (poll %d)", i)
+		}
+	}
+
+	return state
+}
+
+func TestObserveAnomalySurge(t *testing.T) {
+	state := steadyState(t)
+
+	anomaly, ok := state.observeAnomaly(40, 3, 60)
+	if !ok || anomaly.Kind != AnomalySurge || anomaly.ZScore < 3 {
+		t.Errorf("Expected a surge, got %+v (%v)", anomaly, ok)
+	}
+
+	if _, ok := state.observeAnomaly(40, 3, 60); ok {
+		t.Error("Expected a single notification per episode")
+	}
+}
+
+func TestObserveAnomalySilence(t *testing.T) {
+	state := steadyState(t)
+
+	anomaly, ok := state.observeAnomaly(0, 3, 60)
+	if !ok || anomaly.Kind != AnomalySilence || anomaly.ZScore > -3 {
+		t.Errorf("Expected a silence, got %+v (%v)", anomaly, ok)
+	}
+}
+
+func TestObserveAnomalyNeedsSamples(t *testing.T) {
+	state := newArrakis(newTestSQS(&fakeSQS{}).config)
+
+	for i := 0; i < _minAnomalySamples-1; i++ {
+		_, _ = state.observeAnomaly(0, 3, 60)
+	}
+
+	if _, ok := state.observeAnomaly(100, 3, 60); ok {
+		t.Error("Expected no anomaly before enough samples are collected")
+	}
+}
+
+func TestReceiveReportsAnomalies(t *testing.T) {
+	fake := &fakeSQS{}
+	var anomalies []Anomaly
+	client := newTestSQS(fake, WithAnomalyDetection(3, func(anomaly Anomaly) {
+		anomalies = append(anomalies, anomaly)
+	}))
+
+	state := client.state("queue")
+	for i := 0; i < _minAnomalySamples; i++ {
+		_, _ = state.observeAnomaly(0, 3, 60)
+	}
+
+	fake.push(testMessage("m1", ""), testMessage("m2", ""), testMessage("m3", ""), testMessage("m4", ""))
+	if _, err := client.ReceiveMessage(context.Background(), "queue", 10, nil); err != nil {
+		t.Fatalf("ReceiveMessage returned error: %v", err)
+	}
+
+	if len(anomalies) != 1 || anomalies[0].QueueURL != "queue" || anomalies[0].Kind != AnomalySurge {
+		t.Errorf("Expected a surge on queue, got %+v", anomalies)
+	}
+
+	if AnomalySilence.String() != "silence" {
+		t.Errorf("Expected silence, got %s", AnomalySilence)
+	}
+}
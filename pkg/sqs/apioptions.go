@@ -0,0 +1,46 @@
+package sqs
+
+import (
+	"context"
+	"slices"
+
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+)
+
+// apiOptionsKey is the context key of the AWS SDK options set with WithAPIOptions.
+type apiOptionsKey struct{}
+
+// WithAPIOptions returns a copy of ctx carrying AWS SDK options applied to every SQS API
+// call made with it, as the optFns of the raw SDK allow: custom endpoints, request
+// signing tweaks, middleware, retryer overrides. Receives (adaptive or not, consumers,
+// TryReceive, drains, peeks), deletes, visibility changes, batch calls and sends all
+// honor them, so a single call can be customized without building another client.
+// Options set on a parent context are kept, and applied first.
+//
+// Parameters:
+//   - ctx: The parent context
+//   - optFns: The AWS SDK options to apply to the calls made with the returned context
+//
+// Returns:
+//   - context.Context: The context carrying the options
+//
+// Example:
+//
+//	ctx = sqs.WithAPIOptions(ctx, func(o *awssqs.Options) {
+//	    o.BaseEndpoint = aws.String("https://sqs.eu-west-1.amazonaws.com")
+//	})
+//	messages, err := sqsClient.ReceiveMessages(ctx, queueURL, 10)
+func WithAPIOptions(ctx context.Context, optFns ...func(*sqs.Options)) context.Context {
+	if len(optFns) == 0 {
+		return ctx
+	}
+
+	return context.WithValue(ctx, apiOptionsKey{}, append(apiOptions(ctx), optFns...))
+}
+
+// apiOptions returns the AWS SDK options carried by ctx. The slice can be appended to
+// without affecting ctx.
+func apiOptions(ctx context.Context) []func(*sqs.Options) {
+	optFns, _ := ctx.Value(apiOptionsKey{}).([]func(*sqs.Options))
+	return slices.Clip(optFns)
+}
@@ -0,0 +1,100 @@
+package sqs
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+)
+
+// optionsSQS is a fakeSQS recording the AppID set by the AWS SDK options of each call.
+type optionsSQS struct {
+	*fakeSQS
+
+	mu     sync.Mutex
+	appIDs map[string][]string // AppIDs by operation
+}
+
+func (o *optionsSQS) record(operation string, optFns []func(*sqs.Options)) {
+	var options sqs.Options
+	for _, fn := range optFns {
+		fn(&options)
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.appIDs == nil {
+		o.appIDs = map[string][]string{}
+	}
+	o.appIDs[operation] = append(o.appIDs[operation], options.AppID)
+}
+
+func (o *optionsSQS) ReceiveMessage(ctx context.Context, params *sqs.ReceiveMessageInput, optFns ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error) {
+	o.record("ReceiveMessage", optFns)
+	return o.fakeSQS.ReceiveMessage(ctx, params, optFns...)
+}
+
+func (o *optionsSQS) DeleteMessage(ctx context.Context, params *sqs.DeleteMessageInput, optFns ...func(*sqs.Options)) (*sqs.DeleteMessageOutput, error) {
+	o.record("DeleteMessage", optFns)
+	return o.fakeSQS.DeleteMessage(ctx, params, optFns...)
+}
+
+func (o *optionsSQS) ChangeMessageVisibilityBatch(ctx context.Context, params *sqs.ChangeMessageVisibilityBatchInput, optFns ...func(*sqs.Options)) (*sqs.ChangeMessageVisibilityBatchOutput, error) {
+	o.record("ChangeMessageVisibilityBatch", optFns)
+	return o.fakeSQS.ChangeMessageVisibilityBatch(ctx, params, optFns...)
+}
+
+func (o *optionsSQS) SendMessage(ctx context.Context, params *sqs.SendMessageInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageOutput, error) {
+	o.record("SendMessage", optFns)
+	return o.fakeSQS.SendMessage(ctx, params, optFns...)
+}
+
+func (o *optionsSQS) SendMessageBatch(ctx context.Context, params *sqs.SendMessageBatchInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageBatchOutput, error) {
+	o.record("SendMessageBatch", optFns)
+	return o.fakeSQS.SendMessageBatch(ctx, params, optFns...)
+}
+
+func TestWithAPIOptions(t *testing.T) {
+	api := &optionsSQS{fakeSQS: &fakeSQS{}}
+	api.push(testMessage("m1", ""))
+	client := newTestSQS(api, WithMaxReceiveCallsPerSecond(100))
+	setAppID := func(appID string) func(*sqs.Options) {
+		return func(o *sqs.Options) { o.AppID = appID }
+	}
+
+	ctx := WithAPIOptions(context.Background(), setAppID("ctx"))
+
+	if _, err := client.ReceiveMessages(ctx, "queue", 1); err != nil {
+		t.Fatalf("ReceiveMessages returned error: %v", err)
+	}
+	if _, err := client.DeleteMessage(ctx, "queue", "m1", setAppID("call")); err != nil {
+		t.Fatalf("DeleteMessage returned error: %v", err)
+	}
+	if _, err := client.changeVisibilityBatch(ctx, "queue", []Message{{ID: "m1", ReceiptHandle: "m1"}}, 0); err != nil {
+		t.Fatalf("changeVisibilityBatch returned error: %v", err)
+	}
+	if _, err := client.SendMessage(ctx, "queue", "body"); err != nil {
+		t.Fatalf("SendMessage returned error: %v", err)
+	}
+	if _, err := NewProducer(client, "queue").SendBatch(ctx, []OutgoingMessage{{ID: "1", Body: "body"}}); err != nil {
+		t.Fatalf("SendBatch returned error: %v", err)
+	}
+
+	expected := map[string]string{
+		"ReceiveMessage":               "ctx",
+		"DeleteMessage":                "call", // Per-call options apply after the context ones
+		"ChangeMessageVisibilityBatch": "ctx",
+		"SendMessage":                  "ctx",
+		"SendMessageBatch":             "ctx",
+	}
+	for operation, appID := range expected {
+		if got := api.appIDs[operation]; len(got) != 1 || got[0] != appID {
+			t.Errorf("Expected %s called with AppID %q, got %q", operation, appID, got)
+		}
+	}
+
+	if got := apiOptions(context.Background()); len(got) != 0 {
+		t.Errorf("Expected no options without WithAPIOptions, got %d", len(got))
+	}
+}
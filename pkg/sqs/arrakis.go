@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/elissonalvesilva/arrakis/pkg/internal/infra/utils"
 )
 
 // arrakis contains the state and algorithm implementation for adaptive SQS polling.
@@ -20,29 +21,122 @@ import (
 // 4. Selecting appropriate wait times based on volume classification
 // 5. Implementing decay mechanisms for idle periods
 // 6. Detecting volume drops and resetting when appropriate
+//
+// Each queue polled by a client has its own arrakis state, so queues with different
+// traffic patterns adapt independently.
 type arrakis struct {
 	// mu protects concurrent access to the EWMA calculation and state updates
 	mu sync.RWMutex
 
+	// config is the client configuration shared by every queue state
+	config *config
+
 	// Atomic counters for thread-safe message tracking
 	messageCount    int64 // Current message count from last polling operation
 	lastUpdate      int64 // Unix timestamp of last update
 	messageSum      int64 // Cumulative sum of messages (used for statistics)
 	messageCounts   int64 // Number of polling operations performed
 	windowStartTime int64 // Start time of current measurement window
+	lastWaitTime    int64 // Wait time (seconds) used by the last adaptive ReceiveMessage call
+	inFlight        int64 // Messages received locally but not yet deleted or released
+	capacity        int64 // Messages that can be processed concurrently (0 when unknown)
+	oldestAge       int64 // Age (nanoseconds) of the oldest message returned by the last poll
+	slowHandlers    int64 // Handler runs that exceeded the slow handler threshold
 
 	// EWMA calculation state (protected by mutex)
-	average          float64   // Current EWMA average of message volume
-	lowVolumeCycle   int       // Counter of consecutive low-volume cycles
-	lastReceiveEmpty time.Time // Timestamp of last empty response
-	lastReset        time.Time // Timestamp of last EWMA reset
+	average          float64          // Current EWMA average of message volume
+	lowVolumeCycle   int              // Counter of consecutive low-volume cycles
+	lastReceiveEmpty time.Time        // Timestamp of last empty response
+	lastReset        time.Time        // Timestamp of last EWMA reset
+	class            VolumeClass      // Volume class, following the average with hysteresis (see observeClass)
+	events           AlgorithmEvents  // Counters of algorithm events
+	reportedEvents   AlgorithmEvents  // Counters of algorithm events already recorded as metrics
+	override         *bool            // Per-queue enable/disable, nil follows the client setting
+	profile          QueueProfile     // Learned traffic per hour of the week
+	spikePolls       int              // Consecutive polls above the volume spike threshold
+	anomalies        anomalyDetector  // Recent poll sizes for anomaly detection
+	zeroSince        time.Time        // When the average last decayed to zero, zero while active
+	idle             bool             // Whether the queue was reported idle
+	nextPoll         time.Time        // Earliest time of the next paced back-to-back poll
+	clock            func() time.Time // Source of the current time, nil uses time.Now
+	polls            pollSet          // Receives in progress, cancelled when polling is disabled
+
+	// decisions keeps the most recent wait time decisions (protected by mutex)
+	decisions *utils.Ring[Decision]
+	// latencies keeps the most recent delivery latencies (protected by mutex)
+	latencies *utils.Ring[time.Duration]
 
 	// Algorithm configuration (set during initialization)
 	dropDetectionThreshold   int64   // Threshold for detecting volume drops
 	ewmaAlpha                float64 // EWMA smoothing factor
+	alpha                    float64 // Last smoothing factor tuned by WithAdaptiveEwmaAlpha
+	smoothedError            float64 // Smoothed prediction error of the average, for the adaptive alpha
+	smoothedAbsError         float64 // Smoothed absolute prediction error of the average
+	variance                 float64 // Exponentially weighted variance of the message counts
 	consecutiveEmptyMessages int64   // Counter of consecutive empty responses
 }
 
+// newArrakis creates the adaptive polling state of a queue, taking the algorithm
+// settings from the client configuration.
+func newArrakis(config *config) *arrakis {
+	settings := config.adaptivePolling()
+
+	return &arrakis{
+		config:                 config,
+		ewmaAlpha:              settings.EwmaAlpha,
+		dropDetectionThreshold: int64(settings.DropDetectionThreshold),
+		clock:                  config.Clock,
+		decisions:              utils.NewRing[Decision](_decisionHistorySize),
+		latencies:              utils.NewRing[time.Duration](_latencySampleSize),
+	}
+}
+
+// now returns the current time of the algorithm. It is the wall clock, except in
+// simulations which replay recorded traffic on a virtual clock and with WithClock.
+func (a *arrakis) now() time.Time {
+	if a.clock != nil {
+		return a.clock()
+	}
+
+	return time.Now()
+}
+
+// state returns the adaptive polling state of a queue, creating it on first use.
+//
+// Parameters:
+//   - queueURL: The URL of the queue
+//
+// Returns:
+//   - *arrakis: The state tracking this queue's message volume
+func (s *SQS) state(queueURL string) *arrakis {
+	s.statesMu.RLock()
+	state, ok := s.states[queueURL]
+	s.statesMu.RUnlock()
+
+	if ok {
+		return state
+	}
+
+	s.statesMu.Lock()
+	defer s.statesMu.Unlock()
+
+	if state, ok = s.states[queueURL]; !ok {
+		state = newArrakis(s.config)
+		s.states[queueURL] = state
+	}
+
+	return state
+}
+
+// lookupState returns the adaptive polling state of a queue without creating it.
+func (s *SQS) lookupState(queueURL string) (*arrakis, bool) {
+	s.statesMu.RLock()
+	defer s.statesMu.RUnlock()
+
+	state, ok := s.states[queueURL]
+	return state, ok
+}
+
 // updateMessageCount processes a new message count observation and updates the EWMA algorithm state.
 // This method is called after each polling operation to incorporate the new message count
 // into the adaptive polling algorithm's calculations.
@@ -55,33 +149,35 @@ type arrakis struct {
 //
 // Parameters:
 //   - messageCount: Number of messages received in the current polling operation
-func (s *SQS) updateMessageCount(messageCount int) {
-	now := time.Now().Unix()
+func (a *arrakis) updateMessageCount(messageCount int) {
+	now := a.now().Unix()
 
 	// Update atomic counters for thread-safe access
-	atomic.StoreInt64(&s.config.arrakis.messageCount, int64(messageCount))
-	atomic.StoreInt64(&s.config.arrakis.lastUpdate, now)
-	atomic.StoreInt64(&s.config.arrakis.messageSum, int64(messageCount))
-	atomic.StoreInt64(&s.config.arrakis.messageCounts, 1)
+	atomic.StoreInt64(&a.messageCount, int64(messageCount))
+	atomic.StoreInt64(&a.lastUpdate, now)
+	atomic.StoreInt64(&a.messageSum, int64(messageCount))
+	atomic.StoreInt64(&a.messageCounts, 1)
 
 	// Protect EWMA calculation with mutex
-	defer s.config.arrakis.mu.Unlock()
-	s.config.arrakis.mu.Lock()
+	defer a.mu.Unlock()
+	a.mu.Lock()
 
 	// Update EWMA with new observation
-	s.config.arrakis.average = s.calculateAverage(messageCount)
+	a.average = a.calculateAverage(messageCount)
 
 	// Track low-volume cycles for drop detection
-	if messageCount < _lowVolumeMessageThreshold {
-		s.config.arrakis.lowVolumeCycle++
+	if messageCount < a.config.adaptivePolling().LowVolumeMessageThreshold {
+		a.lowVolumeCycle++
 		// Check if we should reset EWMA due to sustained low volume
-		if s.shouldResetEWMA() {
-			s.resetEWMA()
+		if a.shouldResetEWMA() {
+			a.resetEWMA()
 		}
 	} else {
 		// Reset low-volume cycle counter on higher volume
-		s.config.arrakis.lowVolumeCycle = 0
+		a.lowVolumeCycle = 0
 	}
+
+	a.observeClass()
 }
 
 // calculateAverage computes the new EWMA (Exponentially Weighted Moving Average) value
@@ -99,22 +195,25 @@ func (s *SQS) updateMessageCount(messageCount int) {
 //
 // Returns:
 //   - float64: The updated EWMA average
-func (s *SQS) calculateAverage(messageCount int) float64 {
+func (a *arrakis) calculateAverage(messageCount int) float64 {
 	count := float64(messageCount)
 
 	// Apply spike protection if we have an existing average
-	if s.config.arrakis.average > 0 {
-		delta := count - s.config.arrakis.average
-		maxDelta := s.config.arrakis.average * 2 // Allow maximum 200% increase per update
+	if a.average > 0 {
+		delta := count - a.average
+		maxDelta := a.spikeLimit() // Allow 200% increase per update, more on noisy queues
 		if delta > maxDelta {
-			count = s.config.arrakis.average + maxDelta
+			count = a.average + maxDelta
+			a.events.SpikeClamps++
 		}
 	}
 
 	// Calculate EWMA: α * current + (1-α) * previous
-	s.config.arrakis.average = s.config.arrakis.ewmaAlpha*count + (1.0-s.config.arrakis.ewmaAlpha)*s.config.arrakis.average
+	alpha := a.alphaFor(count)
+	a.updateVariance(count-a.average, alpha)
+	a.average = alpha*count + (1.0-alpha)*a.average
 
-	return s.config.arrakis.average
+	return a.average
 }
 
 // shouldResetEWMA determines whether the EWMA should be reset due to sustained low volume.
@@ -128,12 +227,12 @@ func (s *SQS) calculateAverage(messageCount int) float64 {
 //
 // Returns:
 //   - bool: true if EWMA should be reset, false otherwise
-func (s *SQS) shouldResetEWMA() bool {
-	ewmaConfig := &s.config.arrakis
+func (a *arrakis) shouldResetEWMA() bool {
+	settings := a.config.adaptivePolling()
 
-	hasEnoughLowVolumeCycles := ewmaConfig.lowVolumeCycle >= s.config.AdaptivePolling.DropDetectionThreshold
-	isAverageBelowThreshold := ewmaConfig.average < _ewmaResetAverageThreshold
-	hasMinimumTimePassed := time.Since(ewmaConfig.lastReset) > _minResetIntervalMinutes*time.Minute
+	hasEnoughLowVolumeCycles := a.lowVolumeCycle >= settings.DropDetectionThreshold
+	isAverageBelowThreshold := a.average < settings.EwmaResetAverageThreshold
+	hasMinimumTimePassed := a.now().Sub(a.lastReset) > settings.MinResetInterval
 
 	return hasEnoughLowVolumeCycles && isAverageBelowThreshold && hasMinimumTimePassed
 }
@@ -146,10 +245,12 @@ func (s *SQS) shouldResetEWMA() bool {
 // 1. Sets the EWMA average to zero (fresh start)
 // 2. Resets the low-volume cycle counter
 // 3. Records the reset timestamp to prevent frequent resets
-func (s *SQS) resetEWMA() {
-	s.config.arrakis.average = 0
-	s.config.arrakis.lowVolumeCycle = 0
-	s.config.arrakis.lastReset = time.Now()
+func (a *arrakis) resetEWMA() {
+	a.average = 0
+	a.variance = 0
+	a.lowVolumeCycle = 0
+	a.lastReset = a.now()
+	a.events.EWMAResets++
 }
 
 // handleReceiveResponse processes the result of a ReceiveMessage operation and updates
@@ -161,11 +262,15 @@ func (s *SQS) resetEWMA() {
 //
 // Parameters:
 //   - res: The SQS ReceiveMessage response to process
-func (s *SQS) handleReceiveResponse(res *sqs.ReceiveMessageOutput) {
+func (a *arrakis) handleReceiveResponse(res *sqs.ReceiveMessageOutput) {
+	if a.enabled() {
+		a.recordProfile(a.now(), len(res.Messages))
+	}
+
 	if len(res.Messages) == 0 {
-		s.handleEmptyResponse()
-	} else if s.IsArrakisEnabled() {
-		s.handleNonEmptyResponse(len(res.Messages))
+		a.handleEmptyResponse()
+	} else if a.enabled() {
+		a.handleNonEmptyResponse(len(res.Messages))
 	}
 }
 
@@ -177,16 +282,19 @@ func (s *SQS) handleReceiveResponse(res *sqs.ReceiveMessageOutput) {
 //
 // Empty responses are important signals that help the algorithm detect when
 // message volume has decreased and adjust polling intervals accordingly.
-func (s *SQS) handleEmptyResponse() {
-	if s.IsArrakisEnabled() {
-		s.incrementConsecutiveEmptyMessages()
+func (a *arrakis) handleEmptyResponse() {
+	if a.enabled() {
+		a.incrementConsecutiveEmptyMessages()
 
 		// Apply EWMA decay if we've had enough consecutive empty responses
-		if s.shouldDecayEWMA() {
-			s.decayEWMA()
+		if a.shouldDecayEWMA() {
+			a.decayEWMA()
 		}
 	}
-	s.config.arrakis.lastReceiveEmpty = time.Now()
+
+	a.mu.Lock()
+	a.lastReceiveEmpty = a.now()
+	a.mu.Unlock()
 }
 
 // handleNonEmptyResponse processes a polling operation that returned messages.
@@ -195,9 +303,9 @@ func (s *SQS) handleEmptyResponse() {
 //
 // Parameters:
 //   - messageCount: Number of messages received in this polling operation
-func (s *SQS) handleNonEmptyResponse(messageCount int) {
-	s.resetConsecutiveEmptyMessages()
-	s.updateMessageCount(messageCount)
+func (a *arrakis) handleNonEmptyResponse(messageCount int) {
+	a.resetConsecutiveEmptyMessages()
+	a.updateMessageCount(messageCount)
 }
 
 // incrementConsecutiveEmptyMessages safely increments the counter of consecutive
@@ -205,10 +313,10 @@ func (s *SQS) handleNonEmptyResponse(messageCount int) {
 // should be applied during idle periods.
 //
 // Thread-safe operation using mutex protection.
-func (s *SQS) incrementConsecutiveEmptyMessages() {
-	s.config.arrakis.mu.Lock()
-	s.config.arrakis.consecutiveEmptyMessages++
-	s.config.arrakis.mu.Unlock()
+func (a *arrakis) incrementConsecutiveEmptyMessages() {
+	a.mu.Lock()
+	a.consecutiveEmptyMessages++
+	a.mu.Unlock()
 }
 
 // resetConsecutiveEmptyMessages resets the counter of consecutive empty responses
@@ -216,10 +324,10 @@ func (s *SQS) incrementConsecutiveEmptyMessages() {
 // is no longer idle and EWMA decay should be suspended.
 //
 // Thread-safe operation using mutex protection.
-func (s *SQS) resetConsecutiveEmptyMessages() {
-	s.config.arrakis.mu.Lock()
-	s.config.arrakis.consecutiveEmptyMessages = 0
-	s.config.arrakis.mu.Unlock()
+func (a *arrakis) resetConsecutiveEmptyMessages() {
+	a.mu.Lock()
+	a.consecutiveEmptyMessages = 0
+	a.mu.Unlock()
 }
 
 // shouldDecayEWMA determines whether EWMA decay should be applied based on
@@ -228,55 +336,221 @@ func (s *SQS) resetConsecutiveEmptyMessages() {
 //
 // Returns:
 //   - bool: true if EWMA decay should be applied, false otherwise
-func (s *SQS) shouldDecayEWMA() bool {
-	return s.config.arrakis.consecutiveEmptyMessages >= _consecutiveEmptyThreshold
+func (a *arrakis) shouldDecayEWMA() bool {
+	return a.consecutiveEmptyMessages >= int64(a.config.adaptivePolling().ConsecutiveEmptyThreshold)
 }
 
-// calculateWaitTime determines the optimal SQS long polling wait time based on
-// the current EWMA average message volume. The algorithm classifies volume into
-// discrete categories and selects appropriate wait times for each category.
+// VolumeClass is the message volume category Arrakis assigns to a queue based on its
+// EWMA average. Each class maps to one of the configured wait times.
+type VolumeClass int
+
+// Volume classes, ordered from the quietest to the busiest.
+const (
+	// VolumeIdle means no recent messages (avg = 0)
+	VolumeIdle VolumeClass = iota
+	// VolumeLow means very few messages (avg < 2)
+	VolumeLow
+	// VolumeMedium means moderate traffic (avg 2-5)
+	VolumeMedium
+	// VolumeHigh means many messages (avg 5-10)
+	VolumeHigh
+	// VolumeVeryHigh means constant traffic (avg > 10)
+	VolumeVeryHigh
+)
+
+// MarshalText encodes the volume class as its name, so it reads well in JSON documents.
+func (v VolumeClass) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// String returns the lowercase name of the volume class.
+func (v VolumeClass) String() string {
+	switch v {
+	case VolumeIdle:
+		return "idle"
+	case VolumeLow:
+		return "low"
+	case VolumeMedium:
+		return "medium"
+	case VolumeHigh:
+		return "high"
+	case VolumeVeryHigh:
+		return "very_high"
+	default:
+		return "unknown"
+	}
+}
+
+// classifyVolume maps an EWMA average to its volume class. The thresholds below are for
+// batches of 10 messages and scale with batchSize.
 //
 // Volume Classification:
-// - Idle (avg = 0): No recent messages → longest wait time
-// - Low (avg < 2): Very few messages → long wait time
-// - Medium (avg 2-5): Moderate messages → medium wait time
-// - High (avg 5-10): Many messages → short wait time
-// - Very High (avg > 10): Constant messages → shortest wait time
+// - Idle (avg = 0): No recent messages
+// - Low (avg < 2): Very few messages
+// - Medium (avg 2-5): Moderate messages
+// - High (avg 5-10): Many messages
+// - Very High (avg > 10): Constant messages
+func classifyVolume(avg float64, batchSize int) VolumeClass {
+	scale := float64(batchSize) / _defaultNumberOfMessages
+
+	switch {
+	case avg == 0:
+		return VolumeIdle
+	case avg < _lowVolumeThreshold*scale:
+		return VolumeLow
+	case avg < _mediumVolumeThreshold*scale:
+		return VolumeMedium
+	case avg < _highVolumeThreshold*scale:
+		return VolumeHigh
+	default:
+		return VolumeVeryHigh
+	}
+}
+
+// enabled reports whether adaptive polling applies to the queue: its own override if one
+// was set, the client-wide setting otherwise.
+//
+// Thread-safe operation using mutex protection.
+func (a *arrakis) enabled() bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return a.enabledLocked()
+}
+
+// enabledLocked implements enabled. Must be called with the mutex held.
+func (a *arrakis) enabledLocked() bool {
+	if a.override != nil {
+		return *a.override
+	}
+
+	return a.config.adaptivePolling().EnableAdaptivePolling
+}
+
+// setOverride sets (or clears, with nil) the per-queue enable/disable override.
+//
+// Thread-safe operation using mutex protection.
+func (a *arrakis) setOverride(enabled *bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.override = enabled
+}
+
+// volumeClass returns the current volume class of the client.
+//
+// Thread-safe operation using mutex protection.
+func (a *arrakis) volumeClass() VolumeClass {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return a.class
+}
+
+// classify maps an EWMA average to its volume class for the configured batch size.
+func (a *arrakis) classify(avg float64) VolumeClass {
+	return classifyVolume(avg, a.config.batchSize())
+}
+
+// currentAverage returns the current EWMA average.
+//
+// Thread-safe operation using mutex protection.
+func (a *arrakis) currentAverage() float64 {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return a.average
+}
+
+// calculateWaitTime determines the optimal SQS long polling wait time based on
+// the current EWMA average message volume. The algorithm classifies volume into
+// discrete categories (see classifyVolume) and selects appropriate wait times for each category:
+// the quieter the queue, the longer the wait.
 //
 // This classification optimizes the trade-off between API call frequency and
 // message processing latency based on observed traffic patterns.
 //
 // Returns:
 //   - int64: Optimal wait time in seconds for the next SQS ReceiveMessage call
-func (s *SQS) calculateWaitTime() int64 {
-	s.config.arrakis.mu.Lock()
-	defer s.config.arrakis.mu.Unlock()
+func (a *arrakis) calculateWaitTime() int64 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
 
-	avg := s.config.arrakis.average
+	settings := a.config.adaptivePolling()
 
 	var waitTime int64
 
-	switch {
-	case avg == 0:
+	// A backlog of aging messages speeds polling up, a saturated consumer slows it down
+	oldestAge := a.oldestMessageAge()
+	volume := a.class
+	class := agedClass(volume, oldestAge, settings.MessageAgeThreshold)
+	class = targetClass(class, oldestAge, settings.MessageAgeTarget)
+	class = saturatedClass(class, a.saturation())
+
+	if settings.ContinuousWaitTimeScale > 0 && class == volume {
+		return interpolateWaitTime(a.average, settings)
+	}
+
+	switch class {
+	case VolumeIdle:
 		// Idle: No recent messages, use maximum wait time
-		waitTime = int64(s.config.AdaptivePolling.IdleWaitTimeSeconds)
-	case avg < _lowVolumeThreshold:
+		waitTime = int64(settings.IdleWaitTimeSeconds)
+	case VolumeLow:
 		// Low volume: Few messages, use long wait time
-		waitTime = int64(s.config.AdaptivePolling.LowVolumeWaitTimeSeconds)
-	case avg < _mediumVolumeThreshold:
+		waitTime = int64(settings.LowVolumeWaitTimeSeconds)
+	case VolumeMedium:
 		// Medium volume: Moderate messages, use medium wait time
-		waitTime = int64(s.config.AdaptivePolling.MediumVolumeWaitTimeSeconds)
-	case avg < _highVolumeThreshold:
+		waitTime = int64(settings.MediumVolumeWaitTimeSeconds)
+	case VolumeHigh:
 		// High volume: Many messages, use short wait time
-		waitTime = int64(s.config.AdaptivePolling.HighVolumeWaitTimeSeconds)
+		waitTime = int64(settings.HighVolumeWaitTimeSeconds)
 	default:
-		// Very high volume: Constant messages, use shortest wait time
-		waitTime = int64(s.config.AdaptivePolling.VeryHighVolumeWaitTimeSeconds)
+		// Very high volume: Constant messages, use shortest wait time, or poll back to back
+		waitTime = int64(settings.VeryHighVolumeWaitTimeSeconds)
+		if settings.BackToBackPolling {
+			waitTime = 0
+		}
+	}
+
+	return waitTime
+}
+
+// adjustedWaitTime returns the wait time computed by calculateWaitTime, adjusted by the
+// WaitTimeHook of the configuration when there is one.
+func (a *arrakis) adjustedWaitTime(queueURL string) int64 {
+	waitTime := a.calculateWaitTime()
+
+	if a.config.WaitTimeHook != nil {
+		waitTime = a.config.WaitTimeHook(queueURL, waitTime)
 	}
 
 	return waitTime
 }
 
+// clampWaitTime guarantees that a computed wait time is within the SQS long polling
+// range (0-20 seconds), so a misconfigured wait time can't make ReceiveMessage fail
+// at runtime. The OnWaitTimeClamped hook, if configured, is notified of every adjustment.
+//
+// Parameters:
+//   - waitTime: The wait time computed by the algorithm, in seconds
+//
+// Returns:
+//   - int64: The wait time to use, within SQS limits
+func (a *arrakis) clampWaitTime(waitTime int64) int64 {
+	clamped := min(max(waitTime, _minWaitTimeSeconds), _maxWaitTimeSeconds)
+
+	if clamped != waitTime && a.config.OnWaitTimeClamped != nil {
+		a.config.OnWaitTimeClamped(waitTime, clamped)
+	}
+
+	return clamped
+}
+
+// recordWaitTime remembers the wait time used by the latest adaptive ReceiveMessage call.
+func (a *arrakis) recordWaitTime(waitTime int64) {
+	atomic.StoreInt64(&a.lastWaitTime, waitTime)
+}
+
 // decayEWMA applies exponential decay to the EWMA average during idle periods.
 // This mechanism gradually reduces the average when no messages are being received,
 // allowing the algorithm to adapt to decreased message volume without waiting
@@ -291,15 +565,15 @@ func (s *SQS) calculateWaitTime() int64 {
 // 2. Minimum time gap must have passed (prevents excessive decay)
 // 3. Calculated decay factor is applied to current average
 // 4. Very small averages are reset to zero (cleanup threshold)
-func (s *SQS) decayEWMA() {
+func (a *arrakis) decayEWMA() {
 	// Get the last update timestamp atomically
-	last := atomic.LoadInt64(&s.config.arrakis.lastUpdate)
+	last := atomic.LoadInt64(&a.lastUpdate)
 	if last == 0 {
 		// No previous updates, nothing to decay
 		return
 	}
 
-	timeSinceLastUpdate := time.Since(time.Unix(last, 0))
+	timeSinceLastUpdate := a.now().Sub(time.Unix(last, 0))
 	if timeSinceLastUpdate < _minDecayGapSeconds*time.Second {
 		// Not enough time has passed, skip decay
 		return
@@ -308,14 +582,17 @@ func (s *SQS) decayEWMA() {
 	// Calculate exponential decay: decay = 0.5^(time_elapsed / half_life)
 	decay := math.Pow(0.5, timeSinceLastUpdate.Seconds()/_halfLifeSeconds)
 
-	s.config.arrakis.mu.Lock()
-	defer s.config.arrakis.mu.Unlock()
+	a.mu.Lock()
+	defer a.mu.Unlock()
 
 	// Apply decay to current average
-	s.config.arrakis.average *= decay
+	a.average *= decay
 
 	// Reset very small averages to zero for cleaner behavior
-	if s.config.arrakis.average < _ewmaDecayThreshold {
-		s.config.arrakis.average = 0
+	if a.average < _ewmaDecayThreshold {
+		a.average = 0
 	}
+
+	a.events.Decays++
+	a.observeClass()
 }
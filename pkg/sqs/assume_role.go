@@ -0,0 +1,59 @@
+package sqs
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// Assume role configuration
+const (
+	_defaultRoleSessionName = "arrakis" // Session name recorded in CloudTrail for assumed roles
+)
+
+// assumeRole holds the role the client assumes to access queues of another account.
+type assumeRole struct {
+	// RoleARN is the ARN of the role to assume.
+	RoleARN string
+	// ExternalID is the external ID required by the role trust policy (optional).
+	ExternalID string
+	// SessionName identifies the role session.
+	SessionName string
+}
+
+// WithAssumeRole makes the client access SQS with the credentials of a role, typically in
+// another account, so queues of a multi-account event bus can be polled without wiring STS
+// manually. The role is assumed with the credentials of the aws.Config given to the
+// constructor, and the temporary credentials are cached and refreshed automatically
+// before they expire.
+//
+// Parameters:
+//   - roleARN: ARN of the role to assume (e.g., "arn:aws:iam::123456789012:role/queue-reader")
+//   - externalID: External ID required by the role trust policy, or "" if none
+//
+// Example:
+//
+//	sqsClient := sqs.NewSQSWithOptions(&cfg, sqs.WithAssumeRole("arn:aws:iam::123456789012:role/queue-reader", "orders-team"))
+func WithAssumeRole(roleARN, externalID string) Option {
+	return func(c *config) {
+		c.AssumeRole = &assumeRole{
+			RoleARN:     roleARN,
+			ExternalID:  externalID,
+			SessionName: _defaultRoleSessionName,
+		}
+	}
+}
+
+// assumeRoleCredentials returns a copy of awsconfig whose credentials come from assuming role.
+func assumeRoleCredentials(awsconfig aws.Config, role *assumeRole) aws.Config {
+	provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(awsconfig), role.RoleARN, func(o *stscreds.AssumeRoleOptions) {
+		o.RoleSessionName = role.SessionName
+		if role.ExternalID != "" {
+			o.ExternalID = aws.String(role.ExternalID)
+		}
+	})
+
+	awsconfig.Credentials = aws.NewCredentialsCache(provider)
+
+	return awsconfig
+}
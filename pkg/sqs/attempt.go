@@ -0,0 +1,79 @@
+package sqs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+// FIFO receive retry configuration
+const (
+	_fifoQueueSuffix        = ".fifo"
+	_receiveAttemptRetries  = 2  // Extra attempts after a network failure on FIFO queues
+	_receiveAttemptIDLength = 16 // Random bytes in generated ReceiveRequestAttemptIds
+)
+
+// WithReceiveRequestAttemptID sets the ReceiveRequestAttemptId of a FIFO receive.
+// Retrying a failed receive with the same ID returns the same batch of messages
+// (within 5 minutes), instead of leaving that batch invisible until its timeout.
+//
+// When no ID is set on a FIFO queue, one is generated automatically.
+//
+// Parameters:
+//   - attemptID: Up to 128 alphanumeric or punctuation characters
+func WithReceiveRequestAttemptID(attemptID string) ReceiveOption {
+	return func(input *sqs.ReceiveMessageInput) {
+		input.ReceiveRequestAttemptId = aws.String(attemptID)
+	}
+}
+
+// receiveWithRetry issues a ReceiveMessage call. On FIFO queues it makes sure the request
+// carries a ReceiveRequestAttemptId and retries network failures with that same ID, so a
+// batch lost in transit is delivered again rather than hidden for the visibility timeout.
+func (s *SQS) receiveWithRetry(ctx context.Context, input *sqs.ReceiveMessageInput) (*sqs.ReceiveMessageOutput, error) {
+	if !isFIFOQueue(aws.ToString(input.QueueUrl)) {
+		return s.receiveMessage(ctx, input)
+	}
+
+	if input.ReceiveRequestAttemptId == nil {
+		input.ReceiveRequestAttemptId = aws.String(newReceiveAttemptID())
+	}
+
+	var err error
+	for attempt := 0; attempt <= _receiveAttemptRetries; attempt++ {
+		var output *sqs.ReceiveMessageOutput
+
+		output, err = s.receiveMessage(ctx, input)
+		if err == nil || !isNetworkError(err) || ctx.Err() != nil {
+			return output, err
+		}
+	}
+
+	return nil, err
+}
+
+// isFIFOQueue reports whether a queue URL (or name) designates a FIFO queue.
+func isFIFOQueue(queueURL string) bool {
+	return strings.HasSuffix(queueURL, _fifoQueueSuffix)
+}
+
+// isNetworkError reports whether err is a transport failure, where the request may or
+// may not have reached SQS.
+func isNetworkError(err error) bool {
+	var sendErr *smithyhttp.RequestSendError
+	return errors.As(err, &sendErr)
+}
+
+// newReceiveAttemptID generates a random ReceiveRequestAttemptId.
+func newReceiveAttemptID() string {
+	b := make([]byte, _receiveAttemptIDLength)
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,86 @@
+package sqs
+
+import "time"
+
+// DeleteReason is why a consumer deleted a message from its queue.
+type DeleteReason string
+
+// Reasons recorded in delete audits.
+const (
+	// DeleteAcknowledged means the handler processed the message successfully.
+	DeleteAcknowledged DeleteReason = "acknowledged"
+	// DeleteFiltered means the receive filter rejected the message (see FilterDelete).
+	DeleteFiltered DeleteReason = "filtered"
+	// DeleteDeadLettered means a retry policy moved the message to its dead-letter queue.
+	DeleteDeadLettered DeleteReason = "dead_lettered"
+	// DeleteQuarantined means the message was moved to the quarantine queue.
+	DeleteQuarantined DeleteReason = "quarantined"
+	// DeleteDuplicate means the message had already been handled (see WithDeduplication).
+	DeleteDuplicate DeleteReason = "duplicate"
+	// DeleteDropped means the failure sink of the handler dropped the message (see DropSink).
+	DeleteDropped DeleteReason = "dropped"
+	// DeleteRetried means the message was re-sent to a retry queue (see WithRetryQueue).
+	DeleteRetried DeleteReason = "retried"
+)
+
+// DeleteAudit describes a message a consumer deleted from its queue.
+type DeleteAudit struct {
+	// QueueURL is the queue the message was deleted from.
+	QueueURL string
+	// MessageID is the SQS MessageId of the message.
+	MessageID string
+	// ReceiveCount is how many times the message had been received.
+	ReceiveCount int
+	// ProcessingDuration is the time between the start of processing and the delete (zero
+	// for filtered messages, which are not processed).
+	ProcessingDuration time.Duration
+	// Reason is why the message was deleted.
+	Reason DeleteReason
+	// Time is when the delete succeeded.
+	Time time.Time
+}
+
+// WithDeleteAudit calls hook after every successful delete made by the consumer, so
+// regulated environments can keep an audit trail of the messages consumed. Messages moved
+// to another queue (dead-lettered or quarantined) are audited too, once the copy was sent
+// and the original deleted.
+//
+// The hook runs on the worker goroutine before the next message is processed, so it should
+// be fast, typically appending to a buffered log.
+//
+// Parameters:
+//   - hook: Function receiving every delete
+//
+// Example:
+//
+//	consumer := NewConsumer(client, queueURL, handler, WithDeleteAudit(func(audit DeleteAudit) {
+//	    auditLog.Info("message consumed", "queue", audit.QueueURL, "message_id", audit.MessageID,
+//	        "receive_count", audit.ReceiveCount, "duration", audit.ProcessingDuration, "reason", audit.Reason)
+//	}))
+func WithDeleteAudit(hook func(DeleteAudit)) ConsumerOption {
+	return func(c *consumerConfig) {
+		c.OnDelete = hook
+	}
+}
+
+// auditDelete notifies the delete audit hook, if any, that msg was deleted. start is when
+// its processing began, zero if it wasn't processed.
+func (c *Consumer) auditDelete(source queueSource, msg Message, reason DeleteReason, start time.Time) {
+	if c.config.OnDelete == nil {
+		return
+	}
+
+	now := time.Now()
+	audit := DeleteAudit{
+		QueueURL:     source.queueURL,
+		MessageID:    msg.ID,
+		ReceiveCount: msg.ReceiveCount,
+		Reason:       reason,
+		Time:         now,
+	}
+	if !start.IsZero() {
+		audit.ProcessingDuration = now.Sub(start)
+	}
+
+	c.config.OnDelete(audit)
+}
@@ -0,0 +1,55 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConsumerDeleteAudit(t *testing.T) {
+	fake := &fakeSQS{}
+	ok := testMessage("ok", "")
+	ok.Attributes[_attributeApproximateReceiveCount] = "2"
+	fake.push(ok, testMessage("failed", ""), testMessage("skipped", ""))
+
+	var (
+		mu     sync.Mutex
+		audits []DeleteAudit
+	)
+	consumer := NewConsumer(newTestSQS(fake), "queue", HandlerFunc(func(ctx context.Context, msg Message) error {
+		if msg.ID == "failed" {
+			return errors.New("boom")
+		}
+		time.Sleep(5 * time.Millisecond)
+		return nil
+	}), WithReceiveFilter(func(msg Message) bool {
+		return msg.ID != "skipped"
+	}), WithFilterAction(FilterDelete), WithDeleteAudit(func(audit DeleteAudit) {
+		mu.Lock()
+		defer mu.Unlock()
+		audits = append(audits, audit)
+	}))
+	runConsumer(t, consumer, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(audits) == 2
+	})
+
+	byID := map[string]DeleteAudit{}
+	for _, audit := range audits {
+		byID[audit.MessageID] = audit
+	}
+
+	acked := byID["ok"]
+	if acked.Reason != DeleteAcknowledged || acked.QueueURL != "queue" || acked.ReceiveCount != 2 || acked.ProcessingDuration < 5*time.Millisecond {
+		t.Errorf("Expected an audit of the acknowledged message, got %+v", acked)
+	}
+	if filtered := byID["skipped"]; filtered.Reason != DeleteFiltered || filtered.ProcessingDuration != 0 {
+		t.Errorf("Expected an audit of the filtered message, got %+v", filtered)
+	}
+	if _, found := byID["failed"]; found {
+		t.Error("Expected no audit for a message left in the queue")
+	}
+}
@@ -0,0 +1,182 @@
+package sqs
+
+import (
+	"context"
+	"sync"
+)
+
+// workerPool is a resizable set of goroutines processing messages from a shared,
+// bounded queue. A fixed-size pool is simply one whose minimum and maximum match.
+//
+// The queue holds up to max messages, so the number of messages waiting for a worker
+// (the backlog) can be observed and used as a scaling signal.
+type workerPool struct {
+	mu   sync.Mutex
+	size int // Workers currently running (or about to stop after a pending quit)
+	min  int
+	max  int
+
+	ctx     context.Context // Context handed to process, set by start
+	jobs    chan Message
+	quit    chan struct{}   // Each token stops exactly one worker
+	done    <-chan struct{} // Closed when workers must take no more messages, set by start
+	process func(context.Context, Message)
+	wg      sync.WaitGroup
+
+	undispatched []Message // Messages taken by a worker once done was closed, protected by mu
+}
+
+// newWorkerPool creates a pool. No workers run until start is called.
+func newWorkerPool(minWorkers, maxWorkers int, process func(context.Context, Message)) *workerPool {
+	return &workerPool{
+		min:     minWorkers,
+		max:     maxWorkers,
+		jobs:    make(chan Message, maxWorkers),
+		quit:    make(chan struct{}, maxWorkers),
+		process: process,
+	}
+}
+
+// start launches the minimum number of workers, which process messages with ctx until
+// done is closed.
+func (p *workerPool) start(ctx context.Context, done <-chan struct{}) {
+	p.ctx = ctx
+	p.done = done
+	p.resize(p.min)
+}
+
+// dispatch hands a message over to the pool, blocking while the queue is full. It
+// reports false, without queueing the message, once done is closed.
+func (p *workerPool) dispatch(msg Message) bool {
+	if stopped(p.done) {
+		return false
+	}
+
+	select {
+	case p.jobs <- msg:
+		return true
+	case <-p.done:
+		return false
+	}
+}
+
+// backlog returns the number of messages waiting for a worker.
+func (p *workerPool) backlog() int {
+	return len(p.jobs)
+}
+
+// workers returns the current number of workers.
+func (p *workerPool) workers() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.size
+}
+
+// autoscale adjusts the number of workers for the given volume class and the
+// current backlog. See desiredWorkers for the scaling rules.
+func (p *workerPool) autoscale(class VolumeClass) {
+	if p.min == p.max {
+		return
+	}
+
+	p.resize(desiredWorkers(class, p.backlog(), p.workers(), p.min, p.max))
+}
+
+// resize starts or stops workers until the pool has n of them. Growing first takes back
+// the quit tokens of a previous shrink that no worker read yet, so the workers asked to
+// stop keep running rather than being replaced.
+func (p *workerPool) resize(n int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for ; p.size < n; p.size++ {
+		select {
+		case <-p.quit:
+			continue
+		default:
+		}
+
+		p.wg.Add(1)
+		go p.work()
+	}
+
+	for ; p.size > n; p.size-- {
+		p.quit <- struct{}{}
+	}
+}
+
+// work processes messages until the pool is stopped or the worker is asked to quit.
+func (p *workerPool) work() {
+	defer p.wg.Done()
+
+	for {
+		select {
+		case msg := <-p.jobs:
+			if stopped(p.done) {
+				p.mu.Lock()
+				p.undispatched = append(p.undispatched, msg)
+				p.mu.Unlock()
+				return
+			}
+			p.process(p.ctx, msg)
+		case <-p.quit:
+			return
+		case <-p.done:
+			return
+		}
+	}
+}
+
+// stop waits for the workers to finish the messages they are processing, once done is
+// closed, and returns the messages still waiting in the queue, which no worker started.
+func (p *workerPool) stop() []Message {
+	p.wg.Wait()
+	close(p.jobs)
+
+	undispatched := p.undispatched
+	for msg := range p.jobs {
+		undispatched = append(undispatched, msg)
+	}
+
+	return undispatched
+}
+
+// stopped reports whether done is closed.
+func stopped(done <-chan struct{}) bool {
+	select {
+	case <-done:
+		return true
+	default:
+		return false
+	}
+}
+
+// desiredWorkers computes the target pool size.
+//
+// The volume class sets a baseline that spreads linearly between the bounds: idle
+// queues run with minWorkers and very high volume queues with maxWorkers. A backlog
+// of messages waiting for a worker grows the pool by one beyond its current size,
+// regardless of the class. Without backlog, the pool shrinks by one worker per
+// evaluation so short lulls don't tear down the whole pool at once.
+//
+// Parameters:
+//   - class: Current volume class of the queue
+//   - backlog: Messages received but still waiting for a worker
+//   - current: Current number of workers
+//   - minWorkers, maxWorkers: Bounds of the pool
+//
+// Returns:
+//   - int: The number of workers the pool should have, within the bounds
+func desiredWorkers(class VolumeClass, backlog, current, minWorkers, maxWorkers int) int {
+	target := minWorkers + (maxWorkers-minWorkers)*int(class)/int(VolumeVeryHigh)
+
+	switch {
+	case backlog > 0:
+		target = max(target, current+1)
+	case target < current:
+		target = current - 1
+	}
+
+	return min(max(target, minWorkers), maxWorkers)
+}
@@ -0,0 +1,121 @@
+package sqs
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDesiredWorkers(t *testing.T) {
+	tests := []struct {
+		name     string
+		class    VolumeClass
+		backlog  int
+		current  int
+		expected int
+	}{
+		{"idle stays at minimum", VolumeIdle, 0, 2, 2},
+		{"very high jumps to maximum", VolumeVeryHigh, 0, 2, 10},
+		{"medium scales to middle", VolumeMedium, 0, 2, 6},
+		{"backlog grows beyond class target", VolumeIdle, 3, 4, 5},
+		{"backlog never exceeds maximum", VolumeVeryHigh, 5, 10, 10},
+		{"shrinks one worker at a time", VolumeIdle, 0, 8, 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := desiredWorkers(tt.class, tt.backlog, tt.current, 2, 10)
+			if got != tt.expected {
+				t.Errorf("desiredWorkers(%v, %d, %d, 2, 10) = %d, expected %d", tt.class, tt.backlog, tt.current, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestWorkerPoolResize(t *testing.T) {
+	var mu sync.Mutex
+	processed := 0
+	pool := newWorkerPool(1, 4, func(ctx context.Context, msg Message) {
+		mu.Lock()
+		processed++
+		mu.Unlock()
+	})
+	done := make(chan struct{})
+	pool.start(context.Background(), done)
+
+	pool.resize(4)
+	if pool.workers() != 4 {
+		t.Errorf("Expected 4 workers, got %d", pool.workers())
+	}
+
+	pool.resize(1)
+	if pool.workers() != 1 {
+		t.Errorf("Expected 1 worker, got %d", pool.workers())
+	}
+
+	for i := 0; i < 3; i++ {
+		pool.dispatch(Message{ID: "m"})
+	}
+	close(done)
+	undispatched := pool.stop()
+
+	// Messages no worker started by the time of stop are handed back instead of processed
+	if processed+len(undispatched) != 3 {
+		t.Errorf("Expected 3 messages processed or handed back, got %d and %d", processed, len(undispatched))
+	}
+}
+
+func TestWorkerPoolShrinkThenGrow(t *testing.T) {
+	var running atomic.Int32
+	release := make(chan struct{})
+	pool := newWorkerPool(1, 4, func(ctx context.Context, msg Message) {
+		running.Add(1)
+		<-release
+	})
+	done := make(chan struct{})
+	pool.start(context.Background(), done)
+
+	// Shrinking queues quit tokens the idle workers may not have read when the pool grows
+	pool.resize(4)
+	pool.resize(1)
+	pool.resize(4)
+
+	for i := 0; i < 5; i++ {
+		pool.dispatch(Message{ID: "m"})
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for running.Load() < 4 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if got := running.Load(); got != 4 || pool.workers() != 4 {
+		t.Errorf("Expected 4 running workers, got %d running for a size of %d", got, pool.workers())
+	}
+
+	close(done)
+	close(release)
+	pool.stop()
+}
+
+func TestVolumeClassString(t *testing.T) {
+	if VolumeVeryHigh.String() != "very_high" || VolumeIdle.String() != "idle" {
+		t.Errorf("Unexpected volume class names: %s, %s", VolumeVeryHigh, VolumeIdle)
+	}
+}
+
+func TestConsumerAutoscalingBounds(t *testing.T) {
+	client := newTestSQS(&fakeSQS{})
+
+	consumer := NewConsumer(client, "queue", HandlerFunc(func(ctx context.Context, msg Message) error { return nil }), WithAutoscaling(2, 8))
+	if consumer.config.MinWorkers != 2 || consumer.config.MaxWorkers != 8 {
+		t.Errorf("Expected bounds 2-8, got %d-%d", consumer.config.MinWorkers, consumer.config.MaxWorkers)
+	}
+
+	fixed := NewConsumer(client, "queue", HandlerFunc(func(ctx context.Context, msg Message) error { return nil }), WithWorkers(3))
+	if fixed.config.MinWorkers != 3 || fixed.config.MaxWorkers != 3 {
+		t.Errorf("Expected fixed pool of 3, got %d-%d", fixed.config.MinWorkers, fixed.config.MaxWorkers)
+	}
+}
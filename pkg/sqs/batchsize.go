@@ -0,0 +1,60 @@
+package sqs
+
+import (
+	"fmt"
+)
+
+// ErrInvalidMaxMessages is returned when a receive asks for a number of messages outside
+// the range accepted by SQS. It is detected locally, before any request is sent.
+type ErrInvalidMaxMessages struct {
+	// MaxMessages is the requested number of messages.
+	MaxMessages int32
+}
+
+func (e *ErrInvalidMaxMessages) Error() string {
+	return fmt.Sprintf("max number of messages %d is outside the SQS range [1, %d]", e.MaxMessages, _maxNumberOfMessages)
+}
+
+// WithMaxNumberOfMessages sets the number of messages requested by receives that don't
+// ask for a specific number: ReceiveMessage, ReceiveMessages and TryReceive called with 0,
+// consumers without WithMaxMessages, and Drain (default: 10). Values outside 1-10 keep
+// the default.
+//
+// The volume thresholds of adaptive polling scale with the batch size: with 5 messages
+// per receive, a queue is classified as very high volume from an average of 5 messages
+// per poll instead of 10, as every receive then returns a full batch.
+//
+// Parameters:
+//   - maxMessages: The number of messages per receive (1-10)
+//
+// Example:
+//
+//	// Handlers are slow: take fewer messages at once so they don't sit invisible
+//	sqsClient := NewSQSWithOptions(&cfg, WithMaxNumberOfMessages(2))
+func WithMaxNumberOfMessages(maxMessages int) Option {
+	return func(c *config) {
+		c.MaxNumberOfMessages = maxMessages
+	}
+}
+
+// batchSize returns the number of messages requested by default per receive.
+func (c *config) batchSize() int {
+	if c.MaxNumberOfMessages < 1 || c.MaxNumberOfMessages > _maxNumberOfMessages {
+		return _defaultNumberOfMessages
+	}
+
+	return c.MaxNumberOfMessages
+}
+
+// maxMessages resolves the number of messages of a receive: the configured batch size
+// for 0, maxMessages itself when SQS accepts it.
+func (s *SQS) maxMessages(maxMessages int32) (int32, error) {
+	switch {
+	case maxMessages == 0:
+		return int32(s.config.batchSize()), nil
+	case maxMessages < 0 || maxMessages > _maxNumberOfMessages:
+		return 0, &ErrInvalidMaxMessages{MaxMessages: maxMessages}
+	default:
+		return maxMessages, nil
+	}
+}
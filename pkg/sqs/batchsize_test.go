@@ -0,0 +1,66 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestMaxNumberOfMessagesDefault(t *testing.T) {
+	fake := &fakeSQS{}
+	client := newTestSQS(fake, WithMaxNumberOfMessages(4))
+
+	if _, err := client.ReceiveMessages(context.Background(), "queue", 0); err != nil {
+		t.Fatalf("ReceiveMessages returned error: %v", err)
+	}
+	if _, err := client.ReceiveMessages(context.Background(), "queue", 7); err != nil {
+		t.Fatalf("ReceiveMessages returned error: %v", err)
+	}
+
+	if got := fake.receiveInputs[0].MaxNumberOfMessages; got != 4 {
+		t.Errorf("Expected the configured batch size, got %d", got)
+	}
+	if got := fake.receiveInputs[1].MaxNumberOfMessages; got != 7 {
+		t.Errorf("Expected an explicit batch size to win, got %d", got)
+	}
+
+	consumer := NewConsumer(client, "queue", HandlerFunc(func(ctx context.Context, msg Message) error { return nil }))
+	if consumer.config.MaxMessages != 4 {
+		t.Errorf("Expected consumers to default to the configured batch size, got %d", consumer.config.MaxMessages)
+	}
+
+	if got := newTestSQS(fake, WithMaxNumberOfMessages(11)).config.batchSize(); got != _defaultNumberOfMessages {
+		t.Errorf("Expected an out of range option to keep the default, got %d", got)
+	}
+}
+
+func TestMaxNumberOfMessagesValidation(t *testing.T) {
+	fake := &fakeSQS{}
+	client := newTestSQS(fake)
+
+	var invalid *ErrInvalidMaxMessages
+	if _, err := client.ReceiveMessage(context.Background(), "queue", 11, nil); !errors.As(err, &invalid) || invalid.MaxMessages != 11 {
+		t.Errorf("Expected ErrInvalidMaxMessages, got %v", err)
+	}
+	if _, err := client.TryReceive(context.Background(), "queue", -1); !errors.As(err, &invalid) {
+		t.Errorf("Expected ErrInvalidMaxMessages, got %v", err)
+	}
+
+	if len(fake.receiveInputs) != 0 {
+		t.Errorf("Expected invalid receives to fail without an API call, got %d calls", len(fake.receiveInputs))
+	}
+}
+
+func TestVolumeThresholdsScaleWithBatchSize(t *testing.T) {
+	if class := classifyVolume(5, _defaultNumberOfMessages); class != VolumeHigh {
+		t.Errorf("Expected 5 messages per poll of 10 to be high volume, got %s", class)
+	}
+
+	if class := classifyVolume(5, 5); class != VolumeVeryHigh {
+		t.Errorf("Expected full batches of 5 to be very high volume, got %s", class)
+	}
+
+	if class := classifyVolume(0.5, 1); class != VolumeHigh {
+		t.Errorf("Expected half-full batches of 1 to be high volume, got %s", class)
+	}
+}
@@ -0,0 +1,169 @@
+package sqs
+
+import (
+	"crypto/md5"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// Checksum fields returned by SQS
+const (
+	ChecksumBody       = "MD5OfMessageBody"
+	ChecksumAttributes = "MD5OfMessageAttributes"
+)
+
+// Transport types of message attributes in the SQS attribute digest
+const (
+	_checksumTransportString = 1
+	_checksumTransportBinary = 2
+)
+
+// ErrChecksumMismatch is returned when a digest returned by SQS doesn't match the one
+// computed locally from the message, meaning the body or the attributes were corrupted
+// between the client and the queue.
+type ErrChecksumMismatch struct {
+	// MessageID is the SQS MessageId of the message.
+	MessageID string
+	// Sent is true when the message was sent: SQS accepted it, and it is in the queue
+	// with the digest it returned.
+	Sent bool
+	// Field is the mismatching digest: ChecksumBody or ChecksumAttributes.
+	Field string
+	// Expected is the digest computed locally.
+	Expected string
+	// Actual is the digest returned by SQS.
+	Actual string
+}
+
+func (e *ErrChecksumMismatch) Error() string {
+	if e.Sent {
+		return fmt.Sprintf("message %s sent, but %s is %s, expected %s", e.MessageID, e.Field, e.Actual, e.Expected)
+	}
+
+	return fmt.Sprintf("message %s: %s is %s, expected %s", e.MessageID, e.Field, e.Actual, e.Expected)
+}
+
+// WithChecksumVerification verifies the MD5 digests SQS returns for every message sent and
+// received against digests computed locally, guarding against rare payload corruption:
+//   - SendMessage returns an *ErrChecksumMismatch along with its output, and Producer
+//     batches report the entry as successful with it in BatchSuccess.Err. Its Sent field
+//     is set: the message was accepted by SQS, so sending it again duplicates it.
+//   - Received messages that don't match are dropped from the response and logged, so
+//     they are delivered again once their visibility timeout expires.
+//
+// Example:
+//
+//	sqsClient := NewSQSWithOptions(&cfg, WithChecksumVerification())
+func WithChecksumVerification() Option {
+	return func(c *config) {
+		c.VerifyChecksums = true
+	}
+}
+
+// VerifyMessage checks the digests of a received message against its body and message
+// attributes. Digests missing from the message are not checked.
+//
+// Parameters:
+//   - m: The SDK message, as returned by ReceiveMessage
+//
+// Returns:
+//   - error: *ErrChecksumMismatch for a corrupted message, or nil
+func VerifyMessage(m types.Message) error {
+	return verifyChecksums(aws.ToString(m.MessageId), aws.ToString(m.Body), m.MessageAttributes, m.MD5OfBody, m.MD5OfMessageAttributes)
+}
+
+// verifySent checks the digests returned by a SendMessage call.
+func (s *SQS) verifySent(input *sqs.SendMessageInput, output *sqs.SendMessageOutput) error {
+	if !s.config.VerifyChecksums {
+		return nil
+	}
+
+	return verifySentChecksums(aws.ToString(output.MessageId), input, output.MD5OfMessageBody, output.MD5OfMessageAttributes)
+}
+
+// verifySentChecksums checks the digests returned by SQS for a message it accepted.
+func verifySentChecksums(messageID string, input *sqs.SendMessageInput, bodyMD5, attributesMD5 *string) error {
+	err := verifyChecksums(messageID, aws.ToString(input.MessageBody), input.MessageAttributes, bodyMD5, attributesMD5)
+	var mismatch *ErrChecksumMismatch
+	if errors.As(err, &mismatch) {
+		mismatch.Sent = true
+	}
+
+	return err
+}
+
+// verifyReceived drops the messages of a receive whose digests don't match.
+func (s *SQS) verifyReceived(queueURL string, output *sqs.ReceiveMessageOutput) {
+	if !s.config.VerifyChecksums {
+		return
+	}
+
+	output.Messages = slices.DeleteFunc(output.Messages, func(m types.Message) bool {
+		err := VerifyMessage(m)
+		if err != nil {
+			s.logger().Warn("dropping corrupted message, it will be redelivered", "queue", queueURL, "message_id", aws.ToString(m.MessageId), "error", err)
+		}
+		return err != nil
+	})
+}
+
+// verifyChecksums compares the digests returned by SQS with the ones of body and
+// attributes. A nil digest is not checked.
+func verifyChecksums(messageID, body string, attributes map[string]types.MessageAttributeValue, bodyMD5, attributesMD5 *string) error {
+	if bodyMD5 != nil {
+		if expected := md5Hex([]byte(body)); !strings.EqualFold(expected, *bodyMD5) {
+			return &ErrChecksumMismatch{MessageID: messageID, Field: ChecksumBody, Expected: expected, Actual: *bodyMD5}
+		}
+	}
+
+	if attributesMD5 != nil && len(attributes) > 0 {
+		if expected := attributesDigest(attributes); !strings.EqualFold(expected, *attributesMD5) {
+			return &ErrChecksumMismatch{MessageID: messageID, Field: ChecksumAttributes, Expected: expected, Actual: *attributesMD5}
+		}
+	}
+
+	return nil
+}
+
+// attributesDigest returns the MD5 digest of message attributes as computed by SQS: the
+// attributes sorted by name, each encoded as length-prefixed name, data type, transport
+// type and value.
+func attributesDigest(attributes map[string]types.MessageAttributeValue) string {
+	var buffer []byte
+	appendValue := func(value []byte) {
+		buffer = binary.BigEndian.AppendUint32(buffer, uint32(len(value)))
+		buffer = append(buffer, value...)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(attributes)) {
+		attribute := attributes[name]
+		dataType := aws.ToString(attribute.DataType)
+		appendValue([]byte(name))
+		appendValue([]byte(dataType))
+
+		if strings.HasPrefix(dataType, _attributeDataTypeBinary) {
+			buffer = append(buffer, _checksumTransportBinary)
+			appendValue(attribute.BinaryValue)
+		} else {
+			buffer = append(buffer, _checksumTransportString)
+			appendValue([]byte(aws.ToString(attribute.StringValue)))
+		}
+	}
+
+	return md5Hex(buffer)
+}
+
+// md5Hex returns the hexadecimal MD5 digest of data.
+func md5Hex(data []byte) string {
+	sum := md5.Sum(data)
+	return hex.EncodeToString(sum[:])
+}
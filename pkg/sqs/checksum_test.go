@@ -0,0 +1,129 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// corruptingSQS is a fake echoing the digest of a different body on sends.
+type corruptingSQS struct {
+	*fakeSQS
+}
+
+func (c *corruptingSQS) SendMessage(ctx context.Context, params *sqs.SendMessageInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageOutput, error) {
+	output, err := c.fakeSQS.SendMessage(ctx, params, optFns...)
+	if err == nil {
+		output.MD5OfMessageBody = aws.String(md5Hex([]byte(aws.ToString(params.MessageBody) + "!")))
+	}
+
+	return output, err
+}
+
+func (c *corruptingSQS) SendMessageBatch(ctx context.Context, params *sqs.SendMessageBatchInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageBatchOutput, error) {
+	output, err := c.fakeSQS.SendMessageBatch(ctx, params, optFns...)
+	if err == nil {
+		for i := range output.Successful {
+			output.Successful[i].MD5OfMessageBody = aws.String(md5Hex([]byte("corrupted")))
+		}
+	}
+
+	return output, err
+}
+
+func checksummedMessage(id, body string) types.Message {
+	m := testMessage(id, "")
+	m.Body = aws.String(body)
+	m.MD5OfBody = aws.String(md5Hex([]byte(body)))
+	m.MessageAttributes = map[string]types.MessageAttributeValue{
+		"type": {DataType: aws.String(_attributeDataTypeString), StringValue: aws.String("order")},
+		"n":    {DataType: aws.String(_attributeDataTypeNumber), StringValue: aws.String("42")},
+		"bin":  {DataType: aws.String(_attributeDataTypeBinary), BinaryValue: []byte{1, 2, 3}},
+	}
+	// Digest computed by SQS for the attributes above
+	m.MD5OfMessageAttributes = aws.String("10fb46bb0d77aac7fe7293ebabb046b5")
+
+	return m
+}
+
+func TestVerifyMessage(t *testing.T) {
+	m := checksummedMessage("m1", "payload")
+	if err := VerifyMessage(m); err != nil {
+		t.Fatalf("Expected an intact message to verify, got %v", err)
+	}
+
+	m.Body = aws.String("pay1oad")
+	var mismatch *ErrChecksumMismatch
+	if err := VerifyMessage(m); !errors.As(err, &mismatch) || mismatch.Field != ChecksumBody {
+		t.Errorf("Expected a body mismatch, got %v", err)
+	}
+
+	m = checksummedMessage("m1", "payload")
+	m.MessageAttributes["type"] = types.MessageAttributeValue{DataType: aws.String(_attributeDataTypeString), StringValue: aws.String("refund")}
+	if err := VerifyMessage(m); !errors.As(err, &mismatch) || mismatch.Field != ChecksumAttributes {
+		t.Errorf("Expected an attributes mismatch, got %v", err)
+	}
+}
+
+func TestChecksumVerificationDropsCorruptedMessages(t *testing.T) {
+	corrupted := checksummedMessage("m2", "payload")
+	corrupted.Body = aws.String("pay1oad")
+
+	fake := &fakeSQS{}
+	fake.push(checksummedMessage("m1", "payload"), corrupted)
+
+	messages, err := newTestSQS(fake, WithChecksumVerification()).ReceiveMessages(context.Background(), "queue", 10)
+	if err != nil {
+		t.Fatalf("ReceiveMessages returned error: %v", err)
+	}
+
+	if len(messages) != 1 || messages[0].ID != "m1" {
+		t.Errorf("Expected only the intact message, got %v", messages)
+	}
+}
+
+func TestChecksumVerificationOnSend(t *testing.T) {
+	api := &corruptingSQS{fakeSQS: &fakeSQS{}}
+
+	if _, err := newTestSQS(api).SendMessage(context.Background(), "queue", "payload"); err != nil {
+		t.Fatalf("Expected digests to be ignored without the option, got %v", err)
+	}
+
+	output, err := newTestSQS(api, WithChecksumVerification()).SendMessage(context.Background(), "queue", "payload")
+	var mismatch *ErrChecksumMismatch
+	if !errors.As(err, &mismatch) || mismatch.MessageID != "sent-payload" || !mismatch.Sent {
+		t.Errorf("Expected a checksum mismatch, got %v", err)
+	}
+	if output == nil {
+		t.Error("Expected the output of the accepted message along with the mismatch")
+	}
+}
+
+func TestChecksumVerificationOnBatchSend(t *testing.T) {
+	api := &corruptingSQS{fakeSQS: &fakeSQS{}}
+	producer := NewProducer(newTestSQS(api, WithChecksumVerification()), "queue", fastSpool(SpoolPolicy{}))
+
+	result, err := producer.SendBatch(context.Background(), []OutgoingMessage{{ID: "1", Body: "payload"}})
+	if err != nil {
+		t.Fatalf("SendBatch returned error: %v", err)
+	}
+	if len(result.Failed) != 0 || len(result.Successful) != 1 {
+		t.Fatalf("Expected the accepted message reported as sent, got %+v", result)
+	}
+
+	var mismatch *ErrChecksumMismatch
+	if success := result.Successful[0]; success.MessageID != "sent-payload" || !errors.As(success.Err, &mismatch) || !mismatch.Sent {
+		t.Errorf("Expected a checksum mismatch of a sent message, got %+v", success)
+	}
+
+	if err := producer.Close(context.Background()); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+	if sent := api.sentMessages(); len(sent) != 1 {
+		t.Errorf("Expected the message sent once, got %d", len(sent))
+	}
+}
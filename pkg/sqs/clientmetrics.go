@@ -0,0 +1,121 @@
+package sqs
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+)
+
+// Client metrics configuration values
+const (
+	_metricAlgorithmEvents         = "arrakis.algorithm.events" // Counter of adaptive polling algorithm events, by event
+	_metricSlowHandlers            = "arrakis.handler.slow"     // Counter of handler runs over the slow handler threshold
+	_metricDeliveryLatency         = "arrakis.delivery.latency" // Histogram of delivery latencies, in seconds
+	_attributeAlgorithmEvent       = attribute.Key("arrakis.algorithm_event")
+	_algorithmEventEWMAReset       = "ewma_reset"
+	_algorithmEventDecay           = "decay"
+	_algorithmEventSpikeClamp      = "spike_clamp"
+	_algorithmEventClassTransition = "class_transition"
+)
+
+// WithMetrics records OpenTelemetry metrics for the queues of the client:
+//
+//   - arrakis.algorithm.events: counter of the adaptive polling algorithm events counted
+//     in Stats, with an arrakis.algorithm_event attribute of "ewma_reset", "decay",
+//     "spike_clamp" or "class_transition"
+//   - arrakis.handler.slow: counter of the consumer handler runs over the slow handler
+//     threshold (see WithSlowHandlerThreshold)
+//   - arrakis.delivery.latency: histogram of the delivery latencies of the received
+//     messages, in seconds, from which the backend computes the percentiles reported by
+//     DeliveryLatency
+//
+// All carry the queue name (messaging.destination.name), like the consumer metrics of
+// HandlerMetrics.
+//
+// Parameters:
+//   - provider: The meter provider (nil uses the global provider, a no-op unless one was
+//     registered with otel.SetMeterProvider)
+//
+// Example:
+//
+//	client := sqs.NewSQSWithOptions(&cfg, sqs.WithMetrics(meterProvider))
+func WithMetrics(provider metric.MeterProvider) Option {
+	return WithMetricsSink(OTelSink(provider))
+}
+
+// WithMetricsSink records the metrics of WithMetrics into sink, e.g. a bespoke telemetry
+// system implementing MetricsSink.
+//
+// Parameters:
+//   - sink: The sink receiving the metrics (nil disables them)
+func WithMetricsSink(sink MetricsSink) Option {
+	return func(c *config) {
+		c.Metrics = sink
+	}
+}
+
+// recordEvents records the algorithm events of a queue counted since the last call.
+func (s *SQS) recordEvents(ctx context.Context, queueURL string, state *arrakis) {
+	sink := s.config.Metrics
+	if sink == nil {
+		return
+	}
+
+	events := state.unreportedEvents()
+	queue := semconv.MessagingDestinationName(queueName(queueURL))
+	for _, e := range []struct {
+		name  string
+		count int64
+	}{
+		{_algorithmEventEWMAReset, events.EWMAResets},
+		{_algorithmEventDecay, events.Decays},
+		{_algorithmEventSpikeClamp, events.SpikeClamps},
+		{_algorithmEventClassTransition, events.ClassTransitions},
+	} {
+		if e.count > 0 {
+			sink.Counter(ctx, _metricAlgorithmEvents, e.count, queue, _attributeAlgorithmEvent.String(e.name))
+		}
+	}
+}
+
+// recordSlowHandler records a handler run of a queue over the slow handler threshold.
+func (s *SQS) recordSlowHandler(queueURL string) {
+	if sink := s.config.Metrics; sink != nil {
+		sink.Counter(context.Background(), _metricSlowHandlers, 1, semconv.MessagingDestinationName(queueName(queueURL)))
+	}
+}
+
+// recordLatencies records the delivery latencies of messages received from a queue.
+func (s *SQS) recordLatencies(ctx context.Context, queueURL string, latencies []time.Duration) {
+	sink := s.config.Metrics
+	if sink == nil || len(latencies) == 0 {
+		return
+	}
+
+	queue := semconv.MessagingDestinationName(queueName(queueURL))
+	for _, latency := range latencies {
+		sink.Histogram(ctx, _metricDeliveryLatency, latency.Seconds(), queue)
+	}
+}
+
+// unreportedEvents returns the algorithm events counted since the last call, and marks
+// them as recorded.
+//
+// Thread-safe operation using mutex protection.
+func (a *arrakis) unreportedEvents() AlgorithmEvents {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	events := AlgorithmEvents{
+		EWMAResets:       a.events.EWMAResets - a.reportedEvents.EWMAResets,
+		Decays:           a.events.Decays - a.reportedEvents.Decays,
+		SpikeClamps:      a.events.SpikeClamps - a.reportedEvents.SpikeClamps,
+		ClassTransitions: a.events.ClassTransitions - a.reportedEvents.ClassTransitions,
+	}
+	a.reportedEvents = a.events
+
+	return events
+}
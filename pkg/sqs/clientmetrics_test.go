@@ -0,0 +1,36 @@
+package sqs
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestClientMetricsSink(t *testing.T) {
+	fake := &fakeSQS{}
+	fake.push(agedMessage("m1", time.Second))
+	fake.push(testMessage("m2", ""), testMessage("m3", ""), testMessage("m4", ""), testMessage("m5", ""),
+		testMessage("m6", ""), testMessage("m7", ""), testMessage("m8", ""), agedMessage("m9", 2*time.Second))
+	sink := newRecordingSink()
+	client := newTestSQS(fake, WithMetricsSink(sink))
+	client.EnableArrakis()
+
+	for range 2 {
+		if _, err := client.ReceiveMessage(context.Background(), "queue", 10, nil); err != nil {
+			t.Fatalf("ReceiveMessage returned error: %v", err)
+		}
+	}
+	client.recordSlowHandler("queue")
+
+	events := client.Stats()["queue"].Events
+	total := events.EWMAResets + events.Decays + events.SpikeClamps + events.ClassTransitions
+	if total == 0 || sink.counters[_metricAlgorithmEvents] != total {
+		t.Errorf("Expected the %d algorithm events recorded once, got %d", total, sink.counters[_metricAlgorithmEvents])
+	}
+	if sink.histograms[_metricDeliveryLatency] != 2 {
+		t.Errorf("Expected the latencies of the 2 messages with a SentTimestamp, got %d", sink.histograms[_metricDeliveryLatency])
+	}
+	if sink.counters[_metricSlowHandlers] != 1 {
+		t.Errorf("Expected 1 slow handler, got %d", sink.counters[_metricSlowHandlers])
+	}
+}
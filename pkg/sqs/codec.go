@@ -0,0 +1,92 @@
+package sqs
+
+import (
+	"encoding/json"
+)
+
+// Codec converts message bodies to and from Go values.
+type Codec interface {
+	// Marshal encodes v into a message body.
+	Marshal(v any) ([]byte, error)
+	// Unmarshal decodes a message body into v, a pointer.
+	Unmarshal(data []byte, v any) error
+}
+
+// JSONCodec is the default Codec, based on encoding/json.
+var JSONCodec Codec = jsonCodec{}
+
+// jsonCodec implements JSONCodec.
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+// WithCodec sets the codec decoding the bodies of the messages received by the consumer,
+// used by Message.Bind, Body and the typed handlers of a TypeRegistry (default: JSONCodec).
+// Transforms run before decoding, so the codec sees decompressed, decrypted bodies.
+//
+// Parameters:
+//   - codec: The codec of the queue payloads
+//
+// Example:
+//
+//	consumer := NewConsumer(client, queueURL, handler, WithCodec(protoCodec{}))
+func WithCodec(codec Codec) ConsumerOption {
+	return func(c *consumerConfig) {
+		c.Codec = codec
+	}
+}
+
+// Bind decodes the body of the message into v with the codec of the consumer that
+// received it (see WithCodec), or JSONCodec for messages built elsewhere.
+//
+// Parameters:
+//   - v: Pointer to the value to fill
+//
+// Returns:
+//   - error: Any decoding error of the codec
+//
+// Example:
+//
+//	var order Order
+//	if err := msg.Bind(&order); err != nil {
+//	    return err
+//	}
+func (m Message) Bind(v any) error {
+	codec := m.codec
+	if codec == nil {
+		codec = JSONCodec
+	}
+
+	return codec.Unmarshal([]byte(m.Body), v)
+}
+
+// Body decodes the body of msg into a T. It is the typed form of Message.Bind.
+//
+// Parameters:
+//   - msg: The received message
+//
+// Returns:
+//   - T: The decoded payload
+//   - error: Any decoding error of the codec
+//
+// Example:
+//
+//	handler := sqs.HandlerFunc(func(ctx context.Context, msg sqs.Message) error {
+//	    order, err := sqs.Body[Order](msg)
+//	    if err != nil {
+//	        return err
+//	    }
+//	    return orders.Create(ctx, order)
+//	})
+func Body[T any](msg Message) (T, error) {
+	var payload T
+	err := msg.Bind(&payload)
+
+	return payload, err
+}
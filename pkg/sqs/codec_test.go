@@ -0,0 +1,69 @@
+package sqs
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// upperCodec decodes JSON bodies after upper-casing them, to tell it apart from JSONCodec.
+type upperCodec struct{}
+
+func (upperCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (upperCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal([]byte(strings.ToUpper(string(data))), v)
+}
+
+func TestMessageBody(t *testing.T) {
+	type order struct {
+		ID    string `json:"id"`
+		Total int    `json:"total"`
+	}
+
+	msg := Message{Body: `{"id":"42","total":10}`}
+	decoded, err := Body[order](msg)
+	if err != nil || decoded.ID != "42" || decoded.Total != 10 {
+		t.Errorf("Expected the JSON body to be decoded, got %+v (%v)", decoded, err)
+	}
+
+	var bound order
+	if err := (Message{Body: "not json"}).Bind(&bound); err == nil {
+		t.Error("Expected an invalid body to fail decoding")
+	}
+}
+
+func TestConsumerCodec(t *testing.T) {
+	fake := &fakeSQS{}
+	message := testMessage("m1", "")
+	message.Body = aws.String(`{"name":"acme"}`)
+	fake.push(message)
+
+	var mu sync.Mutex
+	var names []string
+	handler := HandlerFunc(func(ctx context.Context, msg Message) error {
+		payload, err := Body[map[string]string](msg)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		names = append(names, payload["NAME"])
+		mu.Unlock()
+		return nil
+	})
+
+	consumer := NewConsumer(newTestSQS(fake), "queue", handler, WithCodec(upperCodec{}))
+	runConsumer(t, consumer, func() bool { return len(fake.deletedHandles()) == 1 })
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(names) != 1 || names[0] != "ACME" {
+		t.Errorf("Expected the body decoded with the consumer codec, got %v", names)
+	}
+}
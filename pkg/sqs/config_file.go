@@ -0,0 +1,357 @@
+package sqs
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"time"
+)
+
+// Configuration file watching
+const (
+	_defaultConfigWatchInterval = 5 * time.Second // How often a watched file is checked for changes
+)
+
+// FileConfig is the JSON representation of the client configuration used by
+// LoadConfigFile and WatchConfigFile. Fields left out (or set to zero) keep their
+// default values.
+//
+// Example file:
+//
+//	{
+//	  "visibility_timeout": 60,
+//	  "enable_adaptive_polling": true,
+//	  "idle_wait_time_seconds": 20,
+//	  "very_high_volume_wait_time_seconds": 1,
+//	  "ewma_alpha": 0.3,
+//	  "adaptive_ewma_alpha_min": 0.1,
+//	  "adaptive_ewma_alpha_max": 0.8
+//	}
+type FileConfig struct {
+	VisibilityTimeout             int     `json:"visibility_timeout"`
+	EnableAdaptivePolling         *bool   `json:"enable_adaptive_polling"`
+	IdleWaitTimeSeconds           int     `json:"idle_wait_time_seconds"`
+	LowVolumeWaitTimeSeconds      int     `json:"low_volume_wait_time_seconds"`
+	MediumVolumeWaitTimeSeconds   int     `json:"medium_volume_wait_time_seconds"`
+	HighVolumeWaitTimeSeconds     int     `json:"high_volume_wait_time_seconds"`
+	VeryHighVolumeWaitTimeSeconds int     `json:"very_high_volume_wait_time_seconds"`
+	EwmaAlpha                     float64 `json:"ewma_alpha"`
+	DropDetectionThreshold        int     `json:"drop_detection_threshold"`
+	LowVolumeMessageThreshold     int     `json:"low_volume_message_threshold"`
+	EwmaResetAverageThreshold     float64 `json:"ewma_reset_average_threshold"`
+	MinResetIntervalSeconds       int     `json:"min_reset_interval_seconds"`
+	ConsecutiveEmptyThreshold     int     `json:"consecutive_empty_threshold"`
+	MessageAgeThresholdSeconds    int     `json:"message_age_threshold_seconds"`
+	MessageAgeTargetSeconds       int     `json:"message_age_target_seconds"`
+	BackToBackPolling             *bool   `json:"back_to_back_polling"`
+	PollPacingMilliseconds        int     `json:"poll_pacing_milliseconds"`
+	ContinuousWaitTimeScale       float64 `json:"continuous_wait_time_scale"`
+	AdaptiveEwmaAlphaMin          float64 `json:"adaptive_ewma_alpha_min"`
+	AdaptiveEwmaAlphaMax          float64 `json:"adaptive_ewma_alpha_max"`
+}
+
+// ReloadEvent is emitted by WatchConfigFile every time the watched file changes.
+type ReloadEvent struct {
+	// Path is the watched configuration file.
+	Path string
+	// Time is when the change was detected.
+	Time time.Time
+	// Err is set when the file couldn't be read or parsed; the running configuration
+	// is then left untouched.
+	Err error
+}
+
+// LoadConfigFile reads a JSON configuration file (see FileConfig) and returns it as an
+// Option for NewSQSWithOptions.
+//
+// Parameters:
+//   - path: Path of the JSON configuration file
+//
+// Returns:
+//   - Option: Applies the file settings to the client configuration
+//   - error: Any error reading or parsing the file
+//
+// Example:
+//
+//	fileOption, err := sqs.LoadConfigFile("/etc/arrakis.json")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	sqsClient := sqs.NewSQSWithOptions(&cfg, fileOption)
+func LoadConfigFile(path string) (Option, error) {
+	fileConfig, err := readConfigFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return fileConfig.apply, nil
+}
+
+// WatchConfigFile checks a JSON configuration file (see FileConfig) for changes and applies
+// them to the running client, without restarting consumers. Wait times, thresholds, the
+// EWMA alpha and the visibility timeout are swapped atomically: every poll sees either the
+// old or the new configuration, never a mix. Settings missing from the file return to their
+// defaults; adaptive polling is only toggled if the file sets enable_adaptive_polling.
+//
+// The file is checked every 5 seconds until ctx is cancelled. onReload (optional) is called
+// after every detected change, with Err set if the new file was rejected.
+//
+// Parameters:
+//   - ctx: Context that stops the watcher when cancelled
+//   - path: Path of the JSON configuration file
+//   - onReload: Optional callback notified of every reload attempt
+//
+// Returns:
+//   - error: Any error accessing the file when the watcher starts
+//
+// Example:
+//
+//	err := sqsClient.WatchConfigFile(ctx, "/etc/arrakis.json", func(event sqs.ReloadEvent) {
+//	    log.Printf("configuration reloaded from %s (err: %v)", event.Path, event.Err)
+//	})
+func (s *SQS) WatchConfigFile(ctx context.Context, path string, onReload func(ReloadEvent)) error {
+	return s.watchConfigFile(ctx, path, _defaultConfigWatchInterval, onReload)
+}
+
+// watchConfigFile implements WatchConfigFile with a configurable check interval.
+func (s *SQS) watchConfigFile(ctx context.Context, path string, interval time.Duration, onReload func(ReloadEvent)) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		lastModified := info.ModTime()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			info, err := os.Stat(path)
+			if err != nil || info.ModTime().Equal(lastModified) {
+				continue
+			}
+			lastModified = info.ModTime()
+
+			event := ReloadEvent{Path: path, Time: time.Now(), Err: s.reloadConfigFile(path)}
+			if onReload != nil {
+				onReload(event)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// reloadConfigFile reads a configuration file and applies it to the running client.
+func (s *SQS) reloadConfigFile(path string) error {
+	fileConfig, err := readConfigFile(path)
+	if err != nil {
+		return err
+	}
+
+	// Build the new settings from scratch so removed entries fall back to defaults
+	var fresh config
+	fileConfig.apply(&fresh)
+	setDefaults(&fresh)
+
+	// Swap the settings and the enable flag together, so receives never see one without
+	// the other
+	s.interruptDisabled(func() {
+		s.config.mu.Lock()
+		defer s.config.mu.Unlock()
+
+		if fileConfig.EnableAdaptivePolling == nil {
+			fresh.AdaptivePolling.EnableAdaptivePolling = s.config.AdaptivePolling.EnableAdaptivePolling
+		}
+		s.config.VisibilityTimeout = fresh.VisibilityTimeout
+		s.config.AdaptivePolling = fresh.AdaptivePolling
+	})
+
+	s.syncStates(fresh.AdaptivePolling)
+
+	return nil
+}
+
+// syncStates copies the EWMA parameters of settings into every queue state, which keep
+// their own copy.
+func (s *SQS) syncStates(settings adaptivePolling) {
+	s.statesMu.RLock()
+	defer s.statesMu.RUnlock()
+
+	for _, state := range s.states {
+		state.mu.Lock()
+		state.ewmaAlpha = settings.EwmaAlpha
+		state.dropDetectionThreshold = int64(settings.DropDetectionThreshold)
+		state.mu.Unlock()
+	}
+}
+
+// Config returns the running configuration of the client in the FileConfig format, so
+// it can be inspected or saved and loaded back with LoadConfigFile.
+//
+// Returns:
+//   - FileConfig: The current settings, with every field set
+func (s *SQS) Config() FileConfig {
+	s.config.mu.RLock()
+	defer s.config.mu.RUnlock()
+
+	settings := s.config.AdaptivePolling
+	enabled := settings.EnableAdaptivePolling
+	backToBack := settings.BackToBackPolling
+
+	return FileConfig{
+		VisibilityTimeout:             s.config.VisibilityTimeout,
+		EnableAdaptivePolling:         &enabled,
+		IdleWaitTimeSeconds:           settings.IdleWaitTimeSeconds,
+		LowVolumeWaitTimeSeconds:      settings.LowVolumeWaitTimeSeconds,
+		MediumVolumeWaitTimeSeconds:   settings.MediumVolumeWaitTimeSeconds,
+		HighVolumeWaitTimeSeconds:     settings.HighVolumeWaitTimeSeconds,
+		VeryHighVolumeWaitTimeSeconds: settings.VeryHighVolumeWaitTimeSeconds,
+		EwmaAlpha:                     settings.EwmaAlpha,
+		DropDetectionThreshold:        settings.DropDetectionThreshold,
+		LowVolumeMessageThreshold:     settings.LowVolumeMessageThreshold,
+		EwmaResetAverageThreshold:     settings.EwmaResetAverageThreshold,
+		MinResetIntervalSeconds:       int(settings.MinResetInterval / time.Second),
+		ConsecutiveEmptyThreshold:     settings.ConsecutiveEmptyThreshold,
+		MessageAgeThresholdSeconds:    int(settings.MessageAgeThreshold / time.Second),
+		MessageAgeTargetSeconds:       int(settings.MessageAgeTarget / time.Second),
+		BackToBackPolling:             &backToBack,
+		PollPacingMilliseconds:        int(settings.PollPacing / time.Millisecond),
+		ContinuousWaitTimeScale:       settings.ContinuousWaitTimeScale,
+		AdaptiveEwmaAlphaMin:          settings.AdaptiveAlphaMin,
+		AdaptiveEwmaAlphaMax:          settings.AdaptiveAlphaMax,
+	}
+}
+
+// UpdateConfig applies the settings present in update (non-zero fields, and
+// enable_adaptive_polling when set) to the running client, leaving the others unchanged.
+// Like WatchConfigFile, the change is atomic and doesn't require restarting consumers.
+//
+// Parameters:
+//   - update: The settings to change
+//
+// Example:
+//
+//	// Poll less eagerly during a maintenance window
+//	sqsClient.UpdateConfig(sqs.FileConfig{IdleWaitTimeSeconds: 20, LowVolumeWaitTimeSeconds: 20})
+func (s *SQS) UpdateConfig(update FileConfig) {
+	var settings adaptivePolling
+	s.interruptDisabled(func() {
+		s.config.mu.Lock()
+		defer s.config.mu.Unlock()
+
+		update.apply(s.config)
+		settings = s.config.AdaptivePolling
+	})
+
+	s.syncStates(settings)
+}
+
+// readConfigFile reads and parses a JSON configuration file.
+func readConfigFile(path string) (*FileConfig, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var fileConfig FileConfig
+	if err := json.Unmarshal(raw, &fileConfig); err != nil {
+		return nil, err
+	}
+
+	return &fileConfig, nil
+}
+
+// apply copies the settings present in the file into the configuration.
+func (f *FileConfig) apply(c *config) {
+	if f.VisibilityTimeout != 0 {
+		c.VisibilityTimeout = f.VisibilityTimeout
+		c.AdaptivePolling.VisibilityTimeout = f.VisibilityTimeout
+	}
+
+	if f.EnableAdaptivePolling != nil {
+		c.AdaptivePolling.EnableAdaptivePolling = *f.EnableAdaptivePolling
+	}
+
+	if f.IdleWaitTimeSeconds != 0 {
+		c.AdaptivePolling.IdleWaitTimeSeconds = f.IdleWaitTimeSeconds
+	}
+
+	if f.LowVolumeWaitTimeSeconds != 0 {
+		c.AdaptivePolling.LowVolumeWaitTimeSeconds = f.LowVolumeWaitTimeSeconds
+	}
+
+	if f.MediumVolumeWaitTimeSeconds != 0 {
+		c.AdaptivePolling.MediumVolumeWaitTimeSeconds = f.MediumVolumeWaitTimeSeconds
+	}
+
+	if f.HighVolumeWaitTimeSeconds != 0 {
+		c.AdaptivePolling.HighVolumeWaitTimeSeconds = f.HighVolumeWaitTimeSeconds
+	}
+
+	if f.VeryHighVolumeWaitTimeSeconds != 0 {
+		c.AdaptivePolling.VeryHighVolumeWaitTimeSeconds = f.VeryHighVolumeWaitTimeSeconds
+	}
+
+	if f.EwmaAlpha != 0 {
+		c.AdaptivePolling.EwmaAlpha = f.EwmaAlpha
+	}
+
+	if f.DropDetectionThreshold != 0 {
+		c.AdaptivePolling.DropDetectionThreshold = f.DropDetectionThreshold
+	}
+
+	if f.LowVolumeMessageThreshold != 0 {
+		c.AdaptivePolling.LowVolumeMessageThreshold = f.LowVolumeMessageThreshold
+	}
+
+	if f.EwmaResetAverageThreshold != 0 {
+		c.AdaptivePolling.EwmaResetAverageThreshold = f.EwmaResetAverageThreshold
+	}
+
+	if f.MinResetIntervalSeconds != 0 {
+		c.AdaptivePolling.MinResetInterval = time.Duration(f.MinResetIntervalSeconds) * time.Second
+	}
+
+	if f.ConsecutiveEmptyThreshold != 0 {
+		c.AdaptivePolling.ConsecutiveEmptyThreshold = f.ConsecutiveEmptyThreshold
+	}
+
+	if f.MessageAgeThresholdSeconds != 0 {
+		c.AdaptivePolling.MessageAgeThreshold = time.Duration(f.MessageAgeThresholdSeconds) * time.Second
+	}
+
+	if f.MessageAgeTargetSeconds != 0 {
+		c.AdaptivePolling.MessageAgeTarget = time.Duration(f.MessageAgeTargetSeconds) * time.Second
+	}
+
+	if f.BackToBackPolling != nil {
+		c.AdaptivePolling.BackToBackPolling = *f.BackToBackPolling
+	}
+
+	if f.PollPacingMilliseconds != 0 {
+		c.AdaptivePolling.PollPacing = time.Duration(f.PollPacingMilliseconds) * time.Millisecond
+	}
+
+	if f.ContinuousWaitTimeScale != 0 {
+		c.AdaptivePolling.ContinuousWaitTimeScale = f.ContinuousWaitTimeScale
+	}
+
+	if f.AdaptiveEwmaAlphaMin != 0 || f.AdaptiveEwmaAlphaMax != 0 {
+		minAlpha, maxAlpha := c.AdaptivePolling.AdaptiveAlphaMin, c.AdaptivePolling.AdaptiveAlphaMax
+		if f.AdaptiveEwmaAlphaMin != 0 {
+			minAlpha = f.AdaptiveEwmaAlphaMin
+		}
+		if f.AdaptiveEwmaAlphaMax != 0 {
+			maxAlpha = f.AdaptiveEwmaAlphaMax
+		}
+		// Bounded like WithAdaptiveEwmaAlpha
+		WithAdaptiveEwmaAlpha(minAlpha, maxAlpha)(c)
+	}
+}
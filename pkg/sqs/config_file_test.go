@@ -0,0 +1,210 @@
+package sqs
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeConfigFile(t *testing.T, path, content string, modTime time.Time) {
+	t.Helper()
+
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatalf("Failed to set config file time: %v", err)
+	}
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "arrakis.json")
+	writeConfigFile(t, path, `{"visibility_timeout": 45, "enable_adaptive_polling": true, "ewma_alpha": 0.5}`, time.Now())
+
+	option, err := LoadConfigFile(path)
+	if err != nil {
+		t.Fatalf("LoadConfigFile returned error: %v", err)
+	}
+
+	client := newTestSQS(&fakeSQS{}, option)
+
+	if client.config.VisibilityTimeout != 45 {
+		t.Errorf("Expected VisibilityTimeout 45, got %d", client.config.VisibilityTimeout)
+	}
+
+	if !client.IsArrakisEnabled() {
+		t.Error("Expected adaptive polling to be enabled")
+	}
+
+	if client.config.AdaptivePolling.EwmaAlpha != 0.5 {
+		t.Errorf("Expected EwmaAlpha 0.5, got %f", client.config.AdaptivePolling.EwmaAlpha)
+	}
+
+	if client.config.AdaptivePolling.IdleWaitTimeSeconds != _defaultIdleWaitTimeSeconds {
+		t.Errorf("Expected default IdleWaitTimeSeconds, got %d", client.config.AdaptivePolling.IdleWaitTimeSeconds)
+	}
+}
+
+func TestLoadConfigFileInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "arrakis.json")
+	writeConfigFile(t, path, `{not json`, time.Now())
+
+	if _, err := LoadConfigFile(path); err == nil {
+		t.Error("Expected an error for invalid JSON")
+	}
+}
+
+func TestWatchConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "arrakis.json")
+	start := time.Now().Add(-time.Minute)
+	writeConfigFile(t, path, `{"idle_wait_time_seconds": 20}`, start)
+
+	client := newTestSQS(&fakeSQS{})
+	state := client.state("queue")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := make(chan ReloadEvent, 1)
+	err := client.watchConfigFile(ctx, path, 5*time.Millisecond, func(event ReloadEvent) {
+		events <- event
+	})
+	if err != nil {
+		t.Fatalf("watchConfigFile returned error: %v", err)
+	}
+
+	writeConfigFile(t, path, `{"idle_wait_time_seconds": 15, "visibility_timeout": 90, "ewma_alpha": 0.4, "enable_adaptive_polling": true}`, start.Add(time.Second))
+
+	select {
+	case event := <-events:
+		if event.Err != nil {
+			t.Fatalf("Expected a successful reload, got %v", event.Err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected a reload event")
+	}
+
+	settings := client.config.adaptivePolling()
+	if settings.IdleWaitTimeSeconds != 15 {
+		t.Errorf("Expected IdleWaitTimeSeconds 15, got %d", settings.IdleWaitTimeSeconds)
+	}
+
+	if client.config.visibilityTimeout() != 90 {
+		t.Errorf("Expected VisibilityTimeout 90, got %d", client.config.visibilityTimeout())
+	}
+
+	if !client.IsArrakisEnabled() {
+		t.Error("Expected adaptive polling to be enabled by the reload")
+	}
+
+	state.mu.Lock()
+	alpha := state.ewmaAlpha
+	state.mu.Unlock()
+
+	if alpha != 0.4 {
+		t.Errorf("Expected existing queue state to use EwmaAlpha 0.4, got %f", alpha)
+	}
+
+	// A broken file is reported and leaves the configuration untouched
+	writeConfigFile(t, path, `{broken`, start.Add(2*time.Second))
+
+	select {
+	case event := <-events:
+		if event.Err == nil {
+			t.Error("Expected a reload error for invalid JSON")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected a reload event")
+	}
+
+	if client.config.adaptivePolling().IdleWaitTimeSeconds != 15 {
+		t.Error("Expected the previous configuration to be kept after a failed reload")
+	}
+}
+
+func TestWatchConfigFileMissing(t *testing.T) {
+	client := newTestSQS(&fakeSQS{})
+
+	err := client.WatchConfigFile(context.Background(), filepath.Join(t.TempDir(), "missing.json"), nil)
+	if err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
+
+func TestUpdateConfig(t *testing.T) {
+	client := newTestSQS(&fakeSQS{})
+	state := client.state("queue")
+
+	client.UpdateConfig(FileConfig{IdleWaitTimeSeconds: 15, EwmaAlpha: 0.6})
+
+	current := client.Config()
+	if current.IdleWaitTimeSeconds != 15 || current.EwmaAlpha != 0.6 {
+		t.Errorf("Expected the updated settings, got %+v", current)
+	}
+	if current.LowVolumeWaitTimeSeconds != _defaultLowVolumeWaitTimeSeconds || current.VisibilityTimeout != _defaultVisibilityTimeout {
+		t.Errorf("Expected the other settings to be left unchanged, got %+v", current)
+	}
+	if current.EnableAdaptivePolling == nil || *current.EnableAdaptivePolling {
+		t.Error("Expected adaptive polling to stay disabled")
+	}
+
+	state.mu.RLock()
+	alpha := state.ewmaAlpha
+	state.mu.RUnlock()
+	if alpha != 0.6 {
+		t.Errorf("Expected the queue states to get the new alpha, got %v", alpha)
+	}
+
+	enabled := true
+	client.UpdateConfig(FileConfig{EnableAdaptivePolling: &enabled})
+	if !client.IsArrakisEnabled() {
+		t.Error("Expected adaptive polling to be enabled by the update")
+	}
+}
+
+func TestWatchConfigFileAdaptiveEwmaAlpha(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "arrakis.json")
+	start := time.Now().Add(-time.Minute)
+	writeConfigFile(t, path, `{"adaptive_ewma_alpha_min": 0.1, "adaptive_ewma_alpha_max": 0.8}`, start)
+
+	option, err := LoadConfigFile(path)
+	if err != nil {
+		t.Fatalf("LoadConfigFile returned error: %v", err)
+	}
+	client := newTestSQS(&fakeSQS{}, option)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := make(chan ReloadEvent, 1)
+	if err := client.watchConfigFile(ctx, path, 5*time.Millisecond, func(event ReloadEvent) { events <- event }); err != nil {
+		t.Fatalf("watchConfigFile returned error: %v", err)
+	}
+
+	// The bounds survive a reload of the file setting them
+	writeConfigFile(t, path, `{"idle_wait_time_seconds": 15, "adaptive_ewma_alpha_min": 0.2, "adaptive_ewma_alpha_max": 0.9}`, start.Add(time.Second))
+
+	select {
+	case event := <-events:
+		if event.Err != nil {
+			t.Fatalf("Expected a successful reload, got %v", event.Err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected a reload event")
+	}
+
+	current := client.Config()
+	if current.AdaptiveEwmaAlphaMin != 0.2 || current.AdaptiveEwmaAlphaMax != 0.9 {
+		t.Errorf("Expected the reloaded alpha bounds 0.2-0.9, got %v-%v", current.AdaptiveEwmaAlphaMin, current.AdaptiveEwmaAlphaMax)
+	}
+
+	// UpdateConfig changes one bound and keeps the other
+	client.UpdateConfig(FileConfig{AdaptiveEwmaAlphaMax: 0.5})
+	settings := client.config.adaptivePolling()
+	if settings.AdaptiveAlphaMin != 0.2 || settings.AdaptiveAlphaMax != 0.5 {
+		t.Errorf("Expected the alpha bounds 0.2-0.5, got %v-%v", settings.AdaptiveAlphaMin, settings.AdaptiveAlphaMax)
+	}
+}
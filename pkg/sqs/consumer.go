@@ -0,0 +1,803 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Default consumer configuration values
+const (
+	_defaultConsumerWorkers      = 1           // Number of workers processing messages concurrently
+	_defaultPartitionQueueSize   = 10          // Buffered messages per partition worker
+	_consumerReceiveErrorBackoff = time.Second // Pause after a failed ReceiveMessage call
+	_allMessageAttributes        = "All"       // Requests every user-defined message attribute
+)
+
+// Handler processes messages delivered by a Consumer.
+//
+// Returning nil acknowledges the message, which is then deleted from the queue.
+// Returning an error leaves the message in the queue so it becomes visible again
+// once its visibility timeout expires.
+type Handler interface {
+	Handle(ctx context.Context, msg Message) error
+}
+
+// HandlerFunc adapts an ordinary function to the Handler interface.
+type HandlerFunc func(ctx context.Context, msg Message) error
+
+// Handle calls f(ctx, msg).
+func (f HandlerFunc) Handle(ctx context.Context, msg Message) error {
+	return f(ctx, msg)
+}
+
+// consumerConfig holds the configuration of a Consumer.
+type consumerConfig struct {
+	// Workers is the number of goroutines processing messages from the shared pool.
+	Workers int
+	// MaxMessages is the maximum number of messages requested per ReceiveMessage call.
+	MaxMessages int32
+
+	// MinWorkers and MaxWorkers bound the worker pool when autoscaling is enabled.
+	MinWorkers int
+	MaxWorkers int
+
+	// Partitions is the number of per-group workers. Zero disables group partitioning.
+	Partitions int
+	// PartitionQueueSize bounds how many messages may wait for each partition worker.
+	PartitionQueueSize int
+	// SharedPool keeps the shared worker pool on FIFO queues instead of partitioning by group.
+	SharedPool bool
+
+	// NackStrategy computes the requeue delay of retryable errors. Nil disables nacks.
+	NackStrategy NackStrategy
+
+	// Failover configures the secondary queue. Nil disables failover.
+	Failover *failoverConfig
+
+	// ReceiveFilter selects the messages passed to the handler. Nil accepts every message.
+	ReceiveFilter func(Message) bool
+	// FilterAction is applied to messages rejected by ReceiveFilter.
+	FilterAction FilterAction
+
+	// GroupSharding assigns FIFO message groups to instances. Nil handles every group.
+	GroupSharding *Coordinator
+
+	// Transforms are applied in order to every message before the handler.
+	Transforms []Transform
+	// Codec decodes message bodies in Message.Bind. Nil uses JSONCodec.
+	Codec Codec
+
+	// MaxInFlight bounds the messages received but not yet acknowledged. Zero disables it.
+	MaxInFlight int
+	// MaxInFlightBytes bounds the payload bytes of those messages. Zero disables it.
+	MaxInFlightBytes int64
+	// MaxInFlightPerGroup bounds those messages per message group. Zero disables it.
+	MaxInFlightPerGroup int
+
+	// SlowHandlerFraction is the fraction of the visibility timeout after which a handler is
+	// reported as slow. Zero disables slow handler detection.
+	SlowHandlerFraction float64
+	// OnSlowHandler is notified of slow handlers, if not nil.
+	OnSlowHandler func(SlowHandler)
+	// WatchVisibilityExpiry enables the detection of visibility expiring during handling.
+	WatchVisibilityExpiry bool
+	// OnVisibilityExpired is notified of visibility expiring during handling, if not nil.
+	OnVisibilityExpired func(VisibilityExpired)
+	// CancelOnVisibilityExpiry cancels the context of handlers whose visibility expired.
+	CancelOnVisibilityExpiry bool
+
+	// RetryAttribute is the message attribute holding the message type of RetryPolicies.
+	RetryAttribute string
+	// RetryPolicies are the retry policies per message type. Nil disables them.
+	RetryPolicies map[string]RetryPolicy
+
+	// RetryQueue re-sends failed messages to retry queues. Nil disables it.
+	RetryQueue *RetryQueuePolicy
+
+	// ImmediateRetries are the error classes retried without waiting, in order. Nil
+	// disables immediate retries.
+	ImmediateRetries []immediateRetry
+
+	// Quarantine is the quarantine policy. Nil disables quarantining.
+	Quarantine *QuarantinePolicy
+
+	// OnDelete is notified of every successful delete, if not nil.
+	OnDelete func(DeleteAudit)
+
+	// DedupWindow is how long handled message IDs are remembered. Zero disables
+	// deduplication.
+	DedupWindow time.Duration
+	// DedupSize bounds the number of message IDs remembered.
+	DedupSize int
+	// Priority is the priority queue whose levels are polled. Nil polls the consumer
+	// queue only.
+	Priority *PriorityQueue
+
+	// ValidateQueues checks the queues of the consumer before Start polls them.
+	ValidateQueues bool
+	// ValidatedQueues are extra queues checked along with them.
+	ValidatedQueues []string
+
+	// SharedDedup deduplicates messages across queues through a shared store. Nil
+	// disables it.
+	SharedDedup *sharedDedupConfig
+
+	// RateLimiter paces polling to the permits it grants. Nil disables rate limiting.
+	RateLimiter RateLimiter
+
+	// IdleBackoff is the longest sleep between receives of an empty queue. Zero disables it.
+	IdleBackoff time.Duration
+	// IdleProbeInterval is the interval between zero-wait probes while sleeping.
+	IdleProbeInterval time.Duration
+
+	// PauseWindows are the periods during which the consumer doesn't poll.
+	PauseWindows []PauseWindow
+	// OnPauseWindowChange is notified when the consumer enters or leaves a pause window.
+	OnPauseWindowChange func(PauseWindowChange)
+}
+
+// ConsumerOption is a function type for configuring a Consumer with the functional options pattern.
+type ConsumerOption func(*consumerConfig)
+
+// WithWorkers sets how many messages the consumer processes concurrently.
+//
+// Parameters:
+//   - workers: Number of worker goroutines (values below 1 are treated as 1)
+func WithWorkers(workers int) ConsumerOption {
+	return func(c *consumerConfig) {
+		c.Workers = workers
+	}
+}
+
+// WithAutoscaling lets the consumer grow and shrink its worker pool between minWorkers
+// and maxWorkers. The pool follows the Arrakis volume class of the client (idle queues run
+// with minWorkers, very high volume queues with maxWorkers) and grows further whenever
+// received messages are waiting for a free worker.
+//
+// Autoscaling overrides WithWorkers and has no effect when group partitioning is enabled,
+// since partitions must keep a fixed group-to-worker mapping.
+//
+// Parameters:
+//   - minWorkers: Workers kept running during quiet periods (at least 1)
+//   - maxWorkers: Upper bound on concurrent workers during spikes
+//
+// Example:
+//
+//	consumer := NewConsumer(client, queueURL, handler, WithAutoscaling(2, 32))
+func WithAutoscaling(minWorkers, maxWorkers int) ConsumerOption {
+	return func(c *consumerConfig) {
+		c.MinWorkers = minWorkers
+		c.MaxWorkers = maxWorkers
+	}
+}
+
+// WithMaxMessages sets the maximum number of messages requested per ReceiveMessage call.
+//
+// Parameters:
+//   - maxMessages: Batch size between 1 and 10
+func WithMaxMessages(maxMessages int32) ConsumerOption {
+	return func(c *consumerConfig) {
+		c.MaxMessages = maxMessages
+	}
+}
+
+// WithGroupPartitioning routes messages to a fixed set of partition workers by hashing
+// their MessageGroupId. Every message of a group is always handled by the same worker,
+// in the order it was received, while different groups are processed in parallel.
+//
+// Each partition worker has a bounded queue; when it is full, polling blocks until the
+// worker catches up. Messages without a group ID are spread across partitions by MessageId.
+// When partitioning is enabled the WithWorkers setting is ignored.
+//
+// Parameters:
+//   - partitions: Number of partition workers (recommended: number of concurrent groups you expect)
+//   - queueSize: Messages buffered per partition before polling blocks (recommended: 10)
+//
+// Example:
+//
+//	consumer := NewConsumer(client, queueURL, handler, WithGroupPartitioning(16, 10))
+func WithGroupPartitioning(partitions, queueSize int) ConsumerOption {
+	return func(c *consumerConfig) {
+		c.Partitions = partitions
+		c.PartitionQueueSize = queueSize
+	}
+}
+
+// WithSharedPool keeps the shared worker pool on FIFO queues, disabling the default group
+// partitioning. Messages of the same group may then be handled concurrently and out of
+// order; use it only when handlers don't depend on ordering.
+func WithSharedPool() ConsumerOption {
+	return func(c *consumerConfig) {
+		c.SharedPool = true
+	}
+}
+
+// WithNackBackoff enables visibility-based backoff for retryable handler errors
+// (see Retryable). Instead of reappearing after the full visibility timeout, a failed
+// message is made visible again after baseDelay * 2^(receiveCount-1), capped at maxDelay.
+//
+// Errors that are not marked as retryable keep the default behavior. It is a shorthand for
+// WithNackStrategy(ExponentialNack(baseDelay, maxDelay, 0)); see WithNackStrategy for other
+// delay curves.
+//
+// Parameters:
+//   - baseDelay: Delay after the first failed delivery (recommended: 1-5 seconds, 0 disables it)
+//   - maxDelay: Maximum delay between attempts (at most 12 hours)
+//
+// Example:
+//
+//	consumer := NewConsumer(client, queueURL, handler, WithNackBackoff(2*time.Second, 5*time.Minute))
+func WithNackBackoff(baseDelay, maxDelay time.Duration) ConsumerOption {
+	return func(c *consumerConfig) {
+		c.NackStrategy = nil
+		if baseDelay > 0 {
+			c.NackStrategy = ExponentialNack(baseDelay, maxDelay, 0)
+		}
+	}
+}
+
+// setConsumerDefaults initializes the consumer configuration with sensible default values.
+func setConsumerDefaults(c *consumerConfig) {
+	if c.Workers < 1 {
+		c.Workers = _defaultConsumerWorkers
+	}
+
+	// Without autoscaling the pool has a fixed size
+	if c.MaxWorkers == 0 {
+		c.MinWorkers = c.Workers
+		c.MaxWorkers = c.Workers
+	}
+
+	if c.MinWorkers < 1 {
+		c.MinWorkers = 1
+	}
+
+	if c.MaxWorkers < c.MinWorkers {
+		c.MaxWorkers = c.MinWorkers
+	}
+
+	if c.MaxMessages < 1 || c.MaxMessages > _defaultNumberOfMessages {
+		c.MaxMessages = _defaultNumberOfMessages
+	}
+
+	if c.Partitions > 0 && c.PartitionQueueSize < 1 {
+		c.PartitionQueueSize = _defaultPartitionQueueSize
+	}
+}
+
+// Consumer continuously polls a queue using the SQS client (and therefore Arrakis
+// adaptive polling, when enabled) and dispatches every message to a Handler.
+// Successfully handled messages are deleted from the queue.
+type Consumer struct {
+	client   *SQS
+	queueURL string
+	handler  Handler
+	config   consumerConfig
+
+	state      *arrakis           // Adaptive polling state of the queue, fed with the in-flight count
+	pool       *workerPool        // Shared worker pool (nil when group partitioning is enabled)
+	failover   *failover          // Primary/secondary switching (nil when failover is disabled)
+	secondary  queueSource        // Secondary queue, when failover is enabled
+	priorities *priorityScheduler // Level polled next (nil without a priority queue)
+	limiter    *inFlightLimiter   // Bound on unacknowledged messages (nil when unlimited)
+	budget     *byteBudget        // Bound on their payload bytes (nil when unlimited)
+	groups     *groupLimiter      // Bound on them per message group (nil when unlimited)
+	gate       pauseGate          // Blocks polling while the consumer is paused
+	scheduled  pauseGate          // Blocks polling during pause windows
+	window     PauseWindow        // Pause window last entered, only used by followPauseWindows
+	inFlight   inFlightSet        // Messages dispatched and not yet acknowledged
+	expiries   expiryWatches      // Visibility expiry timers of the messages being handled
+	quarantine *quarantine        // Moves unprocessable messages aside (nil when disabled)
+	dedup      *dedupWindow       // Recently handled message IDs (nil when disabled)
+	idle       *idleBackoff       // Sleeps between receives of an empty queue (nil when disabled)
+	wg         sync.WaitGroup
+}
+
+// NewConsumer creates a Consumer for a single queue.
+//
+// On FIFO queues group partitioning is enabled by default, with one partition per worker,
+// so messages of a group are handled in order while groups are processed in parallel (the
+// way high-throughput FIFO queues scale). See WithGroupPartitioning and WithSharedPool.
+//
+// Parameters:
+//   - client: The SQS client used to receive and delete messages
+//   - queueURL: The URL of the queue to consume
+//   - handler: The handler invoked for every message
+//   - options: A list of functional options to configure the consumer
+//
+// Returns:
+//   - *Consumer: A consumer ready to be started with Start
+//
+// Example:
+//
+//	consumer := NewConsumer(sqsClient, queueURL, HandlerFunc(func(ctx context.Context, msg Message) error {
+//	    log.Printf("processing %s", msg.ID)
+//	    return nil
+//	}), WithWorkers(4))
+//	err := consumer.Start(ctx)
+func NewConsumer(client *SQS, queueURL string, handler Handler, options ...ConsumerOption) *Consumer {
+	var config consumerConfig
+
+	// Apply any provided options
+	for _, opt := range options {
+		opt(&config)
+	}
+
+	// Messages of a FIFO group must be handled in order, so FIFO queues process groups in
+	// parallel (one partition per worker) rather than sharing the pool
+	if isFIFOQueue(queueURL) && config.Partitions == 0 && !config.SharedPool {
+		config.Partitions = max(config.Workers, config.MaxWorkers, _defaultConsumerWorkers)
+	}
+
+	// Fill in anything left unset
+	if config.MaxMessages == 0 {
+		config.MaxMessages = int32(client.config.batchSize())
+	}
+	setConsumerDefaults(&config)
+
+	c := &Consumer{
+		client:     client,
+		queueURL:   queueURL,
+		handler:    handler,
+		config:     config,
+		state:      client.state(queueURL),
+		failover:   newFailover(config.Failover, client.logger()),
+		limiter:    newInFlightLimiter(config.MaxInFlight),
+		budget:     newByteBudget(config.MaxInFlightBytes),
+		groups:     newGroupLimiter(config.MaxInFlightPerGroup),
+		quarantine: newQuarantine(config.Quarantine),
+		dedup:      newDedupWindow(config.DedupWindow, config.DedupSize),
+		idle:       newIdleBackoff(config.IdleBackoff, config.IdleProbeInterval),
+		priorities: newPriorityScheduler(config.Priority),
+	}
+
+	if config.Failover != nil {
+		c.secondary = queueSource{
+			client:   config.Failover.Secondary,
+			queueURL: config.Failover.SecondaryURL,
+			state:    config.Failover.Secondary.state(config.Failover.SecondaryURL),
+		}
+	}
+
+	if config.Partitions == 0 {
+		c.pool = newWorkerPool(config.MinWorkers, config.MaxWorkers, c.process)
+	}
+
+	return c
+}
+
+// Start polls the queue and dispatches messages until ctx is cancelled. It then stops
+// polling, waits for the messages being handled and returns. Messages received but not
+// handed to a worker yet are made visible again right away, with a visibility timeout of
+// 0, so other consumers pick them up instead of waiting out the visibility timeout.
+//
+// Handlers receive a context that is not cancelled when polling stops, so messages
+// already received can finish processing during shutdown.
+//
+// If ctx has a deadline (e.g., the grace period of a rollout), the consumer stops issuing
+// new ReceiveMessage calls once the deadline is closer than the next wait time plus the
+// visibility timeout: messages received that late would likely be abandoned mid-processing
+// and redelivered anyway. Start then returns as soon as in-flight messages are handled.
+//
+// Closing the client (see SQS.Close) stops the consumer the same way, without waiting
+// for the receive in progress to complete.
+//
+// Returns:
+//   - error: nil when stopped through ctx, ErrClosed when the client was closed, or the
+//     validation errors of WithValidateQueues
+func (c *Consumer) Start(ctx context.Context) error {
+	if err := c.validateQueues(ctx); err != nil {
+		return err
+	}
+
+	handlerCtx := context.WithoutCancel(ctx)
+
+	// Workers stop taking messages as soon as polling stops; the messages they didn't
+	// start are made visible again rather than handled during shutdown
+	polling, stopPolling := context.WithCancel(ctx)
+	dispatch, stop := c.startWorkers(handlerCtx, polling)
+	var undispatched []Message
+	defer func() {
+		stopPolling()
+		c.returnUndispatched(handlerCtx, append(undispatched, stop()...))
+	}()
+
+	if len(c.config.PauseWindows) > 0 {
+		// Apply the current window before the first poll
+		c.applyPauseWindows(time.Now())
+		go c.followPauseWindows(ctx)
+	}
+
+	for ctx.Err() == nil && !c.nearDeadline(ctx) {
+		if c.failover != nil {
+			c.failover.probe(ctx, c.primary(), time.Now())
+		}
+
+		// Sleep while the queue is idle, then wait until resumed and for room under the
+		// in-flight limits before polling
+		probe := c.idle.pause(ctx)
+		if ctx.Err() != nil || !c.gate.wait(ctx) || !c.scheduled.wait(ctx) || !c.budget.wait(ctx) {
+			continue
+		}
+		granted := c.limiter.acquire(ctx, int(c.config.MaxMessages))
+		if granted == 0 {
+			continue
+		}
+
+		// Ask for no more messages than the rate limiter allows
+		permitted := c.takePermits(ctx, granted)
+		c.limiter.release(granted - permitted)
+		if granted = permitted; granted == 0 {
+			continue
+		}
+
+		source := c.source()
+		level, scan := 0, false
+		if c.priorities != nil {
+			level, scan = c.priorities.pick()
+			source = c.priorities.levels[level]
+		}
+		source.state.setCapacity(c.Workers())
+
+		var output *sqs.ReceiveMessageOutput
+		var err error
+		if probe || scan {
+			output, err = source.client.probe(ctx, c.receiveInput(source, int32(granted)))
+		} else {
+			output, err = source.client.receive(ctx, c.receiveInput(source, int32(granted)))
+		}
+		if c.failover != nil && source.queueURL == c.queueURL {
+			c.failover.observe(err, time.Now())
+		}
+
+		if err != nil {
+			c.limiter.release(granted)
+
+			if errors.Is(err, ErrClosed) {
+				return ErrClosed
+			}
+
+			if ctx.Err() == nil {
+				source.client.logger().Warn("receive failed", "queue", source.queueURL, "error", err)
+			}
+
+			// Avoid a hot loop while SQS (or the network) is failing
+			sleep(ctx, _consumerReceiveErrorBackoff)
+			continue
+		}
+
+		c.limiter.release(granted - len(output.Messages))
+		if c.priorities != nil {
+			c.priorities.observe(level, len(output.Messages))
+		}
+		c.idle.observe(len(output.Messages), probe, time.Now())
+
+		overflowed := map[string]bool{}
+		receivedAt := time.Now()
+		for _, m := range output.Messages {
+			msg := NewMessage(source.queueURL, m)
+			msg.codec = c.config.Codec
+			msg.receivedAt = receivedAt
+			if !c.ownsGroup(msg) {
+				c.makeVisible(handlerCtx, source, msg)
+				c.limiter.release(1)
+				continue
+			}
+
+			if !c.accepts(msg) {
+				c.reject(handlerCtx, source, msg)
+				c.limiter.release(1)
+				continue
+			}
+
+			if !c.groups.admit(msg.GroupID, overflowed) {
+				c.makeVisible(handlerCtx, source, msg)
+				c.limiter.release(1)
+				continue
+			}
+
+			if status := c.dedup.begin(msg.ID, time.Now()); status != dedupNew {
+				c.skipDuplicate(handlerCtx, source, msg, status)
+				c.groups.release(msg.GroupID)
+				c.limiter.release(1)
+				continue
+			}
+
+			source.state.addInFlight(1)
+			c.budget.add(payloadSize(msg))
+			c.inFlight.add(msg)
+			if !dispatch(msg) {
+				undispatched = append(undispatched, msg)
+			}
+		}
+
+		if c.pool != nil {
+			c.pool.autoscale(source.state.volumeClass())
+		}
+	}
+
+	return nil
+}
+
+// Workers returns the number of goroutines currently processing messages.
+func (c *Consumer) Workers() int {
+	if c.pool != nil {
+		return c.pool.workers()
+	}
+
+	return c.config.Partitions
+}
+
+// ActiveQueueURL returns the URL of the queue currently polled: the consumer queue, or the
+// secondary queue while failed over (see WithFailover).
+func (c *Consumer) ActiveQueueURL() string {
+	return c.source().queueURL
+}
+
+// primary returns the consumer queue.
+func (c *Consumer) primary() queueSource {
+	return queueSource{client: c.client, queueURL: c.queueURL, state: c.state}
+}
+
+// source returns the queue to poll: the secondary queue while failed over, the consumer
+// queue otherwise.
+func (c *Consumer) source() queueSource {
+	if c.failover.secondaryActive() {
+		return c.secondary
+	}
+
+	return c.primary()
+}
+
+// sourceOf returns the queue a message was received from.
+func (c *Consumer) sourceOf(msg Message) queueSource {
+	if source, ok := c.priorities.sourceOf(msg.QueueURL); ok {
+		return source
+	}
+
+	if c.failover != nil && msg.QueueURL == c.secondary.queueURL {
+		return c.secondary
+	}
+
+	return c.primary()
+}
+
+// nearDeadline reports whether the deadline of ctx leaves too little time for another
+// receive: the upcoming long poll plus the visibility timeout of the messages it returns.
+func (c *Consumer) nearDeadline(ctx context.Context) bool {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return false
+	}
+
+	source := c.source()
+
+	var waitTime int64
+	if source.state.enabled() {
+		waitTime = min(max(source.state.adjustedWaitTime(source.queueURL), _minWaitTimeSeconds), _maxWaitTimeSeconds)
+	}
+
+	budget := time.Duration(waitTime+int64(source.client.config.visibilityTimeout())) * time.Second
+
+	return time.Until(deadline) < budget
+}
+
+// receiveInput builds the ReceiveMessage request issued on every poll of source, asking
+// for at most maxMessages messages.
+func (c *Consumer) receiveInput(source queueSource, maxMessages int32) *sqs.ReceiveMessageInput {
+	return &sqs.ReceiveMessageInput{
+		QueueUrl:                    aws.String(source.queueURL),
+		MaxNumberOfMessages:         maxMessages,
+		VisibilityTimeout:           int32(source.client.config.visibilityTimeout()),
+		MessageAttributeNames:       []string{_allMessageAttributes},
+		MessageSystemAttributeNames: []types.MessageSystemAttributeName{types.MessageSystemAttributeNameAll},
+	}
+}
+
+// startWorkers launches the worker goroutines, which handle messages with ctx until
+// polling is done. It returns the function used to hand messages over to them, which
+// reports false once polling is done, plus a function that waits for the workers to
+// finish their current message and returns the messages they didn't start.
+func (c *Consumer) startWorkers(ctx, polling context.Context) (dispatch func(Message) bool, stop func() []Message) {
+	if c.config.Partitions > 0 {
+		return c.startPartitionWorkers(ctx, polling)
+	}
+
+	c.pool.start(ctx, polling.Done())
+
+	return c.pool.dispatch, c.pool.stop
+}
+
+// startPartitionWorkers launches one worker per partition, each with its own bounded
+// queue, so that messages sharing a group ID are handled sequentially.
+func (c *Consumer) startPartitionWorkers(ctx, polling context.Context) (dispatch func(Message) bool, stop func() []Message) {
+	var (
+		partitions   = make([]chan Message, c.config.Partitions)
+		done         = polling.Done()
+		mu           sync.Mutex
+		undispatched []Message
+	)
+	giveBack := func(msg Message) {
+		mu.Lock()
+		defer mu.Unlock()
+		undispatched = append(undispatched, msg)
+	}
+
+	for i := range partitions {
+		partitions[i] = make(chan Message, c.config.PartitionQueueSize)
+		c.wg.Add(1)
+		go c.work(ctx, partitions[i], done, giveBack)
+	}
+
+	dispatch = func(msg Message) bool {
+		if stopped(done) {
+			return false
+		}
+
+		select {
+		case partitions[partitionFor(msg, len(partitions))] <- msg:
+			return true
+		case <-done:
+			return false
+		}
+	}
+	stop = func() []Message {
+		c.wg.Wait()
+
+		for _, p := range partitions {
+			close(p)
+			for msg := range p {
+				undispatched = append(undispatched, msg)
+			}
+		}
+
+		return undispatched
+	}
+
+	return dispatch, stop
+}
+
+// partitionFor maps a message to a partition index by hashing its group ID, falling
+// back to the message ID for messages that don't belong to a group.
+func partitionFor(msg Message, partitions int) int {
+	key := msg.GroupID
+	if key == "" {
+		key = msg.ID
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+
+	return int(h.Sum32() % uint32(partitions))
+}
+
+// work handles messages from jobs until done is closed. Messages taken once done is
+// closed are handed to giveBack instead.
+func (c *Consumer) work(ctx context.Context, jobs <-chan Message, done <-chan struct{}, giveBack func(Message)) {
+	defer c.wg.Done()
+
+	for {
+		select {
+		case msg := <-jobs:
+			if stopped(done) {
+				giveBack(msg)
+				return
+			}
+			c.process(ctx, msg)
+		case <-done:
+			return
+		}
+	}
+}
+
+// handle runs the transform pipeline, the quarantine validation and the handler on a message.
+func (c *Consumer) handle(ctx context.Context, msg Message) error {
+	msg, err := applyTransforms(ctx, msg, c.config.Transforms)
+	if err != nil {
+		return err
+	}
+
+	if err := c.quarantine.validate(msg); err != nil {
+		return err
+	}
+
+	return c.handler.Handle(ctx, msg)
+}
+
+// process invokes the handler for a single message and deletes it on success.
+func (c *Consumer) process(ctx context.Context, msg Message) {
+	source := c.sourceOf(msg)
+	defer c.limiter.release(1)
+	defer c.groups.release(msg.GroupID)
+	defer c.budget.release(payloadSize(msg))
+	defer source.state.addInFlight(-1)
+	defer c.inFlight.remove(msg)
+
+	if !c.claim(ctx, source, msg) {
+		c.dedup.finish(msg.ID, false, time.Now())
+		return
+	}
+
+	start := time.Now()
+	if reason, cause := c.quarantine.check(msg, start); reason != "" {
+		c.dedup.finish(msg.ID, false, start)
+		if c.quarantine.move(ctx, source, msg, reason, cause) {
+			c.auditDelete(source, msg, DeleteQuarantined, start)
+		}
+		return
+	}
+
+	ctx, span := source.client.startSpan(ctx, _operationProcess, _operationNameProcess, semconv.MessagingOperationTypeProcess, trace.SpanKindConsumer, source.queueURL)
+	span.SetAttributes(semconv.MessagingMessageID(msg.ID), semconv.MessagingMessageBodySize(len(msg.Body)))
+
+	stopWatch := c.watchSlow(source, msg)
+	handlerCtx, stopExpiry := c.watchExpiry(ctx, source, msg)
+	err := c.handle(handlerCtx, msg)
+	stopExpiry()
+	stopWatch()
+	endSpan(span, err)
+	c.dedup.finish(msg.ID, err == nil, time.Now())
+
+	if err != nil {
+		c.unclaim(ctx, source, msg)
+
+		if reason, ok := c.quarantine.reason(err); ok {
+			if c.quarantine.move(ctx, source, msg, reason, err) {
+				c.auditDelete(source, msg, DeleteQuarantined, start)
+			}
+			return
+		}
+
+		if c.sinkFailure(ctx, source, msg, err, start) {
+			return
+		}
+
+		if c.retryImmediately(ctx, msg, err) {
+			return
+		}
+
+		if c.retryThroughQueue(ctx, source, msg, start) {
+			return
+		}
+
+		if policy, ok := c.retryPolicy(msg); ok {
+			if c.retry(ctx, msg, policy) {
+				c.auditDelete(source, msg, DeleteDeadLettered, start)
+			}
+			return
+		}
+
+		if c.config.NackStrategy != nil && IsRetryable(err) {
+			c.nack(ctx, msg)
+		}
+		// Otherwise leave the message in the queue; it becomes visible again after the visibility timeout
+		return
+	}
+
+	if _, err := source.client.DeleteMessage(ctx, source.queueURL, msg.ReceiptHandle); err != nil {
+		source.client.logger().Warn("delete failed, message will be redelivered", "queue", source.queueURL, "message_id", msg.ID, "error", err)
+		return
+	}
+
+	c.auditDelete(source, msg, DeleteAcknowledged, start)
+}
+
+// sleep pauses for d or until ctx is cancelled, whichever happens first.
+func sleep(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
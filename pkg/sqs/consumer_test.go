@@ -0,0 +1,178 @@
+package sqs
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// runConsumer starts the consumer, waits until done reports true (or a timeout
+// elapses) and then stops it, waiting for Start to return.
+func runConsumer(t *testing.T, consumer *Consumer, done func() bool) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error, 1)
+	go func() {
+		result <- consumer.Start(ctx)
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !done() {
+		if time.Now().After(deadline) {
+			cancel()
+			t.Fatal("timed out waiting for consumer")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	cancel()
+	if err := <-result; err != nil {
+		t.Errorf("Start returned unexpected error: %v", err)
+	}
+}
+
+func TestConsumerDeletesHandledMessages(t *testing.T) {
+	fake := &fakeSQS{}
+	fake.push(testMessage("m1", ""), testMessage("m2", ""))
+	client := newTestSQS(fake)
+
+	handler := HandlerFunc(func(ctx context.Context, msg Message) error {
+		if msg.ID == "m2" {
+			return fmt.Errorf("failed")
+		}
+		return nil
+	})
+
+	consumer := NewConsumer(client, "queue", handler, WithWorkers(2))
+	runConsumer(t, consumer, func() bool { return len(fake.deletedHandles()) == 1 })
+
+	deleted := fake.deletedHandles()
+	if len(deleted) != 1 || deleted[0] != "m1" {
+		t.Errorf("Expected only m1 to be deleted, got %v", deleted)
+	}
+}
+
+func TestConsumerRequestsSystemAttributes(t *testing.T) {
+	fake := &fakeSQS{}
+	client := newTestSQS(fake)
+
+	consumer := NewConsumer(client, "queue", HandlerFunc(func(ctx context.Context, msg Message) error { return nil }), WithMaxMessages(5))
+	runConsumer(t, consumer, func() bool {
+		fake.mu.Lock()
+		defer fake.mu.Unlock()
+		return len(fake.receiveInputs) > 0
+	})
+
+	input := fake.receiveInputs[0]
+	if input.MaxNumberOfMessages != 5 {
+		t.Errorf("Expected MaxNumberOfMessages 5, got %d", input.MaxNumberOfMessages)
+	}
+	if len(input.MessageSystemAttributeNames) == 0 {
+		t.Error("Expected system attributes to be requested")
+	}
+}
+
+func TestConsumerGroupPartitioningPreservesOrder(t *testing.T) {
+	fake := &fakeSQS{}
+	for i := 0; i < 5; i++ {
+		fake.push(testMessage(fmt.Sprintf("a%d", i), "group-a"), testMessage(fmt.Sprintf("b%d", i), "group-b"))
+	}
+	client := newTestSQS(fake)
+
+	var mu sync.Mutex
+	seen := map[string][]string{}
+	handler := HandlerFunc(func(ctx context.Context, msg Message) error {
+		if msg.GroupID == "group-a" {
+			// Slow group must not reorder its own messages
+			time.Sleep(2 * time.Millisecond)
+		}
+		mu.Lock()
+		seen[msg.GroupID] = append(seen[msg.GroupID], msg.ID)
+		mu.Unlock()
+		return nil
+	})
+
+	consumer := NewConsumer(client, "queue", handler, WithGroupPartitioning(4, 2))
+	runConsumer(t, consumer, func() bool { return len(fake.deletedHandles()) == 10 })
+
+	for group, prefix := range map[string]string{"group-a": "a", "group-b": "b"} {
+		ids := seen[group]
+		if len(ids) != 5 {
+			t.Fatalf("Expected 5 messages for %s, got %v", group, ids)
+		}
+		for i, id := range ids {
+			if want := fmt.Sprintf("%s%d", prefix, i); id != want {
+				t.Errorf("Group %s out of order: position %d has %s, expected %s", group, i, id, want)
+			}
+		}
+	}
+}
+
+func TestPartitionForIsStable(t *testing.T) {
+	msg := Message{ID: "id-1", GroupID: "orders-42"}
+
+	first := partitionFor(msg, 8)
+	for i := 0; i < 10; i++ {
+		if got := partitionFor(msg, 8); got != first {
+			t.Fatalf("Expected stable partition %d, got %d", first, got)
+		}
+	}
+
+	// Messages of the same group land on the same partition regardless of ID
+	other := Message{ID: "id-2", GroupID: "orders-42"}
+	if partitionFor(other, 8) != first {
+		t.Error("Expected messages of the same group to share a partition")
+	}
+}
+
+func TestConsumerSuppressesReceivesNearDeadline(t *testing.T) {
+	fake := &fakeSQS{}
+	fake.push(testMessage("m1", ""))
+	client := newTestSQS(fake, WithVisibilityTimeout(30))
+	consumer := NewConsumer(client, "queue", HandlerFunc(func(ctx context.Context, msg Message) error {
+		return nil
+	}))
+
+	// The deadline is closer than the visibility timeout: no message should be pulled
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := consumer.Start(ctx); err != nil {
+		t.Fatalf("Start returned error: %v", err)
+	}
+
+	if len(fake.receiveInputs) != 0 {
+		t.Errorf("Expected no receive near the shutdown deadline, got %d", len(fake.receiveInputs))
+	}
+}
+
+func TestConsumerReceivesWithDistantDeadline(t *testing.T) {
+	fake := &fakeSQS{}
+	fake.push(testMessage("m1", ""))
+	client := newTestSQS(fake, WithVisibilityTimeout(30))
+	consumer := NewConsumer(client, "queue", HandlerFunc(func(ctx context.Context, msg Message) error {
+		return nil
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+
+	result := make(chan error, 1)
+	go func() {
+		result <- consumer.Start(ctx)
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(fake.deletedHandles()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	<-result
+
+	if len(fake.deletedHandles()) != 1 {
+		t.Error("Expected the message to be processed when the deadline is far away")
+	}
+}
@@ -0,0 +1,169 @@
+package sqs
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// Default deduplication configuration values
+const (
+	_defaultDedupSize = 10000 // Message IDs remembered when the size is not set
+)
+
+// WithDeduplication skips messages delivered again while, or shortly after, being
+// processed by this consumer. It catches the common duplicate of a message that became
+// visible again because its handler outlived the visibility timeout, without an external
+// store.
+//
+// Message IDs are kept in an in-memory LRU window bounded both in time and size: an ID is
+// forgotten window after its message was handled, or earlier when more than size IDs are
+// tracked. A duplicate of a message still being processed is left in the queue, to be
+// checked again on its next delivery; a duplicate of a message already handled is deleted
+// without calling the handler. Failed messages are forgotten right away so their retries
+// are handled normally.
+//
+// The window only covers the deliveries of this consumer instance; use an idempotent
+// handler when several instances consume the queue.
+//
+// Parameters:
+//   - window: How long handled message IDs are remembered (e.g., a few visibility timeouts)
+//   - size: Maximum number of message IDs remembered (0 uses 10000)
+//
+// Example:
+//
+//	consumer := NewConsumer(client, queueURL, handler, WithDeduplication(10*time.Minute, 50000))
+func WithDeduplication(window time.Duration, size int) ConsumerOption {
+	return func(c *consumerConfig) {
+		c.DedupWindow = window
+		c.DedupSize = size
+	}
+}
+
+// dedupStatus is what a deduplication window knows about a delivered message.
+type dedupStatus int
+
+const (
+	// dedupNew means the message is not a known duplicate and may be handled.
+	dedupNew dedupStatus = iota
+	// dedupProcessing means another delivery of the message is being processed.
+	dedupProcessing
+	// dedupHandled means the message was handled within the window.
+	dedupHandled
+)
+
+// dedupEntry is a message ID tracked by a deduplication window.
+type dedupEntry struct {
+	id        string
+	handledAt time.Time // Zero while the message is being processed
+}
+
+// dedupWindow is a time and size bounded LRU set of message IDs. A nil window
+// deduplicates nothing.
+type dedupWindow struct {
+	mu      sync.Mutex
+	window  time.Duration
+	size    int
+	order   *list.List // Least recently used first
+	entries map[string]*list.Element
+}
+
+// newDedupWindow creates a deduplication window, or returns nil when window is not positive.
+func newDedupWindow(window time.Duration, size int) *dedupWindow {
+	if window <= 0 {
+		return nil
+	}
+
+	if size <= 0 {
+		size = _defaultDedupSize
+	}
+
+	return &dedupWindow{window: window, size: size, order: list.New(), entries: map[string]*list.Element{}}
+}
+
+// begin returns what the window knows about a delivery of id, and starts tracking it as
+// being processed when it is new.
+func (d *dedupWindow) begin(id string, now time.Time) dedupStatus {
+	if d == nil || id == "" {
+		return dedupNew
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if element, ok := d.entries[id]; ok {
+		entry := element.Value.(*dedupEntry)
+		switch {
+		case entry.handledAt.IsZero():
+			return dedupProcessing
+		case now.Sub(entry.handledAt) <= d.window:
+			d.order.MoveToBack(element)
+			return dedupHandled
+		}
+
+		// Expired: handle it as a new delivery
+		d.order.Remove(element)
+		delete(d.entries, id)
+	}
+
+	d.entries[id] = d.order.PushBack(&dedupEntry{id: id})
+	d.evict(now)
+
+	return dedupNew
+}
+
+// finish records the outcome of processing id: handled messages are remembered for the
+// window, failed ones are forgotten.
+func (d *dedupWindow) finish(id string, handled bool, now time.Time) {
+	if d == nil || id == "" {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	element, ok := d.entries[id]
+	if !ok {
+		return
+	}
+
+	if !handled {
+		d.order.Remove(element)
+		delete(d.entries, id)
+		return
+	}
+
+	element.Value.(*dedupEntry).handledAt = now
+	d.order.MoveToBack(element)
+}
+
+// evict drops expired entries and, beyond the size bound, the least recently used ones.
+// Must be called with the mutex held.
+func (d *dedupWindow) evict(now time.Time) {
+	for element := d.order.Front(); element != nil; {
+		next := element.Next()
+		entry := element.Value.(*dedupEntry)
+
+		expired := !entry.handledAt.IsZero() && now.Sub(entry.handledAt) > d.window
+		if !expired && d.order.Len() <= d.size {
+			break
+		}
+
+		d.order.Remove(element)
+		delete(d.entries, entry.id)
+		element = next
+	}
+}
+
+// skipDuplicate deletes the delivery of a message already handled, or leaves a delivery of
+// a message still being processed in the queue.
+func (c *Consumer) skipDuplicate(ctx context.Context, source queueSource, msg Message, status dedupStatus) {
+	if status != dedupHandled {
+		return
+	}
+
+	if _, err := source.client.DeleteMessage(ctx, source.queueURL, msg.ReceiptHandle); err == nil {
+		c.auditDelete(source, msg, DeleteDuplicate, time.Time{})
+	}
+}
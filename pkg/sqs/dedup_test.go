@@ -0,0 +1,78 @@
+package sqs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+func TestDedupWindow(t *testing.T) {
+	window := newDedupWindow(time.Minute, 2)
+	now := time.Now()
+
+	if status := window.begin("a", now); status != dedupNew {
+		t.Fatalf("Expected a new message, got %d", status)
+	}
+	if status := window.begin("a", now); status != dedupProcessing {
+		t.Errorf("Expected a duplicate of a message being processed, got %d", status)
+	}
+
+	window.finish("a", true, now)
+	if status := window.begin("a", now.Add(30*time.Second)); status != dedupHandled {
+		t.Errorf("Expected a duplicate of a handled message, got %d", status)
+	}
+	if status := window.begin("a", now.Add(2*time.Minute)); status != dedupNew {
+		t.Errorf("Expected the ID to expire after the window, got %d", status)
+	}
+
+	window.begin("b", now)
+	window.finish("b", false, now)
+	if status := window.begin("b", now); status != dedupNew {
+		t.Errorf("Expected failed messages to be forgotten, got %d", status)
+	}
+
+	// "a" and "b" are tracked: a third ID evicts the least recently used one
+	window.begin("c", now)
+	if status := window.begin("a", now); status != dedupNew {
+		t.Errorf("Expected the oldest ID to be evicted beyond the size bound, got %d", status)
+	}
+
+	var disabled *dedupWindow
+	if status := disabled.begin("a", now); status != dedupNew {
+		t.Errorf("Expected a nil window to deduplicate nothing, got %d", status)
+	}
+}
+
+func TestConsumerDeduplication(t *testing.T) {
+	fake := &fakeSQS{}
+	first := testMessage("m1", "")
+	duplicate := testMessage("m1", "")
+	duplicate.ReceiptHandle = aws.String("m1-again")
+	fake.push(first)
+
+	handled := 0
+	consumer := NewConsumer(newTestSQS(fake), "queue", HandlerFunc(func(ctx context.Context, msg Message) error {
+		handled++
+		return nil
+	}), WithDeduplication(time.Minute, 0))
+
+	// Redeliver the message once its first delivery was handled and deleted
+	redelivered := false
+	runConsumer(t, consumer, func() bool {
+		deleted := len(fake.deletedHandles())
+		if deleted == 1 && !redelivered {
+			redelivered = true
+			fake.push(duplicate)
+		}
+		return deleted == 2
+	})
+
+	if handled != 1 {
+		t.Errorf("Expected the duplicate not to be handled, got %d handler calls", handled)
+	}
+	if deleted := fake.deletedHandles(); deleted[1] != "m1-again" {
+		t.Errorf("Expected the duplicate delivery to be deleted, got %v", deleted)
+	}
+}
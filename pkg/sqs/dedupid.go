@@ -0,0 +1,85 @@
+package sqs
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+)
+
+// DeduplicationKey returns the content identifying a message for deduplication: two
+// messages with the same key sent to a FIFO queue within the 5-minute deduplication
+// interval are delivered once.
+type DeduplicationKey func(body string) string
+
+// WithContentDeduplication computes the MessageDeduplicationId of FIFO sends that don't
+// set one (see WithDeduplicationID) as the hex SHA-256 of a key extracted from the body,
+// so producers get deduplication without enabling ContentBasedDeduplication on the queue.
+// It applies to SendMessage and Producer.SendBatch; standard queues are left untouched.
+//
+// The default key, CanonicalBody, makes JSON bodies that differ only in whitespace or key
+// order duplicates. Pass a key extractor to deduplicate on a business identifier instead,
+// so retries carrying e.g. a new timestamp are still recognized.
+//
+// Parameters:
+//   - key: Extracts the deduplication key from a body (nil uses CanonicalBody)
+//
+// Example:
+//
+//	client := NewSQSWithOptions(&cfg, WithContentDeduplication(func(body string) string {
+//	    var order struct{ ID string `json:"order_id"` }
+//	    _ = json.Unmarshal([]byte(body), &order)
+//	    return order.ID
+//	}))
+func WithContentDeduplication(key DeduplicationKey) Option {
+	return func(c *config) {
+		if key == nil {
+			key = CanonicalBody
+		}
+		c.ContentDeduplication = key
+	}
+}
+
+// CanonicalBody is the default DeduplicationKey. JSON bodies are re-encoded compactly
+// with object keys sorted, so equivalent documents get the same key; other bodies are
+// used as they are.
+//
+// Parameters:
+//   - body: The message body
+//
+// Returns:
+//   - string: The canonical form of the body
+func CanonicalBody(body string) string {
+	decoder := json.NewDecoder(strings.NewReader(body))
+	decoder.UseNumber()
+
+	var document any
+	if err := decoder.Decode(&document); err != nil || decoder.More() {
+		return body
+	}
+
+	var canonical bytes.Buffer
+	encoder := json.NewEncoder(&canonical)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(document); err != nil {
+		return body
+	}
+
+	return strings.TrimSuffix(canonical.String(), "\n")
+}
+
+// deduplicate sets the content-based deduplication ID of a FIFO send, when enabled and
+// the caller didn't provide one.
+func (s *SQS) deduplicate(input *sqs.SendMessageInput) {
+	key := s.config.ContentDeduplication
+	if key == nil || input.MessageDeduplicationId != nil || !isFIFOQueue(aws.ToString(input.QueueUrl)) {
+		return
+	}
+
+	sum := sha256.Sum256([]byte(key(aws.ToString(input.MessageBody))))
+	input.MessageDeduplicationId = aws.String(hex.EncodeToString(sum[:]))
+}
@@ -0,0 +1,82 @@
+package sqs
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+func TestCanonicalBody(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		same bool
+	}{
+		{name: "key order and whitespace", a: `{"b": 1, "a": [1, 2]}`, b: `{"a":[1,2],"b":1}`, same: true},
+		{name: "large numbers", a: `{"id": 12345678901234567890}`, b: `{"id": 12345678901234567891}`, same: false},
+		{name: "different values", a: `{"a":1}`, b: `{"a":2}`, same: false},
+		{name: "plain text", a: "hello ", b: "hello", same: false},
+		{name: "trailing data", a: `{"a":1} {"b":2}`, b: `{"a":1}`, same: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if same := CanonicalBody(tt.a) == CanonicalBody(tt.b); same != tt.same {
+				t.Errorf("Expected equal canonical forms to be %v, got %q and %q", tt.same, CanonicalBody(tt.a), CanonicalBody(tt.b))
+			}
+		})
+	}
+}
+
+func TestContentDeduplicationIDs(t *testing.T) {
+	fake := &fakeSQS{}
+	client := newTestSQS(fake, WithContentDeduplication(nil))
+	ctx := context.Background()
+
+	_, _ = client.SendMessage(ctx, "orders.fifo", `{"order": 1, "total": 10}`, WithMessageGroupID("g"))
+	_, _ = client.SendMessage(ctx, "orders.fifo", `{"total":10,"order":1}`, WithMessageGroupID("g"))
+	_, _ = client.SendMessage(ctx, "orders.fifo", `{"order": 1}`, WithMessageGroupID("g"), WithDeduplicationID("explicit"))
+	_, _ = client.SendMessage(ctx, "orders", `{"order": 1}`)
+
+	first, second := aws.ToString(fake.sent[0].MessageDeduplicationId), aws.ToString(fake.sent[1].MessageDeduplicationId)
+	if len(first) != 64 || first != second {
+		t.Errorf("Expected equivalent bodies to share a SHA-256 deduplication ID, got %q and %q", first, second)
+	}
+	if id := aws.ToString(fake.sent[2].MessageDeduplicationId); id != "explicit" {
+		t.Errorf("Expected an explicit deduplication ID to be kept, got %q", id)
+	}
+	if fake.sent[3].MessageDeduplicationId != nil {
+		t.Error("Expected no deduplication ID on a standard queue")
+	}
+}
+
+func TestContentDeduplicationKeyExtractor(t *testing.T) {
+	fake := &fakeSQS{}
+	client := newTestSQS(fake, WithContentDeduplication(func(body string) string {
+		var order struct {
+			ID string `json:"order_id"`
+		}
+		_ = json.Unmarshal([]byte(body), &order)
+		return order.ID
+	}))
+
+	producer := NewProducer(client, "orders.fifo")
+	_, err := producer.SendBatch(context.Background(), []OutgoingMessage{
+		{ID: "1", Body: `{"order_id":"42","sent_at":"10:00"}`, Options: []SendOption{WithMessageGroupID("g")}},
+		{ID: "2", Body: `{"order_id":"42","sent_at":"10:01"}`, Options: []SendOption{WithMessageGroupID("g")}},
+		{ID: "3", Body: `{"order_id":"43","sent_at":"10:01"}`, Options: []SendOption{WithMessageGroupID("g")}},
+	})
+	if err != nil {
+		t.Fatalf("SendBatch returned error: %v", err)
+	}
+
+	entries := fake.sentBatches[0].Entries
+	if aws.ToString(entries[0].MessageDeduplicationId) != aws.ToString(entries[1].MessageDeduplicationId) {
+		t.Error("Expected messages with the same key to share a deduplication ID")
+	}
+	if aws.ToString(entries[1].MessageDeduplicationId) == aws.ToString(entries[2].MessageDeduplicationId) {
+		t.Error("Expected messages with different keys to get different deduplication IDs")
+	}
+}
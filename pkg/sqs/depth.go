@@ -0,0 +1,333 @@
+package sqs
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+)
+
+// Depth watcher configuration values
+const (
+	_defaultDepthInterval          = time.Minute              // Default interval between two samples
+	_metricQueueMessages           = "arrakis.queue.messages" // Gauge of the messages in a queue, by state
+	_attributeMessageState         = attribute.Key("arrakis.message_state")
+	_queueAttributeNotVisibleCount = "ApproximateNumberOfMessagesNotVisible"
+	_queueAttributeDelayedCount    = "ApproximateNumberOfMessagesDelayed"
+	_messageStateVisible           = "visible"
+	_messageStateInFlight          = "in_flight"
+	_messageStateDelayed           = "delayed"
+)
+
+// QueueDepth is a sample of the message counts of a queue, as reported by SQS. The counts
+// are approximate and lag behind the queue by up to a minute.
+type QueueDepth struct {
+	// QueueURL is the sampled queue.
+	QueueURL string
+	// Visible is the number of messages available for retrieval: the backlog.
+	Visible int
+	// InFlight is the number of messages received but not yet deleted.
+	InFlight int
+	// Delayed is the number of messages not yet available because of a delivery delay.
+	Delayed int
+	// SampledAt is when the sample was taken.
+	SampledAt time.Time
+}
+
+// depthWatcherConfig holds the configuration of a DepthWatcher.
+type depthWatcherConfig struct {
+	// Interval is the time between two samples of every queue.
+	Interval time.Duration
+	// Metrics exposes the samples as metrics. Nil disables them.
+	Metrics MetricsSink
+	// OnSample is notified of every sample, if not nil.
+	OnSample func(QueueDepth)
+}
+
+// DepthWatcherOption is a function type for configuring a DepthWatcher with the functional options pattern.
+type DepthWatcherOption func(*depthWatcherConfig)
+
+// WithDepthInterval sets the time between two samples of every queue (default: 1 minute).
+// Each sample costs one GetQueueAttributes call per queue.
+func WithDepthInterval(interval time.Duration) DepthWatcherOption {
+	return func(c *depthWatcherConfig) {
+		c.Interval = interval
+	}
+}
+
+// WithDepthMetrics exposes the samples as the arrakis.queue.messages gauge, with the queue
+// name (messaging.destination.name) and an arrakis.message_state attribute of "visible",
+// "in_flight" or "delayed".
+//
+// Parameters:
+//   - provider: The meter provider (nil uses the global provider, a no-op unless one was
+//     registered with otel.SetMeterProvider)
+func WithDepthMetrics(provider metric.MeterProvider) DepthWatcherOption {
+	return WithDepthMetricsSink(OTelSink(provider))
+}
+
+// WithDepthMetricsSink records the gauge of WithDepthMetrics into sink, e.g. a bespoke
+// telemetry system implementing MetricsSink. OTelSink reads the gauge on every collection,
+// so unwatched queues and queues whose sampling fails stop being exported; other sinks
+// are pushed the gauge after every successful sample, and should expire series that
+// stop being updated.
+//
+// Parameters:
+//   - sink: The sink receiving the gauge (nil disables it)
+func WithDepthMetricsSink(sink MetricsSink) DepthWatcherOption {
+	return func(c *depthWatcherConfig) {
+		c.Metrics = sink
+	}
+}
+
+// WithDepthHandler registers a function notified of every sample.
+func WithDepthHandler(onSample func(QueueDepth)) DepthWatcherOption {
+	return func(c *depthWatcherConfig) {
+		c.OnSample = onSample
+	}
+}
+
+// DepthWatcher periodically samples the message counts of a set of queues in the
+// background. The latest samples are available through Depth, exposed as metrics (see
+// WithDepthMetrics) and feed the wait time strategies built on them (see
+// QueueDepthWaitTime). Message ages aren't queue attributes; see QueueStats for the age of
+// the oldest message of the last poll.
+//
+// Queues may be added and removed while the watcher runs.
+type DepthWatcher struct {
+	client *SQS
+	config depthWatcherConfig
+
+	mu        sync.RWMutex
+	queueURLs []string
+	depths    map[string]QueueDepth
+	failing   map[string]bool // Queues whose last sample failed
+	observed  bool            // Whether the sink reads the gauge itself (see gaugeObserver)
+}
+
+// NewDepthWatcher creates a watcher of the given queues.
+//
+// Parameters:
+//   - client: The SQS client used for the GetQueueAttributes calls
+//   - queueURLs: The queues to watch; more can be added with Watch
+//   - options: Optional settings such as WithDepthInterval and WithDepthMetrics
+//
+// Returns:
+//   - *DepthWatcher: A watcher ready to Run
+//
+// Example:
+//
+//	// Sampling doesn't use adaptive polling, so the watcher can have a client of its own
+//	watcher := sqs.NewDepthWatcher(sqs.NewSQS(&cfg), []string{ordersURL}, sqs.WithDepthInterval(30*time.Second), sqs.WithDepthMetrics(meterProvider))
+//	go watcher.Run(ctx)
+//
+//	sqsClient := sqs.NewSQSWithOptions(&cfg, sqs.WithWaitTimeStrategy(sqs.MinWaitTime(
+//	    sqs.AdaptiveWaitTime(),
+//	    sqs.QueueDepthWaitTime(watcher, 1000, 1),
+//	)))
+func NewDepthWatcher(client *SQS, queueURLs []string, options ...DepthWatcherOption) *DepthWatcher {
+	config := depthWatcherConfig{Interval: _defaultDepthInterval}
+	for _, opt := range options {
+		opt(&config)
+	}
+
+	if config.Interval <= 0 {
+		config.Interval = _defaultDepthInterval
+	}
+
+	w := &DepthWatcher{client: client, config: config, depths: map[string]QueueDepth{}, failing: map[string]bool{}}
+	for _, queueURL := range queueURLs {
+		w.Watch(queueURL)
+	}
+
+	if observer, ok := config.Metrics.(gaugeObserver); ok {
+		observer.observeGauge(_metricQueueMessages, w.observe)
+		w.observed = true
+	}
+
+	return w
+}
+
+// Watch adds a queue to the watched queues. Watching a queue twice has no effect.
+func (w *DepthWatcher) Watch(queueURL string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !slices.Contains(w.queueURLs, queueURL) {
+		w.queueURLs = append(w.queueURLs, queueURL)
+	}
+}
+
+// Unwatch removes a queue from the watched queues and forgets its last sample, which is
+// no longer exported by WithDepthMetrics.
+func (w *DepthWatcher) Unwatch(queueURL string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.queueURLs = slices.DeleteFunc(w.queueURLs, func(watched string) bool { return watched == queueURL })
+	delete(w.depths, queueURL)
+	delete(w.failing, queueURL)
+}
+
+// Depth returns the last sample of a queue.
+//
+// Parameters:
+//   - queueURL: The URL of a watched queue
+//
+// Returns:
+//   - QueueDepth: The last sample
+//   - bool: false if the queue hasn't been sampled yet
+func (w *DepthWatcher) Depth(queueURL string) (QueueDepth, bool) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	depth, ok := w.depths[queueURL]
+	return depth, ok
+}
+
+// Run samples every watched queue at the configured interval until ctx is cancelled.
+// Failed samples are logged and the previous sample of the queue is kept for Depth, but
+// not exported by WithDepthMetrics until the queue is sampled again.
+//
+// Returns:
+//   - error: Always nil when stopped through ctx
+func (w *DepthWatcher) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		w.SampleOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// SampleOnce samples every watched queue once.
+func (w *DepthWatcher) SampleOnce(ctx context.Context) {
+	w.mu.RLock()
+	queueURLs := slices.Clone(w.queueURLs)
+	w.mu.RUnlock()
+
+	for _, queueURL := range queueURLs {
+		if ctx.Err() != nil {
+			return
+		}
+
+		depth, err := w.sample(ctx, queueURL)
+
+		w.mu.Lock()
+		// Skip queues removed while sampling
+		watched := slices.Contains(w.queueURLs, queueURL)
+		if watched {
+			w.failing[queueURL] = err != nil
+			if err == nil {
+				w.depths[queueURL] = depth
+			}
+		}
+		w.mu.Unlock()
+
+		if err != nil {
+			w.client.logger().Warn("queue depth sampling failed", "queue", queueURL, "error", err)
+			continue
+		}
+
+		if watched {
+			w.record(ctx, depth)
+		}
+
+		if w.config.OnSample != nil {
+			w.config.OnSample(depth)
+		}
+	}
+}
+
+// sample reads the message counts of a queue.
+func (w *DepthWatcher) sample(ctx context.Context, queueURL string) (QueueDepth, error) {
+	output, err := w.client.client.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{
+		QueueUrl: aws.String(queueURL),
+		AttributeNames: []types.QueueAttributeName{
+			types.QueueAttributeNameApproximateNumberOfMessages,
+			types.QueueAttributeNameApproximateNumberOfMessagesNotVisible,
+			types.QueueAttributeNameApproximateNumberOfMessagesDelayed,
+		},
+	}, apiOptions(ctx)...)
+	if err != nil {
+		return QueueDepth{}, err
+	}
+
+	// Missing or malformed counts are reported as zero
+	visible, _ := strconv.Atoi(output.Attributes[_queueAttributeMessageCount])
+	inFlight, _ := strconv.Atoi(output.Attributes[_queueAttributeNotVisibleCount])
+	delayed, _ := strconv.Atoi(output.Attributes[_queueAttributeDelayedCount])
+
+	return QueueDepth{QueueURL: queueURL, Visible: visible, InFlight: inFlight, Delayed: delayed, SampledAt: time.Now()}, nil
+}
+
+// record pushes a sample to the queue depth gauge of sinks that don't read it themselves.
+func (w *DepthWatcher) record(ctx context.Context, depth QueueDepth) {
+	if w.config.Metrics == nil || w.observed {
+		return
+	}
+
+	recordDepth(depth, func(value int64, attributes ...attribute.KeyValue) {
+		w.config.Metrics.Gauge(ctx, _metricQueueMessages, value, attributes...)
+	})
+}
+
+// observe reports the last sample of every watched queue whose sampling succeeded, for
+// the sinks reading the queue depth gauge themselves.
+func (w *DepthWatcher) observe(record func(value int64, attributes ...attribute.KeyValue)) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	for queueURL, depth := range w.depths {
+		if !w.failing[queueURL] {
+			recordDepth(depth, record)
+		}
+	}
+}
+
+// recordDepth reports the message counts of a sample as the series of the queue depth
+// gauge.
+func recordDepth(depth QueueDepth, record func(value int64, attributes ...attribute.KeyValue)) {
+	queue := semconv.MessagingDestinationName(queueName(depth.QueueURL))
+	record(int64(depth.Visible), queue, _attributeMessageState.String(_messageStateVisible))
+	record(int64(depth.InFlight), queue, _attributeMessageState.String(_messageStateInFlight))
+	record(int64(depth.Delayed), queue, _attributeMessageState.String(_messageStateDelayed))
+}
+
+// QueueDepthWaitTime waits seconds on queues whose backlog, as last sampled by watcher,
+// is at least backlog messages. It has no opinion on smaller backlogs and on queues the
+// watcher hasn't sampled, so compose it with AdaptiveWaitTime (see MinWaitTime and
+// FallbackWaitTime).
+//
+// Parameters:
+//   - watcher: The watcher sampling the queues
+//   - backlog: Visible messages from which the strategy applies
+//   - seconds: The wait time used above the backlog
+//
+// Example:
+//
+//	strategy := sqs.MinWaitTime(sqs.AdaptiveWaitTime(), sqs.QueueDepthWaitTime(watcher, 1000, 0))
+func QueueDepthWaitTime(watcher *DepthWatcher, backlog int, seconds int64) WaitTimeStrategy {
+	return WaitTimeStrategyFunc(func(queueURL string, _ int64) (int64, bool) {
+		depth, ok := watcher.Depth(queueURL)
+		if !ok || depth.Visible < backlog {
+			return 0, false
+		}
+
+		return seconds, true
+	})
+}
@@ -0,0 +1,103 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestDepthWatcherSamples(t *testing.T) {
+	fake := &fakeSQS{queueAttrs: map[string]string{
+		_queueAttributeMessageCount:    "1500",
+		_queueAttributeNotVisibleCount: "40",
+		_queueAttributeDelayedCount:    "3",
+	}}
+	reader := sdkmetric.NewManualReader()
+
+	var samples []QueueDepth
+	watcher := NewDepthWatcher(newTestSQS(fake), []string{"https://sqs/123/orders"},
+		WithDepthMetrics(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+		WithDepthHandler(func(depth QueueDepth) { samples = append(samples, depth) }),
+	)
+	ctx := context.Background()
+
+	if _, ok := watcher.Depth("https://sqs/123/orders"); ok {
+		t.Error("Expected no depth before the first sample")
+	}
+
+	watcher.SampleOnce(ctx)
+	depth, ok := watcher.Depth("https://sqs/123/orders")
+	if !ok || depth.Visible != 1500 || depth.InFlight != 40 || depth.Delayed != 3 || depth.SampledAt.IsZero() {
+		t.Errorf("Expected the sampled counts, got %+v", depth)
+	}
+	if len(samples) != 1 {
+		t.Errorf("Expected the handler to be notified once, got %d", len(samples))
+	}
+
+	collect := func() map[string]int64 {
+		var data metricdata.ResourceMetrics
+		if err := reader.Collect(ctx, &data); err != nil {
+			t.Fatalf("Collect returned error: %v", err)
+		}
+		states := map[string]int64{}
+		for _, scope := range data.ScopeMetrics {
+			for _, m := range scope.Metrics {
+				if m.Name != _metricQueueMessages {
+					continue
+				}
+				for _, point := range m.Data.(metricdata.Gauge[int64]).DataPoints {
+					state, _ := point.Attributes.Value(_attributeMessageState)
+					states[state.AsString()] = point.Value
+				}
+			}
+		}
+		return states
+	}
+	if states := collect(); states[_messageStateVisible] != 1500 || states[_messageStateInFlight] != 40 || states[_messageStateDelayed] != 3 {
+		t.Errorf("Expected the counts exposed as gauges, got %v", states)
+	}
+
+	fake.attributesErr = errors.New("unavailable")
+	watcher.SampleOnce(ctx)
+	if depth, _ := watcher.Depth("https://sqs/123/orders"); depth.Visible != 1500 {
+		t.Error("Expected a failed sample to keep the previous one")
+	}
+	if states := collect(); len(states) != 0 {
+		t.Errorf("Expected no gauges for a queue failing to sample, got %v", states)
+	}
+
+	fake.attributesErr = nil
+	watcher.SampleOnce(ctx)
+	if states := collect(); len(states) != 3 {
+		t.Errorf("Expected the gauges back after a successful sample, got %v", states)
+	}
+
+	watcher.Unwatch("https://sqs/123/orders")
+	if _, ok := watcher.Depth("https://sqs/123/orders"); ok {
+		t.Error("Expected an unwatched queue to be forgotten")
+	}
+	if states := collect(); len(states) != 0 {
+		t.Errorf("Expected no gauges for an unwatched queue, got %v", states)
+	}
+}
+
+func TestQueueDepthWaitTime(t *testing.T) {
+	fake := &fakeSQS{queueAttrs: map[string]string{_queueAttributeMessageCount: "1500"}}
+	watcher := NewDepthWatcher(newTestSQS(fake), []string{"deep"})
+
+	strategy := MinWaitTime(AdaptiveWaitTime(), QueueDepthWaitTime(watcher, 1000, 1))
+	if seconds, _ := strategy.WaitTime("deep", 20); seconds != 20 {
+		t.Errorf("Expected no opinion before the first sample, got %d", seconds)
+	}
+
+	watcher.SampleOnce(context.Background())
+	if seconds, _ := strategy.WaitTime("deep", 20); seconds != 1 {
+		t.Errorf("Expected the backlog wait time, got %d", seconds)
+	}
+	if seconds, _ := strategy.WaitTime("other", 20); seconds != 20 {
+		t.Errorf("Expected the adaptive wait time for unwatched queues, got %d", seconds)
+	}
+}
@@ -0,0 +1,146 @@
+package sqs
+
+import (
+	"encoding/json"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// Diagnostics configuration
+const (
+	_decisionHistorySize = 32 // Wait time decisions kept per queue
+)
+
+// Decision records a single wait time decision taken by Arrakis for a queue.
+type Decision struct {
+	// Time is when the ReceiveMessage call was issued.
+	Time time.Time
+	// WaitTimeSeconds is the wait time used for the call.
+	WaitTimeSeconds int64
+	// Class is the volume class the wait time was derived from.
+	Class VolumeClass
+	// Average is the EWMA average after the response was processed.
+	Average float64
+	// Messages is the number of messages the call returned.
+	Messages int
+}
+
+// stateDump is the JSON document produced by DumpState.
+type stateDump struct {
+	GeneratedAt      time.Time
+	ArrakisEnabled   bool
+	Configuration    configDump
+	Queues           []queueDump
+	DecisionCapacity int
+}
+
+// configDump is the effective client configuration included in a state dump.
+type configDump struct {
+	VisibilityTimeout int
+	AdaptivePolling   adaptivePolling
+}
+
+// queueDump is the algorithm state of a single queue included in a state dump.
+type queueDump struct {
+	QueueURL                 string
+	ArrakisEnabled           bool
+	Average                  float64
+	EwmaAlpha                float64
+	VolumeClass              VolumeClass
+	LastWaitTimeSeconds      int64
+	LastMessageCount         int64
+	ConsecutiveEmptyMessages int64
+	LowVolumeCycles          int
+	LastUpdate               time.Time
+	LastReceiveEmpty         time.Time
+	LastReset                time.Time
+	Events                   AlgorithmEvents
+	Decisions                []Decision
+}
+
+// DumpState returns a JSON document describing the full adaptive polling state of the
+// client: the effective configuration and, for every queue polled so far, the algorithm
+// state and its most recent wait time decisions. It is intended to be attached to bug
+// reports and support tickets.
+//
+// Returns:
+//   - []byte: Indented JSON document
+//   - error: Any error that occurred while encoding the document
+//
+// Example:
+//
+//	dump, err := sqsClient.DumpState()
+//	if err == nil {
+//	    os.WriteFile("arrakis-state.json", dump, 0o644)
+//	}
+func (s *SQS) DumpState() ([]byte, error) {
+	dump := stateDump{
+		GeneratedAt:    time.Now(),
+		ArrakisEnabled: s.IsArrakisEnabled(),
+		Configuration: configDump{
+			VisibilityTimeout: s.config.visibilityTimeout(),
+			AdaptivePolling:   s.config.adaptivePolling(),
+		},
+		DecisionCapacity: _decisionHistorySize,
+	}
+
+	s.statesMu.RLock()
+	for queueURL, state := range s.states {
+		dump.Queues = append(dump.Queues, state.dump(queueURL))
+	}
+	s.statesMu.RUnlock()
+
+	sort.Slice(dump.Queues, func(i, j int) bool {
+		return dump.Queues[i].QueueURL < dump.Queues[j].QueueURL
+	})
+
+	return json.MarshalIndent(dump, "", "  ")
+}
+
+// dump captures a consistent snapshot of the queue state.
+func (a *arrakis) dump(queueURL string) queueDump {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	var lastUpdate time.Time
+	if last := atomic.LoadInt64(&a.lastUpdate); last > 0 {
+		lastUpdate = time.Unix(last, 0)
+	}
+
+	return queueDump{
+		QueueURL:                 queueURL,
+		ArrakisEnabled:           a.enabledLocked(),
+		Average:                  a.average,
+		EwmaAlpha:                a.currentAlpha(),
+		VolumeClass:              a.class,
+		LastWaitTimeSeconds:      atomic.LoadInt64(&a.lastWaitTime),
+		LastMessageCount:         atomic.LoadInt64(&a.messageCount),
+		ConsecutiveEmptyMessages: a.consecutiveEmptyMessages,
+		LowVolumeCycles:          a.lowVolumeCycle,
+		LastUpdate:               lastUpdate,
+		LastReceiveEmpty:         a.lastReceiveEmpty,
+		LastReset:                a.lastReset,
+		Events:                   a.events,
+		Decisions:                a.decisions.Items(),
+	}
+}
+
+// recordDecision appends a wait time decision to the queue's history.
+//
+// Parameters:
+//   - issuedAt: When the ReceiveMessage call was issued
+//   - waitTime: The wait time used, in seconds
+//   - messages: Number of messages returned by the call
+func (a *arrakis) recordDecision(issuedAt time.Time, waitTime int64, messages int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.decisions.Push(Decision{
+		Time:            issuedAt,
+		WaitTimeSeconds: waitTime,
+		Class:           a.class,
+		Average:         a.average,
+		Messages:        messages,
+	})
+}
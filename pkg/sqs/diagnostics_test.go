@@ -0,0 +1,47 @@
+package sqs
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestDumpState(t *testing.T) {
+	fake := &fakeSQS{}
+	fake.push(testMessage("m1", ""), testMessage("m2", ""))
+	client := newTestSQS(fake)
+	client.EnableArrakis()
+
+	_, _ = client.ReceiveMessage(context.Background(), "queue", 10, nil)
+	_, _ = client.ReceiveMessage(context.Background(), "queue", 10, nil)
+
+	raw, err := client.DumpState()
+	if err != nil {
+		t.Fatalf("DumpState returned error: %v", err)
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		t.Fatalf("DumpState produced invalid JSON: %v", err)
+	}
+
+	if doc["ArrakisEnabled"] != true {
+		t.Error("Expected ArrakisEnabled to be true")
+	}
+
+	queues := doc["Queues"].([]interface{})
+	if len(queues) != 1 {
+		t.Fatalf("Expected 1 queue, got %d", len(queues))
+	}
+
+	queue := queues[0].(map[string]interface{})
+	decisions := queue["Decisions"].([]interface{})
+	if len(decisions) != 2 {
+		t.Fatalf("Expected 2 decisions, got %d", len(decisions))
+	}
+
+	first := decisions[0].(map[string]interface{})
+	if first["Messages"].(float64) != 2 || first["Class"] != "low" {
+		t.Errorf("Unexpected first decision: %v", first)
+	}
+}
@@ -0,0 +1,175 @@
+package sqs
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// DLQ inspection configuration
+const (
+	_dlqMaxSampledMessages       = 100 // Upper bound of messages sampled per inspection
+	_dlqTopErrorAttributes       = 5   // Number of error attribute values reported
+	_dlqErrorAttributeNameMarker = "error"
+	_attributeDLQSourceArn       = "DeadLetterQueueSourceArn"
+	_queueAttributeMessageCount  = "ApproximateNumberOfMessages"
+)
+
+// _dlqAgeBuckets are the upper bounds of the age distribution reported by InspectDLQ.
+// Messages older than the last bound fall into an extra, unbounded bucket.
+var _dlqAgeBuckets = []time.Duration{
+	time.Minute,
+	time.Hour,
+	24 * time.Hour,
+	7 * 24 * time.Hour,
+}
+
+// DLQSummary describes the content of a dead-letter queue, as returned by InspectDLQ.
+type DLQSummary struct {
+	// ApproximateCount is the number of visible messages reported by SQS.
+	ApproximateCount int
+	// Sampled is the number of messages actually inspected.
+	Sampled int
+	// OldestAge and NewestAge are the ages of the oldest and newest sampled messages.
+	OldestAge time.Duration
+	NewestAge time.Duration
+	// AgeDistribution counts sampled messages per age bucket, youngest first.
+	AgeDistribution []AgeBucket
+	// TopErrors lists the most frequent values of message attributes whose name
+	// contains "error" (e.g., "ErrorType", "last_error"), most frequent first.
+	TopErrors []AttributeCount
+	// SourceQueues counts sampled messages per source queue ARN, as recorded by SQS redrive.
+	SourceQueues map[string]int
+}
+
+// AgeBucket counts messages younger than UpTo. The last bucket of a distribution has
+// a zero UpTo and holds every message older than the previous bucket.
+type AgeBucket struct {
+	UpTo  time.Duration
+	Count int
+}
+
+// AttributeCount counts messages carrying a given attribute value.
+type AttributeCount struct {
+	Name  string
+	Value string
+	Count int
+}
+
+// InspectDLQ samples a dead-letter queue without consuming it and summarizes its content,
+// so a DLQ can be triaged without writing a custom script.
+//
+// Up to 100 messages are received with a short visibility timeout and released again
+// (visibility 0) as soon as sampling ends, so they are not deleted and stay available to
+// redrive. Sampling bypasses the adaptive polling state of the client.
+//
+// Parameters:
+//   - ctx: Context for request cancellation and timeouts
+//   - dlqURL: The URL of the dead-letter queue to inspect
+//
+// Returns:
+//   - *DLQSummary: Counts, age distribution, top error attributes and source queues
+//   - error: Any error that occurred while reading the queue
+//
+// Example:
+//
+//	summary, err := sqsClient.InspectDLQ(ctx, dlqURL)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Printf("%d messages, oldest is %s old\n", summary.ApproximateCount, summary.OldestAge)
+func (s *SQS) InspectDLQ(ctx context.Context, dlqURL string) (*DLQSummary, error) {
+	attributes, err := s.client.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{
+		QueueUrl:       aws.String(dlqURL),
+		AttributeNames: []types.QueueAttributeName{types.QueueAttributeNameApproximateNumberOfMessages},
+	}, apiOptions(ctx)...)
+	if err != nil {
+		return nil, err
+	}
+
+	sampled, err := s.sampleMessages(ctx, dlqURL, _dlqMaxSampledMessages)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := summarizeDLQ(sampled, time.Now())
+	summary.ApproximateCount, _ = strconv.Atoi(attributes.Attributes[_queueAttributeMessageCount])
+
+	return summary, nil
+}
+
+// summarizeDLQ builds the summary of a set of sampled dead-letter messages.
+func summarizeDLQ(messages []Message, now time.Time) *DLQSummary {
+	summary := &DLQSummary{
+		Sampled:         len(messages),
+		AgeDistribution: make([]AgeBucket, len(_dlqAgeBuckets)+1),
+		SourceQueues:    map[string]int{},
+	}
+
+	for i, bound := range _dlqAgeBuckets {
+		summary.AgeDistribution[i].UpTo = bound
+	}
+
+	errorCounts := map[AttributeCount]int{}
+	dated := 0
+
+	for _, msg := range messages {
+		if !msg.SentTimestamp.IsZero() {
+			age := now.Sub(msg.SentTimestamp)
+			if dated == 0 || age > summary.OldestAge {
+				summary.OldestAge = age
+			}
+			if dated == 0 || age < summary.NewestAge {
+				summary.NewestAge = age
+			}
+			summary.AgeDistribution[ageBucket(age)].Count++
+			dated++
+		}
+
+		if source := msg.Attributes[_attributeDLQSourceArn]; source != "" {
+			summary.SourceQueues[source]++
+		}
+
+		for name, value := range msg.MessageAttributes {
+			if strings.Contains(strings.ToLower(name), _dlqErrorAttributeNameMarker) {
+				errorCounts[AttributeCount{Name: name, Value: aws.ToString(value.StringValue)}]++
+			}
+		}
+	}
+
+	for key, count := range errorCounts {
+		key.Count = count
+		summary.TopErrors = append(summary.TopErrors, key)
+	}
+
+	sort.Slice(summary.TopErrors, func(i, j int) bool {
+		a, b := summary.TopErrors[i], summary.TopErrors[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Name+a.Value < b.Name+b.Value
+	})
+
+	if len(summary.TopErrors) > _dlqTopErrorAttributes {
+		summary.TopErrors = summary.TopErrors[:_dlqTopErrorAttributes]
+	}
+
+	return summary
+}
+
+// ageBucket returns the index of the age distribution bucket for age.
+func ageBucket(age time.Duration) int {
+	for i, bound := range _dlqAgeBuckets {
+		if age < bound {
+			return i
+		}
+	}
+
+	return len(_dlqAgeBuckets)
+}
@@ -0,0 +1,81 @@
+package sqs
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+func TestSummarizeDLQ(t *testing.T) {
+	now := time.Now()
+	errorAttr := func(value string) map[string]types.MessageAttributeValue {
+		return map[string]types.MessageAttributeValue{
+			"ErrorType": {DataType: aws.String("String"), StringValue: aws.String(value)},
+		}
+	}
+
+	messages := []Message{
+		{SentTimestamp: now.Add(-30 * time.Second), MessageAttributes: errorAttr("timeout"), Attributes: map[string]string{_attributeDLQSourceArn: "arn:orders"}},
+		{SentTimestamp: now.Add(-2 * time.Hour), MessageAttributes: errorAttr("timeout"), Attributes: map[string]string{_attributeDLQSourceArn: "arn:orders"}},
+		{SentTimestamp: now.Add(-10 * 24 * time.Hour), MessageAttributes: errorAttr("validation"), Attributes: map[string]string{_attributeDLQSourceArn: "arn:billing"}},
+	}
+
+	summary := summarizeDLQ(messages, now)
+
+	if summary.Sampled != 3 {
+		t.Errorf("Expected 3 sampled messages, got %d", summary.Sampled)
+	}
+	if summary.OldestAge != 10*24*time.Hour || summary.NewestAge != 30*time.Second {
+		t.Errorf("Unexpected ages: oldest %s, newest %s", summary.OldestAge, summary.NewestAge)
+	}
+
+	expectedBuckets := []int{1, 0, 1, 0, 1}
+	for i, bucket := range summary.AgeDistribution {
+		if bucket.Count != expectedBuckets[i] {
+			t.Errorf("Bucket %d: expected %d messages, got %d", i, expectedBuckets[i], bucket.Count)
+		}
+	}
+
+	if len(summary.TopErrors) != 2 || summary.TopErrors[0].Value != "timeout" || summary.TopErrors[0].Count != 2 {
+		t.Errorf("Unexpected top errors: %+v", summary.TopErrors)
+	}
+	if summary.SourceQueues["arn:orders"] != 2 || summary.SourceQueues["arn:billing"] != 1 {
+		t.Errorf("Unexpected source queues: %v", summary.SourceQueues)
+	}
+}
+
+func TestInspectDLQReleasesSampledMessages(t *testing.T) {
+	fake := &fakeSQS{queueAttrs: map[string]string{_queueAttributeMessageCount: "42"}}
+	sent := strconv.FormatInt(time.Now().Add(-time.Minute).UnixMilli(), 10)
+	first, second := testMessage("m1", ""), testMessage("m2", "")
+	first.Attributes[_attributeSentTimestamp] = sent
+	second.Attributes[_attributeSentTimestamp] = sent
+	fake.push(first, second)
+	client := newTestSQS(fake)
+	client.EnableArrakis()
+
+	summary, err := client.InspectDLQ(context.Background(), "dlq")
+	if err != nil {
+		t.Fatalf("InspectDLQ returned error: %v", err)
+	}
+
+	if summary.ApproximateCount != 42 || summary.Sampled != 2 {
+		t.Errorf("Expected 42 approximate and 2 sampled, got %d and %d", summary.ApproximateCount, summary.Sampled)
+	}
+
+	for _, handle := range []string{"m1", "m2"} {
+		if v, ok := fake.visibilityOf(handle); !ok || v != 0 {
+			t.Errorf("Expected %s to be released with visibility 0", handle)
+		}
+	}
+	if len(fake.deletedHandles()) != 0 {
+		t.Error("Expected InspectDLQ not to delete messages")
+	}
+	if client.state("dlq").average != 0 {
+		t.Error("Expected InspectDLQ not to affect the adaptive state")
+	}
+}
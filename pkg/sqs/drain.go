@@ -0,0 +1,187 @@
+package sqs
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// Default drain configuration values
+const (
+	_defaultDrainEmptyReceives   = 3 // Consecutive empty receives that end a drain
+	_defaultDrainWaitTimeSeconds = 1 // Short wait to smooth out SQS eventual consistency
+)
+
+// drainConfig holds the configuration of a Drain call.
+type drainConfig struct {
+	// EmptyReceives is the number of consecutive empty receives after which the queue is
+	// considered empty.
+	EmptyReceives int
+	// WaitTimeSeconds is the long polling wait time of each receive.
+	WaitTimeSeconds int32
+}
+
+// DrainOption is a function type for configuring a Drain call with the functional options pattern.
+type DrainOption func(*drainConfig)
+
+// WithDrainEmptyReceives sets how many consecutive empty receives end the drain (default 3).
+// SQS may return empty responses while messages remain, so a single empty receive is not
+// a reliable end-of-queue signal.
+func WithDrainEmptyReceives(emptyReceives int) DrainOption {
+	return func(c *drainConfig) {
+		c.EmptyReceives = emptyReceives
+	}
+}
+
+// WithDrainWaitTimeSeconds sets the long polling wait time of each receive (default 1).
+func WithDrainWaitTimeSeconds(waitTimeSeconds int32) DrainOption {
+	return func(c *drainConfig) {
+		c.WaitTimeSeconds = waitTimeSeconds
+	}
+}
+
+// Drain processes the backlog of a queue and returns once it is empty, for batch jobs and
+// cron workers that should exit instead of long-polling forever. Messages are received with
+// a short wait time and passed to handler one by one; successfully handled messages are
+// deleted, failed ones are left in the queue. The queue is considered empty after 3
+// consecutive empty receives (see WithDrainEmptyReceives).
+//
+// Draining bypasses the adaptive polling state of the client.
+//
+// Parameters:
+//   - ctx: Context for request cancellation and timeouts
+//   - queueURL: The URL of the queue to drain
+//   - handler: The handler processing each message
+//   - options: Optional settings such as the number of empty receives that end the drain
+//
+// Returns:
+//   - int: Number of messages handled successfully
+//   - error: The first receive error, or the context error if ctx ended before the queue was empty
+//
+// Example:
+//
+//	processed, err := sqsClient.Drain(ctx, queueURL, sqs.HandlerFunc(processReport))
+//	log.Printf("nightly job processed %d messages (err: %v)", processed, err)
+func (s *SQS) Drain(ctx context.Context, queueURL string, handler Handler, options ...DrainOption) (int, error) {
+	config := newDrainConfig(options)
+
+	processed := 0
+	for empty := 0; empty < config.EmptyReceives; {
+		if err := ctx.Err(); err != nil {
+			return processed, err
+		}
+
+		output, err := s.receiveMessage(ctx, s.drainInput(queueURL, int32(s.config.batchSize()), config))
+		if err != nil {
+			return processed, err
+		}
+		s.verifyReceived(queueURL, output)
+
+		if len(output.Messages) == 0 {
+			empty++
+			continue
+		}
+		empty = 0
+
+		for _, m := range output.Messages {
+			msg := NewMessage(queueURL, m)
+			if err := handler.Handle(ctx, msg); err != nil {
+				continue
+			}
+
+			if _, err := s.DeleteMessage(ctx, queueURL, msg.ReceiptHandle); err == nil {
+				processed++
+			}
+		}
+	}
+
+	return processed, nil
+}
+
+// ReceiveAll keeps receiving from a queue with short waits until it has accumulated max
+// messages or the queue is empty, and returns them as one batch, for batch ETL jobs that
+// want a single big slice. The queue is considered empty as for Drain (see
+// WithDrainEmptyReceives and WithDrainWaitTimeSeconds).
+//
+// The messages are received, not deleted: the caller deletes them once processed, before
+// their visibility timeout expires, or they are delivered again. Receiving bypasses the
+// adaptive polling state of the client.
+//
+// Parameters:
+//   - ctx: Context for request cancellation and timeouts
+//   - queueURL: The URL of the queue to receive from
+//   - max: Maximum number of messages to return (0 or less means unbounded)
+//   - options: Optional settings such as the number of empty receives that end the batch
+//
+// Returns:
+//   - []Message: The received messages, also returned alongside an error
+//   - error: The first receive error, or the context error if ctx ended first
+//
+// Example:
+//
+//	batch, err := sqsClient.ReceiveAll(ctx, queueURL, 5000)
+//	if err == nil {
+//	    err = loadIntoWarehouse(batch)
+//	}
+func (s *SQS) ReceiveAll(ctx context.Context, queueURL string, max int, options ...DrainOption) ([]Message, error) {
+	config := newDrainConfig(options)
+
+	var messages []Message
+	for empty := 0; empty < config.EmptyReceives && (max <= 0 || len(messages) < max); {
+		if err := ctx.Err(); err != nil {
+			return messages, err
+		}
+
+		batchSize := _maxNumberOfMessages
+		if max > 0 {
+			batchSize = min(batchSize, max-len(messages))
+		}
+
+		output, err := s.receiveMessage(ctx, s.drainInput(queueURL, int32(batchSize), config))
+		if err != nil {
+			return messages, err
+		}
+		s.verifyReceived(queueURL, output)
+
+		if len(output.Messages) == 0 {
+			empty++
+			continue
+		}
+		empty = 0
+
+		for _, m := range output.Messages {
+			messages = append(messages, NewMessage(queueURL, m))
+		}
+	}
+
+	return messages, nil
+}
+
+// newDrainConfig builds the configuration of a Drain or ReceiveAll call.
+func newDrainConfig(options []DrainOption) drainConfig {
+	config := drainConfig{
+		EmptyReceives:   _defaultDrainEmptyReceives,
+		WaitTimeSeconds: _defaultDrainWaitTimeSeconds,
+	}
+
+	for _, opt := range options {
+		opt(&config)
+	}
+	config.EmptyReceives = max(config.EmptyReceives, 1)
+
+	return config
+}
+
+// drainInput builds the receive of a Drain or ReceiveAll call.
+func (s *SQS) drainInput(queueURL string, maxMessages int32, config drainConfig) *sqs.ReceiveMessageInput {
+	return &sqs.ReceiveMessageInput{
+		QueueUrl:                    aws.String(queueURL),
+		MaxNumberOfMessages:         maxMessages,
+		WaitTimeSeconds:             config.WaitTimeSeconds,
+		VisibilityTimeout:           int32(s.config.visibilityTimeout()),
+		MessageAttributeNames:       []string{_allMessageAttributes},
+		MessageSystemAttributeNames: []types.MessageSystemAttributeName{types.MessageSystemAttributeNameAll},
+	}
+}
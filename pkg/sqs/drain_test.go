@@ -0,0 +1,114 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestDrainProcessesBacklogAndReturns(t *testing.T) {
+	fake := &fakeSQS{}
+	fake.push(testMessage("m1", ""), testMessage("m2", ""))
+	fake.push(testMessage("m3", ""))
+
+	handled := 0
+	processed, err := newTestSQS(fake).Drain(context.Background(), "queue", HandlerFunc(func(ctx context.Context, msg Message) error {
+		handled++
+		if msg.ID == "m2" {
+			return errors.New("boom")
+		}
+		return nil
+	}))
+	if err != nil {
+		t.Fatalf("Drain returned error: %v", err)
+	}
+
+	if handled != 3 || processed != 2 {
+		t.Errorf("Expected 3 messages handled and 2 processed, got %d and %d", handled, processed)
+	}
+
+	if deleted := fake.deletedHandles(); len(deleted) != 2 {
+		t.Errorf("Expected only successful messages deleted, got %v", deleted)
+	}
+
+	// 2 receives with messages followed by 3 empty receives
+	if len(fake.receiveInputs) != 5 {
+		t.Errorf("Expected 5 receives, got %d", len(fake.receiveInputs))
+	}
+
+	if wait := fake.receiveInputs[0].WaitTimeSeconds; wait != _defaultDrainWaitTimeSeconds {
+		t.Errorf("Expected a short wait time, got %d", wait)
+	}
+}
+
+func TestDrainEmptyReceivesOption(t *testing.T) {
+	fake := &fakeSQS{}
+
+	_, err := newTestSQS(fake).Drain(context.Background(), "queue", HandlerFunc(func(ctx context.Context, msg Message) error {
+		return nil
+	}), WithDrainEmptyReceives(1))
+	if err != nil {
+		t.Fatalf("Drain returned error: %v", err)
+	}
+
+	if len(fake.receiveInputs) != 1 {
+		t.Errorf("Expected a single receive, got %d", len(fake.receiveInputs))
+	}
+}
+
+func TestDrainReceiveError(t *testing.T) {
+	fake := &fakeSQS{receiveErr: errors.New("denied")}
+
+	_, err := newTestSQS(fake).Drain(context.Background(), "queue", HandlerFunc(func(ctx context.Context, msg Message) error {
+		return nil
+	}))
+	if err == nil {
+		t.Error("Expected the receive error to be returned")
+	}
+}
+
+func TestReceiveAllAccumulatesUpToMax(t *testing.T) {
+	fake := &fakeSQS{}
+	fake.push(testMessage("m1", ""), testMessage("m2", ""))
+	fake.push(testMessage("m3", ""), testMessage("m4", ""))
+
+	messages, err := newTestSQS(fake).ReceiveAll(context.Background(), "queue", 3)
+	if err != nil {
+		t.Fatalf("ReceiveAll returned error: %v", err)
+	}
+
+	if len(messages) != 3 || messages[2].ID != "m3" {
+		t.Errorf("Expected the first 3 messages, got %v", messages)
+	}
+
+	// The second receive only asks for the message still missing
+	if len(fake.receiveInputs) != 2 || fake.receiveInputs[1].MaxNumberOfMessages != 1 {
+		t.Errorf("Expected 2 receives, the last asking for 1 message, got %d", len(fake.receiveInputs))
+	}
+
+	if deleted := fake.deletedHandles(); len(deleted) != 0 {
+		t.Errorf("Expected no message deleted, got %v", deleted)
+	}
+}
+
+func TestReceiveAllStopsOnEmptyQueue(t *testing.T) {
+	fake := &fakeSQS{}
+	fake.push(testMessage("m1", ""))
+
+	messages, err := newTestSQS(fake).ReceiveAll(context.Background(), "queue", 100, WithDrainEmptyReceives(2))
+	if err != nil {
+		t.Fatalf("ReceiveAll returned error: %v", err)
+	}
+
+	if len(messages) != 1 || len(fake.receiveInputs) != 3 {
+		t.Errorf("Expected 1 message in 3 receives, got %d in %d", len(messages), len(fake.receiveInputs))
+	}
+}
+
+func TestReceiveAllReceiveError(t *testing.T) {
+	fake := &fakeSQS{receiveErr: errors.New("denied")}
+
+	if _, err := newTestSQS(fake).ReceiveAll(context.Background(), "queue", 10); err == nil {
+		t.Error("Expected the receive error to be returned")
+	}
+}
@@ -0,0 +1,153 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrVisibilityExpired is the cause of the cancellation of handler contexts whose message
+// became visible again (see WithVisibilityExpiry), as returned by context.Cause.
+var ErrVisibilityExpired = errors.New("arrakis: message visibility expired")
+
+// VisibilityExpired describes a message whose visibility timeout expired while its
+// handler was still running: the message is visible again and may be delivered to
+// another consumer, so it may now be processed twice.
+type VisibilityExpired struct {
+	// QueueURL is the queue the message was received from.
+	QueueURL string
+	// MessageID is the SQS MessageId of the message being handled.
+	MessageID string
+	// Elapsed is how long ago the message was received.
+	Elapsed time.Duration
+	// VisibilityTimeout is the visibility timeout of the message, extensions included.
+	VisibilityTimeout time.Duration
+}
+
+// WithVisibilityExpiry notifies callback when the visibility timeout of a message expires
+// while its handler is still running, so the application knows it may be
+// double-processing the message and can react. Visibility is counted from the receive,
+// and extensions made with ExtendAllInFlight push the expiry back. Unlike
+// WithSlowHandlerThreshold, which warns ahead of time, the callback fires once the
+// message is visible again.
+//
+// With cancelHandler, the context of the handler is also cancelled, with
+// ErrVisibilityExpired as its cause, so handlers that honor their context stop working on
+// a message another consumer may already have.
+//
+// Parameters:
+//   - callback: Optional function notified of each expiry; it runs on its own goroutine
+//   - cancelHandler: Whether to cancel the context of the handler on expiry
+//
+// Example:
+//
+//	consumer := NewConsumer(client, queueURL, handler, WithVisibilityExpiry(func(expired VisibilityExpired) {
+//	    log.Printf("message %s may be processed twice", expired.MessageID)
+//	}, true))
+func WithVisibilityExpiry(callback func(VisibilityExpired), cancelHandler bool) ConsumerOption {
+	return func(c *consumerConfig) {
+		c.WatchVisibilityExpiry = true
+		c.OnVisibilityExpired = callback
+		c.CancelOnVisibilityExpiry = cancelHandler
+	}
+}
+
+// watchExpiry starts watching the visibility of msg while it is handled, and returns the
+// context of the handler and the function to call once the handler returned. It does
+// nothing when visibility expiry detection is disabled.
+func (c *Consumer) watchExpiry(ctx context.Context, source queueSource, msg Message) (context.Context, func()) {
+	if !c.config.WatchVisibilityExpiry || msg.receivedAt.IsZero() {
+		return ctx, func() {}
+	}
+
+	visibility := time.Duration(source.client.config.visibilityTimeout()) * time.Second
+
+	cancel := context.CancelCauseFunc(func(error) {})
+	if c.config.CancelOnVisibilityExpiry {
+		ctx, cancel = context.WithCancelCause(ctx)
+	}
+
+	w := &expiryWatch{receivedAt: msg.receivedAt, visibility: visibility}
+	w.timer = time.AfterFunc(time.Until(msg.receivedAt.Add(visibility)), func() {
+		expired := VisibilityExpired{QueueURL: source.queueURL, MessageID: msg.ID, Elapsed: time.Since(msg.receivedAt), VisibilityTimeout: w.timeout()}
+
+		source.client.logger().Warn("message visibility expired while handling, it may be processed twice", "queue", expired.QueueURL, "message_id", expired.MessageID, "elapsed", expired.Elapsed, "visibility_timeout", expired.VisibilityTimeout)
+		cancel(ErrVisibilityExpired)
+
+		if c.config.OnVisibilityExpired != nil {
+			c.config.OnVisibilityExpired(expired)
+		}
+	})
+	c.expiries.add(msg.ReceiptHandle, w)
+
+	return ctx, func() {
+		w.timer.Stop()
+		c.expiries.remove(msg.ReceiptHandle)
+		cancel(nil)
+	}
+}
+
+// expiryWatch is the visibility expiry timer of a message being handled.
+type expiryWatch struct {
+	receivedAt time.Time
+	timer      *time.Timer
+
+	mu         sync.Mutex
+	visibility time.Duration // Visibility timeout from receivedAt, extensions included
+}
+
+// extend moves the expiry to timeout from now.
+func (w *expiryWatch) extend(timeout time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.visibility = time.Since(w.receivedAt) + timeout
+	w.timer.Reset(timeout)
+}
+
+// timeout returns the visibility timeout of the message.
+func (w *expiryWatch) timeout() time.Duration {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.visibility
+}
+
+// expiryWatches holds the expiry timers of the messages being handled, by receipt
+// handle. Its zero value is empty.
+type expiryWatches struct {
+	mu      sync.Mutex
+	watches map[string]*expiryWatch
+}
+
+// add registers the expiry timer of a message.
+func (e *expiryWatches) add(receiptHandle string, w *expiryWatch) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.watches == nil {
+		e.watches = map[string]*expiryWatch{}
+	}
+	e.watches[receiptHandle] = w
+}
+
+// remove drops the expiry timer of a message.
+func (e *expiryWatches) remove(receiptHandle string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	delete(e.watches, receiptHandle)
+}
+
+// extend moves the expiry of messages whose visibility was changed to timeout from now.
+func (e *expiryWatches) extend(messages []Message, timeout time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, msg := range messages {
+		if w, ok := e.watches[msg.ReceiptHandle]; ok {
+			w.extend(timeout)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConsumerVisibilityExpiry(t *testing.T) {
+	fake := &fakeSQS{}
+	fake.push(testMessage("stuck", ""))
+	client := newTestSQS(fake)
+	client.config.VisibilityTimeout = 1
+
+	var (
+		mu      sync.Mutex
+		expired []VisibilityExpired
+		cause   error
+	)
+
+	handler := HandlerFunc(func(ctx context.Context, msg Message) error {
+		select {
+		case <-ctx.Done():
+		case <-time.After(3 * time.Second):
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		cause = context.Cause(ctx)
+		return ctx.Err()
+	})
+
+	consumer := NewConsumer(client, "queue", handler, WithVisibilityExpiry(func(e VisibilityExpired) {
+		mu.Lock()
+		defer mu.Unlock()
+		expired = append(expired, e)
+	}, true))
+	runConsumer(t, consumer, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return cause != nil
+	})
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(expired) != 1 || expired[0].MessageID != "stuck" || expired[0].VisibilityTimeout != time.Second {
+		t.Fatalf("Expected the expiry of the stuck message, got %+v", expired)
+	}
+	if !errors.Is(cause, ErrVisibilityExpired) {
+		t.Errorf("Expected the handler context cancelled by the expiry, got %v", cause)
+	}
+}
+
+func TestExpiryWatchExtend(t *testing.T) {
+	var watches expiryWatches
+	fired := make(chan struct{})
+	w := &expiryWatch{receivedAt: time.Now(), visibility: 20 * time.Millisecond}
+	w.timer = time.AfterFunc(20*time.Millisecond, func() { close(fired) })
+	watches.add("handle", w)
+
+	watches.extend([]Message{{ReceiptHandle: "handle"}}, time.Hour)
+
+	select {
+	case <-fired:
+		t.Fatal("Expected the extension to push the expiry back")
+	case <-time.After(50 * time.Millisecond):
+	}
+	if got := w.timeout(); got < time.Hour {
+		t.Errorf("Expected the extended visibility timeout, got %v", got)
+	}
+	w.timer.Stop()
+}
@@ -0,0 +1,130 @@
+package sqs
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// ExtendAllInFlight extends the visibility timeout of every message the consumer has
+// received but not yet acknowledged, so none of them becomes visible again for the next d.
+// It is meant for consumers that detect a downstream slowdown and need more time across the
+// board; the messages are extended with ChangeMessageVisibilityBatch calls of up to 10
+// messages each.
+//
+// Messages acknowledged while the call is in progress are reported by SQS as failed entries
+// and are not counted.
+//
+// Parameters:
+//   - ctx: Context for request cancellation and timeouts
+//   - d: New visibility timeout, counted from now (at most 12 hours)
+//
+// Returns:
+//   - int: Number of messages whose visibility was extended
+//   - error: The first error of a whole ChangeMessageVisibilityBatch call, or nil
+//
+// Example:
+//
+//	if downstream.Degraded() {
+//	    extended, err := consumer.ExtendAllInFlight(ctx, 5*time.Minute)
+//	    log.Printf("extended %d messages (err: %v)", extended, err)
+//	}
+func (c *Consumer) ExtendAllInFlight(ctx context.Context, d time.Duration) (int, error) {
+	timeout := visibilityDelay(d)
+
+	var (
+		extended int
+		firstErr error
+	)
+
+	for _, messages := range c.inFlight.byQueue() {
+		source := c.sourceOf(messages[0])
+
+		for start := 0; start < len(messages); start += _maxBatchEntries {
+			batch := messages[start:min(start+_maxBatchEntries, len(messages))]
+
+			changed, err := source.client.changeVisibilityBatch(ctx, source.queueURL, batch, timeout)
+			c.expiries.extend(changed, time.Duration(timeout)*time.Second)
+			extended += len(changed)
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+
+	return extended, firstErr
+}
+
+// changeVisibilityBatch sets the visibility timeout of up to 10 messages of a queue in a
+// single call and returns the messages changed.
+func (s *SQS) changeVisibilityBatch(ctx context.Context, queueURL string, messages []Message, timeout int32) ([]Message, error) {
+	entries := make([]types.ChangeMessageVisibilityBatchRequestEntry, len(messages))
+	for i, msg := range messages {
+		entries[i] = types.ChangeMessageVisibilityBatchRequestEntry{
+			Id:                aws.String(strconv.Itoa(i)),
+			ReceiptHandle:     aws.String(msg.ReceiptHandle),
+			VisibilityTimeout: timeout,
+		}
+	}
+
+	output, err := s.client.ChangeMessageVisibilityBatch(ctx, &sqs.ChangeMessageVisibilityBatchInput{
+		QueueUrl: aws.String(queueURL),
+		Entries:  entries,
+	}, apiOptions(ctx)...)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := make([]Message, 0, len(output.Successful))
+	for _, entry := range output.Successful {
+		if i, err := strconv.Atoi(aws.ToString(entry.Id)); err == nil && i < len(messages) {
+			changed = append(changed, messages[i])
+		}
+	}
+
+	return changed, nil
+}
+
+// inFlightSet tracks the messages dispatched by a consumer and not yet acknowledged. Its
+// zero value is an empty set.
+type inFlightSet struct {
+	mu       sync.Mutex
+	messages map[string]Message // Keyed by receipt handle
+}
+
+// add starts tracking a dispatched message.
+func (s *inFlightSet) add(msg Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.messages == nil {
+		s.messages = map[string]Message{}
+	}
+	s.messages[msg.ReceiptHandle] = msg
+}
+
+// remove stops tracking a message once it has been processed.
+func (s *inFlightSet) remove(msg Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.messages, msg.ReceiptHandle)
+}
+
+// byQueue returns the tracked messages grouped by the queue they were received from.
+func (s *inFlightSet) byQueue() map[string][]Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	queues := map[string][]Message{}
+	for _, msg := range s.messages {
+		queues[msg.QueueURL] = append(queues[msg.QueueURL], msg)
+	}
+
+	return queues
+}
@@ -0,0 +1,59 @@
+package sqs
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestExtendAllInFlight(t *testing.T) {
+	fake := &fakeSQS{}
+	for i := range 12 {
+		fake.push(testMessage(fmt.Sprintf("m%d", i), ""))
+	}
+
+	var started sync.WaitGroup
+	started.Add(12)
+	release := make(chan struct{})
+	consumer := NewConsumer(newTestSQS(fake), "queue", HandlerFunc(func(ctx context.Context, msg Message) error {
+		started.Done()
+		<-release
+		return nil
+	}), WithWorkers(12))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		_ = consumer.Start(ctx)
+		close(done)
+	}()
+	started.Wait()
+
+	extended, err := consumer.ExtendAllInFlight(context.Background(), 5*time.Minute)
+	if err != nil {
+		t.Fatalf("ExtendAllInFlight returned error: %v", err)
+	}
+	if extended != 12 {
+		t.Errorf("Expected 12 messages extended, got %d", extended)
+	}
+
+	fake.mu.Lock()
+	batches := fake.visibilityBatches
+	fake.mu.Unlock()
+	if batches != 2 {
+		t.Errorf("Expected 2 batch calls of at most 10 messages, got %d", batches)
+	}
+	if visibility, ok := fake.visibilityOf("m11"); !ok || visibility != 300 {
+		t.Errorf("Expected a 300s visibility timeout, got %d", visibility)
+	}
+
+	close(release)
+	cancel()
+	<-done
+
+	if extended, _ := consumer.ExtendAllInFlight(context.Background(), time.Minute); extended != 0 {
+		t.Errorf("Expected no message in flight once processed, got %d", extended)
+	}
+}
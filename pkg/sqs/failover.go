@@ -0,0 +1,217 @@
+package sqs
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// Default failover configuration values
+const (
+	_defaultFailoverThreshold   = 5                                                   // Consecutive receive failures before failing over
+	_defaultFailbackInterval    = 30 * time.Second                                    // Time between health probes of the primary queue
+	_defaultFailbackProbes      = 3                                                   // Consecutive healthy probes before failing back
+	_failoverActivePrimary      = "primary"                                           // Reported to OnSwitch when failing back
+	_failoverActiveSecondary    = "secondary"                                         // Reported to OnSwitch when failing over
+	_failoverProbeAttributeName = types.QueueAttributeNameApproximateNumberOfMessages // Cheap attribute read by health probes
+)
+
+// failoverConfig holds the failover settings of a Consumer.
+type failoverConfig struct {
+	// Secondary is the client of the secondary region.
+	Secondary *SQS
+	// SecondaryURL is the URL of the secondary queue.
+	SecondaryURL string
+	// Threshold is the number of consecutive receive failures that trigger a failover.
+	Threshold int
+	// FailbackInterval is the time between health probes of the primary queue.
+	FailbackInterval time.Duration
+	// FailbackProbes is the number of consecutive healthy probes needed to fail back.
+	FailbackProbes int
+	// OnSwitch is notified with "primary" or "secondary" whenever the active queue changes.
+	OnSwitch func(active string)
+}
+
+// FailoverOption is a function type for configuring consumer failover with the functional options pattern.
+type FailoverOption func(*failoverConfig)
+
+// WithFailoverThreshold sets how many consecutive receive failures on the primary queue
+// trigger a failover (default: 5).
+func WithFailoverThreshold(failures int) FailoverOption {
+	return func(c *failoverConfig) {
+		c.Threshold = failures
+	}
+}
+
+// WithFailback sets how the consumer returns to the primary queue: it is probed every
+// interval while the consumer is failed over, and the consumer fails back after probes
+// consecutive healthy probes (default: every 30 seconds, 3 probes).
+func WithFailback(interval time.Duration, probes int) FailoverOption {
+	return func(c *failoverConfig) {
+		c.FailbackInterval = interval
+		c.FailbackProbes = probes
+	}
+}
+
+// WithFailoverNotify registers a function notified with "primary" or "secondary" every
+// time the consumer switches queues.
+func WithFailoverNotify(onSwitch func(active string)) FailoverOption {
+	return func(c *failoverConfig) {
+		c.OnSwitch = onSwitch
+	}
+}
+
+// WithFailover pairs the consumer queue (the primary) with a secondary queue, usually in
+// another region. The consumer polls the primary queue; after sustained receive failures it
+// fails over to the secondary queue, and it fails back once the primary has been healthy for
+// several consecutive probes, so a flapping region doesn't make it switch back and forth.
+//
+// Each queue is polled with its own client, so each region keeps independent adaptive
+// polling state. Messages are always deleted (or nacked) in the queue they came from.
+//
+// Parameters:
+//   - secondary: The client of the secondary region
+//   - secondaryURL: The URL of the secondary queue
+//   - options: Optional failover settings (thresholds, probes, notifications)
+//
+// Example:
+//
+//	consumer := sqs.NewConsumer(usEast1, primaryURL, handler,
+//	    sqs.WithFailover(usWest2, secondaryURL, sqs.WithFailoverThreshold(3)))
+func WithFailover(secondary *SQS, secondaryURL string, options ...FailoverOption) ConsumerOption {
+	return func(c *consumerConfig) {
+		config := &failoverConfig{
+			Secondary:        secondary,
+			SecondaryURL:     secondaryURL,
+			Threshold:        _defaultFailoverThreshold,
+			FailbackInterval: _defaultFailbackInterval,
+			FailbackProbes:   _defaultFailbackProbes,
+		}
+
+		for _, opt := range options {
+			opt(config)
+		}
+
+		c.Failover = config
+	}
+}
+
+// queueSource is a queue together with the client used to reach it.
+type queueSource struct {
+	client   *SQS
+	queueURL string
+	state    *arrakis
+}
+
+// failover tracks the health of the primary queue and decides which queue is active.
+type failover struct {
+	config failoverConfig
+	logger Logger
+
+	mu          sync.Mutex
+	onSecondary bool
+	failures    int       // Consecutive receive failures on the primary
+	healthy     int       // Consecutive healthy probes of the primary while failed over
+	nextProbe   time.Time // When the primary is probed next
+}
+
+// newFailover creates the failover state of a consumer (nil when failover is disabled).
+func newFailover(config *failoverConfig, logger Logger) *failover {
+	if config == nil {
+		return nil
+	}
+
+	return &failover{config: *config, logger: logger}
+}
+
+// secondaryActive reports whether the consumer is failed over.
+func (f *failover) secondaryActive() bool {
+	if f == nil {
+		return false
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.onSecondary
+}
+
+// observe records the outcome of a receive on the primary queue and fails over once the
+// failure threshold is reached.
+func (f *failover) observe(err error, now time.Time) {
+	f.mu.Lock()
+
+	if err == nil {
+		f.failures = 0
+		f.mu.Unlock()
+		return
+	}
+
+	f.failures++
+	if f.failures < max(f.config.Threshold, 1) {
+		f.mu.Unlock()
+		return
+	}
+
+	f.onSecondary = true
+	f.failures = 0
+	f.healthy = 0
+	f.nextProbe = now.Add(f.config.FailbackInterval)
+	f.mu.Unlock()
+
+	f.notify(_failoverActiveSecondary)
+}
+
+// probe checks the health of the primary queue when a probe is due and fails back after
+// enough consecutive healthy probes.
+func (f *failover) probe(ctx context.Context, primary queueSource, now time.Time) {
+	f.mu.Lock()
+	due := f.onSecondary && !now.Before(f.nextProbe)
+	if due {
+		f.nextProbe = now.Add(f.config.FailbackInterval)
+	}
+	f.mu.Unlock()
+
+	if !due {
+		return
+	}
+
+	_, err := primary.client.client.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{
+		QueueUrl:       aws.String(primary.queueURL),
+		AttributeNames: []types.QueueAttributeName{_failoverProbeAttributeName},
+	}, apiOptions(ctx)...)
+
+	f.mu.Lock()
+	if err != nil {
+		f.healthy = 0
+		f.mu.Unlock()
+		return
+	}
+
+	f.healthy++
+	if f.healthy < max(f.config.FailbackProbes, 1) {
+		f.mu.Unlock()
+		return
+	}
+
+	f.onSecondary = false
+	f.healthy = 0
+	f.mu.Unlock()
+
+	f.notify(_failoverActivePrimary)
+}
+
+// notify reports a switch of the active queue.
+func (f *failover) notify(active string) {
+	if f.logger != nil {
+		f.logger.Warn("consumer switched queues", "active", active, "secondary_queue", f.config.SecondaryURL)
+	}
+
+	if f.config.OnSwitch != nil {
+		f.config.OnSwitch(active)
+	}
+}
@@ -0,0 +1,91 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFailoverSwitchesAfterThreshold(t *testing.T) {
+	var switches []string
+	f := newFailover(&failoverConfig{Threshold: 3, OnSwitch: func(active string) { switches = append(switches, active) }}, nil)
+	now := time.Now()
+
+	f.observe(errors.New("unavailable"), now)
+	f.observe(errors.New("unavailable"), now)
+	f.observe(nil, now)
+	f.observe(errors.New("unavailable"), now)
+	f.observe(errors.New("unavailable"), now)
+	if f.secondaryActive() {
+		t.Error("Expected a success to reset the failure count")
+	}
+
+	f.observe(errors.New("unavailable"), now)
+	if !f.secondaryActive() {
+		t.Error("Expected failover after 3 consecutive failures")
+	}
+	if len(switches) != 1 || switches[0] != "secondary" {
+		t.Errorf("Expected a single switch to secondary, got %v", switches)
+	}
+}
+
+func TestFailoverFailsBackAfterHealthyProbes(t *testing.T) {
+	fake := &fakeSQS{}
+	primary := queueSource{client: newTestSQS(fake), queueURL: "primary"}
+	f := newFailover(&failoverConfig{Threshold: 1, FailbackInterval: time.Minute, FailbackProbes: 2}, nil)
+	now := time.Now()
+
+	f.observe(errors.New("unavailable"), now)
+
+	// Probes are spaced by the failback interval
+	f.probe(context.Background(), primary, now)
+	f.probe(context.Background(), primary, now.Add(time.Minute))
+	if !f.secondaryActive() {
+		t.Error("Expected to stay on secondary before the first probe is due")
+	}
+
+	// A failed probe restarts the count
+	fake.attributesErr = errors.New("unavailable")
+	f.probe(context.Background(), primary, now.Add(2*time.Minute))
+	fake.attributesErr = nil
+	f.probe(context.Background(), primary, now.Add(3*time.Minute))
+	if !f.secondaryActive() {
+		t.Error("Expected a failed probe to reset the healthy count")
+	}
+
+	f.probe(context.Background(), primary, now.Add(4*time.Minute))
+	if f.secondaryActive() {
+		t.Error("Expected failback after 2 consecutive healthy probes")
+	}
+}
+
+func TestConsumerFailsOverToSecondary(t *testing.T) {
+	primaryFake := &fakeSQS{receiveErr: errors.New("region unavailable")}
+	secondaryFake := &fakeSQS{}
+	secondaryFake.push(testMessage("m1", ""))
+
+	primary := newTestSQS(primaryFake)
+	secondary := newTestSQS(secondaryFake)
+
+	handler := HandlerFunc(func(ctx context.Context, msg Message) error {
+		if msg.QueueURL != "secondary" {
+			t.Errorf("Expected message from secondary, got %s", msg.QueueURL)
+		}
+		return nil
+	})
+
+	consumer := NewConsumer(primary, "primary", handler,
+		WithFailover(secondary, "secondary", WithFailoverThreshold(1), WithFailback(time.Hour, 1)))
+	runConsumer(t, consumer, func() bool { return len(secondaryFake.deletedHandles()) == 1 })
+
+	if consumer.ActiveQueueURL() != "secondary" {
+		t.Errorf("Expected secondary to be active, got %s", consumer.ActiveQueueURL())
+	}
+	if len(primaryFake.deletedHandles()) != 0 {
+		t.Error("Expected no deletes on the primary queue")
+	}
+	if primary.state("primary") == secondary.state("secondary") {
+		t.Error("Expected independent adaptive state per region")
+	}
+}
@@ -0,0 +1,239 @@
+package sqs
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// fakeSQS is an in-memory sqsAPI implementation used by the tests. Receives are served
+// from a list of prepared batches; once they run out, receives return empty responses.
+type fakeSQS struct {
+	mu sync.Mutex
+
+	batches           [][]types.Message
+	receiveInputs     []*sqs.ReceiveMessageInput
+	deleted           []string
+	visibility        map[string]int32
+	visibilityBatches int
+	queueAttrs        map[string]string
+	sent              []*sqs.SendMessageInput
+	receiveErr        error
+	receiveErrs       []error // Returned once each, in order, before receiveErr and batches
+	sendErr           error
+	deleteErr         error
+	sentBatches       []*sqs.SendMessageBatchInput
+	failBodies        map[string]bool // Batch entries with these bodies are reported as failed
+	attributesErr     error
+	queueURLs         map[string]string // Queue URLs returned by GetQueueUrl, by queue name
+	urlLookups        int
+}
+
+// newTestSQS builds an SQS client backed by the given fake.
+func newTestSQS(api sqsAPI, options ...Option) *SQS {
+	var config config
+
+	setDefaults(&config)
+	for _, opt := range options {
+		opt(&config)
+	}
+
+	return &SQS{client: api, config: &config, states: map[string]*arrakis{}}
+}
+
+// push queues a batch of messages to be returned by the next receive.
+func (f *fakeSQS) push(messages ...types.Message) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.batches = append(f.batches, messages)
+}
+
+func (f *fakeSQS) ReceiveMessage(ctx context.Context, params *sqs.ReceiveMessageInput, optFns ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.receiveInputs = append(f.receiveInputs, params)
+
+	if len(f.receiveErrs) > 0 {
+		err := f.receiveErrs[0]
+		f.receiveErrs = f.receiveErrs[1:]
+		return nil, err
+	}
+
+	if f.receiveErr != nil {
+		return nil, f.receiveErr
+	}
+
+	if len(f.batches) == 0 {
+		// Emulate a short long-poll so idle consumers don't spin
+		f.mu.Unlock()
+		sleep(ctx, time.Millisecond)
+		f.mu.Lock()
+
+		return &sqs.ReceiveMessageOutput{}, ctx.Err()
+	}
+
+	batch := f.batches[0]
+	f.batches = f.batches[1:]
+
+	// Leave whatever exceeds the requested batch size for the next receive
+	if limit := int(params.MaxNumberOfMessages); limit > 0 && len(batch) > limit {
+		f.batches = append([][]types.Message{batch[limit:]}, f.batches...)
+		batch = batch[:limit]
+	}
+
+	return &sqs.ReceiveMessageOutput{Messages: batch}, nil
+}
+
+func (f *fakeSQS) DeleteMessage(ctx context.Context, params *sqs.DeleteMessageInput, optFns ...func(*sqs.Options)) (*sqs.DeleteMessageOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.deleteErr != nil {
+		return nil, f.deleteErr
+	}
+	f.deleted = append(f.deleted, aws.ToString(params.ReceiptHandle))
+
+	return &sqs.DeleteMessageOutput{}, nil
+}
+
+func (f *fakeSQS) ChangeMessageVisibility(ctx context.Context, params *sqs.ChangeMessageVisibilityInput, optFns ...func(*sqs.Options)) (*sqs.ChangeMessageVisibilityOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.visibility == nil {
+		f.visibility = map[string]int32{}
+	}
+	f.visibility[aws.ToString(params.ReceiptHandle)] = params.VisibilityTimeout
+
+	return &sqs.ChangeMessageVisibilityOutput{}, nil
+}
+
+func (f *fakeSQS) ChangeMessageVisibilityBatch(ctx context.Context, params *sqs.ChangeMessageVisibilityBatchInput, optFns ...func(*sqs.Options)) (*sqs.ChangeMessageVisibilityBatchOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.visibility == nil {
+		f.visibility = map[string]int32{}
+	}
+	f.visibilityBatches++
+
+	output := &sqs.ChangeMessageVisibilityBatchOutput{}
+	for _, entry := range params.Entries {
+		f.visibility[aws.ToString(entry.ReceiptHandle)] = entry.VisibilityTimeout
+		output.Successful = append(output.Successful, types.ChangeMessageVisibilityBatchResultEntry{Id: entry.Id})
+	}
+
+	return output, nil
+}
+
+func (f *fakeSQS) GetQueueAttributes(ctx context.Context, params *sqs.GetQueueAttributesInput, optFns ...func(*sqs.Options)) (*sqs.GetQueueAttributesOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.attributesErr != nil {
+		return nil, f.attributesErr
+	}
+
+	return &sqs.GetQueueAttributesOutput{Attributes: f.queueAttrs}, nil
+}
+
+func (f *fakeSQS) GetQueueUrl(ctx context.Context, params *sqs.GetQueueUrlInput, optFns ...func(*sqs.Options)) (*sqs.GetQueueUrlOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.urlLookups++
+	queueURL, ok := f.queueURLs[aws.ToString(params.QueueName)]
+	if !ok {
+		return nil, &types.QueueDoesNotExist{Message: aws.String("queue does not exist")}
+	}
+
+	return &sqs.GetQueueUrlOutput{QueueUrl: aws.String(queueURL)}, nil
+}
+
+func (f *fakeSQS) SendMessage(ctx context.Context, params *sqs.SendMessageInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.sendErr != nil {
+		return nil, f.sendErr
+	}
+	f.sent = append(f.sent, params)
+
+	return &sqs.SendMessageOutput{MessageId: aws.String("sent-" + aws.ToString(params.MessageBody))}, nil
+}
+
+func (f *fakeSQS) SendMessageBatch(ctx context.Context, params *sqs.SendMessageBatchInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageBatchOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.sendErr != nil {
+		return nil, f.sendErr
+	}
+	f.sentBatches = append(f.sentBatches, params)
+
+	output := &sqs.SendMessageBatchOutput{}
+	for _, entry := range params.Entries {
+		body := aws.ToString(entry.MessageBody)
+		if f.failBodies[body] {
+			output.Failed = append(output.Failed, types.BatchResultErrorEntry{Id: entry.Id, Code: aws.String("InternalError"), Message: aws.String("failed")})
+			continue
+		}
+
+		f.sent = append(f.sent, &sqs.SendMessageInput{
+			QueueUrl:               params.QueueUrl,
+			MessageBody:            entry.MessageBody,
+			MessageAttributes:      entry.MessageAttributes,
+			MessageGroupId:         entry.MessageGroupId,
+			MessageDeduplicationId: entry.MessageDeduplicationId,
+		})
+		output.Successful = append(output.Successful, types.SendMessageBatchResultEntry{Id: entry.Id, MessageId: aws.String("sent-" + body)})
+	}
+
+	return output, nil
+}
+
+// sentMessages returns a copy of the send inputs received so far.
+func (f *fakeSQS) sentMessages() []*sqs.SendMessageInput {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]*sqs.SendMessageInput(nil), f.sent...)
+}
+
+// visibilityOf returns the last visibility timeout set for a receipt handle.
+func (f *fakeSQS) visibilityOf(receiptHandle string) (int32, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	v, ok := f.visibility[receiptHandle]
+	return v, ok
+}
+
+// deletedHandles returns a copy of the receipt handles deleted so far.
+func (f *fakeSQS) deletedHandles() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]string(nil), f.deleted...)
+}
+
+// testMessage builds an SDK message with the given ID, used as receipt handle and body too.
+func testMessage(id, groupID string) types.Message {
+	m := types.Message{
+		MessageId:     aws.String(id),
+		ReceiptHandle: aws.String(id),
+		Body:          aws.String(id),
+		Attributes:    map[string]string{},
+	}
+	if groupID != "" {
+		m.Attributes[_attributeMessageGroupID] = groupID
+	}
+
+	return m
+}
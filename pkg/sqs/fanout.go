@@ -0,0 +1,117 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// ReceiveMany receives up to n messages at once by issuing ceil(n/10) concurrent
+// ReceiveMessage calls and merging their results, for callers that need more than the 10
+// messages a single call returns. Every message attribute is requested, as with
+// ReceiveMessages.
+//
+// With adaptive polling, the calls share the wait time of one poll, and the algorithm
+// observes their merged result as one poll of the aggregate volume rather than as
+// separate partial polls. Volume classes and spike protection apply to that aggregate:
+// polling a queue with a larger n than before, or mixing ReceiveMany with single receives,
+// looks like a volume jump, which spike protection caps so the average ramps up over a few
+// polls, and the class thresholds, scaled for the batch size of a single call, are reached
+// sooner. Use the same n for every poll of a queue.
+//
+// Calls that fail are left out of the result and logged; an error joining theirs is
+// returned when every call failed.
+//
+// Parameters:
+//   - ctx: Context for request cancellation and timeouts
+//   - queueURL: The URL of the SQS queue to receive messages from
+//   - n: Maximum number of messages to retrieve. If 0 or less, defaults to the configured
+//     batch size (see WithMaxNumberOfMessages)
+//   - options: Optional per-call settings, applied to every call
+//
+// Returns:
+//   - []Message: The received messages, possibly none
+//   - error: The errors of the calls, when none succeeded
+//
+// Example:
+//
+//	messages, err := sqsClient.ReceiveMany(ctx, queueURL, 50) // 5 concurrent calls
+func (s *SQS) ReceiveMany(ctx context.Context, queueURL string, n int, options ...ReceiveOption) ([]Message, error) {
+	if n <= 0 {
+		n = s.config.batchSize()
+	}
+
+	input := &sqs.ReceiveMessageInput{
+		QueueUrl:                    aws.String(queueURL),
+		VisibilityTimeout:           int32(s.config.visibilityTimeout()),
+		MessageAttributeNames:       []string{_allMessageAttributes},
+		MessageSystemAttributeNames: []types.MessageSystemAttributeName{types.MessageSystemAttributeNameAll},
+	}
+
+	for _, opt := range options {
+		opt(input)
+	}
+
+	output, err := s.receiveMany(ctx, input, n)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]Message, len(output.Messages))
+	for i, m := range output.Messages {
+		messages[i] = NewMessage(queueURL, m)
+	}
+
+	return messages, nil
+}
+
+// receiveParallel issues the calls asking for n messages concurrently and merges their
+// responses. A zero n issues input as is.
+func (s *SQS) receiveParallel(ctx context.Context, input *sqs.ReceiveMessageInput, n int) (*sqs.ReceiveMessageOutput, error) {
+	if n == 0 {
+		return s.receiveWithRetry(ctx, input)
+	}
+
+	calls := (n + _maxNumberOfMessages - 1) / _maxNumberOfMessages
+	outputs := make([]*sqs.ReceiveMessageOutput, calls)
+	errs := make([]error, calls)
+
+	var wg sync.WaitGroup
+	for i := range calls {
+		call := *input
+		call.MaxNumberOfMessages = int32(min(n-i*_maxNumberOfMessages, _maxNumberOfMessages))
+		if i > 0 {
+			// Calls sharing a FIFO attempt ID would all get the same batch
+			call.ReceiveRequestAttemptId = nil
+		}
+
+		wg.Go(func() {
+			outputs[i], errs[i] = s.receiveWithRetry(ctx, &call)
+		})
+	}
+	wg.Wait()
+
+	merged := &sqs.ReceiveMessageOutput{}
+	failed := 0
+	for i, output := range outputs {
+		if errs[i] != nil {
+			failed++
+			continue
+		}
+
+		merged.Messages = append(merged.Messages, output.Messages...)
+	}
+
+	switch {
+	case failed == calls:
+		return nil, errors.Join(errs...)
+	case failed > 0:
+		s.logger().Warn("receive calls failed, returning the messages of the others", "queue", aws.ToString(input.QueueUrl), "failed", failed, "calls", calls, "error", errors.Join(errs...))
+	}
+
+	return merged, nil
+}
@@ -0,0 +1,61 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+func TestReceiveMany(t *testing.T) {
+	fake := &fakeSQS{}
+	batch := make([]types.Message, 25)
+	for i := range batch {
+		batch[i] = testMessage(fmt.Sprintf("m%d", i), "")
+	}
+	fake.push(batch...)
+	client := newTestSQS(fake)
+	client.EnableArrakis()
+
+	messages, err := client.ReceiveMany(context.Background(), "queue", 25)
+	if err != nil {
+		t.Fatalf("ReceiveMany returned error: %v", err)
+	}
+	if len(messages) != 25 {
+		t.Errorf("Expected the merged 25 messages, got %d", len(messages))
+	}
+
+	sizes := map[int32]int{}
+	for _, input := range fake.receiveInputs {
+		sizes[input.MaxNumberOfMessages]++
+	}
+	if len(fake.receiveInputs) != 3 || sizes[10] != 2 || sizes[5] != 1 {
+		t.Errorf("Expected calls for 10, 10 and 5 messages, got %v", sizes)
+	}
+
+	state := client.state("queue")
+	if state.messageCounts != 1 || state.messageCount != 25 {
+		t.Errorf("Expected one observation of the aggregate volume, got %d observations of %d", state.messageCounts, state.messageCount)
+	}
+}
+
+func TestReceiveManyFailures(t *testing.T) {
+	fake := &fakeSQS{receiveErrs: []error{errors.New("unavailable")}}
+	fake.push(testMessage("m1", ""))
+	client := newTestSQS(fake)
+
+	messages, err := client.ReceiveMany(context.Background(), "queue", 20)
+	if err != nil || len(messages) != 1 {
+		t.Errorf("Expected the messages of the call that succeeded, got %d, %v", len(messages), err)
+	}
+
+	fake.mu.Lock()
+	fake.receiveErr = errors.New("unavailable")
+	fake.mu.Unlock()
+
+	if messages, err := client.ReceiveMany(context.Background(), "queue", 20); err == nil || messages != nil {
+		t.Errorf("Expected an error when every call failed, got %d messages, %v", len(messages), err)
+	}
+}
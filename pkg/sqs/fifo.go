@@ -0,0 +1,118 @@
+package sqs
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// FIFO queue attribute values
+const (
+	_fifoDeduplicationScopeGroup = "messageGroup"      // DeduplicationScope of high-throughput FIFO queues
+	_fifoThroughputLimitPerGroup = "perMessageGroupId" // FifoThroughputLimit of high-throughput FIFO queues
+)
+
+// ErrInvalidFIFOMessage is returned when a message doesn't match the type of its queue:
+// a FIFO message without group ID, a per-message delay on a FIFO queue, or a
+// deduplication ID on a standard queue. It is detected locally, before any request is sent.
+type ErrInvalidFIFOMessage struct {
+	// QueueURL is the destination queue.
+	QueueURL string
+	// Reason describes the rule that was broken.
+	Reason string
+}
+
+func (e *ErrInvalidFIFOMessage) Error() string {
+	return "invalid message for " + e.QueueURL + ": " + e.Reason
+}
+
+// FIFOSettings describes the deduplication and throughput configuration of a FIFO queue.
+type FIFOSettings struct {
+	// ContentBasedDeduplication is true when SQS derives deduplication IDs from the body,
+	// so sends may omit WithDeduplicationID.
+	ContentBasedDeduplication bool
+	// DeduplicationScope is "messageQueue" or "messageGroup".
+	DeduplicationScope string
+	// ThroughputLimit is "perQueue" or "perMessageGroupId".
+	ThroughputLimit string
+}
+
+// HighThroughput reports whether the queue runs in high-throughput mode, where the
+// throughput quota applies per message group instead of per queue. Throughput then scales
+// with the number of distinct group IDs, so producers should spread messages across many
+// groups and consumers should process groups in parallel. Deduplication IDs only need to
+// be unique within their group.
+func (f *FIFOSettings) HighThroughput() bool {
+	return f.DeduplicationScope == _fifoDeduplicationScopeGroup && f.ThroughputLimit == _fifoThroughputLimitPerGroup
+}
+
+// FIFOSettings reads the FIFO configuration of a queue.
+//
+// Parameters:
+//   - ctx: Context for request cancellation and timeouts
+//   - queueURL: The URL of a FIFO queue
+//
+// Returns:
+//   - *FIFOSettings: The deduplication and throughput settings of the queue
+//   - error: *ErrInvalidFIFOMessage when queueURL is not a FIFO queue, or any error that
+//     occurred during the operation
+//
+// Example:
+//
+//	settings, err := sqsClient.FIFOSettings(ctx, queueURL)
+//	if err == nil && !settings.HighThroughput() {
+//	    log.Println("queue limited to 300 messages per second per API action")
+//	}
+func (s *SQS) FIFOSettings(ctx context.Context, queueURL string) (*FIFOSettings, error) {
+	if !isFIFOQueue(queueURL) {
+		return nil, &ErrInvalidFIFOMessage{QueueURL: queueURL, Reason: "not a FIFO queue"}
+	}
+
+	output, err := s.client.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{
+		QueueUrl: aws.String(queueURL),
+		AttributeNames: []types.QueueAttributeName{
+			types.QueueAttributeNameContentBasedDeduplication,
+			types.QueueAttributeNameDeduplicationScope,
+			types.QueueAttributeNameFifoThroughputLimit,
+		},
+	}, apiOptions(ctx)...)
+	if err != nil {
+		return nil, err
+	}
+
+	attributes := output.Attributes
+
+	return &FIFOSettings{
+		ContentBasedDeduplication: attributes[string(types.QueueAttributeNameContentBasedDeduplication)] == "true",
+		DeduplicationScope:        attributes[string(types.QueueAttributeNameDeduplicationScope)],
+		ThroughputLimit:           attributes[string(types.QueueAttributeNameFifoThroughputLimit)],
+	}, nil
+}
+
+// validateFIFOInput checks that a message matches the type of its destination queue.
+// Inputs without a queue URL are not checked.
+func validateFIFOInput(input *sqs.SendMessageInput) error {
+	queueURL := aws.ToString(input.QueueUrl)
+	if queueURL == "" {
+		return nil
+	}
+
+	if !isFIFOQueue(queueURL) {
+		if aws.ToString(input.MessageDeduplicationId) != "" {
+			return &ErrInvalidFIFOMessage{QueueURL: queueURL, Reason: "deduplication IDs are only supported by FIFO queues"}
+		}
+		return nil
+	}
+
+	if aws.ToString(input.MessageGroupId) == "" {
+		return &ErrInvalidFIFOMessage{QueueURL: queueURL, Reason: "FIFO messages require a group ID (WithMessageGroupID)"}
+	}
+
+	if input.DelaySeconds != 0 {
+		return &ErrInvalidFIFOMessage{QueueURL: queueURL, Reason: "FIFO queues don't support per-message delays"}
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+)
+
+func TestValidateFIFOInput(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   *sqs.SendMessageInput
+		invalid bool
+	}{
+		{"fifo with group", &sqs.SendMessageInput{QueueUrl: aws.String("orders.fifo"), MessageGroupId: aws.String("g")}, false},
+		{"fifo without group", &sqs.SendMessageInput{QueueUrl: aws.String("orders.fifo")}, true},
+		{"fifo with delay", &sqs.SendMessageInput{QueueUrl: aws.String("orders.fifo"), MessageGroupId: aws.String("g"), DelaySeconds: 5}, true},
+		{"standard with deduplication", &sqs.SendMessageInput{QueueUrl: aws.String("orders"), MessageDeduplicationId: aws.String("d")}, true},
+		{"standard with group", &sqs.SendMessageInput{QueueUrl: aws.String("orders"), MessageGroupId: aws.String("g")}, false},
+		{"no queue", &sqs.SendMessageInput{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateFIFOInput(tt.input)
+
+			var invalid *ErrInvalidFIFOMessage
+			if tt.invalid != errors.As(err, &invalid) {
+				t.Errorf("Expected invalid=%v, got %v", tt.invalid, err)
+			}
+		})
+	}
+}
+
+func TestSendMessageRequiresGroupOnFIFO(t *testing.T) {
+	fake := &fakeSQS{}
+	client := newTestSQS(fake)
+
+	if _, err := client.SendMessage(context.Background(), "orders.fifo", "body"); err == nil {
+		t.Error("Expected an error for a FIFO message without group ID")
+	}
+
+	if len(fake.sentMessages()) != 0 {
+		t.Error("Expected no API call for an invalid message")
+	}
+}
+
+func TestFIFOSettingsHighThroughput(t *testing.T) {
+	fake := &fakeSQS{queueAttrs: map[string]string{
+		"ContentBasedDeduplication": "true",
+		"DeduplicationScope":        "messageGroup",
+		"FifoThroughputLimit":       "perMessageGroupId",
+	}}
+	client := newTestSQS(fake)
+
+	settings, err := client.FIFOSettings(context.Background(), "orders.fifo")
+	if err != nil {
+		t.Fatalf("FIFOSettings returned error: %v", err)
+	}
+
+	if !settings.HighThroughput() || !settings.ContentBasedDeduplication {
+		t.Errorf("Expected high-throughput settings, got %+v", settings)
+	}
+
+	if _, err := client.FIFOSettings(context.Background(), "orders"); err == nil {
+		t.Error("Expected an error for a standard queue")
+	}
+}
+
+func TestConsumerPartitionsFIFOQueuesByDefault(t *testing.T) {
+	client := newTestSQS(&fakeSQS{})
+	handler := HandlerFunc(func(ctx context.Context, msg Message) error { return nil })
+
+	fifo := NewConsumer(client, "orders.fifo", handler, WithWorkers(4))
+	if fifo.config.Partitions != 4 || fifo.pool != nil {
+		t.Errorf("Expected 4 group partitions on a FIFO queue, got %d", fifo.config.Partitions)
+	}
+
+	shared := NewConsumer(client, "orders.fifo", handler, WithWorkers(4), WithSharedPool())
+	if shared.config.Partitions != 0 || shared.pool == nil {
+		t.Error("Expected WithSharedPool to keep the shared pool")
+	}
+
+	standard := NewConsumer(client, "orders", handler, WithWorkers(4))
+	if standard.config.Partitions != 0 {
+		t.Errorf("Expected no partitioning on a standard queue, got %d", standard.config.Partitions)
+	}
+}
@@ -0,0 +1,72 @@
+package sqs
+
+import (
+	"context"
+	"time"
+)
+
+// FilterAction is what a consumer does with messages rejected by its receive filter.
+type FilterAction int
+
+// Actions applied to filtered messages.
+const (
+	// FilterRelease makes filtered messages visible again right away, so other consumers of
+	// a shared queue can receive them
+	FilterRelease FilterAction = iota
+	// FilterDelete removes filtered messages from the queue
+	FilterDelete
+)
+
+// WithReceiveFilter sets a predicate deciding which received messages reach the handler.
+// Messages for which filter returns false are not handled: by default they are released
+// (visibility timeout set to 0) so other consumers of a shared queue can pick them up; see
+// WithFilterAction to delete them instead. The filter runs on the polling goroutine, so it
+// should be cheap, typically checking message attributes.
+//
+// Every release counts as a receive, so on queues with a redrive policy a message nobody
+// accepts ends up in the dead-letter queue after maxReceiveCount releases.
+//
+// Parameters:
+//   - filter: Returns true for the messages this consumer handles
+//
+// Example:
+//
+//	consumer := sqs.NewConsumer(client, queueURL, handler, sqs.WithReceiveFilter(func(msg sqs.Message) bool {
+//	    return aws.ToString(msg.MessageAttributes["tenant"].StringValue) == "acme"
+//	}))
+func WithReceiveFilter(filter func(Message) bool) ConsumerOption {
+	return func(c *consumerConfig) {
+		c.ReceiveFilter = filter
+	}
+}
+
+// WithFilterAction sets what happens to messages rejected by the receive filter
+// (default: FilterRelease).
+func WithFilterAction(action FilterAction) ConsumerOption {
+	return func(c *consumerConfig) {
+		c.FilterAction = action
+	}
+}
+
+// accepts reports whether msg passes the receive filter.
+func (c *Consumer) accepts(msg Message) bool {
+	return c.config.ReceiveFilter == nil || c.config.ReceiveFilter(msg)
+}
+
+// reject applies the filter action to a message that didn't pass the receive filter.
+func (c *Consumer) reject(ctx context.Context, source queueSource, msg Message) {
+	if c.config.FilterAction == FilterDelete {
+		if _, err := source.client.DeleteMessage(ctx, source.queueURL, msg.ReceiptHandle); err == nil {
+			c.auditDelete(source, msg, DeleteFiltered, time.Time{})
+		}
+		return
+	}
+
+	c.makeVisible(ctx, source, msg)
+}
+
+// makeVisible releases a message the consumer won't handle, so it can be received again
+// right away.
+func (c *Consumer) makeVisible(ctx context.Context, source queueSource, msg Message) {
+	_, _ = source.client.ChangeMessageVisibility(ctx, source.queueURL, msg.ReceiptHandle, 0)
+}
@@ -0,0 +1,54 @@
+package sqs
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+func TestConsumerReleasesFilteredMessages(t *testing.T) {
+	fake := &fakeSQS{}
+	fake.push(testMessage("m1", ""), testMessage("m2", ""))
+	client := newTestSQS(fake)
+
+	var mu sync.Mutex
+	var handled []string
+	handler := HandlerFunc(func(ctx context.Context, msg Message) error {
+		mu.Lock()
+		handled = append(handled, msg.ID)
+		mu.Unlock()
+		return nil
+	})
+
+	consumer := NewConsumer(client, "queue", handler, WithReceiveFilter(func(msg Message) bool { return msg.ID == "m1" }))
+	runConsumer(t, consumer, func() bool { return len(fake.deletedHandles()) == 1 })
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(handled) != 1 || handled[0] != "m1" {
+		t.Errorf("Expected only m1 to be handled, got %v", handled)
+	}
+
+	if visibility, ok := fake.visibilityOf("m2"); !ok || visibility != 0 {
+		t.Errorf("Expected m2 to be released with visibility 0, got %d (set: %v)", visibility, ok)
+	}
+}
+
+func TestConsumerDeletesFilteredMessages(t *testing.T) {
+	fake := &fakeSQS{}
+	fake.push(testMessage("m1", ""))
+	client := newTestSQS(fake)
+
+	handler := HandlerFunc(func(ctx context.Context, msg Message) error {
+		t.Errorf("Expected filtered message not to be handled, got %s", msg.ID)
+		return nil
+	})
+
+	consumer := NewConsumer(client, "queue", handler,
+		WithReceiveFilter(func(msg Message) bool { return false }), WithFilterAction(FilterDelete))
+	runConsumer(t, consumer, func() bool { return len(fake.deletedHandles()) == 1 })
+
+	if _, ok := fake.visibilityOf("m1"); ok {
+		t.Error("Expected deleted message not to be released")
+	}
+}
@@ -0,0 +1,83 @@
+package sqs
+
+import (
+	"sync"
+)
+
+// WithMaxInFlightPerGroup bounds the messages of each FIFO message group the consumer
+// holds at any time. A ReceiveMessage batch may carry several messages of a group; those
+// beyond the limit are released right away (visibility timeout set to 0), together with
+// the later messages of the group in the same batch so their order is kept, and SQS
+// delivers them again once the group has room. A slow group then holds at most
+// maxPerGroup workers or queue slots, leaving the rest of the pool to unrelated groups.
+//
+// Messages without a group are not limited. Every release counts as a receive, so on
+// queues with a redrive policy keep maxReceiveCount well above the batch size.
+//
+// Parameters:
+//   - maxPerGroup: Maximum number of unacknowledged messages per group (0 disables the limit)
+//
+// Example:
+//
+//	consumer := NewConsumer(client, "https://sqs.us-east-1.amazonaws.com/123456789012/orders.fifo", handler,
+//	    WithSharedPool(), WithWorkers(16), WithMaxInFlightPerGroup(1),
+//	)
+func WithMaxInFlightPerGroup(maxPerGroup int) ConsumerOption {
+	return func(c *consumerConfig) {
+		c.MaxInFlightPerGroup = maxPerGroup
+	}
+}
+
+// groupLimiter counts the messages held per message group. A nil limiter imposes no limit.
+type groupLimiter struct {
+	max int
+
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+// newGroupLimiter creates a limiter of maxPerGroup messages per group, or returns nil when
+// the limit is disabled.
+func newGroupLimiter(maxPerGroup int) *groupLimiter {
+	if maxPerGroup <= 0 {
+		return nil
+	}
+
+	return &groupLimiter{max: maxPerGroup, counts: map[string]int{}}
+}
+
+// admit takes a slot of group for a message of the current batch. Once a group is refused,
+// overflowed remembers it so later messages of the group in the batch are refused too.
+func (l *groupLimiter) admit(group string, overflowed map[string]bool) bool {
+	if l == nil || group == "" {
+		return true
+	}
+	if overflowed[group] {
+		return false
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.counts[group] >= l.max {
+		overflowed[group] = true
+		return false
+	}
+	l.counts[group]++
+
+	return true
+}
+
+// release frees the slot of a message of group.
+func (l *groupLimiter) release(group string) {
+	if l == nil || group == "" {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.counts[group]--; l.counts[group] <= 0 {
+		delete(l.counts, group)
+	}
+}
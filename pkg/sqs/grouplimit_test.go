@@ -0,0 +1,73 @@
+package sqs
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestGroupLimiterAdmit(t *testing.T) {
+	limiter := newGroupLimiter(2)
+	overflowed := map[string]bool{}
+
+	if !limiter.admit("a", overflowed) || !limiter.admit("a", overflowed) {
+		t.Fatal("Expected two messages of the group to be admitted")
+	}
+	if limiter.admit("a", overflowed) {
+		t.Error("Expected a third message of the group to be refused")
+	}
+
+	limiter.release("a")
+	if limiter.admit("a", overflowed) {
+		t.Error("Expected the group to stay refused for the rest of the batch")
+	}
+	if !limiter.admit("a", map[string]bool{}) {
+		t.Error("Expected the released slot to be available to the next batch")
+	}
+
+	if !limiter.admit("", overflowed) || !newGroupLimiter(0).admit("a", overflowed) {
+		t.Error("Expected messages without a group and disabled limiters to be admitted")
+	}
+}
+
+func TestConsumerReleasesExcessGroupMessages(t *testing.T) {
+	fake := &fakeSQS{}
+	fake.push(testMessage("a1", "a"), testMessage("a2", "a"), testMessage("b1", "b"), testMessage("a3", "a"))
+	client := newTestSQS(fake)
+
+	var mu sync.Mutex
+	var handled []string
+	unblock := make(chan struct{})
+	handler := HandlerFunc(func(ctx context.Context, msg Message) error {
+		if msg.ID == "a1" {
+			<-unblock
+		}
+		mu.Lock()
+		handled = append(handled, msg.ID)
+		mu.Unlock()
+		return nil
+	})
+
+	var once sync.Once
+	consumer := NewConsumer(client, "queue", handler, WithWorkers(4), WithMaxInFlightPerGroup(1))
+	runConsumer(t, consumer, func() bool {
+		if _, ok := fake.visibilityOf("a3"); ok {
+			once.Do(func() { close(unblock) })
+		}
+		return len(fake.deletedHandles()) == 2
+	})
+
+	mu.Lock()
+	defer mu.Unlock()
+	slices.Sort(handled)
+	if !slices.Equal(handled, []string{"a1", "b1"}) {
+		t.Errorf("Expected one message per group to be handled, got %v", handled)
+	}
+
+	for _, id := range []string{"a2", "a3"} {
+		if visibility, ok := fake.visibilityOf(id); !ok || visibility != 0 {
+			t.Errorf("Expected %s to be released with visibility 0, got %d (set: %v)", id, visibility, ok)
+		}
+	}
+}
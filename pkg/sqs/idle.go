@@ -0,0 +1,136 @@
+package sqs
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+)
+
+// Idle backoff configuration values
+const (
+	_idleBackoffBase = time.Second // Sleep after the first empty receive, doubled after each further one
+)
+
+// WithIdleBackoff makes the consumer sleep between receives while its queue stays empty,
+// on top of the long polling wait time, to cut the cost of queues idle for hours. The sleep
+// starts at one second after an empty receive and doubles after every further one, up to
+// maxSleep; the first receive returning messages ends the backoff.
+//
+// Long sleeps delay the pickup of new messages. With a probeInterval, the consumer issues
+// a zero-wait receive every probeInterval while sleeping, which costs a single request and
+// bounds the pickup latency to probeInterval whatever the sleep. Messages found by a probe
+// are handled right away and end the backoff. Probes don't feed the adaptive polling
+// algorithm.
+//
+// Parameters:
+//   - maxSleep: Longest sleep between two receives (0 disables the backoff)
+//   - probeInterval: Interval between probes while sleeping (0 disables probes)
+//
+// Example:
+//
+//	// Sleep up to 10 minutes between polls of a nightly queue, but pick work up within 30s
+//	consumer := NewConsumer(client, queueURL, handler, WithIdleBackoff(10*time.Minute, 30*time.Second))
+func WithIdleBackoff(maxSleep, probeInterval time.Duration) ConsumerOption {
+	return func(c *consumerConfig) {
+		c.IdleBackoff = maxSleep
+		c.IdleProbeInterval = probeInterval
+	}
+}
+
+// idleBackoff spaces out the receives of a consumer whose queue is empty. A nil backoff
+// never sleeps. It is only used by the polling goroutine and needs no locking.
+type idleBackoff struct {
+	max   time.Duration
+	probe time.Duration
+	empty int           // Consecutive empty receives
+	until time.Time     // End of the current sleep, zero when not sleeping
+	woken chan struct{} // Signalled by wake to end the sleep early
+}
+
+// newIdleBackoff creates the backoff of a consumer, or nil when it is disabled.
+func newIdleBackoff(maxSleep, probeInterval time.Duration) *idleBackoff {
+	if maxSleep <= 0 {
+		return nil
+	}
+
+	return &idleBackoff{max: maxSleep, probe: max(probeInterval, 0), woken: make(chan struct{}, 1)}
+}
+
+// pause sleeps until the next receive is due. It reports whether that receive is a probe,
+// issued in the middle of a sleep.
+func (b *idleBackoff) pause(ctx context.Context) (probe bool) {
+	if b == nil || b.until.IsZero() {
+		return false
+	}
+
+	remaining := time.Until(b.until)
+	if b.probe > 0 && b.probe < remaining {
+		if b.sleep(ctx, b.probe) {
+			return true
+		}
+	} else {
+		b.sleep(ctx, remaining)
+	}
+
+	b.until = time.Time{}
+
+	return false
+}
+
+// sleep pauses for d, or until ctx is done or the backoff is woken. It reports whether
+// the full duration elapsed; a woken backoff starts over from its shortest sleep.
+func (b *idleBackoff) sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-b.woken:
+		b.empty = 0
+		return false
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// observe records the number of messages a receive returned, starting or extending the
+// backoff after empty receives and ending it once messages arrive.
+func (b *idleBackoff) observe(messages int, probe bool, now time.Time) {
+	if b == nil {
+		return
+	}
+
+	if messages > 0 {
+		b.empty = 0
+		b.until = time.Time{}
+		return
+	}
+
+	// An empty probe doesn't interrupt the sleep it was issued from
+	if probe {
+		return
+	}
+
+	b.empty++
+	b.until = now.Add(b.delay())
+}
+
+// delay returns the sleep following the current run of empty receives.
+func (b *idleBackoff) delay() time.Duration {
+	delay := _idleBackoffBase
+	for i := 1; i < b.empty && delay < b.max; i++ {
+		delay *= 2
+	}
+
+	return min(delay, b.max)
+}
+
+// probe performs a zero-wait ReceiveMessage call that bypasses the adaptive polling
+// algorithm, like TryReceive, with the attributes of a consumer receive.
+func (s *SQS) probe(ctx context.Context, input *sqs.ReceiveMessageInput) (*sqs.ReceiveMessageOutput, error) {
+	input.WaitTimeSeconds = 0
+
+	return s.receiveMessage(ctx, input)
+}
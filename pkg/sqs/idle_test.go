@@ -0,0 +1,72 @@
+package sqs
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestIdleBackoffDelay(t *testing.T) {
+	backoff := newIdleBackoff(5*time.Second, 0)
+	now := time.Now()
+
+	var delays []time.Duration
+	for range 5 {
+		backoff.observe(0, false, now)
+		delays = append(delays, backoff.delay())
+	}
+
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i := range expected {
+		if delays[i] != expected[i] {
+			t.Errorf("Expected sleep %s after %d empty receives, got %s", expected[i], i+1, delays[i])
+		}
+	}
+
+	backoff.observe(0, true, now)
+	if backoff.empty != 5 {
+		t.Errorf("Expected empty probes not to extend the backoff, got %d empty receives", backoff.empty)
+	}
+
+	backoff.observe(3, true, now)
+	if backoff.empty != 0 || !backoff.until.IsZero() {
+		t.Error("Expected messages to end the backoff")
+	}
+
+	if newIdleBackoff(0, time.Second) != nil {
+		t.Error("Expected no backoff without a maximum sleep")
+	}
+}
+
+func TestConsumerIdleProbes(t *testing.T) {
+	fake := &fakeSQS{}
+	handled := make(chan string, 1)
+	consumer := NewConsumer(newTestSQS(fake), "queue", HandlerFunc(func(ctx context.Context, msg Message) error {
+		handled <- msg.ID
+		return nil
+	}), WithIdleBackoff(time.Hour, 20*time.Millisecond))
+
+	probed := false
+	runConsumer(t, consumer, func() bool {
+		fake.mu.Lock()
+		receives := len(fake.receiveInputs)
+		fake.mu.Unlock()
+
+		// Let the consumer fall asleep and probe a few times, then send a message
+		if receives >= 4 && !probed {
+			probed = true
+			fake.push(testMessage("m1", ""))
+		}
+		return len(fake.deletedHandles()) == 1
+	})
+
+	if id := <-handled; id != "m1" {
+		t.Errorf("Expected the message found by a probe to be handled, got %s", id)
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if len(fake.receiveInputs) > 20 {
+		t.Errorf("Expected the consumer to sleep between receives, got %d receives", len(fake.receiveInputs))
+	}
+}
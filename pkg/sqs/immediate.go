@@ -0,0 +1,59 @@
+package sqs
+
+import "context"
+
+// immediateRetry makes the messages whose handler failed with a matching error visible
+// again right away, for their first deliveries.
+type immediateRetry struct {
+	// MaxRetries is the number of immediate retries before the default behavior applies.
+	MaxRetries int
+	// Match selects the errors retried immediately. Nil matches every error.
+	Match func(error) bool
+}
+
+// WithImmediateRetry retries failed messages immediately: when a handler returns an error
+// selected by match, the message's visibility timeout is set to 0 so it is delivered again
+// on the next poll, instead of after the full visibility timeout. Once a message has been
+// delivered more than maxRetries times, its failures get the default behavior again
+// (retry policies, nack backoff or the visibility timeout).
+//
+// The option can be given several times to treat error classes differently; the first
+// matching class applies. Immediate retries count as deliveries, both for RetryPolicy
+// MaxAttempts and for the redrive policy of the queue.
+//
+// Parameters:
+//   - maxRetries: Number of immediate retries per message
+//   - match: Selects the errors to retry immediately (nil retries every error)
+//
+// Example:
+//
+//	consumer := NewConsumer(client, queueURL, handler,
+//	    WithImmediateRetry(3, func(err error) bool { return errors.Is(err, ErrConflict) }),
+//	    WithImmediateRetry(1, nil),
+//	)
+func WithImmediateRetry(maxRetries int, match func(error) bool) ConsumerOption {
+	return func(c *consumerConfig) {
+		c.ImmediateRetries = append(c.ImmediateRetries, immediateRetry{MaxRetries: maxRetries, Match: match})
+	}
+}
+
+// retryImmediately makes msg visible again if its error matches an immediate retry class
+// with retries left, and reports whether it did.
+func (c *Consumer) retryImmediately(ctx context.Context, msg Message, err error) bool {
+	for _, class := range c.config.ImmediateRetries {
+		if class.Match != nil && !class.Match(err) {
+			continue
+		}
+
+		// Retrying after the nth delivery is the nth immediate retry
+		if max(msg.ReceiveCount, 1) > class.MaxRetries {
+			return false
+		}
+
+		source := c.sourceOf(msg)
+		_, _ = source.client.ChangeMessageVisibility(ctx, source.queueURL, msg.ReceiptHandle, 0)
+		return true
+	}
+
+	return false
+}
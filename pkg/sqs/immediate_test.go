@@ -0,0 +1,48 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+var errConflict = errors.New("conflict")
+
+func TestConsumerImmediateRetry(t *testing.T) {
+	fake := &fakeSQS{}
+	first := testMessage("first", "")
+	first.Attributes[_attributeApproximateReceiveCount] = "1"
+	exhausted := testMessage("exhausted", "")
+	exhausted.Attributes[_attributeApproximateReceiveCount] = "3"
+	other := testMessage("other", "")
+	other.Attributes[_attributeApproximateReceiveCount] = "3"
+	fake.push(first, exhausted, other)
+	client := newTestSQS(fake)
+
+	handled := make(chan struct{}, 3)
+	handler := HandlerFunc(func(ctx context.Context, msg Message) error {
+		defer func() { handled <- struct{}{} }()
+		if msg.ID == "other" {
+			return errors.New("boom")
+		}
+		return errConflict
+	})
+
+	consumer := NewConsumer(client, "queue", handler,
+		WithImmediateRetry(2, func(err error) bool { return errors.Is(err, errConflict) }),
+		WithImmediateRetry(5, nil),
+		WithNackStrategy(FixedNack(time.Minute)),
+	)
+	runConsumer(t, consumer, func() bool { return len(handled) == 3 })
+
+	if v, ok := fake.visibilityOf("first"); !ok || v != 0 {
+		t.Errorf("Expected an immediate retry of the first failure, got %d (set: %v)", v, ok)
+	}
+	if _, ok := fake.visibilityOf("exhausted"); ok {
+		t.Error("Expected the default behavior once out of immediate retries")
+	}
+	if v, ok := fake.visibilityOf("other"); !ok || v != 0 {
+		t.Errorf("Expected the catch-all class to retry other errors immediately, got %d (set: %v)", v, ok)
+	}
+}
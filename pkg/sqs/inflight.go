@@ -0,0 +1,53 @@
+package sqs
+
+import "sync/atomic"
+
+// SetInFlight reports how many messages of a queue are currently being processed locally
+// (received but not yet deleted) and how many can be processed concurrently. Arrakis uses
+// it as an additional signal: every full capacity of in-flight messages lowers the volume
+// class used to pick the wait time by one step, so a saturated consumer polls less eagerly
+// even when arrival volume is high.
+//
+// Consumers report their in-flight count automatically; call SetInFlight when polling
+// with ReceiveMessage and processing messages yourself.
+//
+// Parameters:
+//   - queueURL: The URL of the queue
+//   - inFlight: Number of messages being processed
+//   - capacity: Number of messages that can be processed concurrently (0 disables the signal)
+//
+// Example:
+//
+//	sqsClient.SetInFlight(queueURL, len(inProgress), workers)
+func (s *SQS) SetInFlight(queueURL string, inFlight, capacity int) {
+	state := s.state(queueURL)
+
+	atomic.StoreInt64(&state.inFlight, int64(inFlight))
+	state.setCapacity(capacity)
+}
+
+// addInFlight adjusts the number of in-flight messages of the queue.
+func (a *arrakis) addInFlight(delta int64) {
+	atomic.AddInt64(&a.inFlight, delta)
+}
+
+// setCapacity records how many messages of the queue can be processed concurrently.
+func (a *arrakis) setCapacity(capacity int) {
+	atomic.StoreInt64(&a.capacity, int64(capacity))
+}
+
+// saturation returns how many times the in-flight messages fill the processing capacity
+// (0 when the capacity is unknown or not yet reached).
+func (a *arrakis) saturation() int {
+	capacity := atomic.LoadInt64(&a.capacity)
+	if capacity <= 0 {
+		return 0
+	}
+
+	return int(atomic.LoadInt64(&a.inFlight) / capacity)
+}
+
+// saturatedClass lowers a volume class by the saturation level, never below VolumeIdle.
+func saturatedClass(class VolumeClass, saturation int) VolumeClass {
+	return max(class-VolumeClass(saturation), VolumeIdle)
+}
@@ -0,0 +1,74 @@
+package sqs
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSaturatedClass(t *testing.T) {
+	tests := []struct {
+		class      VolumeClass
+		saturation int
+		expected   VolumeClass
+	}{
+		{VolumeVeryHigh, 0, VolumeVeryHigh},
+		{VolumeVeryHigh, 1, VolumeHigh},
+		{VolumeHigh, 2, VolumeLow},
+		{VolumeLow, 5, VolumeIdle},
+	}
+
+	for _, tt := range tests {
+		if got := saturatedClass(tt.class, tt.saturation); got != tt.expected {
+			t.Errorf("saturatedClass(%s, %d): expected %s, got %s", tt.class, tt.saturation, tt.expected, got)
+		}
+	}
+}
+
+func TestSetInFlightSlowsPolling(t *testing.T) {
+	fake := &fakeSQS{}
+	client := newTestSQS(fake)
+	client.EnableArrakis()
+
+	client.SetAdaptiveState("queue", AdaptiveState{Average: 20})
+
+	_, _ = client.ReceiveMessage(context.Background(), "queue", 10, nil)
+
+	client.SetInFlight("queue", 8, 4)
+	_, _ = client.ReceiveMessage(context.Background(), "queue", 10, nil)
+
+	if wait := fake.receiveInputs[0].WaitTimeSeconds; wait != _defaultVeryHighVolumeWaitTimeSeconds {
+		t.Errorf("Expected the very high volume wait time, got %d", wait)
+	}
+
+	if wait := fake.receiveInputs[1].WaitTimeSeconds; wait != _defaultMediumVolumeWaitTimeSeconds {
+		t.Errorf("Expected a saturated consumer to poll like a medium volume queue, got %d", wait)
+	}
+
+	if inFlight := client.Stats()["queue"].InFlight; inFlight != 8 {
+		t.Errorf("Expected 8 in-flight messages in stats, got %d", inFlight)
+	}
+}
+
+func TestConsumerTracksInFlight(t *testing.T) {
+	fake := &fakeSQS{}
+	fake.push(testMessage("m1", ""), testMessage("m2", ""))
+	client := newTestSQS(fake)
+
+	release := make(chan struct{})
+	consumer := NewConsumer(client, "queue", HandlerFunc(func(ctx context.Context, msg Message) error {
+		<-release
+		return nil
+	}), WithWorkers(2))
+
+	runConsumer(t, consumer, func() bool {
+		if client.Stats()["queue"].InFlight == 2 {
+			close(release)
+			return true
+		}
+		return false
+	})
+
+	if inFlight := client.Stats()["queue"].InFlight; inFlight != 0 {
+		t.Errorf("Expected no in-flight messages after shutdown, got %d", inFlight)
+	}
+}
@@ -0,0 +1,43 @@
+package sqs
+
+import (
+	"math"
+)
+
+// WithContinuousWaitTime replaces the five volume buckets with a continuous function of
+// the EWMA average, so the wait time shrinks smoothly as the volume grows instead of
+// jumping at the class thresholds:
+//
+//	wait = min + (max - min) * exp(-average / scale)
+//
+// max is the idle wait time and min the very high volume wait time (0 with back-to-back
+// polling), so only scale needs tuning: the average at which the wait time has covered
+// about two thirds of the way from max to min. The low, medium and high volume wait times
+// are not used, except while the message age or consumer saturation adjustments move the
+// queue to another volume class, which keeps its bucket wait time.
+//
+// Parameters:
+//   - scale: Messages per poll at which the wait time is about 37% of the range above min
+//     (0 restores the buckets)
+//
+// Example:
+//
+//	// 20s when idle, 8s at 5 messages per poll, 2s at 15 and 1s from 20 on
+//	client := NewSQSWithOptions(&cfg, WithContinuousWaitTime(5))
+func WithContinuousWaitTime(scale float64) Option {
+	return func(c *config) {
+		c.AdaptivePolling.ContinuousWaitTimeScale = max(scale, 0)
+	}
+}
+
+// interpolateWaitTime computes the continuous wait time of an EWMA average.
+func interpolateWaitTime(average float64, settings adaptivePolling) int64 {
+	longest := float64(settings.IdleWaitTimeSeconds)
+	shortest := float64(settings.VeryHighVolumeWaitTimeSeconds)
+	if settings.BackToBackPolling {
+		shortest = 0
+	}
+
+	wait := shortest + (longest-shortest)*math.Exp(-average/settings.ContinuousWaitTimeScale)
+	return int64(math.Round(wait))
+}
@@ -0,0 +1,30 @@
+package sqs
+
+import (
+	"testing"
+)
+
+func TestContinuousWaitTime(t *testing.T) {
+	client := newTestSQS(&fakeSQS{}, WithContinuousWaitTime(5))
+	state := client.state("queue")
+
+	expected := map[float64]int64{0: 20, 1: 17, 5: 8, 15: 2, 50: 1}
+	for average, wait := range expected {
+		state.average = average
+		if got := state.calculateWaitTime(); got != wait {
+			t.Errorf("Expected %ds at an average of %v, got %ds", wait, average, got)
+		}
+	}
+
+	// Back-to-back polling lowers the floor to zero
+	backToBack := true
+	client.UpdateConfig(FileConfig{BackToBackPolling: &backToBack})
+	state.average = 50
+	if got := state.calculateWaitTime(); got != 0 {
+		t.Errorf("Expected back-to-back polls at very high volume, got %ds", got)
+	}
+
+	if scale := client.Config().ContinuousWaitTimeScale; scale != 5 {
+		t.Errorf("Expected the scale in the running configuration, got %v", scale)
+	}
+}
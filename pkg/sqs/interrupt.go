@@ -0,0 +1,109 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrClosed is returned by receives on a client stopped with Close.
+var ErrClosed = errors.New("arrakis: client closed")
+
+// errPollInterrupted is the cancellation cause of a long poll interrupted because adaptive
+// polling was disabled for its queue.
+var errPollInterrupted = errors.New("long poll interrupted")
+
+// Close stops the client from receiving: long polls in progress are cancelled at once
+// instead of running for their full wait time, and later receives return ErrClosed.
+// Consumers of the client stop polling and return ErrClosed from Start once their
+// in-flight messages are handled. Sends, deletes and visibility changes keep working, so
+// those messages can still be acknowledged.
+//
+// Messages SQS had already dispatched to a cancelled poll are redelivered after their
+// visibility timeout. Closing a closed client has no effect.
+//
+// Example:
+//
+//	<-shutdown
+//	sqsClient.Close() // Don't wait up to 20 seconds for the current poll
+//	wg.Wait()         // Consumers drain and return
+func (s *SQS) Close() {
+	if s.closed.Swap(true) {
+		return
+	}
+
+	s.statesMu.RLock()
+	defer s.statesMu.RUnlock()
+
+	for _, state := range s.states {
+		state.polls.interrupt(ErrClosed)
+	}
+}
+
+// interruptDisabled applies change to the adaptive polling settings and interrupts the
+// long polls of the queues it disables, so their consumers poll again at once with the
+// wait time given by the caller instead of finishing an adaptive wait.
+func (s *SQS) interruptDisabled(change func()) {
+	s.statesMu.RLock()
+	enabled := make(map[*arrakis]bool, len(s.states))
+	for _, state := range s.states {
+		enabled[state] = state.enabled()
+	}
+	s.statesMu.RUnlock()
+
+	change()
+
+	for state, was := range enabled {
+		if was && !state.enabled() {
+			state.polls.interrupt(errPollInterrupted)
+		}
+	}
+}
+
+// pollSet tracks the receives in progress on a queue so they can be cancelled. Its zero
+// value is an empty set.
+type pollSet struct {
+	mu      sync.Mutex
+	next    uint64
+	cancels map[uint64]context.CancelCauseFunc
+}
+
+// track derives the context of a receive, cancelled by interrupt. The returned function
+// must be called once the receive completes.
+func (p *pollSet) track(ctx context.Context) (context.Context, func()) {
+	ctx, cancel := context.WithCancelCause(ctx)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.cancels == nil {
+		p.cancels = map[uint64]context.CancelCauseFunc{}
+	}
+	id := p.next
+	p.next++
+	p.cancels[id] = cancel
+
+	return ctx, func() {
+		p.mu.Lock()
+		delete(p.cancels, id)
+		p.mu.Unlock()
+
+		cancel(nil)
+	}
+}
+
+// interrupt cancels every receive in progress with cause.
+func (p *pollSet) interrupt(cause error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, cancel := range p.cancels {
+		cancel(cause)
+	}
+}
+
+// interrupted reports whether a receive failed only because its poll context, and not
+// the caller's, was cancelled with cause.
+func interrupted(ctx, pollCtx context.Context, cause error) bool {
+	return ctx.Err() == nil && errors.Is(context.Cause(pollCtx), cause)
+}
@@ -0,0 +1,99 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+)
+
+// hangingSQS is a fake whose receives block until their context is cancelled, like a
+// long poll on an empty queue.
+type hangingSQS struct {
+	*fakeSQS
+	polling chan struct{}
+}
+
+func (h *hangingSQS) ReceiveMessage(ctx context.Context, params *sqs.ReceiveMessageInput, optFns ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error) {
+	h.polling <- struct{}{}
+	<-ctx.Done()
+
+	return nil, ctx.Err()
+}
+
+// pollAsync starts a receive and returns a channel delivering its error.
+func pollAsync(ctx context.Context, client *SQS, queueURL string) <-chan error {
+	result := make(chan error, 1)
+	go func() {
+		_, err := client.receive(ctx, &sqs.ReceiveMessageInput{QueueUrl: aws.String(queueURL), WaitTimeSeconds: 20})
+		result <- err
+	}()
+
+	return result
+}
+
+func TestDisableInterruptsLongPoll(t *testing.T) {
+	api := &hangingSQS{fakeSQS: &fakeSQS{}, polling: make(chan struct{}, 1)}
+	client := newTestSQS(api)
+	client.EnableArrakis()
+
+	result := pollAsync(context.Background(), client, "orders")
+	<-api.polling
+	client.DisableArrakisFor("orders")
+
+	select {
+	case err := <-result:
+		if err != nil {
+			t.Errorf("Expected an interrupted poll to return an empty result, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected disabling adaptive polling to cancel the long poll")
+	}
+
+	// Polls that weren't adaptive keep their wait time when the client-wide setting changes
+	result = pollAsync(context.Background(), client, "orders")
+	<-api.polling
+	client.DisableArrakis()
+
+	select {
+	case err := <-result:
+		t.Fatalf("Expected the non-adaptive poll to keep running, got %v", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	client.Close()
+	if err := <-result; !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed from a poll cancelled by Close, got %v", err)
+	}
+	if _, err := client.receive(context.Background(), &sqs.ReceiveMessageInput{QueueUrl: aws.String("orders")}); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed from receives after Close, got %v", err)
+	}
+}
+
+func TestConsumerStopsOnClose(t *testing.T) {
+	api := &hangingSQS{fakeSQS: &fakeSQS{}, polling: make(chan struct{}, 1)}
+	client := newTestSQS(api)
+	consumer := NewConsumer(client, "orders", HandlerFunc(func(ctx context.Context, msg Message) error {
+		return nil
+	}))
+
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- consumer.Start(context.Background())
+	}()
+
+	<-api.polling
+	client.Close()
+
+	select {
+	case err := <-stopped:
+		if !errors.Is(err, ErrClosed) {
+			t.Errorf("Expected Start to return ErrClosed, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the consumer to stop promptly once the client is closed")
+	}
+}
@@ -0,0 +1,100 @@
+package sqs
+
+import (
+	"math"
+	"slices"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// Latency tracking configuration
+const (
+	_latencySampleSize = 512 // Most recent delivery latencies kept per queue
+)
+
+// LatencyPercentiles summarizes the delivery latency of the most recent messages of a
+// queue: how long they waited between SendMessage and the ReceiveMessage that returned
+// them. It is the direct cost, in latency, of the wait times chosen by Arrakis.
+type LatencyPercentiles struct {
+	// Samples is the number of messages the percentiles are computed from.
+	Samples int
+	// P50, P90 and P99 are the median, 90th and 99th percentile latencies.
+	P50 time.Duration
+	P90 time.Duration
+	P99 time.Duration
+	// Max is the highest latency among the samples.
+	Max time.Duration
+}
+
+// DeliveryLatency returns the delivery latency percentiles of the last 512 messages
+// received from a queue. Latencies are computed from the SentTimestamp system attribute,
+// so only messages received with it are counted: the Consumer always requests it, while
+// ReceiveMessage callers must request it (or set WithMessageAgeThreshold). Queues without
+// samples report zero percentiles.
+//
+// Parameters:
+//   - queueURL: The URL of the queue
+//
+// Returns:
+//   - LatencyPercentiles: The latency percentiles of the queue
+//
+// Example:
+//
+//	latency := sqsClient.DeliveryLatency(queueURL)
+//	log.Printf("p50=%s p99=%s over %d messages", latency.P50, latency.P99, latency.Samples)
+func (s *SQS) DeliveryLatency(queueURL string) LatencyPercentiles {
+	if state, ok := s.lookupState(queueURL); ok {
+		return state.latencyPercentiles()
+	}
+
+	return LatencyPercentiles{}
+}
+
+// observeLatency records the delivery latency of every message carrying a SentTimestamp,
+// and returns the recorded latencies.
+func (a *arrakis) observeLatency(messages []types.Message, receivedAt time.Time) []time.Duration {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var latencies []time.Duration
+	for _, m := range messages {
+		if sent := parseEpochMillis(m.Attributes[_attributeSentTimestamp]); !sent.IsZero() {
+			latency := max(receivedAt.Sub(sent), 0)
+			a.latencies.Push(latency)
+			latencies = append(latencies, latency)
+		}
+	}
+
+	return latencies
+}
+
+// latencyPercentiles computes the percentiles of the recorded latencies.
+func (a *arrakis) latencyPercentiles() LatencyPercentiles {
+	a.mu.RLock()
+	samples := a.latencies.Items()
+	a.mu.RUnlock()
+
+	return percentiles(samples)
+}
+
+// percentiles computes latency percentiles with the nearest-rank method.
+func percentiles(samples []time.Duration) LatencyPercentiles {
+	if len(samples) == 0 {
+		return LatencyPercentiles{}
+	}
+
+	slices.Sort(samples)
+	rank := func(p float64) time.Duration {
+		index := int(math.Ceil(p*float64(len(samples)))) - 1
+		return samples[min(max(index, 0), len(samples)-1)]
+	}
+
+	return LatencyPercentiles{
+		Samples: len(samples),
+		P50:     rank(0.50),
+		P90:     rank(0.90),
+		P99:     rank(0.99),
+		Max:     samples[len(samples)-1],
+	}
+}
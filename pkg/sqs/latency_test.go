@@ -0,0 +1,50 @@
+package sqs
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPercentiles(t *testing.T) {
+	var samples []time.Duration
+	for i := 100; i >= 1; i-- {
+		samples = append(samples, time.Duration(i)*time.Millisecond)
+	}
+
+	got := percentiles(samples)
+	want := LatencyPercentiles{Samples: 100, P50: 50 * time.Millisecond, P90: 90 * time.Millisecond, P99: 99 * time.Millisecond, Max: 100 * time.Millisecond}
+	if got != want {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+
+	if empty := percentiles(nil); empty != (LatencyPercentiles{}) {
+		t.Errorf("Expected zero percentiles without samples, got %+v", empty)
+	}
+}
+
+func TestDeliveryLatencyFromSentTimestamp(t *testing.T) {
+	fake := &fakeSQS{}
+	fake.push(agedMessage("m1", time.Second), agedMessage("m2", 3*time.Second), testMessage("m3", ""))
+	client := newTestSQS(fake)
+
+	if _, err := client.ReceiveMessage(context.Background(), "queue", 10, nil); err != nil {
+		t.Fatalf("ReceiveMessage returned error: %v", err)
+	}
+
+	latency := client.DeliveryLatency("queue")
+	if latency.Samples != 2 {
+		t.Fatalf("Expected only messages with a SentTimestamp to be sampled, got %d", latency.Samples)
+	}
+	if latency.P50 < time.Second || latency.Max < 3*time.Second || latency.Max > 4*time.Second {
+		t.Errorf("Expected latencies of about 1s and 3s, got %+v", latency)
+	}
+
+	if stats := client.Stats()["queue"]; stats.DeliveryLatency != latency {
+		t.Errorf("Expected the latency in the queue stats, got %+v", stats.DeliveryLatency)
+	}
+
+	if unknown := client.DeliveryLatency("other"); unknown.Samples != 0 {
+		t.Errorf("Expected no samples for an unpolled queue, got %+v", unknown)
+	}
+}
@@ -0,0 +1,336 @@
+package sqs
+
+import (
+	"context"
+	"hash/fnv"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Default coordinator configuration values
+const (
+	_defaultLeaseTTL      = 30 * time.Second // Time an instance stays a member without renewing its lease
+	_defaultQueueReplicas = 1                // Instances polling each queue
+	_leaseRenewalsPerTTL  = 3                // Lease renewals per TTL, so a missed renewal doesn't expire it
+	_leaseReleaseTimeout  = 5 * time.Second  // Bound on releasing the lease when the coordinator stops
+)
+
+// LeaseStore keeps the membership leases of the instances sharing a set of queues. Leases
+// expire unless renewed, so instances that die leave the membership on their own.
+// pkg/adapters/redislease implements it on Redis.
+type LeaseStore interface {
+	// Renew creates or extends the lease of instanceID for ttl and returns the IDs of the
+	// instances holding an unexpired lease, including instanceID.
+	Renew(ctx context.Context, instanceID string, ttl time.Duration) ([]string, error)
+	// Release drops the lease of instanceID, so the other instances take over its queues
+	// without waiting for the lease to expire.
+	Release(ctx context.Context, instanceID string) error
+}
+
+// coordinatorConfig holds the configuration of a Coordinator.
+type coordinatorConfig struct {
+	// TTL is the lifetime of the lease of the instance. Leases are renewed every TTL/3.
+	TTL time.Duration
+	// Replicas is the number of instances assigned to each queue.
+	Replicas int
+	// OnChange is notified of the queues acquired and released by the instance.
+	OnChange func(acquired, released []string)
+	// OnError is notified of lease store errors.
+	OnError func(err error)
+}
+
+// CoordinatorOption is a function type for configuring a Coordinator with the functional options pattern.
+type CoordinatorOption func(*coordinatorConfig)
+
+// WithLeaseTTL sets how long an instance keeps its queues without renewing its lease. A
+// dead instance's queues are reassigned after at most this long.
+func WithLeaseTTL(ttl time.Duration) CoordinatorOption {
+	return func(c *coordinatorConfig) {
+		c.TTL = ttl
+	}
+}
+
+// WithQueueReplicas sets how many instances poll each queue (1 by default). Queues are
+// polled by every instance when there are fewer instances than replicas.
+func WithQueueReplicas(replicas int) CoordinatorOption {
+	return func(c *coordinatorConfig) {
+		c.Replicas = replicas
+	}
+}
+
+// WithAssignmentHandler registers a function notified whenever the queues assigned to the
+// instance change, typically to start consumers for the acquired queues and stop those of
+// the released ones. Calls are sequential and never run concurrently.
+func WithAssignmentHandler(onChange func(acquired, released []string)) CoordinatorOption {
+	return func(c *coordinatorConfig) {
+		c.OnChange = onChange
+	}
+}
+
+// WithCoordinatorErrorHandler registers a function notified of every lease store error.
+// The coordinator keeps its current assignment and retries on the next renewal.
+func WithCoordinatorErrorHandler(onError func(err error)) CoordinatorOption {
+	return func(c *coordinatorConfig) {
+		c.OnError = onError
+	}
+}
+
+// Coordinator assigns a set of queues to the instances of a fleet, so that each queue is
+// polled by a bounded number of replicas instead of by every instance. It is meant for
+// workers serving many queues, such as one queue per tenant.
+//
+// Every instance renews a lease in a shared LeaseStore and reads back the live members.
+// Queues are then spread over the members with rendezvous hashing: each instance computes
+// the same assignment from the same membership, without further coordination, and only
+// the queues of an instance that joins or leaves move. While membership changes propagate
+// (up to one renewal interval), a queue can briefly be polled by one replica more or less
+// than configured; consumers must tolerate this, as SQS delivery is at least once anyway.
+type Coordinator struct {
+	store      LeaseStore
+	instanceID string
+	queueURLs  []string
+	config     coordinatorConfig
+
+	mu       sync.RWMutex
+	assigned []string
+	members  []string // Live instances as of the last renewal
+}
+
+// NewCoordinator creates the coordinator of one instance.
+//
+// Parameters:
+//   - store: The lease store shared by the fleet
+//   - instanceID: Unique ID of this instance (e.g., the hostname or pod name)
+//   - queueURLs: The queues to spread over the fleet; every instance must use the same list
+//   - options: Optional settings such as WithQueueReplicas and WithAssignmentHandler
+//
+// Returns:
+//   - *Coordinator: A coordinator ready to Run
+//
+// Example:
+//
+//	consumers := map[string]context.CancelFunc{}
+//	coordinator := sqs.NewCoordinator(redislease.New(redisClient, "arrakis:tenants"), hostname, tenantQueueURLs,
+//	    sqs.WithQueueReplicas(2),
+//	    sqs.WithAssignmentHandler(func(acquired, released []string) {
+//	        for _, queueURL := range acquired {
+//	            consumerCtx, cancel := context.WithCancel(ctx)
+//	            consumers[queueURL] = cancel
+//	            go sqs.NewConsumer(sqsClient, queueURL, handler).Start(consumerCtx)
+//	        }
+//	        for _, queueURL := range released {
+//	            consumers[queueURL]()
+//	            delete(consumers, queueURL)
+//	        }
+//	    }),
+//	)
+//	go coordinator.Run(ctx)
+func NewCoordinator(store LeaseStore, instanceID string, queueURLs []string, options ...CoordinatorOption) *Coordinator {
+	config := coordinatorConfig{
+		TTL:      _defaultLeaseTTL,
+		Replicas: _defaultQueueReplicas,
+	}
+
+	for _, opt := range options {
+		opt(&config)
+	}
+
+	if config.TTL <= 0 {
+		config.TTL = _defaultLeaseTTL
+	}
+	config.Replicas = max(config.Replicas, 1)
+
+	return &Coordinator{store: store, instanceID: instanceID, queueURLs: slices.Clone(queueURLs), config: config}
+}
+
+// Run renews the lease of the instance and rebalances the queues until ctx is cancelled.
+// It then releases the lease and every assigned queue, so the other instances take them
+// over on their next renewal.
+//
+// Returns:
+//   - error: Always nil; errors are reported through WithCoordinatorErrorHandler
+func (c *Coordinator) Run(ctx context.Context) error {
+	ticker := time.NewTicker(c.config.TTL / _leaseRenewalsPerTTL)
+	defer ticker.Stop()
+
+	for {
+		if err := c.RebalanceOnce(ctx); err != nil && ctx.Err() == nil {
+			c.notify(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			c.stop(ctx)
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// RebalanceOnce renews the lease of the instance and applies the assignment computed from
+// the current members.
+//
+// Parameters:
+//   - ctx: Context for request cancellation and timeouts
+//
+// Returns:
+//   - error: Any error renewing the lease; the assignment is then left unchanged
+func (c *Coordinator) RebalanceOnce(ctx context.Context) error {
+	members, err := c.store.Renew(ctx, c.instanceID, c.config.TTL)
+	if err != nil {
+		return err
+	}
+
+	if !slices.Contains(members, c.instanceID) {
+		members = append(members, c.instanceID)
+	}
+
+	c.mu.Lock()
+	c.members = members
+	c.mu.Unlock()
+
+	c.assign(assignQueues(c.queueURLs, members, c.instanceID, c.config.Replicas))
+
+	return nil
+}
+
+// Assigned returns the queues currently assigned to the instance, sorted.
+func (c *Coordinator) Assigned() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return slices.Clone(c.assigned)
+}
+
+// Owns reports whether queueURL is currently assigned to the instance.
+func (c *Coordinator) Owns(queueURL string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	_, found := slices.BinarySearch(c.assigned, queueURL)
+	return found
+}
+
+// OwnsGroup reports whether the instance owns a FIFO message group: each group is owned by
+// exactly one live instance, chosen with rendezvous hashing like queues. Before the first
+// renewal, the instance owns every group. See WithGroupSharding.
+//
+// Parameters:
+//   - groupID: The MessageGroupId
+//
+// Returns:
+//   - bool: true if the instance should process the messages of the group
+func (c *Coordinator) OwnsGroup(groupID string) bool {
+	c.mu.RLock()
+	members := c.members
+	c.mu.RUnlock()
+
+	if len(members) == 0 {
+		return true
+	}
+
+	owner := members[0]
+	for _, member := range members[1:] {
+		if rendezvousBefore(member, owner, groupID) {
+			owner = member
+		}
+	}
+
+	return owner == c.instanceID
+}
+
+// assign replaces the assignment of the instance and reports the difference.
+func (c *Coordinator) assign(assigned []string) {
+	c.mu.Lock()
+	previous := c.assigned
+	c.assigned = assigned
+	c.mu.Unlock()
+
+	var acquired, released []string
+	for _, queueURL := range assigned {
+		if _, found := slices.BinarySearch(previous, queueURL); !found {
+			acquired = append(acquired, queueURL)
+		}
+	}
+	for _, queueURL := range previous {
+		if _, found := slices.BinarySearch(assigned, queueURL); !found {
+			released = append(released, queueURL)
+		}
+	}
+
+	if c.config.OnChange != nil && (len(acquired) > 0 || len(released) > 0) {
+		c.config.OnChange(acquired, released)
+	}
+}
+
+// stop releases every assigned queue and the lease of the instance.
+func (c *Coordinator) stop(ctx context.Context) {
+	c.assign(nil)
+
+	releaseCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), _leaseReleaseTimeout)
+	defer cancel()
+
+	if err := c.store.Release(releaseCtx, c.instanceID); err != nil {
+		c.notify(err)
+	}
+}
+
+// notify reports an error to the configured error handler.
+func (c *Coordinator) notify(err error) {
+	if c.config.OnError != nil {
+		c.config.OnError(err)
+	}
+}
+
+// assignQueues returns the sorted queues assigned to instanceID: with rendezvous hashing,
+// each queue goes to the replicas members with the highest hash of member and queue.
+func assignQueues(queueURLs, members []string, instanceID string, replicas int) []string {
+	var assigned []string
+
+	for _, queueURL := range queueURLs {
+		owners := slices.Clone(members)
+		sort.Slice(owners, func(i, j int) bool {
+			return rendezvousBefore(owners[i], owners[j], queueURL)
+		})
+
+		if slices.Contains(owners[:min(replicas, len(owners))], instanceID) {
+			assigned = append(assigned, queueURL)
+		}
+	}
+
+	slices.Sort(assigned)
+
+	return slices.Compact(assigned)
+}
+
+// rendezvousBefore reports whether member a ranks before member b for key in rendezvous
+// hashing: by decreasing weight, then by name.
+func rendezvousBefore(a, b, key string) bool {
+	wa, wb := rendezvousWeight(a, key), rendezvousWeight(b, key)
+	if wa != wb {
+		return wa > wb
+	}
+
+	return a < b
+}
+
+// rendezvousWeight is the weight of a member for a queue in rendezvous hashing.
+func rendezvousWeight(member, queueURL string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(member))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(queueURL))
+
+	// FNV barely changes the high bits for keys differing in their last bytes: finish with
+	// the MurmurHash3 finalizer so that every member has an even chance for every queue
+	x := h.Sum64()
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+
+	return x
+}
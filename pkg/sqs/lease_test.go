@@ -0,0 +1,138 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryLeaseStore is an in-memory LeaseStore whose leases never expire.
+type memoryLeaseStore struct {
+	mu       sync.Mutex
+	members  []string
+	renewErr error
+}
+
+func (s *memoryLeaseStore) Renew(ctx context.Context, instanceID string, ttl time.Duration) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.renewErr != nil {
+		return nil, s.renewErr
+	}
+	if !slices.Contains(s.members, instanceID) {
+		s.members = append(s.members, instanceID)
+	}
+
+	return slices.Clone(s.members), nil
+}
+
+func (s *memoryLeaseStore) Release(ctx context.Context, instanceID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.members = slices.DeleteFunc(s.members, func(member string) bool { return member == instanceID })
+
+	return nil
+}
+
+func TestCoordinatorAssignsQueues(t *testing.T) {
+	store := &memoryLeaseStore{}
+	queueURLs := make([]string, 30)
+	for i := range queueURLs {
+		queueURLs[i] = fmt.Sprintf("https://sqs/123/tenant-%d", i)
+	}
+
+	coordinators := make([]*Coordinator, 3)
+	for i := range coordinators {
+		coordinators[i] = NewCoordinator(store, fmt.Sprintf("instance-%d", i), queueURLs, WithQueueReplicas(2))
+		_ = coordinators[i].RebalanceOnce(context.Background())
+	}
+	for _, coordinator := range coordinators {
+		_ = coordinator.RebalanceOnce(context.Background())
+	}
+
+	pollers := map[string]int{}
+	for _, coordinator := range coordinators {
+		assigned := coordinator.Assigned()
+		if len(assigned) == len(queueURLs) {
+			t.Errorf("Expected %s not to poll every queue", coordinator.instanceID)
+		}
+		for _, queueURL := range assigned {
+			pollers[queueURL]++
+		}
+	}
+	for _, queueURL := range queueURLs {
+		if pollers[queueURL] != 2 {
+			t.Errorf("Expected 2 replicas for %s, got %d", queueURL, pollers[queueURL])
+		}
+	}
+
+	// Once an instance leaves, the others take over its queues
+	before := coordinators[0].Assigned()
+	_ = store.Release(context.Background(), "instance-2")
+	_ = coordinators[0].RebalanceOnce(context.Background())
+	if len(coordinators[0].Assigned()) != len(queueURLs) {
+		t.Errorf("Expected the 2 remaining instances to poll every queue, got %d", len(coordinators[0].Assigned()))
+	}
+	for _, queueURL := range before {
+		if !coordinators[0].Owns(queueURL) {
+			t.Errorf("Expected %s to stay assigned when another instance leaves", queueURL)
+		}
+	}
+}
+
+func TestCoordinatorRun(t *testing.T) {
+	store := &memoryLeaseStore{}
+
+	var mu sync.Mutex
+	var acquired, released []string
+	coordinator := NewCoordinator(store, "instance-0", []string{"queue-a", "queue-b"}, WithLeaseTTL(30*time.Millisecond),
+		WithAssignmentHandler(func(a, r []string) {
+			mu.Lock()
+			defer mu.Unlock()
+			acquired = append(acquired, a...)
+			released = append(released, r...)
+		}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = coordinator.Run(ctx)
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for len(coordinator.Assigned()) != 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(acquired) != 2 || len(released) != 2 {
+		t.Errorf("Expected both queues acquired then released, got %v and %v", acquired, released)
+	}
+	if len(store.members) != 0 {
+		t.Errorf("Expected the lease to be released, got %v", store.members)
+	}
+}
+
+func TestCoordinatorKeepsAssignmentOnError(t *testing.T) {
+	store := &memoryLeaseStore{}
+	coordinator := NewCoordinator(store, "instance-0", []string{"queue-a"})
+	_ = coordinator.RebalanceOnce(context.Background())
+
+	store.renewErr = errors.New("unavailable")
+	if err := coordinator.RebalanceOnce(context.Background()); err == nil {
+		t.Fatal("Expected the store error")
+	}
+	if !coordinator.Owns("queue-a") {
+		t.Error("Expected the assignment to be kept when the store fails")
+	}
+}
@@ -0,0 +1,178 @@
+package sqs
+
+import (
+	"context"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// WithMaxInFlight bounds the number of messages the consumer holds at any time: received
+// but not yet acknowledged, whether they are being handled or waiting for a worker. Once
+// the limit is reached the consumer stops polling until messages are handled, and every
+// ReceiveMessage call asks for no more messages than the limit leaves room for.
+//
+// Use it to protect downstream systems and memory when handlers are slow or the worker
+// queues are large (see WithGroupPartitioning).
+//
+// Parameters:
+//   - maxInFlight: Maximum number of unacknowledged messages (0 disables the limit)
+//
+// Example:
+//
+//	consumer := NewConsumer(client, queueURL, handler, WithWorkers(8), WithMaxInFlight(20))
+func WithMaxInFlight(maxInFlight int) ConsumerOption {
+	return func(c *consumerConfig) {
+		c.MaxInFlight = maxInFlight
+	}
+}
+
+// WithMaxInFlightBytes bounds the payload bytes the consumer holds: the bodies and
+// message attributes of the messages received but not yet acknowledged. Polling pauses
+// while the budget is used up and resumes as handled messages free it.
+//
+// The size of a message is only known once received, so the bytes held may exceed the
+// budget by at most one ReceiveMessage batch. Combine it with WithMaxInFlight for queues
+// carrying large messages (up to 256 KB each).
+//
+// Parameters:
+//   - maxBytes: Payload bytes above which polling pauses (0 disables the budget)
+//
+// Example:
+//
+//	consumer := NewConsumer(client, queueURL, handler, WithWorkers(8), WithMaxInFlightBytes(8<<20))
+func WithMaxInFlightBytes(maxBytes int64) ConsumerOption {
+	return func(c *consumerConfig) {
+		c.MaxInFlightBytes = maxBytes
+	}
+}
+
+// inFlightLimiter is a semaphore with one slot per message the consumer may hold. A nil
+// limiter imposes no limit.
+type inFlightLimiter struct {
+	slots chan struct{}
+}
+
+// newInFlightLimiter creates a limiter for maxInFlight messages, or returns nil when the
+// limit is disabled.
+func newInFlightLimiter(maxInFlight int) *inFlightLimiter {
+	if maxInFlight <= 0 {
+		return nil
+	}
+
+	return &inFlightLimiter{slots: make(chan struct{}, maxInFlight)}
+}
+
+// acquire waits for a free slot, then takes as many free slots as possible up to want.
+// It returns the number of slots taken, 0 if ctx is done first.
+func (l *inFlightLimiter) acquire(ctx context.Context, want int) int {
+	if l == nil {
+		return want
+	}
+
+	select {
+	case <-ctx.Done():
+		return 0
+	case l.slots <- struct{}{}:
+	}
+
+	taken := 1
+	for taken < want {
+		select {
+		case l.slots <- struct{}{}:
+			taken++
+		default:
+			return taken
+		}
+	}
+
+	return taken
+}
+
+// release frees n slots.
+func (l *inFlightLimiter) release(n int) {
+	if l == nil {
+		return
+	}
+
+	for range n {
+		<-l.slots
+	}
+}
+
+// byteBudget tracks the payload bytes held by the consumer. A nil budget imposes no limit.
+type byteBudget struct {
+	max int64
+
+	mu    sync.Mutex
+	used  int64
+	freed chan struct{} // Closed, then replaced, whenever bytes are released
+}
+
+// newByteBudget creates a budget of maxBytes, or returns nil when the budget is disabled.
+func newByteBudget(maxBytes int64) *byteBudget {
+	if maxBytes <= 0 {
+		return nil
+	}
+
+	return &byteBudget{max: maxBytes, freed: make(chan struct{})}
+}
+
+// wait blocks while the budget is used up. It returns false if ctx is done first.
+func (b *byteBudget) wait(ctx context.Context) bool {
+	if b == nil {
+		return true
+	}
+
+	for {
+		b.mu.Lock()
+		used, freed := b.used, b.freed
+		b.mu.Unlock()
+
+		if used < b.max {
+			return true
+		}
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-freed:
+		}
+	}
+}
+
+// add records n bytes as held.
+func (b *byteBudget) add(n int) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	b.used += int64(n)
+	b.mu.Unlock()
+}
+
+// release frees n bytes and wakes up a waiting poll.
+func (b *byteBudget) release(n int) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.used -= int64(n)
+	close(b.freed)
+	b.freed = make(chan struct{})
+}
+
+// payloadSize returns the size SQS counts for a message: its body plus the names, types
+// and values of its message attributes.
+func payloadSize(msg Message) int {
+	size := len(msg.Body)
+	for name, value := range msg.MessageAttributes {
+		size += len(name) + len(aws.ToString(value.DataType)) + len(aws.ToString(value.StringValue)) + len(value.BinaryValue)
+	}
+
+	return size
+}
@@ -0,0 +1,189 @@
+package sqs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+func TestInFlightLimiterAcquire(t *testing.T) {
+	limiter := newInFlightLimiter(5)
+	ctx := context.Background()
+
+	if got := limiter.acquire(ctx, 3); got != 3 {
+		t.Errorf("Expected 3 slots, got %d", got)
+	}
+	if got := limiter.acquire(ctx, 10); got != 2 {
+		t.Errorf("Expected the 2 remaining slots, got %d", got)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if got := limiter.acquire(cancelled, 1); got != 0 {
+		t.Errorf("Expected no slot once the context is done, got %d", got)
+	}
+
+	limiter.release(4)
+	if got := limiter.acquire(ctx, 10); got != 4 {
+		t.Errorf("Expected the 4 released slots, got %d", got)
+	}
+
+	if got := newInFlightLimiter(0).acquire(ctx, 10); got != 10 {
+		t.Errorf("Expected a disabled limiter to grant everything, got %d", got)
+	}
+}
+
+func TestConsumerMaxInFlight(t *testing.T) {
+	fake := &fakeSQS{}
+	messages := make([]types.Message, 10)
+	for i := range messages {
+		messages[i] = testMessage(fmt.Sprintf("m%d", i), "")
+	}
+	fake.push(messages...)
+	client := newTestSQS(fake)
+
+	var handling, peak atomic.Int64
+
+	handler := HandlerFunc(func(ctx context.Context, msg Message) error {
+		peak.Store(max(peak.Load(), handling.Add(1)))
+		time.Sleep(5 * time.Millisecond)
+		handling.Add(-1)
+		return nil
+	})
+
+	consumer := NewConsumer(client, "queue", handler, WithWorkers(8), WithMaxInFlight(3))
+	runConsumer(t, consumer, func() bool { return len(fake.deletedHandles()) == 10 })
+
+	if peak.Load() > 3 {
+		t.Errorf("Expected at most 3 messages in flight, got %d", peak.Load())
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	for _, input := range fake.receiveInputs {
+		if input.MaxNumberOfMessages > 3 {
+			t.Fatalf("Expected receives limited to the free slots, got a batch of %d", input.MaxNumberOfMessages)
+		}
+	}
+}
+
+func TestConsumerMaxInFlightPausesPolling(t *testing.T) {
+	fake := &fakeSQS{}
+	fake.push(testMessage("m1", ""), testMessage("m2", ""))
+	client := newTestSQS(fake)
+
+	release := make(chan struct{})
+	var started atomic.Int64
+	handler := HandlerFunc(func(ctx context.Context, msg Message) error {
+		started.Add(1)
+		<-release
+		return nil
+	})
+
+	consumer := NewConsumer(client, "queue", handler, WithWorkers(4), WithMaxInFlight(2))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		_ = consumer.Start(ctx)
+		close(done)
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for started.Load() < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	fake.mu.Lock()
+	receives := len(fake.receiveInputs)
+	fake.mu.Unlock()
+
+	time.Sleep(20 * time.Millisecond)
+
+	fake.mu.Lock()
+	if len(fake.receiveInputs) != receives {
+		t.Errorf("Expected no receive while the limit is reached, got %d more", len(fake.receiveInputs)-receives)
+	}
+	fake.mu.Unlock()
+
+	cancel()
+	close(release)
+	<-done
+}
+
+func TestByteBudgetWait(t *testing.T) {
+	budget := newByteBudget(100)
+	budget.add(150)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if budget.wait(ctx) {
+		t.Error("Expected wait to block while the budget is used up")
+	}
+
+	go budget.release(100)
+	if !budget.wait(context.Background()) {
+		t.Error("Expected wait to return once bytes are released")
+	}
+}
+
+func TestPayloadSize(t *testing.T) {
+	msg := Message{
+		Body: "hello",
+		MessageAttributes: map[string]types.MessageAttributeValue{
+			"type": {DataType: aws.String("String"), StringValue: aws.String("order")},
+		},
+	}
+
+	if got := payloadSize(msg); got != len("hello")+len("type")+len("String")+len("order") {
+		t.Errorf("Expected the body and attributes to be counted, got %d", got)
+	}
+}
+
+func TestConsumerMaxInFlightBytesPausesPolling(t *testing.T) {
+	fake := &fakeSQS{}
+	large := testMessage("large", "")
+	large.Body = aws.String(strings.Repeat("x", 1024))
+	fake.push(large)
+	fake.push(testMessage("small", ""))
+	client := newTestSQS(fake)
+
+	release := make(chan struct{})
+	var started atomic.Int64
+	handler := HandlerFunc(func(ctx context.Context, msg Message) error {
+		started.Add(1)
+		if msg.ID == "large" {
+			<-release
+		}
+		return nil
+	})
+
+	consumer := NewConsumer(client, "queue", handler, WithWorkers(4), WithMaxInFlightBytes(512))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = consumer.Start(ctx) }()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for started.Load() < 1 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	if got := started.Load(); got != 1 {
+		t.Errorf("Expected polling to pause while the large message is held, got %d handled", got)
+	}
+
+	close(release)
+	deadline = time.Now().Add(2 * time.Second)
+	for len(fake.deletedHandles()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if got := fake.deletedHandles(); len(got) != 2 {
+		t.Errorf("Expected polling to resume once the budget is freed, got %v", got)
+	}
+}
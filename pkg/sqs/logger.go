@@ -0,0 +1,43 @@
+package sqs
+
+import (
+	"log/slog"
+)
+
+// Logger receives the log records of the library: failures it recovers from (receive and
+// delete errors, failovers) at Warn level, and adaptive polling decisions at Debug level.
+// Arguments after the message are alternating keys and values, as with log/slog.
+//
+// *slog.Logger implements Logger; adapters for zap and logrus are available in
+// pkg/adapters/zaplogger and pkg/adapters/logruslogger.
+type Logger interface {
+	Debug(msg string, keysAndValues ...any)
+	Info(msg string, keysAndValues ...any)
+	Warn(msg string, keysAndValues ...any)
+	Error(msg string, keysAndValues ...any)
+}
+
+// WithLogger sets the logger of the client and of the consumers built on it
+// (default: slog.Default()).
+//
+// Parameters:
+//   - logger: The logger receiving the library log records
+//
+// Example:
+//
+//	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+//	client := sqs.NewSQSWithOptions(&cfg, sqs.WithLogger(logger))
+func WithLogger(logger Logger) Option {
+	return func(c *config) {
+		c.Logger = logger
+	}
+}
+
+// logger returns the logger of the client.
+func (s *SQS) logger() Logger {
+	if s.config.Logger == nil {
+		return slog.Default()
+	}
+
+	return s.config.Logger
+}
@@ -0,0 +1,84 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"testing"
+)
+
+// Compile-time check that *slog.Logger can be used directly
+var _ Logger = (*slog.Logger)(nil)
+
+// recordingLogger is a Logger keeping every record in memory.
+type recordingLogger struct {
+	mu      sync.Mutex
+	records []string
+}
+
+func (l *recordingLogger) log(level, msg string, keysAndValues ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.records = append(l.records, fmt.Sprintf("%s %s %v", level, msg, keysAndValues))
+}
+
+func (l *recordingLogger) Debug(msg string, keysAndValues ...any) {
+	l.log("DEBUG", msg, keysAndValues...)
+}
+func (l *recordingLogger) Info(msg string, keysAndValues ...any) {
+	l.log("INFO", msg, keysAndValues...)
+}
+func (l *recordingLogger) Warn(msg string, keysAndValues ...any) {
+	l.log("WARN", msg, keysAndValues...)
+}
+func (l *recordingLogger) Error(msg string, keysAndValues ...any) {
+	l.log("ERROR", msg, keysAndValues...)
+}
+
+func (l *recordingLogger) count() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return len(l.records)
+}
+
+func TestConsumerLogsReceiveFailures(t *testing.T) {
+	logger := &recordingLogger{}
+	fake := &fakeSQS{receiveErrs: []error{errors.New("unavailable")}}
+	client := newTestSQS(fake, WithLogger(logger))
+
+	consumer := NewConsumer(client, "queue", HandlerFunc(func(ctx context.Context, msg Message) error { return nil }))
+	runConsumer(t, consumer, func() bool { return logger.count() > 0 })
+
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	if want := "WARN receive failed [queue queue error unavailable]"; logger.records[0] != want {
+		t.Errorf("Expected %q, got %q", want, logger.records[0])
+	}
+}
+
+func TestReceiveLogsVolumeClassChanges(t *testing.T) {
+	logger := &recordingLogger{}
+	fake := &fakeSQS{}
+	fake.push(testMessage("m1", ""), testMessage("m2", ""), testMessage("m3", ""))
+	client := newTestSQS(fake, WithLogger(logger))
+	client.EnableArrakis()
+
+	if _, err := client.ReceiveMessage(context.Background(), "queue", 10, nil); err != nil {
+		t.Fatalf("ReceiveMessage returned error: %v", err)
+	}
+
+	if logger.count() != 1 {
+		t.Errorf("Expected a volume class change record, got %v", logger.records)
+	}
+}
+
+func TestDefaultLogger(t *testing.T) {
+	client := newTestSQS(&fakeSQS{})
+	if client.logger() != slog.Default() {
+		t.Error("Expected slog.Default() when no logger is configured")
+	}
+}
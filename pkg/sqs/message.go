@@ -0,0 +1,91 @@
+package sqs
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// System attribute names requested by the Consumer on every receive.
+const (
+	_attributeMessageGroupID          = "MessageGroupId"
+	_attributeApproximateReceiveCount = "ApproximateReceiveCount"
+	_attributeSentTimestamp           = "SentTimestamp"
+)
+
+// Message is a message received from an SQS queue. It carries the commonly used fields
+// of the SDK message already dereferenced, so handlers don't need to deal with pointers.
+type Message struct {
+	// ID is the SQS MessageId.
+	ID string
+	// ReceiptHandle identifies this particular receive and is required to delete the message.
+	ReceiptHandle string
+	// Body is the message payload.
+	Body string
+	// GroupID is the MessageGroupId of FIFO messages (empty for standard queues).
+	GroupID string
+	// ReceiveCount is how many times the message has been received (1 on first delivery).
+	ReceiveCount int
+	// SentTimestamp is when the message was sent to the queue (zero if not requested).
+	SentTimestamp time.Time
+	// QueueURL is the URL of the queue the message was received from.
+	QueueURL string
+	// Attributes contains the system attributes returned by SQS (e.g., SentTimestamp).
+	Attributes map[string]string
+	// MessageAttributes contains the user-defined message attributes.
+	MessageAttributes map[string]types.MessageAttributeValue
+
+	codec      Codec     // Decodes the body in Bind (nil means JSONCodec)
+	receivedAt time.Time // When the consumer received the message (zero otherwise)
+}
+
+// NewMessage converts an SDK message into a Message bound to the given queue. It is used
+// by the consumer and by adapters receiving messages through other channels (e.g., Lambda).
+//
+// Parameters:
+//   - queueURL: The URL of the queue the message was received from
+//   - m: The SDK message, with system attributes if they were requested
+//
+// Returns:
+//   - Message: The message with its fields dereferenced and its attributes parsed
+func NewMessage(queueURL string, m types.Message) Message {
+	// A missing or malformed count is reported as zero
+	receiveCount, _ := strconv.Atoi(m.Attributes[_attributeApproximateReceiveCount])
+
+	return Message{
+		ID:                aws.ToString(m.MessageId),
+		ReceiptHandle:     aws.ToString(m.ReceiptHandle),
+		Body:              aws.ToString(m.Body),
+		GroupID:           m.Attributes[_attributeMessageGroupID],
+		ReceiveCount:      receiveCount,
+		SentTimestamp:     parseEpochMillis(m.Attributes[_attributeSentTimestamp]),
+		QueueURL:          queueURL,
+		Attributes:        m.Attributes,
+		MessageAttributes: m.MessageAttributes,
+	}
+}
+
+// Attribute returns the string value of a message attribute (String and Number
+// attributes), or "" when the message doesn't have it.
+//
+// Parameters:
+//   - name: Name of the message attribute (e.g., "type")
+//
+// Returns:
+//   - string: The attribute value
+func (m Message) Attribute(name string) string {
+	return aws.ToString(m.MessageAttributes[name].StringValue)
+}
+
+// parseEpochMillis converts an SQS timestamp attribute (epoch milliseconds) into a time.
+// Missing or malformed values yield the zero time.
+func parseEpochMillis(value string) time.Time {
+	millis, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return time.UnixMilli(millis)
+}
@@ -0,0 +1,140 @@
+package sqs
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// MetricsSink receives the metrics recorded by Arrakis: the algorithm event, slow handler
+// and delivery latency metrics of WithMetricsSink, the handler metrics of
+// HandlerMetricsSink, the producer metrics of WithProducerMetricsSink, the queue depth
+// gauge of WithDepthMetricsSink and the quarantine counter of QuarantinePolicy.Metrics.
+// Implement it to plug a bespoke telemetry system in; OTelSink is the OpenTelemetry
+// implementation behind the options taking a metric.MeterProvider.
+//
+// Metric names and attributes are the ones documented by each option. Implementations
+// must be safe for concurrent use.
+type MetricsSink interface {
+	// Counter adds value to a monotonic counter.
+	Counter(ctx context.Context, name string, value int64, attributes ...attribute.KeyValue)
+	// Gauge sets the current value of a gauge.
+	Gauge(ctx context.Context, name string, value int64, attributes ...attribute.KeyValue)
+	// Histogram records a value in a distribution.
+	Histogram(ctx context.Context, name string, value float64, attributes ...attribute.KeyValue)
+}
+
+// gaugeObserver is implemented by the sinks reading gauges when they collect metrics
+// rather than being pushed their values, such as OTelSink. Series no longer observed are
+// then no longer exported, instead of repeating their last value.
+type gaugeObserver interface {
+	// observeGauge registers observe as the source of a gauge; it reports the current
+	// value of every series through record.
+	observeGauge(name string, observe func(record func(value int64, attributes ...attribute.KeyValue)))
+}
+
+// metricDescription is the unit and description of a metric, used to create its
+// OpenTelemetry instrument.
+type metricDescription struct {
+	unit        string
+	description string
+}
+
+// _metricDescriptions describes the metrics recorded by Arrakis.
+var _metricDescriptions = map[string]metricDescription{
+	_metricHandlerDuration:     {"s", "Duration of the message handlers"},
+	_metricHandlerMessages:     {"{message}", "Messages handled, by outcome"},
+	_metricProducerDuration:    {"s", "Duration of the send calls"},
+	_metricProducerBatchFill:   {"1", "Entries of the batch send calls over the batch limit"},
+	_metricProducerBytes:       {"By", "Payload bytes of the messages accepted by SQS"},
+	_metricProducerRetries:     {"{retry}", "Retries of the send calls by the AWS SDK"},
+	_metricProducerFailures:    {"{message}", "Messages not sent, by reason"},
+	_metricQueueMessages:       {"{message}", "Approximate number of messages in the queue, by state"},
+	_metricQuarantinedMessages: {"{message}", "Messages moved to the quarantine queue, by reason"},
+	_metricAlgorithmEvents:     {"{event}", "Adaptive polling algorithm events, by event"},
+	_metricSlowHandlers:        {"{message}", "Handler runs over the slow handler threshold"},
+	_metricDeliveryLatency:     {"s", "Time between the send and the receive of the messages"},
+}
+
+// OTelSink returns a MetricsSink recording OpenTelemetry metrics: counters, synchronous
+// gauges and histograms created on first use, with the units and descriptions of the
+// Arrakis metrics.
+//
+// Parameters:
+//   - provider: The meter provider (nil uses the global provider, a no-op unless one was
+//     registered with otel.SetMeterProvider)
+//
+// Returns:
+//   - MetricsSink: The sink, to share between the metric options
+//
+// Example:
+//
+//	sink := sqs.OTelSink(meterProvider)
+//	producer := sqs.NewProducer(sqsClient, queueURL, sqs.WithProducerMetricsSink(sink))
+func OTelSink(provider metric.MeterProvider) MetricsSink {
+	if provider == nil {
+		provider = otel.GetMeterProvider()
+	}
+
+	return &otelSink{meter: provider.Meter(_meterName)}
+}
+
+// otelSink records metrics through OpenTelemetry instruments, created on first use.
+type otelSink struct {
+	meter       metric.Meter
+	instruments sync.Map // Instrument by metric name
+}
+
+func (s *otelSink) Counter(ctx context.Context, name string, value int64, attributes ...attribute.KeyValue) {
+	counter := s.instrument(name, func(d metricDescription) any {
+		// Instrument creation only fails on invalid names; the no-op instrument returned
+		// alongside the error records nothing
+		counter, _ := s.meter.Int64Counter(name, metric.WithUnit(d.unit), metric.WithDescription(d.description))
+		return counter
+	}).(metric.Int64Counter)
+
+	counter.Add(ctx, value, metric.WithAttributes(attributes...))
+}
+
+func (s *otelSink) Gauge(ctx context.Context, name string, value int64, attributes ...attribute.KeyValue) {
+	gauge := s.instrument(name, func(d metricDescription) any {
+		gauge, _ := s.meter.Int64Gauge(name, metric.WithUnit(d.unit), metric.WithDescription(d.description))
+		return gauge
+	}).(metric.Int64Gauge)
+
+	gauge.Record(ctx, value, metric.WithAttributes(attributes...))
+}
+
+// observeGauge registers observe as the source of a gauge, read on every collection.
+func (s *otelSink) observeGauge(name string, observe func(record func(value int64, attributes ...attribute.KeyValue))) {
+	d := _metricDescriptions[name]
+	_, _ = s.meter.Int64ObservableGauge(name, metric.WithUnit(d.unit), metric.WithDescription(d.description), metric.WithInt64Callback(func(_ context.Context, observer metric.Int64Observer) error {
+		observe(func(value int64, attributes ...attribute.KeyValue) {
+			observer.Observe(value, metric.WithAttributes(attributes...))
+		})
+		return nil
+	}))
+}
+
+func (s *otelSink) Histogram(ctx context.Context, name string, value float64, attributes ...attribute.KeyValue) {
+	histogram := s.instrument(name, func(d metricDescription) any {
+		histogram, _ := s.meter.Float64Histogram(name, metric.WithUnit(d.unit), metric.WithDescription(d.description))
+		return histogram
+	}).(metric.Float64Histogram)
+
+	histogram.Record(ctx, value, metric.WithAttributes(attributes...))
+}
+
+// instrument returns the instrument of a metric, creating it with the unit and
+// description of the metric on first use (none for metrics Arrakis doesn't record).
+func (s *otelSink) instrument(name string, create func(metricDescription) any) any {
+	if instrument, ok := s.instruments.Load(name); ok {
+		return instrument
+	}
+
+	instrument, _ := s.instruments.LoadOrStore(name, create(_metricDescriptions[name]))
+	return instrument
+}
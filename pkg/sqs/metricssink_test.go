@@ -0,0 +1,72 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// recordingSink is a MetricsSink keeping the counters and gauges it receives, by name.
+type recordingSink struct {
+	mu         sync.Mutex
+	counters   map[string]int64
+	gauges     map[string]int64
+	histograms map[string]int
+}
+
+func newRecordingSink() *recordingSink {
+	return &recordingSink{counters: map[string]int64{}, gauges: map[string]int64{}, histograms: map[string]int{}}
+}
+
+func (s *recordingSink) Counter(ctx context.Context, name string, value int64, attributes ...attribute.KeyValue) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.counters[name] += value
+}
+
+func (s *recordingSink) Gauge(ctx context.Context, name string, value int64, attributes ...attribute.KeyValue) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, kv := range attributes {
+		if kv.Key == _attributeMessageState {
+			s.gauges[kv.Value.AsString()] = value
+		}
+	}
+}
+
+func (s *recordingSink) Histogram(ctx context.Context, name string, value float64, attributes ...attribute.KeyValue) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.histograms[name]++
+}
+
+func TestMetricsSinkReceivesAllMetrics(t *testing.T) {
+	sink := newRecordingSink()
+	ctx := context.Background()
+
+	handler := HandlerMetricsSink(sink)(HandlerFunc(func(ctx context.Context, msg Message) error {
+		return errors.New("boom")
+	}))
+	_ = handler.Handle(ctx, Message{QueueURL: "https://sqs/123/orders"})
+
+	fake := &fakeSQS{queueAttrs: map[string]string{_queueAttributeMessageCount: "7"}}
+	producer := NewProducer(newTestSQS(fake), "https://sqs/123/orders", WithProducerMetricsSink(sink))
+	if _, err := producer.Send(ctx, "event"); err != nil {
+		t.Fatalf("Send returned error: %v", err)
+	}
+
+	NewDepthWatcher(newTestSQS(fake), []string{"https://sqs/123/orders"}, WithDepthMetricsSink(sink)).SampleOnce(ctx)
+
+	if sink.counters[_metricHandlerMessages] != 1 || sink.histograms[_metricHandlerDuration] != 1 {
+		t.Errorf("Expected the handler metrics, got %v and %v", sink.counters, sink.histograms)
+	}
+	if sink.counters[_metricProducerBytes] != int64(len("event")) || sink.histograms[_metricProducerDuration] != 1 {
+		t.Errorf("Expected the producer metrics, got %v and %v", sink.counters, sink.histograms)
+	}
+	if sink.gauges[_messageStateVisible] != 7 {
+		t.Errorf("Expected the depth gauge, got %v", sink.gauges)
+	}
+}
@@ -0,0 +1,152 @@
+package sqs
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+)
+
+// Handler metrics configuration values
+const (
+	_meterName                 = "github.com/elissonalvesilva/arrakis/pkg/sqs"
+	_metricHandlerDuration     = "arrakis.handler.duration" // Histogram of handler durations, in seconds
+	_metricHandlerMessages     = "arrakis.handler.messages" // Counter of handled messages by outcome
+	_attributeHandler          = attribute.Key("arrakis.handler")
+	_attributeMessageType      = attribute.Key("arrakis.message_type")
+	_attributeOutcome          = attribute.Key("arrakis.outcome")
+	_outcomeSuccess            = "success"
+	_outcomeFailure            = "failure"
+	_defaultHandlerMetricsName = "default" // Handler name when none is set
+)
+
+// Middleware wraps a Handler with additional behavior, such as instrumentation.
+type Middleware func(Handler) Handler
+
+// Chain wraps handler with middlewares. The first middleware is the outermost one, so it
+// sees every message first.
+//
+// Parameters:
+//   - handler: The handler to wrap
+//   - middlewares: The middlewares, outermost first
+//
+// Returns:
+//   - Handler: The wrapped handler
+//
+// Example:
+//
+//	handler := sqs.Chain(orders, sqs.HandlerMetrics(meterProvider, sqs.WithHandlerName("orders")))
+func Chain(handler Handler, middlewares ...Middleware) Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+
+	return handler
+}
+
+// handlerMetricsConfig holds the configuration of the HandlerMetrics middleware.
+type handlerMetricsConfig struct {
+	// Name identifies the handler in the metric attributes.
+	Name string
+	// TypeAttribute is the message attribute recorded as the message type. Empty disables it.
+	TypeAttribute string
+}
+
+// HandlerMetricsOption is a function type for configuring the HandlerMetrics middleware.
+type HandlerMetricsOption func(*handlerMetricsConfig)
+
+// WithHandlerName sets the name recorded in the arrakis.handler attribute (default:
+// "default"). Give each handler wrapped with HandlerMetrics its own name.
+func WithHandlerName(name string) HandlerMetricsOption {
+	return func(c *handlerMetricsConfig) {
+		c.Name = name
+	}
+}
+
+// WithMessageTypeAttribute records the value of a message attribute (e.g., the attribute a
+// Router dispatches on) in the arrakis.message_type attribute, breaking the metrics down
+// per message type.
+func WithMessageTypeAttribute(attribute string) HandlerMetricsOption {
+	return func(c *handlerMetricsConfig) {
+		c.TypeAttribute = attribute
+	}
+}
+
+// HandlerMetrics returns a middleware recording OpenTelemetry metrics for every message
+// handled:
+//
+//   - arrakis.handler.duration: histogram of the handler durations, in seconds
+//   - arrakis.handler.messages: counter of handled messages, with an arrakis.outcome
+//     attribute of "success" or "failure"
+//
+// Both carry the queue name (messaging.destination.name), the handler name and, when
+// configured, the message type.
+//
+// Parameters:
+//   - provider: The meter provider (nil uses the global provider, a no-op unless one was
+//     registered with otel.SetMeterProvider)
+//   - options: Optional settings such as WithHandlerName and WithMessageTypeAttribute
+//
+// Returns:
+//   - Middleware: The middleware to wrap handlers with (see Chain)
+//
+// Example:
+//
+//	metrics := sqs.HandlerMetrics(meterProvider, sqs.WithHandlerName("orders"), sqs.WithMessageTypeAttribute("type"))
+//	consumer := sqs.NewConsumer(sqsClient, queueURL, metrics(router))
+func HandlerMetrics(provider metric.MeterProvider, options ...HandlerMetricsOption) Middleware {
+	return HandlerMetricsSink(OTelSink(provider), options...)
+}
+
+// HandlerMetricsSink returns a middleware recording the metrics of HandlerMetrics into
+// sink, e.g. a bespoke telemetry system implementing MetricsSink.
+//
+// Parameters:
+//   - sink: The sink receiving the metrics
+//   - options: Optional settings such as WithHandlerName and WithMessageTypeAttribute
+//
+// Returns:
+//   - Middleware: The middleware to wrap handlers with (see Chain)
+func HandlerMetricsSink(sink MetricsSink, options ...HandlerMetricsOption) Middleware {
+	config := handlerMetricsConfig{Name: _defaultHandlerMetricsName}
+	for _, opt := range options {
+		opt(&config)
+	}
+
+	return func(next Handler) Handler {
+		return HandlerFunc(func(ctx context.Context, msg Message) error {
+			start := time.Now()
+			err := next.Handle(ctx, msg)
+			elapsed := time.Since(start)
+
+			attributes := config.attributes(msg)
+			sink.Histogram(ctx, _metricHandlerDuration, elapsed.Seconds(), attributes...)
+
+			outcome := _outcomeSuccess
+			if err != nil {
+				outcome = _outcomeFailure
+			}
+			sink.Counter(ctx, _metricHandlerMessages, 1, append(attributes, _attributeOutcome.String(outcome))...)
+
+			return err
+		})
+	}
+}
+
+// attributes returns the metric attributes of a handled message.
+func (c handlerMetricsConfig) attributes(msg Message) []attribute.KeyValue {
+	attributes := []attribute.KeyValue{
+		semconv.MessagingDestinationName(queueName(msg.QueueURL)),
+		_attributeHandler.String(c.Name),
+	}
+
+	if c.TypeAttribute != "" {
+		messageType := aws.ToString(msg.MessageAttributes[c.TypeAttribute].StringValue)
+		attributes = append(attributes, _attributeMessageType.String(messageType))
+	}
+
+	return attributes
+}
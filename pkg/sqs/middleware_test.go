@@ -0,0 +1,89 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestChainOrder(t *testing.T) {
+	var order []string
+	tag := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return HandlerFunc(func(ctx context.Context, msg Message) error {
+				order = append(order, name)
+				return next.Handle(ctx, msg)
+			})
+		}
+	}
+
+	handler := Chain(HandlerFunc(func(ctx context.Context, msg Message) error {
+		order = append(order, "handler")
+		return nil
+	}), tag("outer"), tag("inner"))
+
+	_ = handler.Handle(context.Background(), Message{})
+
+	if len(order) != 3 || order[0] != "outer" || order[1] != "inner" || order[2] != "handler" {
+		t.Errorf("Expected outer, inner, handler, got %v", order)
+	}
+}
+
+func TestHandlerMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	handler := Chain(HandlerFunc(func(ctx context.Context, msg Message) error {
+		if msg.ID == "bad" {
+			return errors.New("failed")
+		}
+		return nil
+	}), HandlerMetrics(provider, WithHandlerName("orders"), WithMessageTypeAttribute("type")))
+
+	typed := map[string]types.MessageAttributeValue{"type": {DataType: aws.String("String"), StringValue: aws.String("order.created")}}
+	ctx := context.Background()
+	_ = handler.Handle(ctx, Message{ID: "ok", QueueURL: "https://sqs/123/orders", MessageAttributes: typed})
+	_ = handler.Handle(ctx, Message{ID: "ok", QueueURL: "https://sqs/123/orders", MessageAttributes: typed})
+	_ = handler.Handle(ctx, Message{ID: "bad", QueueURL: "https://sqs/123/orders", MessageAttributes: typed})
+
+	var data metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &data); err != nil {
+		t.Fatalf("Collect returned error: %v", err)
+	}
+
+	counts := map[string]int64{}
+	var histogramCount uint64
+	for _, scope := range data.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			switch d := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				for _, point := range d.DataPoints {
+					outcome, _ := point.Attributes.Value(_attributeOutcome)
+					if name, _ := point.Attributes.Value(_attributeHandler); name.AsString() != "orders" {
+						t.Errorf("Expected the handler name attribute, got %v", point.Attributes)
+					}
+					counts[outcome.AsString()] += point.Value
+				}
+			case metricdata.Histogram[float64]:
+				for _, point := range d.DataPoints {
+					if value, _ := point.Attributes.Value(_attributeMessageType); value.AsString() != "order.created" {
+						t.Errorf("Expected the message type attribute, got %v", point.Attributes)
+					}
+					histogramCount += point.Count
+				}
+			}
+		}
+	}
+
+	if counts[_outcomeSuccess] != 2 || counts[_outcomeFailure] != 1 {
+		t.Errorf("Expected 2 successes and 1 failure, got %v", counts)
+	}
+	if histogramCount != 3 {
+		t.Errorf("Expected 3 recorded durations, got %d", histogramCount)
+	}
+}
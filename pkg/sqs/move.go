@@ -0,0 +1,173 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// MoveOptions controls how MoveMessages transfers messages between queues.
+type MoveOptions struct {
+	// MaxMessages stops the move after this many messages. Zero moves until the source is empty.
+	MaxMessages int
+	// MessagesPerSecond limits how fast messages are re-sent. Zero means unlimited.
+	MessagesPerSecond float64
+	// GroupID is the message group of the messages moved from a standard queue to a FIFO
+	// queue, which have none. It is required for such moves and ignored otherwise.
+	GroupID string
+}
+
+// ErrSentNotDeleted is returned when a message was sent to its destination but couldn't
+// be deleted from its source afterwards: the message is now in both queues, and retrying
+// the send would add a third copy.
+type ErrSentNotDeleted struct {
+	// QueueURL is the source queue still holding the message.
+	QueueURL string
+	// MessageID is the SQS MessageId of the message in the source queue.
+	MessageID string
+	// Err is the error of the delete.
+	Err error
+}
+
+func (e *ErrSentNotDeleted) Error() string {
+	return fmt.Sprintf("message %s was sent, but deleting it from %s failed: %v", e.MessageID, e.QueueURL, e.Err)
+}
+
+func (e *ErrSentNotDeleted) Unwrap() error {
+	return e.Err
+}
+
+// MoveMessages transfers messages from one queue to another: each message is received from
+// fromURL, re-sent to toURL with its body, message attributes and FIFO group preserved,
+// and only then deleted from the source. This covers operational reshuffling such as
+// redriving a DLQ or draining a queue into a new one.
+//
+// Messages sent to a FIFO destination use the original MessageId as deduplication ID, so
+// re-running an interrupted move doesn't duplicate messages within the deduplication window.
+// Moving from a standard queue to a FIFO queue requires MoveOptions.GroupID. Moving
+// bypasses the adaptive polling state of the client.
+//
+// Parameters:
+//   - ctx: Context for request cancellation and timeouts
+//   - fromURL: The URL of the queue to move messages from
+//   - toURL: The URL of the queue to move messages to
+//   - opts: Limits for the number of messages and the send rate
+//
+// Returns:
+//   - int: Number of messages moved (sent and deleted)
+//   - error: The first error that interrupted the move; messages not yet sent stay in the
+//     source. *ErrSentNotDeleted reports a message sent whose delete failed, now in both
+//     queues. *ErrInvalidFIFOMessage reports a move to a FIFO queue without a group ID.
+//
+// Example:
+//
+//	moved, err := sqsClient.MoveMessages(ctx, dlqURL, queueURL, sqs.MoveOptions{MaxMessages: 500, MessagesPerSecond: 50})
+func (s *SQS) MoveMessages(ctx context.Context, fromURL, toURL string, opts MoveOptions) (int, error) {
+	if isFIFOQueue(toURL) && !isFIFOQueue(fromURL) && opts.GroupID == "" {
+		return 0, &ErrInvalidFIFOMessage{QueueURL: toURL, Reason: "messages moved from a standard queue need MoveOptions.GroupID"}
+	}
+
+	var interval time.Duration
+	if opts.MessagesPerSecond > 0 {
+		interval = time.Duration(float64(time.Second) / opts.MessagesPerSecond)
+	}
+
+	moved := 0
+	for opts.MaxMessages == 0 || moved < opts.MaxMessages {
+		batchSize := _maxNumberOfMessages
+		if opts.MaxMessages > 0 {
+			batchSize = min(batchSize, opts.MaxMessages-moved)
+		}
+
+		output, err := s.receiveMessage(ctx, &sqs.ReceiveMessageInput{
+			QueueUrl:                    aws.String(fromURL),
+			MaxNumberOfMessages:         int32(batchSize),
+			VisibilityTimeout:           int32(s.config.visibilityTimeout()),
+			MessageAttributeNames:       []string{_allMessageAttributes},
+			MessageSystemAttributeNames: []types.MessageSystemAttributeName{types.MessageSystemAttributeNameMessageGroupId},
+		})
+		if err != nil {
+			return moved, err
+		}
+
+		if len(output.Messages) == 0 {
+			break
+		}
+
+		for i, m := range output.Messages {
+			msg := NewMessage(fromURL, m)
+			if msg.GroupID == "" {
+				msg.GroupID = opts.GroupID
+			}
+
+			if err := s.moveMessage(ctx, fromURL, toURL, msg); err != nil {
+				// A message sent but not deleted is already in the destination: releasing
+				// it would deliver it from the source too
+				var sentNotDeleted *ErrSentNotDeleted
+				if errors.As(err, &sentNotDeleted) {
+					i++
+				}
+				s.release(ctx, fromURL, output.Messages[i:])
+				return moved, err
+			}
+			moved++
+
+			if interval > 0 {
+				sleep(ctx, interval)
+			}
+		}
+	}
+
+	return moved, nil
+}
+
+// moveMessage re-sends a single message to toURL and deletes it from fromURL.
+func (s *SQS) moveMessage(ctx context.Context, fromURL, toURL string, msg Message) error {
+	return s.resend(ctx, fromURL, moveInput(toURL, msg), msg)
+}
+
+// moveInput builds the send of a message moved to toURL.
+func moveInput(toURL string, msg Message) *sqs.SendMessageInput {
+	input := &sqs.SendMessageInput{
+		QueueUrl:          aws.String(toURL),
+		MessageBody:       aws.String(msg.Body),
+		MessageAttributes: msg.MessageAttributes,
+	}
+
+	if msg.GroupID != "" {
+		input.MessageGroupId = aws.String(msg.GroupID)
+	}
+
+	if isFIFOQueue(toURL) {
+		input.MessageDeduplicationId = aws.String(msg.ID)
+	}
+
+	return input
+}
+
+// resend sends input, the copy of msg, and then deletes msg from fromURL. A failed delete
+// is reported as *ErrSentNotDeleted.
+func (s *SQS) resend(ctx context.Context, fromURL string, input *sqs.SendMessageInput, msg Message) error {
+	if _, err := s.send(ctx, input); err != nil {
+		return err
+	}
+
+	if _, err := s.DeleteMessage(ctx, fromURL, msg.ReceiptHandle); err != nil {
+		return &ErrSentNotDeleted{QueueURL: fromURL, MessageID: msg.ID, Err: err}
+	}
+
+	return nil
+}
+
+// release makes received messages visible again right away, so an interrupted operation
+// doesn't hide them for the whole visibility timeout.
+func (s *SQS) release(ctx context.Context, queueURL string, messages []types.Message) {
+	for _, m := range messages {
+		_, _ = s.ChangeMessageVisibility(context.WithoutCancel(ctx), queueURL, aws.ToString(m.ReceiptHandle), 0)
+	}
+}
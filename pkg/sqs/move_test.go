@@ -0,0 +1,135 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+func TestMoveMessagesPreservesAttributesAndDeletes(t *testing.T) {
+	fake := &fakeSQS{}
+	fifo := testMessage("m1", "group-1")
+	fifo.MessageAttributes = map[string]types.MessageAttributeValue{
+		"type": {DataType: aws.String("String"), StringValue: aws.String("order.created")},
+	}
+	fake.push(fifo, testMessage("m2", ""))
+	client := newTestSQS(fake)
+
+	moved, err := client.MoveMessages(context.Background(), "from", "to", MoveOptions{})
+	if err != nil {
+		t.Fatalf("MoveMessages returned error: %v", err)
+	}
+	if moved != 2 {
+		t.Errorf("Expected 2 moved messages, got %d", moved)
+	}
+
+	sent := fake.sentMessages()
+	if len(sent) != 2 || aws.ToString(sent[0].QueueUrl) != "to" {
+		t.Fatalf("Expected 2 messages sent to destination, got %+v", sent)
+	}
+	if aws.ToString(sent[0].MessageGroupId) != "group-1" || sent[0].MessageDeduplicationId != nil {
+		t.Error("Expected group to be preserved without a deduplication ID on a standard queue")
+	}
+	if aws.ToString(sent[0].MessageAttributes["type"].StringValue) != "order.created" {
+		t.Error("Expected message attributes to be preserved")
+	}
+	if sent[1].MessageGroupId != nil {
+		t.Error("Expected standard message to be sent without group ID")
+	}
+	if len(fake.deletedHandles()) != 2 {
+		t.Errorf("Expected 2 deleted messages, got %v", fake.deletedHandles())
+	}
+}
+
+func TestMoveMessagesHonorsMaxMessages(t *testing.T) {
+	fake := &fakeSQS{}
+	fake.push(testMessage("m1", ""), testMessage("m2", ""), testMessage("m3", ""))
+	client := newTestSQS(fake)
+
+	moved, err := client.MoveMessages(context.Background(), "from", "to", MoveOptions{MaxMessages: 2})
+	if err != nil {
+		t.Fatalf("MoveMessages returned error: %v", err)
+	}
+	if moved != 2 {
+		t.Errorf("Expected 2 moved messages, got %d", moved)
+	}
+	if fake.receiveInputs[0].MaxNumberOfMessages != 2 {
+		t.Errorf("Expected receive batch limited to 2, got %d", fake.receiveInputs[0].MaxNumberOfMessages)
+	}
+}
+
+func TestMoveMessagesReleasesOnSendFailure(t *testing.T) {
+	fake := &fakeSQS{sendErr: errors.New("access denied")}
+	fake.push(testMessage("m1", ""), testMessage("m2", ""))
+	client := newTestSQS(fake)
+
+	moved, err := client.MoveMessages(context.Background(), "from", "to", MoveOptions{})
+	if err == nil || moved != 0 {
+		t.Fatalf("Expected error and nothing moved, got %d, %v", moved, err)
+	}
+	if len(fake.deletedHandles()) != 0 {
+		t.Error("Expected no message to be deleted when sending fails")
+	}
+	if _, ok := fake.visibilityOf("m2"); !ok {
+		t.Error("Expected unsent messages to be released")
+	}
+}
+
+func TestMoveMessagesDeduplicatesOnFIFODestination(t *testing.T) {
+	fake := &fakeSQS{}
+	fake.push(testMessage("m1", "group-1"))
+	client := newTestSQS(fake)
+
+	if _, err := client.MoveMessages(context.Background(), "from.fifo", "to.fifo", MoveOptions{}); err != nil {
+		t.Fatalf("MoveMessages returned error: %v", err)
+	}
+
+	sent := fake.sentMessages()
+	if len(sent) != 1 || aws.ToString(sent[0].MessageDeduplicationId) != "m1" {
+		t.Errorf("Expected the message ID as deduplication ID, got %+v", sent)
+	}
+}
+
+func TestMoveMessagesKeepsSentMessageHiddenOnDeleteFailure(t *testing.T) {
+	fake := &fakeSQS{deleteErr: errors.New("throttled")}
+	fake.push(testMessage("m1", ""), testMessage("m2", ""))
+	client := newTestSQS(fake)
+
+	moved, err := client.MoveMessages(context.Background(), "from", "to", MoveOptions{})
+
+	var sentNotDeleted *ErrSentNotDeleted
+	if !errors.As(err, &sentNotDeleted) || sentNotDeleted.MessageID != "m1" || moved != 0 {
+		t.Fatalf("Expected m1 reported as sent but not deleted, got %d, %v", moved, err)
+	}
+	if _, ok := fake.visibilityOf("m1"); ok {
+		t.Error("Expected the sent message not to be released in the source")
+	}
+	if _, ok := fake.visibilityOf("m2"); !ok {
+		t.Error("Expected the unsent message to be released")
+	}
+}
+
+func TestMoveMessagesStandardToFIFO(t *testing.T) {
+	fake := &fakeSQS{}
+	fake.push(testMessage("m1", ""))
+	client := newTestSQS(fake)
+
+	var invalid *ErrInvalidFIFOMessage
+	if _, err := client.MoveMessages(context.Background(), "from", "to.fifo", MoveOptions{}); !errors.As(err, &invalid) {
+		t.Fatalf("Expected a missing group ID to be rejected up front, got %v", err)
+	}
+	if len(fake.receiveInputs) != 0 {
+		t.Error("Expected no message received before the validation")
+	}
+
+	moved, err := client.MoveMessages(context.Background(), "from", "to.fifo", MoveOptions{GroupID: "redrive"})
+	if err != nil || moved != 1 {
+		t.Fatalf("Expected the message moved, got %d, %v", moved, err)
+	}
+	if sent := fake.sentMessages(); aws.ToString(sent[0].MessageGroupId) != "redrive" {
+		t.Errorf("Expected the supplied group ID, got %q", aws.ToString(sent[0].MessageGroupId))
+	}
+}
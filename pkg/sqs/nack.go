@@ -0,0 +1,191 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+)
+
+// Nack backoff limits
+const (
+	_maxVisibilityTimeoutSeconds = 43200 // SQS maximum visibility timeout (12 hours)
+)
+
+// retryableError marks a handler error as transient, so the message should be retried
+// after a backoff delay instead of the full visibility timeout.
+type retryableError struct {
+	err error
+}
+
+func (e *retryableError) Error() string {
+	return e.err.Error()
+}
+
+func (e *retryableError) Unwrap() error {
+	return e.err
+}
+
+// Retryable marks err as a transient failure. When a handler returns a retryable error and
+// the consumer has nack backoff enabled (see WithNackBackoff), the message is made visible
+// again after an escalating delay rather than after the full visibility timeout.
+//
+// Parameters:
+//   - err: The underlying error (nil stays nil)
+//
+// Returns:
+//   - error: An error wrapping err that IsRetryable reports as retryable
+//
+// Example:
+//
+//	if errors.Is(err, context.DeadlineExceeded) {
+//	    return sqs.Retryable(err)
+//	}
+func Retryable(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &retryableError{err: err}
+}
+
+// IsRetryable reports whether any error in err's chain was marked with Retryable.
+func IsRetryable(err error) bool {
+	var target *retryableError
+	return errors.As(err, &target)
+}
+
+// nackBackoff computes the visibility delay for a failed delivery using exponential
+// backoff: base * 2^(receiveCount-1), capped at maxDelay and at the SQS maximum
+// visibility timeout. The first delivery (or an unknown receive count) gets base.
+//
+// Parameters:
+//   - receiveCount: ApproximateReceiveCount of the message
+//   - base: Delay applied after the first failed delivery
+//   - maxDelay: Upper bound for the delay
+//
+// Returns:
+//   - time.Duration: Delay before the next delivery, not yet rounded to whole seconds
+func nackBackoff(receiveCount int, base, maxDelay time.Duration) time.Duration {
+	delay := base
+	for attempt := 1; attempt < receiveCount && delay < maxDelay; attempt++ {
+		delay *= 2
+	}
+
+	return min(delay, maxDelay, _maxVisibilityTimeoutSeconds*time.Second)
+}
+
+// ceilSeconds rounds a positive delay up to whole seconds, the resolution of visibility
+// timeouts, so sub-second delays wait a second rather than being truncated to none.
+func ceilSeconds(delay time.Duration) time.Duration {
+	if delay <= 0 {
+		return 0
+	}
+
+	return min((delay + time.Second - 1).Truncate(time.Second), _maxVisibilityTimeoutSeconds*time.Second)
+}
+
+// NackStrategy decides how long a failed message stays invisible before it is delivered
+// again, so the requeue delay can follow the failure mode of the handler.
+type NackStrategy interface {
+	// Delay returns the visibility delay after the attempt-th failed delivery (1 for the
+	// first delivery). Delays are rounded up to whole seconds, up to 12 hours.
+	Delay(attempt int) time.Duration
+}
+
+// NackStrategyFunc adapts an ordinary function of the attempt count to NackStrategy.
+type NackStrategyFunc func(attempt int) time.Duration
+
+// Delay calls f(attempt).
+func (f NackStrategyFunc) Delay(attempt int) time.Duration {
+	return f(attempt)
+}
+
+// ImmediateNack makes failed messages visible again right away.
+func ImmediateNack() NackStrategy {
+	return FixedNack(0)
+}
+
+// FixedNack waits the same delay after every failed attempt.
+//
+// Parameters:
+//   - delay: Delay between attempts
+func FixedNack(delay time.Duration) NackStrategy {
+	return NackStrategyFunc(func(int) time.Duration {
+		return delay
+	})
+}
+
+// LinearNack waits step more after every failed attempt (step * attempt), up to maxDelay.
+//
+// Parameters:
+//   - step: Delay after the first failed attempt, added again after each further one
+//   - maxDelay: Maximum delay between attempts
+func LinearNack(step, maxDelay time.Duration) NackStrategy {
+	return NackStrategyFunc(func(attempt int) time.Duration {
+		return min(step*time.Duration(max(attempt, 1)), max(maxDelay, step))
+	})
+}
+
+// ExponentialNack doubles the delay after every failed attempt (base * 2^(attempt-1)), up
+// to maxDelay. A jitter spreads the retries of messages that failed together (e.g., during
+// a downstream outage) by removing a random part of up to jitter times the delay. Delays
+// are rounded up to whole seconds once jittered, so a positive base never yields an
+// immediate retry.
+//
+// Parameters:
+//   - base: Delay after the first failed attempt
+//   - maxDelay: Maximum delay between attempts
+//   - jitter: Randomized fraction of each delay, between 0 (none) and 1 (full jitter)
+//
+// Example:
+//
+//	consumer := NewConsumer(client, queueURL, handler, WithNackStrategy(ExponentialNack(time.Second, 5*time.Minute, 0.5)))
+func ExponentialNack(base, maxDelay time.Duration, jitter float64) NackStrategy {
+	jitter = min(max(jitter, 0), 1)
+
+	return NackStrategyFunc(func(attempt int) time.Duration {
+		delay := nackBackoff(attempt, base, max(maxDelay, base))
+		if jitter > 0 {
+			delay -= time.Duration(rand.Float64() * jitter * float64(delay))
+		}
+
+		if base > 0 {
+			delay = max(delay, time.Nanosecond)
+		}
+
+		return ceilSeconds(delay)
+	})
+}
+
+// WithNackStrategy sets how long messages whose handler returned a retryable error (see
+// Retryable) stay invisible before their next delivery. Errors that are not marked as
+// retryable keep the default behavior: the message reappears after its visibility timeout.
+//
+// Parameters:
+//   - strategy: The requeue delay strategy, such as ExponentialNack or a NackStrategyFunc
+//
+// Example:
+//
+//	consumer := NewConsumer(client, queueURL, handler, WithNackStrategy(LinearNack(5*time.Second, time.Minute)))
+func WithNackStrategy(strategy NackStrategy) ConsumerOption {
+	return func(c *consumerConfig) {
+		c.NackStrategy = strategy
+	}
+}
+
+// visibilityDelay converts a requeue delay to a visibility timeout in seconds, within the
+// bounds accepted by SQS. Delays are rounded up (see ceilSeconds), so a sub-second delay
+// of any strategy never turns into an immediate redelivery.
+func visibilityDelay(delay time.Duration) int32 {
+	return int32(ceilSeconds(delay) / time.Second)
+}
+
+// nack releases a failed message back to the queue after the delay the nack strategy
+// derives from its receive count.
+func (c *Consumer) nack(ctx context.Context, msg Message) {
+	delay := c.config.NackStrategy.Delay(max(msg.ReceiveCount, 1))
+	source := c.sourceOf(msg)
+
+	_, _ = source.client.ChangeMessageVisibility(ctx, source.queueURL, msg.ReceiptHandle, visibilityDelay(delay))
+}
@@ -0,0 +1,200 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestNackBackoff(t *testing.T) {
+	tests := []struct {
+		name         string
+		receiveCount int
+		expected     time.Duration
+	}{
+		{"unknown receive count uses base", 0, 2 * time.Second},
+		{"first delivery uses base", 1, 2 * time.Second},
+		{"second delivery doubles", 2, 4 * time.Second},
+		{"fourth delivery", 4, 16 * time.Second},
+		{"capped at max delay", 20, time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := nackBackoff(tt.receiveCount, 2*time.Second, time.Minute)
+			if got != tt.expected {
+				t.Errorf("nackBackoff(%d) = %v, expected %v", tt.receiveCount, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNackBackoffRespectsSQSMaximum(t *testing.T) {
+	if got := nackBackoff(30, time.Hour, 48*time.Hour); got != _maxVisibilityTimeoutSeconds*time.Second {
+		t.Errorf("Expected delay capped at %ds, got %v", _maxVisibilityTimeoutSeconds, got)
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	base := errors.New("timeout")
+	err := fmt.Errorf("calling partner: %w", Retryable(base))
+
+	if !IsRetryable(err) {
+		t.Error("Expected wrapped retryable error to be retryable")
+	}
+	if !errors.Is(err, base) {
+		t.Error("Expected retryable error to unwrap to the original error")
+	}
+	if IsRetryable(base) {
+		t.Error("Expected plain error not to be retryable")
+	}
+	if Retryable(nil) != nil {
+		t.Error("Expected Retryable(nil) to be nil")
+	}
+}
+
+func TestConsumerNacksRetryableErrors(t *testing.T) {
+	fake := &fakeSQS{}
+	retry := testMessage("retry", "")
+	retry.Attributes[_attributeApproximateReceiveCount] = "3"
+	fake.push(retry, testMessage("fail", ""))
+	client := newTestSQS(fake)
+
+	handler := HandlerFunc(func(ctx context.Context, msg Message) error {
+		if msg.ID == "retry" {
+			return Retryable(errors.New("try later"))
+		}
+		return errors.New("permanent")
+	})
+
+	handled := make(chan struct{}, 2)
+	wrapped := HandlerFunc(func(ctx context.Context, msg Message) error {
+		defer func() { handled <- struct{}{} }()
+		return handler(ctx, msg)
+	})
+
+	consumer := NewConsumer(client, "queue", wrapped, WithNackBackoff(time.Second, time.Minute))
+	runConsumer(t, consumer, func() bool { return len(handled) == 2 })
+
+	if v, ok := fake.visibilityOf("retry"); !ok || v != 4 {
+		t.Errorf("Expected retryable message visibility 4s, got %d (set: %v)", v, ok)
+	}
+	if _, ok := fake.visibilityOf("fail"); ok {
+		t.Error("Expected non-retryable message visibility to be left untouched")
+	}
+}
+
+func TestNackStrategies(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy NackStrategy
+		attempt  int
+		expected time.Duration
+	}{
+		{"immediate", ImmediateNack(), 3, 0},
+		{"fixed", FixedNack(10 * time.Second), 5, 10 * time.Second},
+		{"linear", LinearNack(5*time.Second, time.Minute), 3, 15 * time.Second},
+		{"linear capped", LinearNack(5*time.Second, time.Minute), 30, time.Minute},
+		{"exponential", ExponentialNack(2*time.Second, time.Minute, 0), 3, 8 * time.Second},
+		{"custom", NackStrategyFunc(func(attempt int) time.Duration { return time.Duration(attempt) * time.Hour }), 2, 2 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.strategy.Delay(tt.attempt); got != tt.expected {
+				t.Errorf("Delay(%d) = %v, expected %v", tt.attempt, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExponentialNackJitter(t *testing.T) {
+	strategy := ExponentialNack(10*time.Second, time.Minute, 0.5)
+
+	for range 100 {
+		if got := strategy.Delay(2); got < 10*time.Second || got > 20*time.Second {
+			t.Fatalf("Expected a jittered delay between 10s and 20s, got %v", got)
+		}
+	}
+}
+
+func TestExponentialNackSubSecondBase(t *testing.T) {
+	tests := []struct {
+		attempt  int
+		expected time.Duration
+	}{
+		{1, time.Second},     // 200ms
+		{3, time.Second},     // 800ms
+		{4, 2 * time.Second}, // 1.6s
+	}
+
+	strategy := ExponentialNack(200*time.Millisecond, time.Minute, 0)
+	for _, tt := range tests {
+		if got := strategy.Delay(tt.attempt); got != tt.expected {
+			t.Errorf("Delay(%d) = %v, expected %v", tt.attempt, got, tt.expected)
+		}
+	}
+
+	jittered := ExponentialNack(200*time.Millisecond, time.Minute, 1)
+	for range 100 {
+		if got := jittered.Delay(1); got != time.Second {
+			t.Fatalf("Expected jittered sub-second delays rounded up to 1s, got %v", got)
+		}
+	}
+}
+
+func TestSubSecondNackStrategies(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy NackStrategy
+		attempt  int
+		expected int32
+	}{
+		{"fixed", FixedNack(500 * time.Millisecond), 1, 1},
+		{"linear first step", LinearNack(300*time.Millisecond, time.Minute), 1, 1},
+		{"linear third step", LinearNack(300*time.Millisecond, time.Minute), 3, 1},  // 900ms
+		{"linear fourth step", LinearNack(300*time.Millisecond, time.Minute), 4, 2}, // 1.2s
+		{"immediate", ImmediateNack(), 1, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := visibilityDelay(tt.strategy.Delay(tt.attempt)); got != tt.expected {
+				t.Errorf("Expected a visibility timeout of %ds, got %d", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestVisibilityDelay(t *testing.T) {
+	if got := visibilityDelay(-time.Second); got != 0 {
+		t.Errorf("Expected negative delays to be immediate, got %d", got)
+	}
+	if got := visibilityDelay(48 * time.Hour); got != _maxVisibilityTimeoutSeconds {
+		t.Errorf("Expected delays capped at %d, got %d", _maxVisibilityTimeoutSeconds, got)
+	}
+}
+
+func TestConsumerNackStrategy(t *testing.T) {
+	fake := &fakeSQS{}
+	retry := testMessage("retry", "")
+	retry.Attributes[_attributeApproximateReceiveCount] = "2"
+	fake.push(retry)
+	client := newTestSQS(fake)
+
+	handler := HandlerFunc(func(ctx context.Context, msg Message) error {
+		return Retryable(errors.New("try later"))
+	})
+
+	consumer := NewConsumer(client, "queue", handler, WithNackStrategy(LinearNack(7*time.Second, time.Minute)))
+	runConsumer(t, consumer, func() bool {
+		_, ok := fake.visibilityOf("retry")
+		return ok
+	})
+
+	if v, _ := fake.visibilityOf("retry"); v != 14 {
+		t.Errorf("Expected the linear strategy delay of 14s, got %d", v)
+	}
+}
@@ -1,13 +1,64 @@
 package sqs
 
+import (
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"go.opentelemetry.io/otel/trace"
+)
+
 // config holds the complete configuration for the SQS client with adaptive polling capabilities.
 type config struct {
+	// mu protects VisibilityTimeout and the AdaptivePolling tuning parameters, which can
+	// be replaced at runtime by a configuration reload
+	mu sync.RWMutex
+
 	// VisibilityTimeout defines how long messages remain invisible after being received (in seconds).
 	VisibilityTimeout int
 	// AdaptivePolling contains all settings related to the Arrakis adaptive polling algorithm.
 	AdaptivePolling adaptivePolling
-
-	arrakis arrakis
+	// OnWaitTimeClamped is called when a computed wait time falls outside the SQS limits.
+	OnWaitTimeClamped func(computed, clamped int64)
+	// WaitTimeHook adjusts every computed wait time before it is clamped. Nil keeps them.
+	WaitTimeHook func(queueURL string, proposed int64) int64
+	// Clock is the time source of the adaptive polling algorithm. Nil uses time.Now.
+	Clock func() time.Time
+	// ContentDeduplication extracts the key hashed into FIFO deduplication IDs. Nil disables it.
+	ContentDeduplication DeduplicationKey
+	// MaxNumberOfMessages is the number of messages requested by receives that don't ask for one.
+	MaxNumberOfMessages int
+	// ReceiveCallLimit caps the ReceiveMessage calls of the client. Nil leaves them uncapped.
+	ReceiveCallLimit *TokenBucket
+	// VerifyChecksums checks the MD5 digests returned by SQS against the sent and received messages.
+	VerifyChecksums bool
+	// ClientOptions are applied to the underlying AWS SDK client when it is built.
+	ClientOptions []func(*sqs.Options)
+	// AssumeRole, when set, is assumed to obtain the credentials of the SQS client.
+	AssumeRole *assumeRole
+	// TracerProvider creates the spans of the client. Nil uses the global provider.
+	TracerProvider trace.TracerProvider
+	// Logger receives the library log records. Nil uses slog.Default().
+	Logger Logger
+	// Metrics records the algorithm events, slow handlers and delivery latencies of the queues. Nil disables them.
+	Metrics MetricsSink
+	// VolumeSpikeFactor is the multiple of the EWMA average a poll must exceed to count as a spike.
+	VolumeSpikeFactor float64
+	// VolumeSpikePolls is the number of consecutive spiking polls that fire OnVolumeSpike.
+	VolumeSpikePolls int
+	// OnVolumeSpike is notified of sustained volume spikes.
+	OnVolumeSpike func(VolumeSpike)
+	// AnomalyThreshold is the absolute z-score from which a poll size is anomalous.
+	AnomalyThreshold float64
+	// AnomalyWindow is the number of recent polls used by anomaly detection.
+	AnomalyWindow int
+	// OnAnomaly is notified of anomalous poll sizes.
+	OnAnomaly func(Anomaly)
+	// IdleAfter is how long the EWMA average must stay at zero before a queue is idle.
+	IdleAfter time.Duration
+	// OnActivityChange is notified when a queue becomes idle or active again.
+	OnActivityChange func(ActivityChange)
 }
 
 // adaptivePolling contains configuration parameters for the adaptive polling algorithm.
@@ -35,6 +86,60 @@ type adaptivePolling struct {
 	EwmaAlpha float64
 	// DropDetectionThreshold defines how many consecutive low-volume cycles trigger EWMA reset.
 	DropDetectionThreshold int
+	// LowVolumeMessageThreshold is the message count below which a poll counts as a low-volume cycle.
+	LowVolumeMessageThreshold int
+	// EwmaResetAverageThreshold is the EWMA average below which a reset is allowed.
+	EwmaResetAverageThreshold float64
+	// MinResetInterval is the minimum time between two EWMA resets.
+	MinResetInterval time.Duration
+	// ConsecutiveEmptyThreshold defines how many consecutive empty responses trigger EWMA decay.
+	ConsecutiveEmptyThreshold int
+	// MessageAgeThreshold is the message age above which polling speeds up (0 disables it).
+	MessageAgeThreshold time.Duration
+	// MessageAgeTarget is the message age each doubling of which beyond it shortens the wait
+	// time by one volume class (0 disables it).
+	MessageAgeTarget time.Duration
+	// BackToBackPolling polls with a wait time of 0 at very high volume.
+	BackToBackPolling bool
+	// PollPacing is the minimum interval between two back-to-back polls of a queue.
+	PollPacing time.Duration
+	// ContinuousWaitTimeScale interpolates the wait time from the EWMA average instead of
+	// using the volume buckets (0 uses the buckets).
+	ContinuousWaitTimeScale float64
+	// AdaptiveAlphaMin and AdaptiveAlphaMax bound the smoothing factor tuned from the
+	// prediction errors of the average (0 uses EwmaAlpha).
+	AdaptiveAlphaMin float64
+	AdaptiveAlphaMax float64
+}
+
+// adaptivePolling returns a consistent copy of the adaptive polling parameters.
+//
+// Thread-safe operation using mutex protection.
+func (c *config) adaptivePolling() adaptivePolling {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.AdaptivePolling
+}
+
+// setAdaptivePollingEnabled turns the adaptive polling algorithm on or off.
+//
+// Thread-safe operation using mutex protection.
+func (c *config) setAdaptivePollingEnabled(enabled bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.AdaptivePolling.EnableAdaptivePolling = enabled
+}
+
+// visibilityTimeout returns the configured visibility timeout in seconds.
+//
+// Thread-safe operation using mutex protection.
+func (c *config) visibilityTimeout() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.VisibilityTimeout
 }
 
 // Option is a function type for configuring the SQS client with the functional options pattern.
@@ -161,6 +266,151 @@ func WithDropDetectionThreshold(dropDetectionThreshold int) Option {
 	}
 }
 
+// WithLowVolumeMessageThreshold sets the message count below which a poll counts as a
+// low-volume cycle for drop detection (see WithDropDetectionThreshold).
+//
+// Parameters:
+//   - lowVolumeMessageThreshold: Number of messages per poll (default: 2)
+func WithLowVolumeMessageThreshold(lowVolumeMessageThreshold int) Option {
+	return func(c *config) {
+		c.AdaptivePolling.LowVolumeMessageThreshold = lowVolumeMessageThreshold
+	}
+}
+
+// WithEwmaResetAverageThreshold sets the EWMA average below which a volume drop may reset
+// the average. Lower values make resets rarer.
+//
+// Parameters:
+//   - ewmaResetAverageThreshold: Average in messages per poll (default: 1.0)
+func WithEwmaResetAverageThreshold(ewmaResetAverageThreshold float64) Option {
+	return func(c *config) {
+		c.AdaptivePolling.EwmaResetAverageThreshold = ewmaResetAverageThreshold
+	}
+}
+
+// WithMinResetInterval sets the minimum time between two EWMA resets. Queues receiving only
+// a few messages per hour should use a long interval so the average isn't reset between
+// every burst.
+//
+// Parameters:
+//   - minResetInterval: Minimum duration between resets (default: 1 minute)
+//
+// Example:
+//
+//	option := WithMinResetInterval(30 * time.Minute)
+func WithMinResetInterval(minResetInterval time.Duration) Option {
+	return func(c *config) {
+		c.AdaptivePolling.MinResetInterval = minResetInterval
+	}
+}
+
+// WithConsecutiveEmptyThreshold sets how many consecutive empty responses are needed
+// before the EWMA average starts decaying.
+//
+// Parameters:
+//   - consecutiveEmptyThreshold: Number of empty responses (default: 2)
+func WithConsecutiveEmptyThreshold(consecutiveEmptyThreshold int) Option {
+	return func(c *config) {
+		c.AdaptivePolling.ConsecutiveEmptyThreshold = consecutiveEmptyThreshold
+	}
+}
+
+// WithOnWaitTimeClamped registers a hook called whenever a computed wait time falls
+// outside the SQS long polling range (0-20 seconds) and is clamped. Arrakis always clamps;
+// the hook lets applications log or alert on the misconfiguration.
+//
+// Parameters:
+//   - hook: Function receiving the computed and the clamped wait time, in seconds
+//
+// Example:
+//
+//	option := WithOnWaitTimeClamped(func(computed, clamped int64) {
+//	    log.Printf("wait time %ds out of SQS range, using %ds", computed, clamped)
+//	})
+func WithOnWaitTimeClamped(hook func(computed, clamped int64)) Option {
+	return func(c *config) {
+		c.OnWaitTimeClamped = hook
+	}
+}
+
+// WithWaitTimeHook registers a function adjusting every wait time computed by the adaptive
+// polling algorithm, for policies the volume classes can't express, such as capping the
+// wait time during business hours, without replacing the algorithm. The result is then
+// clamped to the SQS long polling range as usual (see WithOnWaitTimeClamped).
+//
+// The hook runs before every adaptive ReceiveMessage call and must be fast and safe for
+// concurrent use. Simulate calls it with an empty queue URL.
+//
+// Parameters:
+//   - hook: Function receiving the queue URL and the computed wait time, in seconds, and
+//     returning the wait time to use
+//
+// Example:
+//
+//	option := WithWaitTimeHook(func(queueURL string, proposed int64) int64 {
+//	    if hour := time.Now().Hour(); hour >= 9 && hour < 18 {
+//	        return min(proposed, 5)
+//	    }
+//	    return proposed
+//	})
+func WithWaitTimeHook(hook func(queueURL string, proposed int64) int64) Option {
+	return func(c *config) {
+		c.WaitTimeHook = hook
+	}
+}
+
+// WithClock replaces the time source of the adaptive polling algorithm, which drives EWMA
+// decay, drop detection and the recorded decisions. It lets tests exercise time-dependent
+// behavior on a fake clock (see arrakistest.DecisionRecorder) instead of sleeping.
+//
+// Parameters:
+//   - clock: Function returning the current time
+//
+// Example:
+//
+//	now := time.Now()
+//	client := NewSQSWithOptions(&cfg, WithClock(func() time.Time { return now }))
+func WithClock(clock func() time.Time) Option {
+	return func(c *config) {
+		c.Clock = clock
+	}
+}
+
+// WithSQSClientOptions passes options to the underlying AWS SDK SQS client when it is built
+// by NewSQSWithOptions, for customizations such as a custom retryer, HTTP client or API
+// middleware. Options are applied in order, after the ones derived from the aws.Config.
+//
+// Parameters:
+//   - options: AWS SDK client options
+//
+// Example:
+//
+//	option := WithSQSClientOptions(func(o *sqs.Options) {
+//	    o.Retryer = retry.AddWithMaxAttempts(retry.NewStandard(), 5)
+//	    o.HTTPClient = &http.Client{Timeout: 30 * time.Second}
+//	})
+func WithSQSClientOptions(options ...func(*sqs.Options)) Option {
+	return func(c *config) {
+		c.ClientOptions = append(c.ClientOptions, options...)
+	}
+}
+
+// WithEndpoint points the client at a custom SQS endpoint, such as LocalStack or a VPC
+// interface endpoint, by setting the BaseEndpoint of the underlying SDK client. It replaces
+// the deprecated endpoint resolver setup.
+//
+// Parameters:
+//   - endpointURL: The endpoint URL (e.g., "http://localhost:4566")
+//
+// Example:
+//
+//	sqsClient := NewSQSWithOptions(&cfg, WithEndpoint("http://localhost:4566"))
+func WithEndpoint(endpointURL string) Option {
+	return WithSQSClientOptions(func(o *sqs.Options) {
+		o.BaseEndpoint = aws.String(endpointURL)
+	})
+}
+
 // setDefaults initializes the configuration with sensible default values.
 // This function ensures that all adaptive polling parameters have valid values
 // even if they weren't explicitly configured by the user.
@@ -172,6 +422,14 @@ func WithDropDetectionThreshold(dropDetectionThreshold int) Option {
 // - EWMA alpha: 0.3 (balanced responsiveness and stability)
 // - Drop detection: 10 cycles (reasonable adaptation to volume changes)
 func setDefaults(c *config) {
+	if c.VolumeSpikePolls == 0 {
+		c.VolumeSpikePolls = _defaultVolumeSpikePolls
+	}
+
+	if c.AnomalyWindow == 0 {
+		c.AnomalyWindow = _defaultAnomalyWindow
+	}
+
 	// Set main VisibilityTimeout if not already set
 	if c.VisibilityTimeout == 0 {
 		c.VisibilityTimeout = _defaultVisibilityTimeout
@@ -208,4 +466,20 @@ func setDefaults(c *config) {
 	if c.AdaptivePolling.DropDetectionThreshold == 0 {
 		c.AdaptivePolling.DropDetectionThreshold = _defaultDropDetectionThreshold
 	}
+
+	if c.AdaptivePolling.LowVolumeMessageThreshold == 0 {
+		c.AdaptivePolling.LowVolumeMessageThreshold = _defaultLowVolumeMessageThreshold
+	}
+
+	if c.AdaptivePolling.EwmaResetAverageThreshold == 0 {
+		c.AdaptivePolling.EwmaResetAverageThreshold = _defaultEwmaResetAverageThreshold
+	}
+
+	if c.AdaptivePolling.MinResetInterval == 0 {
+		c.AdaptivePolling.MinResetInterval = _defaultMinResetInterval
+	}
+
+	if c.AdaptivePolling.ConsecutiveEmptyThreshold == 0 {
+		c.AdaptivePolling.ConsecutiveEmptyThreshold = _defaultConsecutiveEmptyThreshold
+	}
 }
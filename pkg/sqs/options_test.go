@@ -0,0 +1,47 @@
+package sqs
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+)
+
+func TestWithSQSClientOptions(t *testing.T) {
+	calls := 0
+	client := NewSQSWithOptions(&aws.Config{}, WithSQSClientOptions(func(o *sqs.Options) {
+		calls++
+		o.AppID = "orders-service"
+	}))
+
+	if calls != 1 {
+		t.Errorf("Expected the client option to be applied once, got %d", calls)
+	}
+
+	if appID := client.client.(*sqs.Client).Options().AppID; appID != "orders-service" {
+		t.Errorf("Expected AppID to be set by the client option, got %q", appID)
+	}
+}
+
+func TestWithEndpoint(t *testing.T) {
+	client := NewSQSWithOptions(&aws.Config{}, WithEndpoint("http://localhost:4566"))
+
+	if endpoint := aws.ToString(client.client.(*sqs.Client).Options().BaseEndpoint); endpoint != "http://localhost:4566" {
+		t.Errorf("Expected BaseEndpoint to be set, got %q", endpoint)
+	}
+}
+
+func TestWithAssumeRole(t *testing.T) {
+	client := NewSQSWithOptions(&aws.Config{Region: "us-east-1"}, WithAssumeRole("arn:aws:iam::123456789012:role/reader", "external"))
+
+	credentials := client.client.(*sqs.Client).Options().Credentials
+	cache, ok := credentials.(*aws.CredentialsCache)
+	if !ok {
+		t.Fatalf("Expected cached assume role credentials, got %T", credentials)
+	}
+
+	if !cache.IsCredentialsProvider(&stscreds.AssumeRoleProvider{}) {
+		t.Error("Expected the credentials to come from an assume role provider")
+	}
+}
@@ -0,0 +1,232 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// Default outbox relay configuration values
+const (
+	_defaultOutboxBatchSize = 100             // Records read from the store per relay cycle
+	_defaultOutboxInterval  = 1 * time.Second // Pause between cycles once the outbox is empty
+)
+
+// OutboxRecord is a message written to the outbox, usually in the same database
+// transaction as the business change it announces.
+type OutboxRecord struct {
+	// ID uniquely identifies the record in the store.
+	ID string
+	// Body is the message payload.
+	Body string
+	// GroupID is the MessageGroupId for FIFO queues (empty for standard queues).
+	GroupID string
+	// DeduplicationID is the FIFO deduplication ID. When empty on a FIFO record, the
+	// record ID is used, so a record published twice is deduplicated by SQS.
+	DeduplicationID string
+	// Attributes are sent as String message attributes.
+	Attributes map[string]string
+}
+
+// OutboxStore gives the relay access to the outbox storage. SQLOutboxStore implements it
+// for database/sql; other stores only need these two operations.
+type OutboxStore interface {
+	// Pending returns up to limit records that haven't been published yet, oldest first.
+	Pending(ctx context.Context, limit int) ([]OutboxRecord, error)
+	// MarkSent flags records as published so Pending doesn't return them again.
+	MarkSent(ctx context.Context, ids []string) error
+}
+
+// outboxConfig holds the configuration of an OutboxRelay.
+type outboxConfig struct {
+	// BatchSize is the number of records read from the store per cycle.
+	BatchSize int
+	// Interval is the pause between cycles once the outbox is empty.
+	Interval time.Duration
+	// OnError is notified of store and send errors.
+	OnError func(err error)
+}
+
+// OutboxOption is a function type for configuring an OutboxRelay with the functional options pattern.
+type OutboxOption func(*outboxConfig)
+
+// WithOutboxBatchSize sets how many records the relay reads from the store per cycle.
+func WithOutboxBatchSize(batchSize int) OutboxOption {
+	return func(c *outboxConfig) {
+		c.BatchSize = batchSize
+	}
+}
+
+// WithOutboxInterval sets how long the relay waits before checking an empty outbox again.
+func WithOutboxInterval(interval time.Duration) OutboxOption {
+	return func(c *outboxConfig) {
+		c.Interval = interval
+	}
+}
+
+// WithOutboxErrorHandler registers a function notified of every error met by the relay,
+// including records SQS rejected. The relay keeps running after errors.
+func WithOutboxErrorHandler(onError func(err error)) OutboxOption {
+	return func(c *outboxConfig) {
+		c.OnError = onError
+	}
+}
+
+// OutboxRelay publishes the records of a transactional outbox to a queue. It reads pending
+// records from an OutboxStore, sends them through a Producer in batches, and marks as sent
+// only the records SQS accepted; the others stay pending and are retried on the next cycle.
+//
+// Delivery is at least once: a record sent but not marked (e.g., the process died in
+// between) is published again. On FIFO queues the record ID is used as deduplication ID,
+// so such duplicates are dropped by SQS within its 5 minute deduplication window.
+//
+// With a producer spooling failed sends (see WithSpool), spooled records are marked as
+// sent too: the spool delivers them, and publishing them again would duplicate them. The
+// records then rely on the spool, so use SpoolPolicy.Dir for them to survive a restart.
+type OutboxRelay struct {
+	producer *Producer
+	store    OutboxStore
+	config   outboxConfig
+}
+
+// NewOutboxRelay creates a relay publishing the records of store through producer.
+//
+// Parameters:
+//   - producer: The producer of the destination queue
+//   - store: The outbox storage
+//   - options: Optional settings such as the batch size or the polling interval
+//
+// Returns:
+//   - *OutboxRelay: A relay ready to Run
+//
+// Example:
+//
+//	store := sqs.NewSQLOutboxStore(db, "outbox", sqs.WithDollarPlaceholders())
+//	relay := sqs.NewOutboxRelay(sqs.NewProducer(sqsClient, queueURL), store)
+//	go relay.Run(ctx)
+func NewOutboxRelay(producer *Producer, store OutboxStore, options ...OutboxOption) *OutboxRelay {
+	config := outboxConfig{
+		BatchSize: _defaultOutboxBatchSize,
+		Interval:  _defaultOutboxInterval,
+	}
+
+	for _, opt := range options {
+		opt(&config)
+	}
+
+	if config.BatchSize < 1 {
+		config.BatchSize = _defaultOutboxBatchSize
+	}
+
+	return &OutboxRelay{producer: producer, store: store, config: config}
+}
+
+// Run publishes pending records until ctx is cancelled. Full batches are relayed back to
+// back; once the outbox is drained the relay waits for the configured interval.
+//
+// Returns:
+//   - error: Always nil; errors are reported through WithOutboxErrorHandler
+func (r *OutboxRelay) Run(ctx context.Context) error {
+	for ctx.Err() == nil {
+		relayed, err := r.RelayOnce(ctx)
+		if err != nil {
+			r.notify(err)
+		}
+
+		if err != nil || relayed < r.config.BatchSize {
+			sleep(ctx, r.config.Interval)
+		}
+	}
+
+	return nil
+}
+
+// RelayOnce publishes a single batch of pending records.
+//
+// Parameters:
+//   - ctx: Context for request cancellation and timeouts
+//
+// Returns:
+//   - int: Number of records read from the store
+//   - error: Any error reading, sending or marking records
+func (r *OutboxRelay) RelayOnce(ctx context.Context) (int, error) {
+	records, err := r.store.Pending(ctx, r.config.BatchSize)
+	if err != nil || len(records) == 0 {
+		return 0, err
+	}
+
+	messages := make([]OutgoingMessage, len(records))
+	for i, record := range records {
+		messages[i] = outboxMessage(record, isFIFOQueue(r.producer.queueURL))
+	}
+
+	result, sendErr := r.producer.SendBatch(ctx, messages)
+
+	ids := make([]string, 0, len(records))
+	for _, success := range result.Successful {
+		ids = append(ids, success.ID)
+		if success.Err != nil {
+			r.notify(success.Err)
+		}
+	}
+
+	for _, failure := range result.Failed {
+		switch {
+		case errors.Is(failure.Err, ErrSpooled):
+			// The spool of the producer sends the record from now on
+			ids = append(ids, failure.ID)
+		case failure.Err != sendErr:
+			r.notify(failure.Err)
+		}
+	}
+
+	if len(ids) > 0 {
+		// Mark even if the context is being cancelled: the messages are already sent
+		if err := r.store.MarkSent(context.WithoutCancel(ctx), ids); err != nil {
+			return len(records), err
+		}
+	}
+
+	return len(records), sendErr
+}
+
+// notify reports an error to the configured error handler.
+func (r *OutboxRelay) notify(err error) {
+	if r.config.OnError != nil {
+		r.config.OnError(err)
+	}
+}
+
+// outboxMessage converts an outbox record into a message for the producer. Deduplication
+// IDs are only set for FIFO destinations.
+func outboxMessage(record OutboxRecord, fifo bool) OutgoingMessage {
+	msg := OutgoingMessage{ID: record.ID, Body: record.Body}
+
+	if len(record.Attributes) > 0 {
+		attributes := make(map[string]types.MessageAttributeValue, len(record.Attributes))
+		for name, value := range record.Attributes {
+			attributes[name] = types.MessageAttributeValue{
+				DataType:    aws.String(_attributeDataTypeString),
+				StringValue: aws.String(value),
+			}
+		}
+		msg.Options = append(msg.Options, WithMessageAttributes(attributes))
+	}
+
+	if record.GroupID != "" {
+		msg.Options = append(msg.Options, WithMessageGroupID(record.GroupID))
+	}
+
+	if fifo {
+		deduplicationID := record.DeduplicationID
+		if deduplicationID == "" {
+			deduplicationID = record.ID
+		}
+		msg.Options = append(msg.Options, WithDeduplicationID(deduplicationID))
+	}
+
+	return msg
+}
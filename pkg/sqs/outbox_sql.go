@@ -0,0 +1,172 @@
+package sqs
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// SQLExecer is the subset of *sql.DB and *sql.Tx used to write outbox records, so
+// records can be added inside the caller's transaction.
+type SQLExecer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// SQLOutboxStore is an OutboxStore backed by a database/sql table with this layout
+// (adapt the types to your database):
+//
+//	CREATE TABLE outbox (
+//	    id               VARCHAR(128) PRIMARY KEY,
+//	    body             TEXT         NOT NULL,
+//	    group_id         VARCHAR(128),
+//	    deduplication_id VARCHAR(128),
+//	    attributes       TEXT,        -- JSON object of string attributes
+//	    created_at       TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+//	    sent_at          TIMESTAMP
+//	);
+//
+// Records are pending while sent_at is NULL. Running several relays on the same table is
+// safe but may publish a record more than once.
+type SQLOutboxStore struct {
+	db    *sql.DB
+	table string
+
+	dollarPlaceholders bool
+}
+
+// SQLOutboxOption is a function type for configuring a SQLOutboxStore with the functional options pattern.
+type SQLOutboxOption func(*SQLOutboxStore)
+
+// WithDollarPlaceholders makes the store use $1, $2, ... query placeholders (PostgreSQL)
+// instead of ? (MySQL, SQLite).
+func WithDollarPlaceholders() SQLOutboxOption {
+	return func(s *SQLOutboxStore) {
+		s.dollarPlaceholders = true
+	}
+}
+
+// NewSQLOutboxStore creates an outbox store on the given table.
+//
+// Parameters:
+//   - db: The database holding the outbox table
+//   - table: Name of the outbox table (trusted input, it is not escaped)
+//   - options: Optional settings such as the placeholder style
+//
+// Returns:
+//   - *SQLOutboxStore: A store usable by NewOutboxRelay
+//
+// Example:
+//
+//	store := sqs.NewSQLOutboxStore(db, "outbox", sqs.WithDollarPlaceholders())
+//
+//	tx, _ := db.BeginTx(ctx, nil)
+//	_, _ = tx.ExecContext(ctx, "UPDATE orders SET status = 'paid' WHERE id = $1", orderID)
+//	_ = store.Add(ctx, tx, sqs.OutboxRecord{ID: eventID, Body: payload})
+//	_ = tx.Commit()
+func NewSQLOutboxStore(db *sql.DB, table string, options ...SQLOutboxOption) *SQLOutboxStore {
+	store := &SQLOutboxStore{db: db, table: table}
+
+	for _, opt := range options {
+		opt(store)
+	}
+
+	return store
+}
+
+// Add writes a record to the outbox using exec, typically the transaction that also
+// writes the business change, so the message is published if and only if it commits.
+func (s *SQLOutboxStore) Add(ctx context.Context, exec SQLExecer, record OutboxRecord) error {
+	var attributes sql.NullString
+	if len(record.Attributes) > 0 {
+		raw, err := json.Marshal(record.Attributes)
+		if err != nil {
+			return err
+		}
+		attributes = sql.NullString{String: string(raw), Valid: true}
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (id, body, group_id, deduplication_id, attributes) VALUES (%s)",
+		s.table, s.placeholders(1, 5))
+
+	_, err := exec.ExecContext(ctx, query, record.ID, record.Body,
+		nullString(record.GroupID), nullString(record.DeduplicationID), attributes)
+
+	return err
+}
+
+// Pending returns up to limit unsent records, oldest first.
+func (s *SQLOutboxStore) Pending(ctx context.Context, limit int) ([]OutboxRecord, error) {
+	query := fmt.Sprintf("SELECT id, body, group_id, deduplication_id, attributes FROM %s WHERE sent_at IS NULL ORDER BY created_at, id LIMIT %s",
+		s.table, s.placeholders(1, 1))
+
+	rows, err := s.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []OutboxRecord
+	for rows.Next() {
+		var (
+			record                               OutboxRecord
+			groupID, deduplicationID, attributes sql.NullString
+		)
+
+		if err := rows.Scan(&record.ID, &record.Body, &groupID, &deduplicationID, &attributes); err != nil {
+			return nil, err
+		}
+
+		record.GroupID = groupID.String
+		record.DeduplicationID = deduplicationID.String
+
+		if attributes.Valid && attributes.String != "" {
+			if err := json.Unmarshal([]byte(attributes.String), &record.Attributes); err != nil {
+				return nil, fmt.Errorf("outbox record %s: invalid attributes: %w", record.ID, err)
+			}
+		}
+
+		records = append(records, record)
+	}
+
+	return records, rows.Err()
+}
+
+// MarkSent sets sent_at on the given records.
+func (s *SQLOutboxStore) MarkSent(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET sent_at = CURRENT_TIMESTAMP WHERE id IN (%s)",
+		s.table, s.placeholders(1, len(ids)))
+
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+
+	_, err := s.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+// placeholders returns count comma-separated query placeholders, numbered from first
+// when dollar placeholders are enabled.
+func (s *SQLOutboxStore) placeholders(first, count int) string {
+	parts := make([]string, count)
+	for i := range parts {
+		if s.dollarPlaceholders {
+			parts[i] = fmt.Sprintf("$%d", first+i)
+		} else {
+			parts[i] = "?"
+		}
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// nullString maps an empty string to SQL NULL.
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
@@ -0,0 +1,157 @@
+package sqs
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// recordingDriver is a minimal database/sql driver recording statements and serving
+// prepared rows to queries.
+type recordingDriver struct {
+	mu    sync.Mutex
+	execs []recordedStatement
+	rows  [][]driver.Value
+}
+
+type recordedStatement struct {
+	query string
+	args  []driver.Value
+}
+
+var (
+	_recordingDriver     = &recordingDriver{}
+	_recordingDriverOnce sync.Once
+)
+
+// openRecordingDB opens a database served by a fresh recordingDriver state.
+func openRecordingDB(t *testing.T, rows [][]driver.Value) (*sql.DB, *recordingDriver) {
+	t.Helper()
+
+	_recordingDriverOnce.Do(func() { sql.Register("recording", _recordingDriver) })
+
+	_recordingDriver.mu.Lock()
+	_recordingDriver.execs = nil
+	_recordingDriver.rows = rows
+	_recordingDriver.mu.Unlock()
+
+	db, err := sql.Open("recording", "")
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	return db, _recordingDriver
+}
+
+func (d *recordingDriver) Open(name string) (driver.Conn, error) { return recordingConn{d}, nil }
+
+type recordingConn struct{ d *recordingDriver }
+
+func (c recordingConn) Prepare(query string) (driver.Stmt, error) {
+	return recordingStmt{d: c.d, query: query}, nil
+}
+func (c recordingConn) Close() error              { return nil }
+func (c recordingConn) Begin() (driver.Tx, error) { return nil, driver.ErrSkip }
+
+type recordingStmt struct {
+	d     *recordingDriver
+	query string
+}
+
+func (s recordingStmt) Close() error  { return nil }
+func (s recordingStmt) NumInput() int { return -1 }
+
+func (s recordingStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+
+	s.d.execs = append(s.d.execs, recordedStatement{query: s.query, args: args})
+	return driver.RowsAffected(1), nil
+}
+
+func (s recordingStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+
+	s.d.execs = append(s.d.execs, recordedStatement{query: s.query, args: args})
+	return &recordingRows{rows: s.d.rows}, nil
+}
+
+type recordingRows struct{ rows [][]driver.Value }
+
+func (r *recordingRows) Columns() []string {
+	return []string{"id", "body", "group_id", "deduplication_id", "attributes"}
+}
+func (r *recordingRows) Close() error { return nil }
+
+func (r *recordingRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+func TestSQLOutboxStoreAdd(t *testing.T) {
+	db, recorder := openRecordingDB(t, nil)
+	store := NewSQLOutboxStore(db, "outbox", WithDollarPlaceholders())
+
+	err := store.Add(context.Background(), db, OutboxRecord{ID: "r1", Body: "body", Attributes: map[string]string{"type": "a"}})
+	if err != nil {
+		t.Fatalf("Add returned error: %v", err)
+	}
+
+	exec := recorder.execs[0]
+	if exec.query != "INSERT INTO outbox (id, body, group_id, deduplication_id, attributes) VALUES ($1, $2, $3, $4, $5)" {
+		t.Errorf("Unexpected insert query: %s", exec.query)
+	}
+
+	expected := []driver.Value{"r1", "body", nil, nil, `{"type":"a"}`}
+	if !reflect.DeepEqual(exec.args, expected) {
+		t.Errorf("Expected args %v, got %v", expected, exec.args)
+	}
+}
+
+func TestSQLOutboxStorePending(t *testing.T) {
+	db, recorder := openRecordingDB(t, [][]driver.Value{
+		{"r1", "first", nil, nil, `{"type":"a"}`},
+		{"r2", "second", "group", "dedup", nil},
+	})
+	store := NewSQLOutboxStore(db, "outbox")
+
+	records, err := store.Pending(context.Background(), 50)
+	if err != nil {
+		t.Fatalf("Pending returned error: %v", err)
+	}
+
+	if recorder.execs[0].query != "SELECT id, body, group_id, deduplication_id, attributes FROM outbox WHERE sent_at IS NULL ORDER BY created_at, id LIMIT ?" {
+		t.Errorf("Unexpected select query: %s", recorder.execs[0].query)
+	}
+
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records, got %d", len(records))
+	}
+
+	if records[0].Attributes["type"] != "a" || records[1].GroupID != "group" || records[1].DeduplicationID != "dedup" {
+		t.Errorf("Unexpected records: %+v", records)
+	}
+}
+
+func TestSQLOutboxStoreMarkSent(t *testing.T) {
+	db, recorder := openRecordingDB(t, nil)
+	store := NewSQLOutboxStore(db, "outbox", WithDollarPlaceholders())
+
+	if err := store.MarkSent(context.Background(), []string{"r1", "r2"}); err != nil {
+		t.Fatalf("MarkSent returned error: %v", err)
+	}
+
+	if recorder.execs[0].query != "UPDATE outbox SET sent_at = CURRENT_TIMESTAMP WHERE id IN ($1, $2)" {
+		t.Errorf("Unexpected update query: %s", recorder.execs[0].query)
+	}
+}
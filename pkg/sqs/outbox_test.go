@@ -0,0 +1,148 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// memoryOutbox is an in-memory OutboxStore.
+type memoryOutbox struct {
+	mu      sync.Mutex
+	records []OutboxRecord
+	sent    map[string]bool
+	markErr error
+}
+
+func (m *memoryOutbox) Pending(ctx context.Context, limit int) ([]OutboxRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var pending []OutboxRecord
+	for _, record := range m.records {
+		if !m.sent[record.ID] && len(pending) < limit {
+			pending = append(pending, record)
+		}
+	}
+
+	return pending, nil
+}
+
+func (m *memoryOutbox) MarkSent(ctx context.Context, ids []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.markErr != nil {
+		return m.markErr
+	}
+
+	if m.sent == nil {
+		m.sent = map[string]bool{}
+	}
+	for _, id := range ids {
+		m.sent[id] = true
+	}
+
+	return nil
+}
+
+func TestOutboxRelayPublishesAndMarks(t *testing.T) {
+	fake := &fakeSQS{failBodies: map[string]bool{"rejected": true}}
+	store := &memoryOutbox{records: []OutboxRecord{
+		{ID: "r1", Body: "first", GroupID: "customer-1", Attributes: map[string]string{"type": "order.created"}},
+		{ID: "r2", Body: "rejected", GroupID: "customer-1"},
+		{ID: "r3", Body: "third", GroupID: "customer-2", DeduplicationID: "event-3"},
+	}}
+
+	var reported []error
+	relay := NewOutboxRelay(NewProducer(newTestSQS(fake), "queue.fifo"), store, WithOutboxErrorHandler(func(err error) {
+		reported = append(reported, err)
+	}))
+
+	relayed, err := relay.RelayOnce(context.Background())
+	if err != nil {
+		t.Fatalf("RelayOnce returned error: %v", err)
+	}
+
+	if relayed != 3 {
+		t.Errorf("Expected 3 records relayed, got %d", relayed)
+	}
+
+	if !store.sent["r1"] || store.sent["r2"] || !store.sent["r3"] {
+		t.Errorf("Expected only accepted records to be marked, got %v", store.sent)
+	}
+
+	if len(reported) != 1 {
+		t.Errorf("Expected the rejected record to be reported, got %v", reported)
+	}
+
+	sent := fake.sentMessages()
+	if aws.ToString(sent[0].MessageAttributes["type"].StringValue) != "order.created" {
+		t.Errorf("Expected attributes to be sent, got %v", sent[0].MessageAttributes)
+	}
+
+	if aws.ToString(sent[0].MessageDeduplicationId) != "r1" || aws.ToString(sent[0].MessageGroupId) != "customer-1" {
+		t.Errorf("Expected FIFO record deduplicated by its ID, got group %q dedup %q",
+			aws.ToString(sent[0].MessageGroupId), aws.ToString(sent[0].MessageDeduplicationId))
+	}
+
+	if aws.ToString(sent[1].MessageDeduplicationId) != "event-3" {
+		t.Errorf("Expected explicit deduplication ID to be kept, got %q", aws.ToString(sent[1].MessageDeduplicationId))
+	}
+}
+
+func TestOutboxRelayKeepsRecordsOnSendError(t *testing.T) {
+	fake := &fakeSQS{sendErr: errors.New("unavailable")}
+	store := &memoryOutbox{records: []OutboxRecord{{ID: "r1", Body: "first"}}}
+	relay := NewOutboxRelay(NewProducer(newTestSQS(fake), "queue"), store)
+
+	if _, err := relay.RelayOnce(context.Background()); err == nil {
+		t.Error("Expected the send error to be returned")
+	}
+
+	if store.sent["r1"] {
+		t.Error("Expected the record to stay pending")
+	}
+}
+
+func TestOutboxRelayMarkError(t *testing.T) {
+	store := &memoryOutbox{records: []OutboxRecord{{ID: "r1", Body: "first"}}, markErr: errors.New("db down")}
+	relay := NewOutboxRelay(NewProducer(newTestSQS(&fakeSQS{}), "queue"), store)
+
+	if _, err := relay.RelayOnce(context.Background()); err == nil || err.Error() != "db down" {
+		t.Errorf("Expected the mark error, got %v", err)
+	}
+}
+
+func TestOutboxRelayMarksSpooledRecords(t *testing.T) {
+	fake := &fakeSQS{sendErr: errThrottled}
+	store := &memoryOutbox{records: []OutboxRecord{{ID: "r1", Body: "first"}}}
+	producer := NewProducer(newTestSQS(fake), "queue", WithAdaptiveThrottling(0), fastSpool(SpoolPolicy{}))
+	relay := NewOutboxRelay(producer, store)
+
+	_, _ = relay.RelayOnce(context.Background())
+	if !store.sent["r1"] {
+		t.Fatal("Expected the spooled record to be marked as sent")
+	}
+
+	fake.mu.Lock()
+	fake.sendErr = nil
+	fake.mu.Unlock()
+
+	if relayed, err := relay.RelayOnce(context.Background()); relayed != 0 || err != nil {
+		t.Errorf("Expected nothing left to relay, got %d, %v", relayed, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := producer.Close(ctx); err != nil {
+		t.Fatalf("Expected the spool to drain, got %v", err)
+	}
+	if sent := fake.sentMessages(); len(sent) != 1 || len(fake.sentBatches) != 0 {
+		t.Errorf("Expected the record delivered once by the spool, got %d sends and %d batches", len(sent), len(fake.sentBatches))
+	}
+}
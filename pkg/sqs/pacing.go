@@ -0,0 +1,48 @@
+package sqs
+
+import (
+	"context"
+	"time"
+)
+
+// WithBackToBackPolling lifts the one second floor of WaitTimeSeconds at very high volume:
+// instead of the very high volume wait time, polls are issued back to back with a wait time
+// of 0, so throughput is only bound by the round trip to SQS. An optional pacing spaces the
+// polls of a queue at least that far apart, across every goroutine polling it, to keep the
+// request rate (and cost) in check while still polling several times per second.
+//
+// The other volume classes keep their wait times, so the client returns to long polling as
+// soon as the volume drops.
+//
+// Parameters:
+//   - pacing: Minimum interval between two back-to-back polls of a queue (0 disables pacing)
+//
+// Example:
+//
+//	// At most 5 receives per second and per queue at very high volume
+//	option := WithBackToBackPolling(200 * time.Millisecond)
+func WithBackToBackPolling(pacing time.Duration) Option {
+	return func(c *config) {
+		c.AdaptivePolling.BackToBackPolling = true
+		c.AdaptivePolling.PollPacing = max(pacing, 0)
+	}
+}
+
+// pace waits until the queue may be polled again, reserving the next poll slot pacing
+// later, so concurrent pollers of the queue are spaced pacing apart. It returns early if
+// ctx is done.
+func (a *arrakis) pace(ctx context.Context, pacing time.Duration) {
+	now := time.Now()
+
+	a.mu.Lock()
+	at := a.nextPoll
+	if at.Before(now) {
+		at = now
+	}
+	a.nextPoll = at.Add(pacing)
+	a.mu.Unlock()
+
+	if wait := at.Sub(now); wait > 0 {
+		sleep(ctx, wait)
+	}
+}
@@ -0,0 +1,51 @@
+package sqs
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// fullBatch returns 10 messages, the largest batch a receive returns.
+func fullBatch(poll int) []types.Message {
+	batch := make([]types.Message, 10)
+	for i := range batch {
+		batch[i] = testMessage(fmt.Sprintf("m%d-%d", poll, i), "")
+	}
+
+	return batch
+}
+
+func TestBackToBackPolling(t *testing.T) {
+	fake := &fakeSQS{}
+	client := newTestSQS(fake, WithBackToBackPolling(50*time.Millisecond))
+	client.EnableArrakis()
+	client.SetAdaptiveState("queue", AdaptiveState{Average: 20})
+
+	start := time.Now()
+	for poll := range 3 {
+		fake.push(fullBatch(poll)...)
+		if _, err := client.ReceiveMessage(context.Background(), "queue", 10, nil); err != nil {
+			t.Fatalf("ReceiveMessage returned error: %v", err)
+		}
+	}
+
+	for _, input := range fake.receiveInputs {
+		if input.WaitTimeSeconds != 0 {
+			t.Fatalf("Expected back-to-back polls at very high volume, got wait time %d", input.WaitTimeSeconds)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("Expected 3 polls paced 50ms apart to take at least 100ms, took %s", elapsed)
+	}
+
+	// Long polling resumes below very high volume
+	client.SetAdaptiveState("queue", AdaptiveState{Average: 3})
+	_, _ = client.ReceiveMessage(context.Background(), "queue", 10, nil)
+	if got := fake.receiveInputs[3].WaitTimeSeconds; got != _defaultMediumVolumeWaitTimeSeconds {
+		t.Errorf("Expected the medium volume wait time, got %d", got)
+	}
+}
@@ -0,0 +1,75 @@
+package sqs
+
+import (
+	"context"
+	"sync"
+)
+
+// Pause stops the consumer from issuing new ReceiveMessage calls until Resume is called.
+// Messages already received keep being handled, and a receive already in progress
+// completes normally. Pausing a paused consumer has no effect.
+//
+// Example:
+//
+//	consumer.Pause() // Downstream maintenance
+//	defer consumer.Resume()
+func (c *Consumer) Pause() {
+	c.gate.set(true)
+}
+
+// Resume lets a paused consumer poll again.
+func (c *Consumer) Resume() {
+	c.gate.set(false)
+}
+
+// Paused reports whether the consumer is paused with Pause. Pause windows (see
+// WithPauseWindows) are reported through their own callback.
+func (c *Consumer) Paused() bool {
+	c.gate.mu.Lock()
+	defer c.gate.mu.Unlock()
+
+	return c.gate.paused
+}
+
+// pauseGate blocks polling while a consumer is paused. Its zero value is an open gate.
+type pauseGate struct {
+	mu      sync.Mutex
+	paused  bool
+	resumed chan struct{} // Closed when the consumer is resumed, nil while not paused
+}
+
+// set pauses or resumes polling.
+func (g *pauseGate) set(paused bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if paused == g.paused {
+		return
+	}
+
+	g.paused = paused
+	if paused {
+		g.resumed = make(chan struct{})
+	} else {
+		close(g.resumed)
+		g.resumed = nil
+	}
+}
+
+// wait blocks while polling is paused. It returns false if ctx is done first.
+func (g *pauseGate) wait(ctx context.Context) bool {
+	g.mu.Lock()
+	resumed := g.resumed
+	g.mu.Unlock()
+
+	if resumed == nil {
+		return true
+	}
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-resumed:
+		return true
+	}
+}
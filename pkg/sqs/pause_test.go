@@ -0,0 +1,51 @@
+package sqs
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestConsumerPauseResume(t *testing.T) {
+	fake := &fakeSQS{}
+	client := newTestSQS(fake)
+
+	consumer := NewConsumer(client, "queue", HandlerFunc(func(ctx context.Context, msg Message) error { return nil }))
+	consumer.Pause()
+	if !consumer.Paused() {
+		t.Fatal("Expected the consumer to be paused")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		_ = consumer.Start(ctx)
+		close(done)
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	fake.mu.Lock()
+	receives := len(fake.receiveInputs)
+	fake.mu.Unlock()
+	if receives != 0 {
+		t.Errorf("Expected no receive while paused, got %d", receives)
+	}
+
+	fake.push(testMessage("m1", ""))
+	consumer.Resume()
+	runDeadline := time.Now().Add(2 * time.Second)
+	for len(fake.deletedHandles()) == 0 && time.Now().Before(runDeadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if len(fake.deletedHandles()) != 1 {
+		t.Error("Expected the consumer to poll again once resumed")
+	}
+
+	consumer.Pause()
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected Start to return when cancelled while paused")
+	}
+}
@@ -0,0 +1,79 @@
+package sqs
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// Sampling configuration shared by Peek and InspectDLQ
+const (
+	_sampleVisibilityTimeout = 30 // Seconds sampled messages stay hidden while sampling
+)
+
+// Peek returns copies of up to n messages from a queue without consuming them, which is
+// useful for debugging production queues.
+//
+// Peeked messages are hidden only while Peek runs (so none is returned twice) and are
+// made visible again (visibility 0) before it returns, so normal consumers receive them
+// right away. They are never deleted, and peeking doesn't affect the adaptive polling state.
+//
+// Note that each peek counts as a receive: it increments ApproximateReceiveCount and may
+// move messages to a dead-letter queue if maxReceiveCount is low.
+//
+// Parameters:
+//   - ctx: Context for request cancellation and timeouts
+//   - queueURL: The URL of the SQS queue to peek into
+//   - n: Maximum number of messages to return
+//
+// Returns:
+//   - []Message: The peeked messages, with all system and message attributes
+//   - error: Any error that occurred during the operation
+//
+// Example:
+//
+//	messages, err := sqsClient.Peek(ctx, queueURL, 5)
+//	for _, msg := range messages {
+//	    fmt.Printf("%s: %s\n", msg.ID, msg.Body)
+//	}
+func (s *SQS) Peek(ctx context.Context, queueURL string, n int) ([]Message, error) {
+	return s.sampleMessages(ctx, queueURL, n)
+}
+
+// sampleMessages receives up to limit messages without consuming them. Messages are
+// hidden while sampling so the same message isn't counted twice, then released.
+func (s *SQS) sampleMessages(ctx context.Context, queueURL string, limit int) ([]Message, error) {
+	var sampled []Message
+
+	// Always make sampled messages visible again, even if sampling fails halfway
+	defer func() {
+		for _, msg := range sampled {
+			_, _ = s.ChangeMessageVisibility(context.WithoutCancel(ctx), queueURL, msg.ReceiptHandle, 0)
+		}
+	}()
+
+	for len(sampled) < limit {
+		output, err := s.receiveMessage(ctx, &sqs.ReceiveMessageInput{
+			QueueUrl:                    aws.String(queueURL),
+			MaxNumberOfMessages:         int32(min(limit-len(sampled), _maxNumberOfMessages)),
+			VisibilityTimeout:           _sampleVisibilityTimeout,
+			MessageAttributeNames:       []string{_allMessageAttributes},
+			MessageSystemAttributeNames: []types.MessageSystemAttributeName{types.MessageSystemAttributeNameAll},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if len(output.Messages) == 0 {
+			break
+		}
+
+		for _, m := range output.Messages {
+			sampled = append(sampled, NewMessage(queueURL, m))
+		}
+	}
+
+	return sampled, nil
+}
@@ -0,0 +1,34 @@
+package sqs
+
+import (
+	"context"
+	"testing"
+)
+
+func TestPeekReturnsMessagesWithoutConsumingThem(t *testing.T) {
+	fake := &fakeSQS{}
+	fake.push(testMessage("m1", ""), testMessage("m2", ""))
+	fake.push(testMessage("m3", ""))
+	client := newTestSQS(fake)
+
+	messages, err := client.Peek(context.Background(), "queue", 2)
+	if err != nil {
+		t.Fatalf("Peek returned error: %v", err)
+	}
+
+	if len(messages) != 2 || messages[0].Body != "m1" {
+		t.Fatalf("Expected the first 2 messages, got %+v", messages)
+	}
+	if fake.receiveInputs[0].VisibilityTimeout == 0 {
+		t.Error("Expected messages to stay hidden while peeking")
+	}
+
+	for _, msg := range messages {
+		if v, ok := fake.visibilityOf(msg.ReceiptHandle); !ok || v != 0 {
+			t.Errorf("Expected %s to be released with visibility 0", msg.ID)
+		}
+	}
+	if len(fake.deletedHandles()) != 0 {
+		t.Error("Expected Peek not to delete messages")
+	}
+}
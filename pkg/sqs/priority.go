@@ -0,0 +1,196 @@
+package sqs
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+)
+
+// Default priority queue configuration values
+const (
+	_defaultStarvationLimit = 10 // Consecutive polls of higher levels before a lower level gets one
+)
+
+// priorityConfig holds the configuration of a PriorityQueue.
+type priorityConfig struct {
+	// StarvationLimit is the number of consecutive non-empty polls of higher levels after
+	// which a lower level is polled once. Zero disables anti-starvation.
+	StarvationLimit int
+}
+
+// PriorityOption is a function type for configuring a PriorityQueue with the functional options pattern.
+type PriorityOption func(*priorityConfig)
+
+// WithStarvationLimit bounds how long lower priority levels can be starved: after polls
+// consecutive receives that returned messages from higher levels, the consumer polls the
+// next lower level once, cycling through the lower levels (default: 10). Zero drains
+// levels strictly by priority.
+func WithStarvationLimit(polls int) PriorityOption {
+	return func(c *priorityConfig) {
+		c.StarvationLimit = polls
+	}
+}
+
+// PriorityQueue models one logical queue as several physical queues, one per priority
+// level. Producers send with a level, and consumers drain higher levels first.
+//
+// Consumers scan the levels from the highest with zero-wait receives, handling messages
+// from the first level that has any, then start over from the highest level. When a full
+// scan finds every level empty, they long poll the highest level (with adaptive polling
+// when enabled), so an idle consumer reacts to high priority messages at once. While the
+// highest level is long polled, lower priority messages wait up to the wait time.
+type PriorityQueue struct {
+	client    *SQS
+	queueURLs []string
+	config    priorityConfig
+}
+
+// NewPriorityQueue creates a priority queue over queueURLs, highest priority first.
+//
+// Parameters:
+//   - client: The SQS client of the queues
+//   - queueURLs: One queue per priority level, from level 0 (highest) down
+//   - options: Optional settings such as WithStarvationLimit
+//
+// Returns:
+//   - *PriorityQueue: The priority queue
+//
+// Example:
+//
+//	jobs := sqs.NewPriorityQueue(sqsClient, []string{urgentURL, normalURL, bulkURL})
+//	_, err := jobs.Send(ctx, 0, `{"job":"refund"}`)
+//	err = jobs.Consumer(handler, sqs.WithWorkers(8)).Start(ctx)
+func NewPriorityQueue(client *SQS, queueURLs []string, options ...PriorityOption) *PriorityQueue {
+	config := priorityConfig{StarvationLimit: _defaultStarvationLimit}
+	for _, opt := range options {
+		opt(&config)
+	}
+
+	return &PriorityQueue{client: client, queueURLs: queueURLs, config: config}
+}
+
+// Levels returns the number of priority levels.
+func (p *PriorityQueue) Levels() int {
+	return len(p.queueURLs)
+}
+
+// Send sends a message with a priority level.
+//
+// Parameters:
+//   - ctx: Context for request cancellation and timeouts
+//   - level: The priority level, 0 being the highest
+//   - body: The message body
+//   - options: Send options, as for SQS.SendMessage
+//
+// Returns:
+//   - *sqs.SendMessageOutput: The SQS response
+//   - error: An error for an unknown level, or the send error
+func (p *PriorityQueue) Send(ctx context.Context, level int, body string, options ...SendOption) (*sqs.SendMessageOutput, error) {
+	if level < 0 || level >= len(p.queueURLs) {
+		return nil, fmt.Errorf("priority level %d out of range [0, %d)", level, len(p.queueURLs))
+	}
+
+	return p.client.SendMessage(ctx, p.queueURLs[level], body, options...)
+}
+
+// Consumer creates a consumer of every level. Messages are deleted (or nacked) in the
+// queue they came from.
+//
+// Parameters:
+//   - handler: The handler of the messages of every level
+//   - options: Consumer options
+//
+// Returns:
+//   - *Consumer: The consumer, ready to Start
+func (p *PriorityQueue) Consumer(handler Handler, options ...ConsumerOption) *Consumer {
+	options = append(options[:len(options):len(options)], func(c *consumerConfig) {
+		c.Priority = p
+	})
+
+	return NewConsumer(p.client, p.queueURLs[0], handler, options...)
+}
+
+// priorityScheduler decides which level of a priority queue a consumer polls next. A nil
+// scheduler polls the consumer queue.
+type priorityScheduler struct {
+	levels []queueSource
+	limit  int
+
+	mu       sync.Mutex
+	next     int  // Level polled next
+	longPoll bool // Whether the next poll is a long poll of the highest level
+	streak   int  // Consecutive non-empty polls since a lower level was last given a turn
+	starved  int  // Lower level given the next anti-starvation turn
+	forced   bool // Whether the next poll is an anti-starvation turn
+}
+
+// newPriorityScheduler creates the scheduler of a priority queue, or nil without one.
+func newPriorityScheduler(queue *PriorityQueue) *priorityScheduler {
+	if queue == nil {
+		return nil
+	}
+
+	s := &priorityScheduler{limit: queue.config.StarvationLimit, starved: 1}
+	for _, queueURL := range queue.queueURLs {
+		s.levels = append(s.levels, queueSource{client: queue.client, queueURL: queueURL, state: queue.client.state(queueURL)})
+	}
+
+	return s
+}
+
+// pick returns the level to poll and whether to scan it with a zero-wait receive.
+func (s *priorityScheduler) pick() (level int, scan bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.next, !s.longPoll
+}
+
+// observe moves the scheduler on after a poll of level returned received messages.
+func (s *priorityScheduler) observe(level, received int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	forced := s.forced
+	s.longPoll, s.forced = false, false
+
+	switch {
+	case forced:
+		s.streak = 0
+		s.next = 0
+	case received > 0 && s.limit > 0 && len(s.levels) > 1 && level < len(s.levels)-1:
+		// Higher levels keep delivering: give a lower level a turn every limit polls
+		if s.streak++; s.streak >= s.limit {
+			s.streak = 0
+			s.next, s.forced = s.starved, true
+			s.starved = s.starved%(len(s.levels)-1) + 1
+			return
+		}
+		s.next = 0
+	case received > 0:
+		s.streak = 0
+		s.next = 0
+	case level+1 < len(s.levels):
+		s.next = level + 1
+	default:
+		// Every level is empty: wait on the highest one
+		s.next, s.longPoll = 0, true
+	}
+}
+
+// sourceOf returns the level a message was received from.
+func (s *priorityScheduler) sourceOf(queueURL string) (queueSource, bool) {
+	if s == nil {
+		return queueSource{}, false
+	}
+
+	for _, level := range s.levels {
+		if level.queueURL == queueURL {
+			return level, true
+		}
+	}
+
+	return queueSource{}, false
+}
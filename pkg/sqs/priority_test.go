@@ -0,0 +1,62 @@
+package sqs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+func TestPrioritySchedulerDrainsHigherLevelsFirst(t *testing.T) {
+	client := newTestSQS(&fakeSQS{})
+	scheduler := newPriorityScheduler(NewPriorityQueue(client, []string{"urgent", "normal", "bulk"}, WithStarvationLimit(2)))
+
+	expect := func(level int, scan bool) {
+		t.Helper()
+		if gotLevel, gotScan := scheduler.pick(); gotLevel != level || gotScan != scan {
+			t.Fatalf("Expected level %d (scan %v), got level %d (scan %v)", level, scan, gotLevel, gotScan)
+		}
+	}
+
+	// Empty levels are scanned down to the lowest, then the highest level is long polled
+	expect(0, true)
+	scheduler.observe(0, 0)
+	expect(1, true)
+	scheduler.observe(1, 0)
+	expect(2, true)
+	scheduler.observe(2, 0)
+	expect(0, false)
+
+	// Messages of a level send the consumer back to the highest level, until the
+	// starvation limit gives the lower levels a turn each
+	scheduler.observe(0, 5)
+	expect(0, true)
+	scheduler.observe(0, 5)
+	expect(1, true)
+	scheduler.observe(1, 3)
+	expect(0, true)
+	scheduler.observe(0, 5)
+	scheduler.observe(0, 5)
+	expect(2, true)
+}
+
+func TestPriorityQueueSend(t *testing.T) {
+	fake := &fakeSQS{}
+	queue := NewPriorityQueue(newTestSQS(fake), []string{"urgent", "bulk"})
+
+	if _, err := queue.Send(context.Background(), 1, "report"); err != nil {
+		t.Fatalf("Expected the message to be sent, got %v", err)
+	}
+	if sent := fake.sentMessages(); len(sent) != 1 || aws.ToString(sent[0].QueueUrl) != "bulk" {
+		t.Errorf("Expected the message in the queue of its level, got %+v", sent)
+	}
+
+	if _, err := queue.Send(context.Background(), 2, "report"); err == nil {
+		t.Error("Expected an error for an unknown level")
+	}
+
+	consumer := queue.Consumer(HandlerFunc(func(ctx context.Context, msg Message) error { return nil }))
+	if source := consumer.sourceOf(Message{QueueURL: "bulk"}); source.queueURL != "bulk" {
+		t.Errorf("Expected messages to be acknowledged in the queue of their level, got %q", source.queueURL)
+	}
+}
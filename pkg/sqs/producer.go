@@ -0,0 +1,326 @@
+package sqs
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/aws/smithy-go/middleware"
+)
+
+// Batch send limits
+const (
+	_maxBatchEntries = 10 // Maximum number of entries per SendMessageBatch call
+)
+
+// OutgoingMessage is a message to be sent by a Producer.
+type OutgoingMessage struct {
+	// ID identifies the message in the BatchResult. It must be unique within a SendBatch call.
+	ID string
+	// Body is the message payload.
+	Body string
+	// Options customize the message, exactly as for SendMessage (attributes, FIFO group, ...).
+	Options []SendOption
+}
+
+// BatchResult reports the outcome of every message of a SendBatch call.
+type BatchResult struct {
+	// Successful lists the messages accepted by SQS.
+	Successful []BatchSuccess
+	// Failed lists the messages that were not sent.
+	Failed []BatchFailure
+}
+
+// BatchSuccess describes a message accepted by SQS.
+type BatchSuccess struct {
+	// ID is the OutgoingMessage ID.
+	ID string
+	// MessageID is the ID assigned by SQS.
+	MessageID string
+	// Err is an *ErrChecksumMismatch when the digests SQS returned for the message don't
+	// match it (see WithChecksumVerification), or nil. The message was sent either way:
+	// sending it again duplicates it.
+	Err error
+}
+
+// BatchFailure describes a message that was not sent.
+type BatchFailure struct {
+	// ID is the OutgoingMessage ID.
+	ID string
+	// Err is the reason: a local validation error, *ErrBatchEntry, or the error of the
+	// whole SendMessageBatch call.
+	Err error
+}
+
+// ErrBatchEntry is a failure reported by SQS for a single entry of a batch.
+type ErrBatchEntry struct {
+	// Code is the SQS error code.
+	Code string
+	// Message describes the failure.
+	Message string
+	// SenderFault is true when the entry itself is invalid; retrying it won't help.
+	SenderFault bool
+}
+
+func (e *ErrBatchEntry) Error() string {
+	return fmt.Sprintf("batch entry rejected (%s): %s", e.Code, e.Message)
+}
+
+// producerConfig holds the settings of a Producer.
+type producerConfig struct {
+	// MaxThrottleDelay is the longest pause between sends while SQS throttles. Zero
+	// disables adaptive throttling.
+	MaxThrottleDelay time.Duration
+	// Metrics records the producer metrics. Nil disables them.
+	Metrics MetricsSink
+	// Spool retries the messages failing with transient errors in the background. Nil disables it.
+	Spool *SpoolPolicy
+}
+
+// ProducerOption is a function type for configuring a Producer with the functional options pattern.
+type ProducerOption func(*producerConfig)
+
+// Producer sends messages to a single queue, grouping them into SendMessageBatch calls
+// to reduce the number of API requests.
+type Producer struct {
+	client   *SQS
+	queueURL string
+	throttle *producerThrottle // Paces sends while SQS pushes back (nil when disabled)
+	metrics  *producerMetrics  // Send metrics (nil when disabled)
+	spool    *producerSpool    // Retries failed sends in the background (nil when disabled)
+}
+
+// NewProducer creates a producer sending to queueURL.
+//
+// Parameters:
+//   - client: The SQS client used for the API calls
+//   - queueURL: The URL of the destination queue
+//   - options: Optional settings such as WithAdaptiveThrottling and WithProducerMetrics
+//
+// Returns:
+//   - *Producer: A producer ready to send messages
+//
+// Example:
+//
+//	producer := sqs.NewProducer(sqsClient, queueURL)
+//	result, err := producer.SendBatch(ctx, []sqs.OutgoingMessage{
+//	    {ID: "1", Body: `{"order":1}`},
+//	    {ID: "2", Body: `{"order":2}`},
+//	})
+func NewProducer(client *SQS, queueURL string, options ...ProducerOption) *Producer {
+	config := producerConfig{MaxThrottleDelay: _defaultProducerMaxDelay}
+	for _, opt := range options {
+		opt(&config)
+	}
+
+	p := &Producer{
+		client:   client,
+		queueURL: queueURL,
+		throttle: newProducerThrottle(config.MaxThrottleDelay, client),
+		metrics:  newProducerMetrics(config.Metrics, queueURL),
+	}
+	p.spool = newProducerSpool(config.Spool, func(ctx context.Context, input *sqs.SendMessageInput) error {
+		output, err := p.send(ctx, input)
+		if err != nil && output != nil {
+			// Sent with a checksum mismatch: retrying it would duplicate it
+			client.logger().Warn("spooled message sent with a checksum mismatch", "queue", queueURL, "error", err)
+			return nil
+		}
+		return err
+	}, client.logger())
+
+	return p
+}
+
+// Send sends a single message. It is equivalent to SendMessage on the client, paced by
+// adaptive throttling (see WithAdaptiveThrottling). With WithSpool, messages failing with a
+// transient error are retried in the background and the error wraps ErrSpooled.
+func (p *Producer) Send(ctx context.Context, body string, options ...SendOption) (*sqs.SendMessageOutput, error) {
+	p.throttle.wait(ctx)
+
+	input := p.client.sendInput(p.queueURL, body, options)
+	output, err := p.send(ctx, input)
+	if err != nil && p.spool.offer(OutgoingMessage{Body: body, Options: options}, input, err) {
+		return nil, fmt.Errorf("%w: %w", ErrSpooled, err)
+	}
+
+	return output, err
+}
+
+// send performs the SendMessage call of a message and records its outcome.
+func (p *Producer) send(ctx context.Context, input *sqs.SendMessageInput) (*sqs.SendMessageOutput, error) {
+	start := time.Now()
+	output, err := p.client.send(ctx, input)
+	p.throttle.observe(isThrottled(err))
+
+	var metadata middleware.Metadata
+	if output != nil {
+		metadata = output.ResultMetadata
+	}
+	p.metrics.call(ctx, _operationNameSend, 1, start, metadata, err)
+	// A checksum mismatch comes with the output of a message that was sent
+	if output == nil {
+		p.metrics.failed(ctx, 1, err)
+	} else {
+		p.metrics.sent(ctx, messageSize(aws.ToString(input.MessageBody), input.MessageAttributes))
+	}
+
+	return output, err
+}
+
+// SendBatch sends messages using as few SendMessageBatch calls as possible: up to 10
+// messages per call, and never more than the SQS payload limit per call. Every message is
+// validated first; invalid messages are reported as failed without being sent. With
+// WithSpool, messages failing with a transient error are retried in the background and
+// reported as failed with an error wrapping ErrSpooled.
+//
+// Parameters:
+//   - ctx: Context for request cancellation and timeouts
+//   - messages: The messages to send, each with a unique ID
+//
+// Returns:
+//   - *BatchResult: The outcome of every message, successful or failed
+//   - error: The first error of a whole SendMessageBatch call (its messages are also
+//     listed as failed), or nil
+//
+// Example:
+//
+//	result, _ := producer.SendBatch(ctx, messages)
+//	for _, failure := range result.Failed {
+//	    log.Printf("message %s not sent: %v", failure.ID, failure.Err)
+//	}
+func (p *Producer) SendBatch(ctx context.Context, messages []OutgoingMessage) (*BatchResult, error) {
+	result := &BatchResult{}
+
+	var (
+		firstErr error
+		batch    []batchEntry
+		size     int
+	)
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+
+		if err := p.sendBatch(ctx, batch, result); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		batch, size = nil, 0
+	}
+
+	for _, msg := range messages {
+		input := &sqs.SendMessageInput{QueueUrl: aws.String(p.queueURL), MessageBody: aws.String(msg.Body)}
+		for _, opt := range msg.Options {
+			opt(input)
+		}
+		p.client.deduplicate(input)
+
+		if err := validateSendInput(input); err != nil {
+			result.Failed = append(result.Failed, BatchFailure{ID: msg.ID, Err: err})
+			p.metrics.failed(ctx, 1, err)
+			continue
+		}
+
+		entrySize := messageSize(msg.Body, input.MessageAttributes)
+		if len(batch) == _maxBatchEntries || size+entrySize > _maxMessageSizeBytes {
+			flush()
+		}
+
+		batch = append(batch, batchEntry{msg: msg, input: input})
+		size += entrySize
+	}
+	flush()
+
+	return result, firstErr
+}
+
+// batchEntry is a validated message waiting to be sent in a batch.
+type batchEntry struct {
+	msg   OutgoingMessage
+	input *sqs.SendMessageInput
+}
+
+// sendBatch sends up to 10 entries in a single SendMessageBatch call and records the
+// outcome of each one in result.
+func (p *Producer) sendBatch(ctx context.Context, batch []batchEntry, result *BatchResult) error {
+	p.throttle.wait(ctx)
+	// Batch entry IDs only accept a restricted alphabet, so positions are sent instead of
+	// the caller IDs and mapped back afterwards
+	entries := make([]types.SendMessageBatchRequestEntry, len(batch))
+	for i, entry := range batch {
+		entries[i] = types.SendMessageBatchRequestEntry{
+			Id:                     aws.String(strconv.Itoa(i)),
+			MessageBody:            entry.input.MessageBody,
+			MessageAttributes:      entry.input.MessageAttributes,
+			MessageGroupId:         entry.input.MessageGroupId,
+			MessageDeduplicationId: entry.input.MessageDeduplicationId,
+			DelaySeconds:           entry.input.DelaySeconds,
+		}
+	}
+
+	start := time.Now()
+	output, err := p.client.client.SendMessageBatch(ctx, &sqs.SendMessageBatchInput{
+		QueueUrl: aws.String(p.queueURL),
+		Entries:  entries,
+	}, apiOptions(ctx)...)
+	if err != nil {
+		p.throttle.observe(isThrottled(err))
+		p.metrics.call(ctx, _operationNameSendBatch, len(batch), start, middleware.Metadata{}, err)
+		p.metrics.failed(ctx, len(batch), err)
+		for _, entry := range batch {
+			p.fail(entry, err, result)
+		}
+		return err
+	}
+
+	p.metrics.call(ctx, _operationNameSendBatch, len(batch), start, output.ResultMetadata, nil)
+
+	throttled, bytes := false, 0
+	for _, success := range output.Successful {
+		if i, ok := batchIndex(success.Id, len(batch)); ok {
+			input := batch[i].input
+			var checksumErr error
+			if p.client.config.VerifyChecksums {
+				checksumErr = verifySentChecksums(aws.ToString(success.MessageId), input, success.MD5OfMessageBody, success.MD5OfMessageAttributes)
+			}
+			bytes += messageSize(aws.ToString(input.MessageBody), input.MessageAttributes)
+			result.Successful = append(result.Successful, BatchSuccess{ID: batch[i].msg.ID, MessageID: aws.ToString(success.MessageId), Err: checksumErr})
+		}
+	}
+	p.metrics.sent(ctx, bytes)
+
+	for _, failure := range output.Failed {
+		if i, ok := batchIndex(failure.Id, len(batch)); ok {
+			entryErr := &ErrBatchEntry{
+				Code:        aws.ToString(failure.Code),
+				Message:     aws.ToString(failure.Message),
+				SenderFault: failure.SenderFault,
+			}
+			throttled = throttled || isThrottled(entryErr)
+			p.fail(batch[i], entryErr, result)
+			p.metrics.failed(ctx, 1, entryErr)
+		}
+	}
+	p.throttle.observe(throttled)
+
+	return nil
+}
+
+// fail records a message of a batch that SQS didn't accept, spooling it when possible.
+func (p *Producer) fail(entry batchEntry, err error, result *BatchResult) {
+	if p.spool.offer(entry.msg, entry.input, err) {
+		err = fmt.Errorf("%w: %w", ErrSpooled, err)
+	}
+	result.Failed = append(result.Failed, BatchFailure{ID: entry.msg.ID, Err: err})
+}
+
+// batchIndex parses a batch entry ID back into its position in the batch.
+func batchIndex(id *string, size int) (int, bool) {
+	i, err := strconv.Atoi(aws.ToString(id))
+	return i, err == nil && i >= 0 && i < size
+}
@@ -0,0 +1,99 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestProducerSendBatchSplitsIntoBatches(t *testing.T) {
+	fake := &fakeSQS{}
+	producer := NewProducer(newTestSQS(fake), "queue")
+
+	var messages []OutgoingMessage
+	for i := 0; i < 23; i++ {
+		messages = append(messages, OutgoingMessage{ID: fmt.Sprintf("id-%d", i), Body: fmt.Sprintf("body-%d", i)})
+	}
+
+	result, err := producer.SendBatch(context.Background(), messages)
+	if err != nil {
+		t.Fatalf("SendBatch returned error: %v", err)
+	}
+
+	if len(fake.sentBatches) != 3 {
+		t.Errorf("Expected 3 batch calls, got %d", len(fake.sentBatches))
+	}
+
+	if len(result.Successful) != 23 || len(result.Failed) != 0 {
+		t.Fatalf("Expected 23 successful messages, got %d successful and %d failed", len(result.Successful), len(result.Failed))
+	}
+
+	if result.Successful[12].ID != "id-12" || result.Successful[12].MessageID != "sent-body-12" {
+		t.Errorf("Expected results mapped back to caller IDs, got %+v", result.Successful[12])
+	}
+}
+
+func TestProducerSendBatchRespectsPayloadLimit(t *testing.T) {
+	fake := &fakeSQS{}
+	producer := NewProducer(newTestSQS(fake), "queue")
+
+	large := strings.Repeat("x", _maxMessageSizeBytes/2)
+	messages := []OutgoingMessage{{ID: "1", Body: large}, {ID: "2", Body: large}, {ID: "3", Body: large}}
+
+	if _, err := producer.SendBatch(context.Background(), messages); err != nil {
+		t.Fatalf("SendBatch returned error: %v", err)
+	}
+
+	if len(fake.sentBatches) != 2 {
+		t.Errorf("Expected 2 batch calls to stay under the payload limit, got %d", len(fake.sentBatches))
+	}
+}
+
+func TestProducerSendBatchFailures(t *testing.T) {
+	fake := &fakeSQS{failBodies: map[string]bool{"bad": true}}
+	producer := NewProducer(newTestSQS(fake), "queue")
+
+	result, err := producer.SendBatch(context.Background(), []OutgoingMessage{
+		{ID: "ok", Body: "good"},
+		{ID: "rejected", Body: "bad"},
+		{ID: "invalid", Body: strings.Repeat("x", _maxMessageSizeBytes+1)},
+	})
+	if err != nil {
+		t.Fatalf("SendBatch returned error: %v", err)
+	}
+
+	if len(result.Successful) != 1 || result.Successful[0].ID != "ok" {
+		t.Errorf("Expected only 'ok' to succeed, got %+v", result.Successful)
+	}
+
+	failed := map[string]error{}
+	for _, failure := range result.Failed {
+		failed[failure.ID] = failure.Err
+	}
+
+	var entryErr *ErrBatchEntry
+	if !errors.As(failed["rejected"], &entryErr) {
+		t.Errorf("Expected ErrBatchEntry for 'rejected', got %v", failed["rejected"])
+	}
+
+	var tooLarge *ErrMessageTooLarge
+	if !errors.As(failed["invalid"], &tooLarge) {
+		t.Errorf("Expected ErrMessageTooLarge for 'invalid', got %v", failed["invalid"])
+	}
+}
+
+func TestProducerSendBatchCallError(t *testing.T) {
+	fake := &fakeSQS{sendErr: errors.New("unavailable")}
+	producer := NewProducer(newTestSQS(fake), "queue")
+
+	result, err := producer.SendBatch(context.Background(), []OutgoingMessage{{ID: "1", Body: "a"}, {ID: "2", Body: "b"}})
+	if err == nil {
+		t.Error("Expected the call error to be returned")
+	}
+
+	if len(result.Failed) != 2 {
+		t.Errorf("Expected both messages to be reported as failed, got %d", len(result.Failed))
+	}
+}
@@ -0,0 +1,140 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/smithy-go/middleware"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+)
+
+// Producer metrics configuration values
+const (
+	_metricProducerDuration  = "arrakis.producer.duration"   // Histogram of send call durations, in seconds
+	_metricProducerBatchFill = "arrakis.producer.batch.fill" // Histogram of batch entries over the batch limit
+	_metricProducerBytes     = "arrakis.producer.bytes"      // Counter of payload bytes accepted by SQS
+	_metricProducerRetries   = "arrakis.producer.retries"    // Counter of SDK retries of send calls
+	_metricProducerFailures  = "arrakis.producer.failures"   // Counter of messages not sent, by reason
+	_attributeFailureReason  = attribute.Key("arrakis.failure_reason")
+	_operationNameSendBatch  = "SendMessageBatch"
+	_failureInvalid          = "invalid"   // The message failed local validation
+	_failureThrottled        = "throttled" // SQS pushed back (see isThrottled)
+	_failureRejected         = "rejected"  // SQS rejected the batch entry
+	_failureError            = "error"     // Any other error
+)
+
+// WithProducerMetrics records OpenTelemetry metrics for the sends of the producer:
+//
+//   - arrakis.producer.duration: histogram of the SendMessage and SendMessageBatch call
+//     durations, in seconds, with the operation name and an arrakis.outcome attribute
+//   - arrakis.producer.batch.fill: histogram of the entries of every SendMessageBatch call
+//     over the 10-entry limit, showing how well messages are batched
+//   - arrakis.producer.bytes: counter of the payload bytes of the messages SQS accepted
+//   - arrakis.producer.retries: counter of the retries the AWS SDK made for send calls
+//   - arrakis.producer.failures: counter of the messages not sent, with an
+//     arrakis.failure_reason attribute of "invalid", "throttled", "rejected" or "error"
+//
+// All carry the queue name (messaging.destination.name), like the consumer metrics of
+// HandlerMetrics.
+//
+// Parameters:
+//   - provider: The meter provider (nil uses the global provider, a no-op unless one was
+//     registered with otel.SetMeterProvider)
+//
+// Example:
+//
+//	producer := sqs.NewProducer(sqsClient, queueURL, sqs.WithProducerMetrics(meterProvider))
+func WithProducerMetrics(provider metric.MeterProvider) ProducerOption {
+	return WithProducerMetricsSink(OTelSink(provider))
+}
+
+// WithProducerMetricsSink records the metrics of WithProducerMetrics into sink, e.g. a
+// bespoke telemetry system implementing MetricsSink.
+//
+// Parameters:
+//   - sink: The sink receiving the metrics (nil disables them)
+func WithProducerMetricsSink(sink MetricsSink) ProducerOption {
+	return func(c *producerConfig) {
+		c.Metrics = sink
+	}
+}
+
+// producerMetrics records the metrics of a producer. Nil metrics record nothing.
+type producerMetrics struct {
+	sink  MetricsSink
+	queue attribute.KeyValue
+}
+
+// newProducerMetrics creates the metrics of a producer sending to queueURL, or returns nil
+// when there is no sink.
+func newProducerMetrics(sink MetricsSink, queueURL string) *producerMetrics {
+	if sink == nil {
+		return nil
+	}
+
+	return &producerMetrics{sink: sink, queue: semconv.MessagingDestinationName(queueName(queueURL))}
+}
+
+// call records a SendMessage or SendMessageBatch call of entries messages, started at start.
+func (m *producerMetrics) call(ctx context.Context, operation string, entries int, start time.Time, metadata middleware.Metadata, err error) {
+	if m == nil {
+		return
+	}
+
+	outcome := _outcomeSuccess
+	if err != nil {
+		outcome = _outcomeFailure
+	}
+	m.sink.Histogram(ctx, _metricProducerDuration, time.Since(start).Seconds(), m.queue, semconv.MessagingOperationName(operation), _attributeOutcome.String(outcome))
+
+	if operation == _operationNameSendBatch {
+		m.sink.Histogram(ctx, _metricProducerBatchFill, float64(entries)/_maxBatchEntries, m.queue)
+	}
+
+	if attempts, ok := retry.GetAttemptResults(metadata); ok && len(attempts.Results) > 1 {
+		m.sink.Counter(ctx, _metricProducerRetries, int64(len(attempts.Results)-1), m.queue)
+	}
+}
+
+// sent records the payload bytes of messages accepted by SQS.
+func (m *producerMetrics) sent(ctx context.Context, bytes int) {
+	if m == nil || bytes == 0 {
+		return
+	}
+
+	m.sink.Counter(ctx, _metricProducerBytes, int64(bytes), m.queue)
+}
+
+// failed records n messages not sent because of err.
+func (m *producerMetrics) failed(ctx context.Context, n int, err error) {
+	if m == nil || n == 0 {
+		return
+	}
+
+	m.sink.Counter(ctx, _metricProducerFailures, int64(n), m.queue, _attributeFailureReason.String(failureReason(err)))
+}
+
+// failureReason classifies the error of a message that was not sent.
+func failureReason(err error) string {
+	var (
+		tooLarge    *ErrMessageTooLarge
+		invalidAttr *ErrInvalidMessageAttribute
+		invalidFIFO *ErrInvalidFIFOMessage
+		entryErr    *ErrBatchEntry
+	)
+
+	switch {
+	case errors.As(err, &tooLarge), errors.As(err, &invalidAttr), errors.As(err, &invalidFIFO):
+		return _failureInvalid
+	case isThrottled(err):
+		return _failureThrottled
+	case errors.As(err, &entryErr):
+		return _failureRejected
+	default:
+		return _failureError
+	}
+}
@@ -0,0 +1,76 @@
+package sqs
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aws/smithy-go"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestProducerMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	fake := &fakeSQS{failBodies: map[string]bool{"lost": true}}
+	producer := NewProducer(newTestSQS(fake), "https://sqs/123/orders", WithProducerMetrics(provider), WithAdaptiveThrottling(0))
+	ctx := context.Background()
+
+	_, _ = producer.SendBatch(ctx, []OutgoingMessage{
+		{ID: "1", Body: "hello"},
+		{ID: "2", Body: "world"},
+		{ID: "3", Body: "lost"},
+		{ID: "4", Body: strings.Repeat("x", _maxMessageSizeBytes+1)},
+	})
+
+	fake.sendErr = &smithy.GenericAPIError{Code: "RequestThrottled"}
+	_, _ = producer.Send(ctx, "throttled")
+
+	var data metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &data); err != nil {
+		t.Fatalf("Collect returned error: %v", err)
+	}
+
+	failures := map[string]int64{}
+	var bytes int64
+	var fill float64
+	var durations uint64
+	for _, scope := range data.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			switch m.Name {
+			case _metricProducerFailures:
+				for _, point := range m.Data.(metricdata.Sum[int64]).DataPoints {
+					reason, _ := point.Attributes.Value(_attributeFailureReason)
+					failures[reason.AsString()] += point.Value
+				}
+			case _metricProducerBytes:
+				for _, point := range m.Data.(metricdata.Sum[int64]).DataPoints {
+					bytes += point.Value
+				}
+			case _metricProducerBatchFill:
+				for _, point := range m.Data.(metricdata.Histogram[float64]).DataPoints {
+					fill += point.Sum
+				}
+			case _metricProducerDuration:
+				for _, point := range m.Data.(metricdata.Histogram[float64]).DataPoints {
+					durations += point.Count
+				}
+			}
+		}
+	}
+
+	if failures[_failureInvalid] != 1 || failures[_failureThrottled] != 2 {
+		t.Errorf("Expected 1 invalid and 2 throttled failures, got %v", failures)
+	}
+	if bytes != int64(len("hello")+len("world")) {
+		t.Errorf("Expected the bytes of the accepted messages, got %d", bytes)
+	}
+	if fill != 0.3 {
+		t.Errorf("Expected a batch filled at 0.3, got %f", fill)
+	}
+	if durations != 2 {
+		t.Errorf("Expected 2 recorded call durations, got %d", durations)
+	}
+}
@@ -0,0 +1,165 @@
+package sqs
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// Traffic profile configuration
+const (
+	_profileVersion    = 1    // Format version of exported traffic profiles
+	_profileMaxSamples = 1000 // Samples after which a slot average turns into a moving average
+)
+
+// TrafficProfile is the learned traffic pattern of a client, as exported by ExportProfile.
+type TrafficProfile struct {
+	// Version is the format version of the profile.
+	Version int
+	// GeneratedAt is when the profile was exported.
+	GeneratedAt time.Time
+	// Queues holds the profile of every queue, keyed by queue URL.
+	Queues map[string]*QueueProfile
+}
+
+// QueueProfile is the traffic pattern of a queue: the average number of messages per poll
+// for every hour of the week, in UTC.
+type QueueProfile struct {
+	// Slots is indexed by weekday (Sunday = 0) and hour of the day.
+	Slots [7][24]ProfileSlot
+}
+
+// ProfileSlot is the traffic observed during one hour of the week.
+type ProfileSlot struct {
+	// Average is the mean number of messages per poll.
+	Average float64
+	// Samples is the number of polls the average is based on.
+	Samples int64
+}
+
+// record adds a poll observation to the slot of t.
+func (p *QueueProfile) record(t time.Time, messages int) {
+	slot := p.slot(t)
+
+	slot.Samples = min(slot.Samples+1, _profileMaxSamples)
+	slot.Average += (float64(messages) - slot.Average) / float64(slot.Samples)
+}
+
+// merge folds another profile into p, weighting every slot by its number of samples.
+func (p *QueueProfile) merge(other *QueueProfile) {
+	for day := range p.Slots {
+		for hour := range p.Slots[day] {
+			slot, imported := &p.Slots[day][hour], other.Slots[day][hour]
+			if imported.Samples <= 0 {
+				continue
+			}
+
+			total := slot.Samples + imported.Samples
+			slot.Average = (slot.Average*float64(slot.Samples) + imported.Average*float64(imported.Samples)) / float64(total)
+			slot.Samples = min(total, _profileMaxSamples)
+		}
+	}
+}
+
+// slot returns the slot covering t.
+func (p *QueueProfile) slot(t time.Time) *ProfileSlot {
+	t = t.UTC()
+	return &p.Slots[t.Weekday()][t.Hour()]
+}
+
+// ExportProfile returns the traffic profile learned from every queue polled with adaptive
+// polling so far, as JSON. Import it into a new deployment (e.g., the other side of a
+// blue/green switch) with ImportProfile so it starts with informed wait times instead of
+// learning from scratch.
+//
+// Returns:
+//   - []byte: JSON encoded TrafficProfile
+//   - error: Any error that occurred while encoding the profile
+//
+// Example:
+//
+//	profile, err := sqsClient.ExportProfile()
+//	if err == nil {
+//	    os.WriteFile("/var/lib/app/arrakis-profile.json", profile, 0o644)
+//	}
+func (s *SQS) ExportProfile() ([]byte, error) {
+	profile := TrafficProfile{
+		Version:     _profileVersion,
+		GeneratedAt: time.Now(),
+		Queues:      map[string]*QueueProfile{},
+	}
+
+	s.statesMu.RLock()
+	for queueURL, state := range s.states {
+		state.mu.RLock()
+		queueProfile := state.profile
+		state.mu.RUnlock()
+
+		profile.Queues[queueURL] = &queueProfile
+	}
+	s.statesMu.RUnlock()
+
+	return json.Marshal(profile)
+}
+
+// ImportProfile merges a profile exported by ExportProfile into the client. Queues that
+// haven't been polled yet start from the average of the current hour of the week, so the
+// first wait times already match the expected traffic.
+//
+// Parameters:
+//   - data: JSON encoded TrafficProfile
+//
+// Returns:
+//   - error: Any error decoding the profile, or an unsupported profile version
+//
+// Example:
+//
+//	if data, err := os.ReadFile("/var/lib/app/arrakis-profile.json"); err == nil {
+//	    _ = sqsClient.ImportProfile(data)
+//	}
+func (s *SQS) ImportProfile(data []byte) error {
+	var profile TrafficProfile
+	if err := json.Unmarshal(data, &profile); err != nil {
+		return err
+	}
+
+	if profile.Version != _profileVersion {
+		return fmt.Errorf("unsupported traffic profile version %d", profile.Version)
+	}
+
+	now := time.Now()
+	for queueURL, queueProfile := range profile.Queues {
+		if queueProfile != nil {
+			s.state(queueURL).importProfile(queueProfile, now)
+		}
+	}
+
+	return nil
+}
+
+// importProfile merges an imported profile and seeds the average of a queue that has no
+// observations of its own yet.
+//
+// Thread-safe operation using mutex protection.
+func (a *arrakis) importProfile(profile *QueueProfile, now time.Time) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.profile.merge(profile)
+
+	if atomic.LoadInt64(&a.lastUpdate) == 0 && a.average == 0 {
+		a.average = a.profile.slot(now).Average
+		a.observeClass()
+	}
+}
+
+// recordProfile adds a poll observation to the traffic profile of the queue.
+//
+// Thread-safe operation using mutex protection.
+func (a *arrakis) recordProfile(t time.Time, messages int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.profile.record(t, messages)
+}
@@ -0,0 +1,70 @@
+package sqs
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestQueueProfileRecordAndMerge(t *testing.T) {
+	var profile QueueProfile
+	at := time.Date(2024, 3, 4, 9, 30, 0, 0, time.UTC) // Monday
+
+	profile.record(at, 4)
+	profile.record(at, 8)
+
+	slot := profile.Slots[time.Monday][9]
+	if slot.Samples != 2 || slot.Average != 6 {
+		t.Fatalf("Expected 2 samples averaging 6, got %+v", slot)
+	}
+
+	var other QueueProfile
+	other.Slots[time.Monday][9] = ProfileSlot{Average: 12, Samples: 2}
+	profile.merge(&other)
+
+	if slot := profile.Slots[time.Monday][9]; slot.Samples != 4 || slot.Average != 9 {
+		t.Errorf("Expected merged slot with 4 samples averaging 9, got %+v", slot)
+	}
+}
+
+func TestExportImportProfile(t *testing.T) {
+	fake := &fakeSQS{}
+	fake.push(testMessage("m1", ""), testMessage("m2", ""), testMessage("m3", ""))
+	source := newTestSQS(fake)
+	source.EnableArrakis()
+
+	_, _ = source.ReceiveMessage(context.Background(), "queue", 10, nil)
+
+	data, err := source.ExportProfile()
+	if err != nil {
+		t.Fatalf("ExportProfile returned error: %v", err)
+	}
+
+	var profile TrafficProfile
+	if err := json.Unmarshal(data, &profile); err != nil {
+		t.Fatalf("ExportProfile produced invalid JSON: %v", err)
+	}
+
+	if slot := profile.Queues["queue"].slot(time.Now()); slot.Samples != 1 || slot.Average != 3 {
+		t.Errorf("Expected the current slot to hold the poll, got %+v", slot)
+	}
+
+	target := newTestSQS(&fakeSQS{})
+	target.EnableArrakis()
+	if err := target.ImportProfile(data); err != nil {
+		t.Fatalf("ImportProfile returned error: %v", err)
+	}
+
+	if avg := target.CurrentAverage("queue"); avg != 3 {
+		t.Errorf("Expected the imported profile to seed the average, got %f", avg)
+	}
+}
+
+func TestImportProfileRejectsUnknownVersion(t *testing.T) {
+	client := newTestSQS(&fakeSQS{})
+
+	if err := client.ImportProfile([]byte(`{"Version": 99}`)); err == nil {
+		t.Error("Expected an error for an unsupported version")
+	}
+}
@@ -0,0 +1,212 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+)
+
+// Quarantine configuration values
+const (
+	_metricQuarantinedMessages = "arrakis.quarantine.messages" // Counter of quarantined messages by reason
+	_attributeQuarantineReason = attribute.Key("arrakis.quarantine.reason")
+	_quarantineAttributeReason = "arrakis.quarantine.reason"       // Message attribute holding the reason
+	_quarantineAttributeError  = "arrakis.quarantine.error"        // Message attribute holding the failure
+	_quarantineAttributeSource = "arrakis.quarantine.source_queue" // Message attribute holding the source queue
+	_maxQuarantineErrorLength  = 1024                              // Longest failure description kept
+	_quarantineUnknownError    = "unknown"                         // Failure description when the cause has none
+)
+
+// QuarantineReason is why a message was quarantined.
+type QuarantineReason string
+
+// Reasons recorded on quarantined messages.
+const (
+	// QuarantineSchema means the message failed the Validate function of the policy.
+	QuarantineSchema QuarantineReason = "schema"
+	// QuarantineTransform means a transform stage failed, e.g. decryption or decoding.
+	QuarantineTransform QuarantineReason = "transform"
+	// QuarantineSize means the body exceeded MaxBodyBytes.
+	QuarantineSize QuarantineReason = "size"
+	// QuarantineAge means the message was older than MaxAge.
+	QuarantineAge QuarantineReason = "age"
+	// QuarantineHandler means the handler failed and its failure sink quarantines (see
+	// QuarantineSink).
+	QuarantineHandler QuarantineReason = "handler"
+)
+
+// QuarantinePolicy decides which messages a consumer moves to a quarantine queue instead
+// of handling them. Checks left at their zero value are disabled.
+type QuarantinePolicy struct {
+	// QueueURL is the queue receiving quarantined messages.
+	QueueURL string
+	// Validate checks a message after the transform pipeline (e.g., against a JSON schema).
+	// Messages it returns an error for are quarantined.
+	Validate func(Message) error
+	// MaxBodyBytes quarantines messages whose body, as received, is larger.
+	MaxBodyBytes int
+	// MaxAge quarantines messages sent longer ago than this.
+	MaxAge time.Duration
+	// MeterProvider records the arrakis.quarantine.messages counter. Nil uses the global
+	// provider, a no-op unless one was registered with otel.SetMeterProvider.
+	MeterProvider metric.MeterProvider
+	// Metrics records the counter into a bespoke telemetry system instead of MeterProvider
+	// (see MetricsSink).
+	Metrics MetricsSink
+}
+
+// WithQuarantine moves messages that can never be handled out of the way: messages failing
+// the Validate function or a transform stage (see WithTransforms), and messages exceeding
+// the size or age limits, are copied to the quarantine queue and deleted from the source,
+// without reaching the handler. Transform failures are always quarantined; the other
+// checks apply when set in the policy.
+//
+// Copies keep the original body and attributes and carry the failure in three extra
+// message attributes: arrakis.quarantine.reason (see QuarantineReason),
+// arrakis.quarantine.error and arrakis.quarantine.source_queue. Messages that already have
+// more than 7 attributes can't be copied; they stay in the source queue and a warning is
+// logged. Every quarantined message increments the arrakis.quarantine.messages counter,
+// with the queue name and the reason as attributes.
+//
+// Parameters:
+//   - policy: The quarantine queue and the checks to apply
+//
+// Example:
+//
+//	consumer := NewConsumer(client, queueURL, handler,
+//	    WithTransforms(Decrypt(kmsDecrypt)),
+//	    WithQuarantine(QuarantinePolicy{QueueURL: quarantineURL, Validate: orderSchema.Validate, MaxAge: 24 * time.Hour}),
+//	)
+func WithQuarantine(policy QuarantinePolicy) ConsumerOption {
+	return func(c *consumerConfig) {
+		c.Quarantine = &policy
+	}
+}
+
+// schemaError marks a message rejected by the Validate function of a quarantine policy.
+type schemaError struct {
+	err error
+}
+
+func (e *schemaError) Error() string {
+	return fmt.Sprintf("schema validation failed: %v", e.err)
+}
+
+func (e *schemaError) Unwrap() error {
+	return e.err
+}
+
+// quarantine applies a quarantine policy. A nil quarantine quarantines nothing.
+type quarantine struct {
+	policy  QuarantinePolicy
+	metrics MetricsSink
+}
+
+// newQuarantine creates the quarantine of a policy, or nil when there is no policy.
+func newQuarantine(policy *QuarantinePolicy) *quarantine {
+	if policy == nil {
+		return nil
+	}
+
+	metrics := policy.Metrics
+	if metrics == nil {
+		metrics = OTelSink(policy.MeterProvider)
+	}
+
+	return &quarantine{policy: *policy, metrics: metrics}
+}
+
+// check returns why msg must be quarantined before handling, if it must.
+func (q *quarantine) check(msg Message, now time.Time) (QuarantineReason, error) {
+	if q == nil {
+		return "", nil
+	}
+
+	if limit := q.policy.MaxBodyBytes; limit > 0 && len(msg.Body) > limit {
+		return QuarantineSize, fmt.Errorf("body of %d bytes exceeds %d bytes", len(msg.Body), limit)
+	}
+
+	if limit := q.policy.MaxAge; limit > 0 && !msg.SentTimestamp.IsZero() && now.Sub(msg.SentTimestamp) > limit {
+		return QuarantineAge, fmt.Errorf("message age %s exceeds %s", now.Sub(msg.SentTimestamp).Round(time.Second), limit)
+	}
+
+	return "", nil
+}
+
+// validate runs the Validate function of the policy on a transformed message.
+func (q *quarantine) validate(msg Message) error {
+	if q == nil || q.policy.Validate == nil {
+		return nil
+	}
+
+	if err := q.policy.Validate(msg); err != nil {
+		return &schemaError{err: err}
+	}
+
+	return nil
+}
+
+// reason returns the quarantine reason of a handling error, if it calls for quarantine.
+func (q *quarantine) reason(err error) (QuarantineReason, bool) {
+	if q == nil {
+		return "", false
+	}
+
+	var schemaErr *schemaError
+	if errors.As(err, &schemaErr) {
+		return QuarantineSchema, true
+	}
+
+	var transformErr *ErrTransform
+	if errors.As(err, &transformErr) {
+		return QuarantineTransform, true
+	}
+
+	return "", false
+}
+
+// move copies msg, with the failure metadata, to the quarantine queue and deletes it from
+// its source queue. It reports whether the message was moved.
+func (q *quarantine) move(ctx context.Context, source queueSource, msg Message, reason QuarantineReason, cause error) bool {
+	description := _quarantineUnknownError
+	if cause != nil && cause.Error() != "" {
+		description = cause.Error()
+	}
+	if len(description) > _maxQuarantineErrorLength {
+		description = description[:_maxQuarantineErrorLength]
+	}
+
+	quarantined := msg
+	quarantined.MessageAttributes = maps.Clone(msg.MessageAttributes)
+	if quarantined.MessageAttributes == nil {
+		quarantined.MessageAttributes = map[string]types.MessageAttributeValue{}
+	}
+	quarantined.MessageAttributes[_quarantineAttributeReason] = stringAttribute(string(reason))
+	quarantined.MessageAttributes[_quarantineAttributeError] = stringAttribute(description)
+	quarantined.MessageAttributes[_quarantineAttributeSource] = stringAttribute(source.queueURL)
+
+	if err := source.client.moveMessage(ctx, source.queueURL, q.policy.QueueURL, quarantined); err != nil {
+		source.client.logger().Warn("quarantine failed, message will be redelivered", "queue", source.queueURL, "message_id", msg.ID, "reason", string(reason), "error", err)
+		return false
+	}
+
+	q.metrics.Counter(ctx, _metricQuarantinedMessages, 1,
+		semconv.MessagingDestinationName(queueName(source.queueURL)),
+		_attributeQuarantineReason.String(string(reason)),
+	)
+
+	return true
+}
+
+// stringAttribute builds a String message attribute.
+func stringAttribute(value string) types.MessageAttributeValue {
+	return types.MessageAttributeValue{DataType: aws.String(_attributeDataTypeString), StringValue: aws.String(value)}
+}
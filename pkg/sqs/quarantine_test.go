@@ -0,0 +1,92 @@
+package sqs
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestConsumerQuarantine(t *testing.T) {
+	encode := func(body string) string { return base64.StdEncoding.EncodeToString([]byte(body)) }
+
+	fake := &fakeSQS{}
+	valid, invalid, undecodable := testMessage("valid", ""), testMessage("invalid", ""), testMessage("undecodable", "")
+	valid.Body, invalid.Body, undecodable.Body = aws.String(encode("{}")), aws.String(encode("nope")), aws.String("%%%")
+	large := testMessage("large", "")
+	large.Body = aws.String(strings.Repeat("x", 100))
+	old := agedMessage("old", time.Hour)
+	old.Body = aws.String(encode("{}"))
+	fake.push(valid, invalid, undecodable, large, old)
+
+	reader := sdkmetric.NewManualReader()
+	handled := 0
+	consumer := NewConsumer(newTestSQS(fake), "https://sqs/123/orders", HandlerFunc(func(ctx context.Context, msg Message) error {
+		handled++
+		return nil
+	}), WithTransforms(Base64Decode()), WithQuarantine(QuarantinePolicy{
+		QueueURL: "https://sqs/123/quarantine",
+		Validate: func(msg Message) error {
+			if !strings.HasPrefix(msg.Body, "{") {
+				return errors.New("not a JSON object")
+			}
+			return nil
+		},
+		MaxBodyBytes:  50,
+		MaxAge:        time.Minute,
+		MeterProvider: sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)),
+	}))
+	runConsumer(t, consumer, func() bool { return len(fake.deletedHandles()) == 5 })
+
+	if handled != 1 {
+		t.Errorf("Expected only the valid message to be handled, got %d", handled)
+	}
+
+	reasons := map[string]string{}
+	for _, sent := range fake.sentMessages() {
+		if aws.ToString(sent.QueueUrl) != "https://sqs/123/quarantine" {
+			t.Errorf("Expected copies to go to the quarantine queue, got %s", aws.ToString(sent.QueueUrl))
+		}
+		if source := aws.ToString(sent.MessageAttributes[_quarantineAttributeSource].StringValue); source != "https://sqs/123/orders" {
+			t.Errorf("Expected the source queue attribute, got %q", source)
+		}
+		reasons[aws.ToString(sent.MessageBody)] = aws.ToString(sent.MessageAttributes[_quarantineAttributeReason].StringValue)
+	}
+
+	expected := map[string]string{
+		encode("nope"):           string(QuarantineSchema),
+		"%%%":                    string(QuarantineTransform),
+		strings.Repeat("x", 100): string(QuarantineSize),
+		encode("{}"):             string(QuarantineAge),
+	}
+	for body, reason := range expected {
+		if reasons[body] != reason {
+			t.Errorf("Expected reason %q for body %q, got %q", reason, body, reasons[body])
+		}
+	}
+
+	var data metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &data); err != nil {
+		t.Fatalf("Collect returned error: %v", err)
+	}
+
+	var total int64
+	for _, scope := range data.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			if sum, ok := m.Data.(metricdata.Sum[int64]); ok {
+				for _, point := range sum.DataPoints {
+					total += point.Value
+				}
+			}
+		}
+	}
+	if total != 4 {
+		t.Errorf("Expected 4 quarantined messages counted, got %d", total)
+	}
+}
@@ -0,0 +1,156 @@
+package sqs
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+)
+
+// Queue is a client bound to one queue: its methods are the ones of SQS without the
+// queueURL parameter, so code handling several queues can't mix them up. Receives go
+// through the adaptive polling state of the queue, as with the SQS methods.
+//
+// Queues given by name are resolved with GetQueueUrl on first use, and the URL is cached.
+// A Queue is safe for concurrent use.
+type Queue struct {
+	client *SQS
+	name   string
+
+	mu  sync.Mutex
+	url string // Empty until a queue given by name is resolved
+}
+
+// Queue returns a handle bound to a queue.
+//
+// Parameters:
+//   - urlOrName: The queue URL, or the name of a queue of the account and region of the client
+//
+// Returns:
+//   - *Queue: The queue handle
+//
+// Example:
+//
+//	orders := sqsClient.Queue("orders")
+//	_, err := orders.Send(ctx, `{"order":42}`)
+//	messages, err := orders.Receive(ctx, 10)
+//	for _, msg := range messages {
+//	    // Process the message...
+//	    _, err = orders.Delete(ctx, msg.ReceiptHandle)
+//	}
+func (s *SQS) Queue(urlOrName string) *Queue {
+	q := &Queue{client: s, name: queueName(urlOrName)}
+	if strings.HasPrefix(urlOrName, "https://") || strings.HasPrefix(urlOrName, "http://") {
+		q.url = urlOrName
+	}
+
+	return q
+}
+
+// Name returns the name of the queue.
+func (q *Queue) Name() string {
+	return q.name
+}
+
+// URL returns the URL of the queue, resolving its name on first use.
+//
+// Parameters:
+//   - ctx: Context for request cancellation and timeouts
+//
+// Returns:
+//   - string: The queue URL
+//   - error: The GetQueueUrl error, e.g. *types.QueueDoesNotExist
+func (q *Queue) URL(ctx context.Context) (string, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.url != "" {
+		return q.url, nil
+	}
+
+	output, err := q.client.client.GetQueueUrl(ctx, &sqs.GetQueueUrlInput{QueueName: aws.String(q.name)}, apiOptions(ctx)...)
+	if err != nil {
+		return "", err
+	}
+	q.url = aws.ToString(output.QueueUrl)
+
+	return q.url, nil
+}
+
+// Receive receives messages from the queue (see SQS.ReceiveMessages).
+//
+// Parameters:
+//   - ctx: Context for request cancellation and timeouts
+//   - maxMsg: Maximum number of messages to retrieve (1-10). If 0, defaults to the configured
+//     batch size
+//   - options: Optional per-call settings such as WithWaitTimeSeconds
+//
+// Returns:
+//   - []Message: The received messages, possibly none
+//   - error: Any error that occurred during the operation
+func (q *Queue) Receive(ctx context.Context, maxMsg int32, options ...ReceiveOption) ([]Message, error) {
+	queueURL, err := q.URL(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.client.ReceiveMessages(ctx, queueURL, maxMsg, options...)
+}
+
+// Delete deletes a received message from the queue (see SQS.DeleteMessage).
+//
+// Parameters:
+//   - ctx: Context for request cancellation and timeouts
+//   - receiptHandle: The receipt handle of the message
+//
+// Returns:
+//   - *sqs.DeleteMessageOutput: The SQS response
+//   - error: Any error that occurred during the operation
+func (q *Queue) Delete(ctx context.Context, receiptHandle string) (*sqs.DeleteMessageOutput, error) {
+	queueURL, err := q.URL(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.client.DeleteMessage(ctx, queueURL, receiptHandle)
+}
+
+// Send sends a message to the queue (see SQS.SendMessage).
+//
+// Parameters:
+//   - ctx: Context for request cancellation and timeouts
+//   - body: The message payload
+//   - options: Optional settings such as attributes or the FIFO group ID
+//
+// Returns:
+//   - *sqs.SendMessageOutput: The SQS response containing the message ID
+//   - error: Any error that occurred during the operation
+func (q *Queue) Send(ctx context.Context, body string, options ...SendOption) (*sqs.SendMessageOutput, error) {
+	queueURL, err := q.URL(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.client.SendMessage(ctx, queueURL, body, options...)
+}
+
+// Consumer creates a consumer of the queue (see NewConsumer).
+//
+// Parameters:
+//   - ctx: Context for resolving the queue URL
+//   - handler: The message handler
+//   - options: Consumer options
+//
+// Returns:
+//   - *Consumer: The consumer, ready to Start
+//   - error: The error resolving the queue URL
+func (q *Queue) Consumer(ctx context.Context, handler Handler, options ...ConsumerOption) (*Consumer, error) {
+	queueURL, err := q.URL(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewConsumer(q.client, queueURL, handler, options...), nil
+}
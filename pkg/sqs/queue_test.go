@@ -0,0 +1,63 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+const _testOrdersURL = "https://sqs.us-east-1.amazonaws.com/123456789012/orders"
+
+func TestQueueResolvesNameOnce(t *testing.T) {
+	fake := &fakeSQS{queueURLs: map[string]string{"orders": _testOrdersURL}}
+	fake.push(testMessage("m1", ""))
+	orders := newTestSQS(fake).Queue("orders")
+
+	if _, err := orders.Send(context.Background(), "hello"); err != nil {
+		t.Fatalf("Send returned error: %v", err)
+	}
+
+	messages, err := orders.Receive(context.Background(), 0)
+	if err != nil || len(messages) != 1 || messages[0].QueueURL != _testOrdersURL {
+		t.Fatalf("Expected the message of the resolved queue, got %v and %v", messages, err)
+	}
+
+	if _, err := orders.Delete(context.Background(), messages[0].ReceiptHandle); err != nil {
+		t.Fatalf("Delete returned error: %v", err)
+	}
+
+	if got := aws.ToString(fake.sentMessages()[0].QueueUrl); got != _testOrdersURL {
+		t.Errorf("Expected the message sent to the resolved queue, got %s", got)
+	}
+	if fake.urlLookups != 1 {
+		t.Errorf("Expected the queue URL resolved once, got %d lookups", fake.urlLookups)
+	}
+}
+
+func TestQueueByURL(t *testing.T) {
+	fake := &fakeSQS{}
+	orders := newTestSQS(fake).Queue(_testOrdersURL)
+
+	if name := orders.Name(); name != "orders" {
+		t.Errorf("Expected the name from the URL, got %s", name)
+	}
+
+	if queueURL, err := orders.URL(context.Background()); err != nil || queueURL != _testOrdersURL {
+		t.Errorf("Expected the URL as given, got %s and %v", queueURL, err)
+	}
+	if fake.urlLookups != 0 {
+		t.Errorf("Expected no lookup for a queue given by URL, got %d", fake.urlLookups)
+	}
+}
+
+func TestQueueUnknownName(t *testing.T) {
+	orders := newTestSQS(&fakeSQS{}).Queue("orders")
+
+	var missing *types.QueueDoesNotExist
+	if _, err := orders.Receive(context.Background(), 0); !errors.As(err, &missing) {
+		t.Errorf("Expected QueueDoesNotExist, got %v", err)
+	}
+}
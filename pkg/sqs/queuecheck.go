@@ -0,0 +1,137 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// ErrQueueValidation is returned by ValidateQueue, and by Consumer.Start with
+// WithValidateQueues, when a queue doesn't exist, can't be read or is misconfigured.
+type ErrQueueValidation struct {
+	// QueueURL is the queue that failed validation.
+	QueueURL string
+	// Problems describe what is wrong with the queue, one entry per failed check.
+	Problems []string
+	// Err is the error of the GetQueueAttributes call, when the queue couldn't be read.
+	Err error
+}
+
+func (e *ErrQueueValidation) Error() string {
+	return fmt.Sprintf("queue %s failed validation: %s", e.QueueURL, strings.Join(e.Problems, "; "))
+}
+
+func (e *ErrQueueValidation) Unwrap() error {
+	return e.Err
+}
+
+// WithValidateQueues makes Start check the queues of the consumer before polling (see
+// SQS.ValidateQueue) and return an *ErrQueueValidation instead of polling a queue that
+// doesn't exist or is misconfigured. The queue, the failover secondary and the priority
+// levels are checked, along with any extra queue given, such as a dead-letter queue.
+//
+// Parameters:
+//   - queueURLs: Extra queues to check with the client of the consumer
+//
+// Example:
+//
+//	consumer := NewConsumer(client, queueURL, handler, WithValidateQueues(dlqURL))
+//	if err := consumer.Start(ctx); err != nil {
+//	    log.Fatal(err) // e.g. queue .../orders failed validation: queue does not exist
+//	}
+func WithValidateQueues(queueURLs ...string) ConsumerOption {
+	return func(c *consumerConfig) {
+		c.ValidateQueues = true
+		c.ValidatedQueues = append(c.ValidatedQueues, queueURLs...)
+	}
+}
+
+// ValidateQueue checks that a queue exists, is accessible with the credentials of the
+// client and has sane attributes: a visibility timeout at least as long as the one the
+// client receives with, and a FIFO flag matching the queue URL (FIFO handling follows
+// the ".fifo" suffix). It costs one GetQueueAttributes call.
+//
+// Parameters:
+//   - ctx: Context for request cancellation and timeouts
+//   - queueURL: The URL of the queue to check
+//
+// Returns:
+//   - error: *ErrQueueValidation listing every failed check, or nil
+//
+// Example:
+//
+//	if err := sqsClient.ValidateQueue(ctx, queueURL); err != nil {
+//	    log.Fatal(err)
+//	}
+func (s *SQS) ValidateQueue(ctx context.Context, queueURL string) error {
+	output, err := s.client.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{
+		QueueUrl: aws.String(queueURL),
+		AttributeNames: []types.QueueAttributeName{
+			types.QueueAttributeNameVisibilityTimeout,
+			types.QueueAttributeNameFifoQueue,
+		},
+	}, apiOptions(ctx)...)
+	if err != nil {
+		problem := "queue is not accessible: " + err.Error()
+		var missing *types.QueueDoesNotExist
+		if errors.As(err, &missing) {
+			problem = "queue does not exist"
+		}
+		return &ErrQueueValidation{QueueURL: queueURL, Problems: []string{problem}, Err: err}
+	}
+
+	var problems []string
+
+	if value, ok := output.Attributes[string(types.QueueAttributeNameVisibilityTimeout)]; ok {
+		visibility, _ := strconv.Atoi(value)
+		if configured := s.config.visibilityTimeout(); visibility < configured {
+			problems = append(problems, fmt.Sprintf("visibility timeout of %ds is shorter than the configured %ds", visibility, configured))
+		}
+	}
+
+	switch fifo := output.Attributes[string(types.QueueAttributeNameFifoQueue)] == "true"; {
+	case fifo && !isFIFOQueue(queueURL):
+		problems = append(problems, "queue is FIFO but its URL lacks the .fifo suffix, so messages would be handled out of order")
+	case !fifo && isFIFOQueue(queueURL):
+		problems = append(problems, "queue URL ends in .fifo but the queue is not FIFO")
+	}
+
+	if len(problems) > 0 {
+		return &ErrQueueValidation{QueueURL: queueURL, Problems: problems}
+	}
+
+	return nil
+}
+
+// validateQueues checks the queues of the consumer when WithValidateQueues is set.
+func (c *Consumer) validateQueues(ctx context.Context) error {
+	if !c.config.ValidateQueues {
+		return nil
+	}
+
+	sources := []queueSource{c.primary()}
+	if c.config.Failover != nil {
+		sources = append(sources, c.secondary)
+	}
+	if c.priorities != nil {
+		sources = append(sources, c.priorities.levels[1:]...)
+	}
+	for _, queueURL := range c.config.ValidatedQueues {
+		sources = append(sources, queueSource{client: c.client, queueURL: queueURL})
+	}
+
+	var errs []error
+	for _, source := range sources {
+		if err := source.client.ValidateQueue(ctx, source.queueURL); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
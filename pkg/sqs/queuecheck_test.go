@@ -0,0 +1,51 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+func TestValidateQueue(t *testing.T) {
+	fake := &fakeSQS{queueAttrs: map[string]string{"VisibilityTimeout": "30", "FifoQueue": "false"}}
+	client := newTestSQS(fake, WithVisibilityTimeout(30))
+
+	if err := client.ValidateQueue(context.Background(), "orders"); err != nil {
+		t.Fatalf("Expected a sane queue to pass, got %v", err)
+	}
+
+	var validation *ErrQueueValidation
+	err := client.ValidateQueue(context.Background(), "orders.fifo")
+	if !errors.As(err, &validation) || len(validation.Problems) != 1 || !strings.Contains(err.Error(), "not FIFO") {
+		t.Errorf("Expected a FIFO mismatch, got %v", err)
+	}
+
+	fake.queueAttrs["VisibilityTimeout"] = "10"
+	if err := client.ValidateQueue(context.Background(), "orders"); err == nil || !strings.Contains(err.Error(), "shorter than the configured 30s") {
+		t.Errorf("Expected a short visibility timeout to be reported, got %v", err)
+	}
+
+	fake.attributesErr = &types.QueueDoesNotExist{}
+	err = client.ValidateQueue(context.Background(), "orders")
+	if !errors.As(err, &validation) || validation.Problems[0] != "queue does not exist" {
+		t.Errorf("Expected a missing queue to be reported, got %v", err)
+	}
+}
+
+func TestConsumerValidatesQueuesOnStart(t *testing.T) {
+	fake := &fakeSQS{attributesErr: &types.QueueDoesNotExist{}}
+	consumer := NewConsumer(newTestSQS(fake), "orders", HandlerFunc(func(ctx context.Context, msg Message) error {
+		return nil
+	}), WithValidateQueues("orders-dlq"))
+
+	err := consumer.Start(context.Background())
+	if !strings.Contains(err.Error(), "queue orders failed validation") || !strings.Contains(err.Error(), "queue orders-dlq failed validation") {
+		t.Errorf("Expected Start to fail with both queues, got %v", err)
+	}
+	if len(fake.receiveInputs) != 0 {
+		t.Error("Expected no receive before validation passes")
+	}
+}
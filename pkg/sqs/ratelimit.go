@@ -0,0 +1,125 @@
+package sqs
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Rate limiting configuration values
+const (
+	_minRateLimitWait = 10 * time.Millisecond // Shortest pause before asking a rate limiter again
+)
+
+// RateLimiter hands out permits for downstream calls, one per message handled. Sharing a
+// RateLimiter backed by a common store (see pkg/adapters/redisratelimit) lets a fleet of
+// consumers enforce a single call budget, e.g. 100 requests per second to a partner API.
+type RateLimiter interface {
+	// Take takes up to n permits. It returns how many were granted and, when none was,
+	// how long to wait before asking again.
+	Take(ctx context.Context, n int) (granted int, wait time.Duration, err error)
+}
+
+// WithRateLimiter paces the consumer with a rate limiter: before every ReceiveMessage call
+// the consumer takes as many permits as it would request messages, then asks SQS for no
+// more messages than it was granted. Polling therefore slows down to the rate the limiter
+// allows instead of receiving messages it can't handle yet.
+//
+// Permits not matched by a message (the queue returned fewer) are not given back. When the
+// limiter fails, the consumer logs a warning and waits a second before trying again.
+//
+// Parameters:
+//   - limiter: The rate limiter, such as NewTokenBucket or a shared Redis limiter
+//
+// Example:
+//
+//	// 100 calls per second to the partner API, shared by every replica
+//	limiter := redisratelimit.New(redisClient, "partner-api", 100, 100)
+//	consumer := NewConsumer(client, queueURL, handler, WithRateLimiter(limiter))
+func WithRateLimiter(limiter RateLimiter) ConsumerOption {
+	return func(c *consumerConfig) {
+		c.RateLimiter = limiter
+	}
+}
+
+// TokenBucket is an in-process RateLimiter refilling rate permits per second, up to burst.
+// It limits a single consumer, or several consumers of the same process sharing it.
+type TokenBucket struct {
+	rate  float64
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// Compile-time check of the RateLimiter interface
+var _ RateLimiter = (*TokenBucket)(nil)
+
+// NewTokenBucket creates a full token bucket.
+//
+// Parameters:
+//   - rate: Permits added per second
+//   - burst: Maximum number of permits available at once (at least 1)
+//
+// Returns:
+//   - *TokenBucket: The rate limiter
+//
+// Example:
+//
+//	consumer := NewConsumer(client, queueURL, handler, WithRateLimiter(NewTokenBucket(50, 10)))
+func NewTokenBucket(rate float64, burst int) *TokenBucket {
+	burst = max(burst, 1)
+
+	return &TokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// Take takes up to n permits from the bucket.
+func (b *TokenBucket) Take(ctx context.Context, n int) (int, time.Duration, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+
+	granted := min(n, int(math.Floor(b.tokens)))
+	if granted > 0 {
+		b.tokens -= float64(granted)
+		return granted, 0, nil
+	}
+
+	if b.rate <= 0 {
+		return 0, time.Second, nil
+	}
+
+	return 0, time.Duration((1 - b.tokens) / b.rate * float64(time.Second)), nil
+}
+
+// takePermits takes up to want permits from the rate limiter of the consumer, waiting
+// until at least one is granted. It returns 0 if ctx is done first.
+func (c *Consumer) takePermits(ctx context.Context, want int) int {
+	if c.config.RateLimiter == nil {
+		return want
+	}
+
+	for ctx.Err() == nil {
+		granted, wait, err := c.config.RateLimiter.Take(ctx, want)
+		if err != nil {
+			if ctx.Err() == nil {
+				c.client.logger().Warn("rate limiter failed", "queue", c.queueURL, "error", err)
+			}
+			sleep(ctx, _consumerReceiveErrorBackoff)
+			continue
+		}
+
+		if granted > 0 {
+			return min(granted, want)
+		}
+
+		sleep(ctx, max(wait, _minRateLimitWait))
+	}
+
+	return 0
+}
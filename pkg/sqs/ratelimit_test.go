@@ -0,0 +1,73 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// scriptedLimiter grants permits from a list of answers, then one permit per call.
+type scriptedLimiter struct {
+	mu      sync.Mutex
+	answers []error
+	asked   []int
+}
+
+func (l *scriptedLimiter) Take(ctx context.Context, n int) (int, time.Duration, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.asked = append(l.asked, n)
+	if len(l.answers) > 0 {
+		err := l.answers[0]
+		l.answers = l.answers[1:]
+		return 0, time.Millisecond, err
+	}
+
+	return 1, 0, nil
+}
+
+func TestTokenBucket(t *testing.T) {
+	bucket := NewTokenBucket(1, 3)
+
+	if granted, _, _ := bucket.Take(context.Background(), 2); granted != 2 {
+		t.Errorf("Expected 2 permits from a full bucket, got %d", granted)
+	}
+	if granted, _, _ := bucket.Take(context.Background(), 5); granted != 1 {
+		t.Errorf("Expected the remaining permit, got %d", granted)
+	}
+
+	granted, wait, _ := bucket.Take(context.Background(), 1)
+	if granted != 0 || wait <= 0 || wait > time.Second {
+		t.Errorf("Expected no permit and a wait of at most a second, got %d and %s", granted, wait)
+	}
+}
+
+func TestConsumerRateLimiter(t *testing.T) {
+	fake := &fakeSQS{}
+	fake.push(testMessage("m1", ""), testMessage("m2", ""), testMessage("m3", ""))
+
+	// Nothing granted, then a failure, then one permit per call
+	limiter := &scriptedLimiter{answers: []error{nil, errors.New("redis unavailable")}}
+	consumer := NewConsumer(newTestSQS(fake), "queue", HandlerFunc(func(ctx context.Context, msg Message) error {
+		return nil
+	}), WithRateLimiter(limiter))
+	consumer.config.MaxMessages = 10
+
+	runConsumer(t, consumer, func() bool {
+		return len(fake.deletedHandles()) == 3
+	})
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	for _, input := range fake.receiveInputs {
+		if input.MaxNumberOfMessages != 1 {
+			t.Fatalf("Expected receives limited to the granted permits, got %d", input.MaxNumberOfMessages)
+		}
+	}
+	if limiter.asked[0] != 10 {
+		t.Errorf("Expected permits requested for a full batch, got %d", limiter.asked[0])
+	}
+}
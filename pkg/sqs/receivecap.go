@@ -0,0 +1,63 @@
+package sqs
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+)
+
+// WithMaxReceiveCallsPerSecond enforces a hard client-side cap on the ReceiveMessage calls
+// of the client, whatever their origin: adaptive receives, consumers, TryReceive, drains,
+// peeks and moves. Calls over the cap wait for a token of a bucket refilling n tokens per
+// second and holding one, so calls are spread evenly rather than bursting. It is a safety
+// net against misconfigurations causing API call storms, e.g. back-to-back polling of an
+// empty queue by many workers, independent of the adaptive polling algorithm.
+//
+// Parameters:
+//   - n: Maximum number of ReceiveMessage calls per second, across every queue of the
+//     client (zero or less removes the cap)
+//
+// Example:
+//
+//	client := sqs.NewSQSWithOptions(&cfg, sqs.WithMaxReceiveCallsPerSecond(20))
+func WithMaxReceiveCallsPerSecond(n float64) Option {
+	return func(c *config) {
+		if n <= 0 {
+			c.ReceiveCallLimit = nil
+			return
+		}
+		c.ReceiveCallLimit = NewTokenBucket(n, 1)
+	}
+}
+
+// receiveMessage performs a ReceiveMessage call once the receive call cap, if any, allows
+// it.
+func (s *SQS) receiveMessage(ctx context.Context, input *sqs.ReceiveMessageInput) (*sqs.ReceiveMessageOutput, error) {
+	if err := s.waitReceiveCall(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.client.ReceiveMessage(ctx, input, apiOptions(ctx)...)
+}
+
+// waitReceiveCall waits for the receive call cap to allow one more call, or for ctx to be
+// done.
+func (s *SQS) waitReceiveCall(ctx context.Context) error {
+	limit := s.config.ReceiveCallLimit
+	if limit == nil {
+		return nil
+	}
+
+	for {
+		// TokenBucket never fails
+		granted, wait, _ := limit.Take(ctx, 1)
+		if granted > 0 {
+			return nil
+		}
+
+		sleep(ctx, wait)
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMaxReceiveCallsPerSecond(t *testing.T) {
+	fake := &fakeSQS{}
+	client := newTestSQS(fake, WithMaxReceiveCallsPerSecond(20))
+	ctx := context.Background()
+
+	start := time.Now()
+	for range 5 {
+		if _, err := client.TryReceive(ctx, "queue", 0); err != nil {
+			t.Fatalf("TryReceive returned error: %v", err)
+		}
+	}
+
+	// The first call takes the token of the full bucket, the next ones wait 50ms each
+	if elapsed := time.Since(start); elapsed < 180*time.Millisecond {
+		t.Errorf("Expected 5 calls at 20 per second to take about 200ms, took %v", elapsed)
+	}
+	if got := len(fake.receiveInputs); got != 5 {
+		t.Errorf("Expected 5 receives, got %d", got)
+	}
+}
+
+func TestMaxReceiveCallsPerSecondCancelled(t *testing.T) {
+	fake := &fakeSQS{}
+	client := newTestSQS(fake, WithMaxReceiveCallsPerSecond(0.1))
+
+	if _, err := client.TryReceive(context.Background(), "queue", 0); err != nil {
+		t.Fatalf("TryReceive returned error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := client.TryReceive(ctx, "queue", 0); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the capped receive to give up with its context, got %v", err)
+	}
+	if got := len(fake.receiveInputs); got != 1 {
+		t.Errorf("Expected the capped receive not to reach SQS, got %d receives", got)
+	}
+}
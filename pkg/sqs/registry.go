@@ -0,0 +1,93 @@
+package sqs
+
+import (
+	"context"
+	"fmt"
+)
+
+// ErrDecodePayload is returned by a TypeRegistry when a message body can't be decoded
+// into the Go type registered for its type attribute.
+type ErrDecodePayload struct {
+	// Type is the value of the type attribute of the message.
+	Type string
+	// Err is the underlying decoding error.
+	Err error
+}
+
+func (e *ErrDecodePayload) Error() string {
+	return fmt.Sprintf("decoding %q payload: %v", e.Type, e.Err)
+}
+
+func (e *ErrDecodePayload) Unwrap() error {
+	return e.Err
+}
+
+// TypeRegistry maps the values of a type attribute to Go types, decodes message bodies
+// (with the codec of the consumer, JSON by default) into the registered type and calls
+// the typed handler for it. A TypeRegistry is a Handler and can be passed to NewConsumer.
+//
+// Messages with an unregistered type go to the sink set with Unknown; without a sink they
+// fail with *ErrNoRoute and stay in the queue.
+type TypeRegistry struct {
+	router *Router
+}
+
+// NewTypeRegistry creates an empty registry dispatching on the given message attribute.
+//
+// Parameters:
+//   - attribute: Name of the message attribute holding the payload type (e.g., "type")
+//
+// Returns:
+//   - *TypeRegistry: A registry without types; register them with Register
+//
+// Example:
+//
+//	registry := sqs.NewTypeRegistry("type")
+//	sqs.Register(registry, "order.created", func(ctx context.Context, order OrderCreated, msg sqs.Message) error {
+//	    return orders.Create(ctx, order)
+//	})
+//	registry.Unknown(sqs.HandlerFunc(sendToAuditQueue))
+//
+//	consumer := sqs.NewConsumer(sqsClient, queueURL, registry)
+func NewTypeRegistry(attribute string) *TypeRegistry {
+	return &TypeRegistry{router: NewRouter(attribute)}
+}
+
+// Register associates the Go type T with a value of the registry type attribute. Bodies of
+// matching messages are decoded into a T (see Message.Bind) before handler is called; a
+// body that can't be decoded fails with *ErrDecodePayload without calling handler.
+//
+// Parameters:
+//   - registry: The registry to add the type to
+//   - typeName: Value of the type attribute identifying T (e.g., "order.created")
+//   - handler: Typed handler receiving the decoded payload and the original message
+//
+// Example:
+//
+//	sqs.Register(registry, "order.created", func(ctx context.Context, order OrderCreated, msg sqs.Message) error {
+//	    log.Printf("order %s created", order.ID)
+//	    return nil
+//	})
+func Register[T any](registry *TypeRegistry, typeName string, handler func(ctx context.Context, payload T, msg Message) error) {
+	registry.router.RouteFunc(typeName, func(ctx context.Context, msg Message) error {
+		payload, err := Body[T](msg)
+		if err != nil {
+			return &ErrDecodePayload{Type: typeName, Err: err}
+		}
+
+		return handler(ctx, payload, msg)
+	})
+}
+
+// Unknown sets the sink for messages whose type isn't registered, including messages
+// without the type attribute. The sink decides what happens to them: returning nil
+// deletes the message, returning an error leaves it in the queue.
+func (r *TypeRegistry) Unknown(sink Handler) {
+	r.router.Default(sink)
+}
+
+// Handle decodes msg into the type registered for its type attribute and calls the
+// matching typed handler.
+func (r *TypeRegistry) Handle(ctx context.Context, msg Message) error {
+	return r.router.Handle(ctx, msg)
+}
@@ -0,0 +1,78 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type orderCreated struct {
+	ID    string `json:"id"`
+	Total int    `json:"total"`
+}
+
+func TestRegistryDecodesRegisteredType(t *testing.T) {
+	var got orderCreated
+	registry := NewTypeRegistry("type")
+	Register(registry, "order.created", func(ctx context.Context, order orderCreated, msg Message) error {
+		got = order
+		return nil
+	})
+
+	msg := routedMessage("m1", "order.created")
+	msg.Body = `{"id":"o-1","total":42}`
+
+	if err := registry.Handle(context.Background(), msg); err != nil {
+		t.Fatalf("Handle returned error: %v", err)
+	}
+
+	if got.ID != "o-1" || got.Total != 42 {
+		t.Errorf("Expected decoded payload, got %+v", got)
+	}
+}
+
+func TestRegistryDecodeError(t *testing.T) {
+	called := false
+	registry := NewTypeRegistry("type")
+	Register(registry, "order.created", func(ctx context.Context, order orderCreated, msg Message) error {
+		called = true
+		return nil
+	})
+
+	msg := routedMessage("m1", "order.created")
+	msg.Body = `not json`
+
+	err := registry.Handle(context.Background(), msg)
+
+	var decodeErr *ErrDecodePayload
+	if !errors.As(err, &decodeErr) || decodeErr.Type != "order.created" {
+		t.Errorf("Expected ErrDecodePayload, got %v", err)
+	}
+
+	if called {
+		t.Error("Expected handler not to be called for an undecodable body")
+	}
+}
+
+func TestRegistryUnknownType(t *testing.T) {
+	registry := NewTypeRegistry("type")
+
+	var noRoute *ErrNoRoute
+	if err := registry.Handle(context.Background(), routedMessage("m1", "other")); !errors.As(err, &noRoute) {
+		t.Errorf("Expected ErrNoRoute without a sink, got %v", err)
+	}
+
+	var sunk []string
+	registry.Unknown(HandlerFunc(func(ctx context.Context, msg Message) error {
+		sunk = append(sunk, msg.ID)
+		return nil
+	}))
+
+	if err := registry.Handle(context.Background(), routedMessage("m2", "other")); err != nil {
+		t.Errorf("Expected the sink to accept the message, got %v", err)
+	}
+
+	if len(sunk) != 1 || sunk[0] != "m2" {
+		t.Errorf("Expected unknown message on the sink, got %v", sunk)
+	}
+}
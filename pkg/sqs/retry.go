@@ -0,0 +1,88 @@
+package sqs
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// RetryPolicy describes how the consumer handles failed deliveries of a message type.
+type RetryPolicy struct {
+	// MaxAttempts is the number of deliveries after which a failed message is given up on
+	// and moved to DeadLetterQueueURL. Zero retries until the redrive policy of the queue,
+	// if any, takes over.
+	MaxAttempts int
+	// Backoff decides how long a message stays invisible after a failed delivery (see
+	// NackStrategy). Nil keeps the message invisible for the rest of its visibility timeout.
+	Backoff NackStrategy
+	// DeadLetterQueueURL receives the messages that failed MaxAttempts times. Empty leaves
+	// them in the queue, for its redrive policy.
+	DeadLetterQueueURL string
+}
+
+// WithRetryPolicies sets retry policies per message type, the type of a message being the
+// value of one of its message attributes. A queue carrying both idempotent and
+// non-idempotent events can then retry the former with backoff and dead-letter the latter
+// at their first failure.
+//
+// A policy applies to every handler error of its message type, retryable or not. Messages
+// whose type has no policy keep the default behavior (see WithNackBackoff).
+//
+// Parameters:
+//   - attribute: Name of the message attribute holding the message type (e.g., "type")
+//   - policies: Retry policy of each message type
+//
+// Example:
+//
+//	consumer := NewConsumer(client, queueURL, router, WithRetryPolicies("type", map[string]RetryPolicy{
+//	    "order.created": {MaxAttempts: 5, Backoff: ExponentialNack(time.Second, time.Minute, 0.5), DeadLetterQueueURL: dlqURL},
+//	    "payment.charged": {MaxAttempts: 1, DeadLetterQueueURL: dlqURL},
+//	}))
+func WithRetryPolicies(attribute string, policies map[string]RetryPolicy) ConsumerOption {
+	return func(c *consumerConfig) {
+		c.RetryAttribute = attribute
+		c.RetryPolicies = policies
+	}
+}
+
+// retryPolicy returns the retry policy of the type of msg, if one is registered.
+func (c *Consumer) retryPolicy(msg Message) (RetryPolicy, bool) {
+	if c.config.RetryPolicies == nil {
+		return RetryPolicy{}, false
+	}
+
+	value, ok := msg.MessageAttributes[c.config.RetryAttribute]
+	if !ok {
+		return RetryPolicy{}, false
+	}
+
+	policy, ok := c.config.RetryPolicies[aws.ToString(value.StringValue)]
+	return policy, ok
+}
+
+// retry applies a retry policy to a failed message: it is moved to the dead-letter queue
+// once out of attempts, and otherwise made visible again after the backoff delay. It
+// reports whether the message was dead-lettered.
+func (c *Consumer) retry(ctx context.Context, msg Message, policy RetryPolicy) bool {
+	source := c.sourceOf(msg)
+	attempt := max(msg.ReceiveCount, 1)
+
+	if policy.MaxAttempts > 0 && attempt >= policy.MaxAttempts {
+		if policy.DeadLetterQueueURL == "" {
+			return false
+		}
+
+		if err := source.client.moveMessage(ctx, source.queueURL, policy.DeadLetterQueueURL, msg); err != nil {
+			source.client.logger().Warn("dead-lettering failed, message will be redelivered", "queue", source.queueURL, "message_id", msg.ID, "error", err)
+			return false
+		}
+		return true
+	}
+
+	if policy.Backoff != nil {
+		delay := policy.Backoff.Delay(attempt)
+		_, _ = source.client.ChangeMessageVisibility(ctx, source.queueURL, msg.ReceiptHandle, visibilityDelay(delay))
+	}
+
+	return false
+}
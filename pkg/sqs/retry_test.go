@@ -0,0 +1,60 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// typedMessage builds an SDK message of the given type, delivered receiveCount times.
+func typedMessage(id, messageType, receiveCount string) types.Message {
+	m := testMessage(id, "")
+	m.Attributes[_attributeApproximateReceiveCount] = receiveCount
+	m.MessageAttributes = map[string]types.MessageAttributeValue{
+		"type": {DataType: aws.String("String"), StringValue: aws.String(messageType)},
+	}
+
+	return m
+}
+
+func TestConsumerRetryPolicies(t *testing.T) {
+	fake := &fakeSQS{}
+	fake.push(
+		typedMessage("retried", "order.created", "2"),
+		typedMessage("exhausted", "order.created", "3"),
+		typedMessage("once", "payment.charged", "1"),
+		typedMessage("untyped", "other", "1"),
+	)
+	client := newTestSQS(fake)
+
+	handled := make(chan string, 4)
+	handler := HandlerFunc(func(ctx context.Context, msg Message) error {
+		handled <- msg.ID
+		return errors.New("failed")
+	})
+
+	consumer := NewConsumer(client, "queue", handler, WithRetryPolicies("type", map[string]RetryPolicy{
+		"order.created":   {MaxAttempts: 3, Backoff: FixedNack(30 * time.Second), DeadLetterQueueURL: "orders-dlq"},
+		"payment.charged": {MaxAttempts: 1, DeadLetterQueueURL: "payments-dlq"},
+	}))
+	runConsumer(t, consumer, func() bool { return len(handled) == 4 && len(fake.deletedHandles()) == 2 })
+
+	if delay, ok := fake.visibilityOf("retried"); !ok || delay != 30 {
+		t.Errorf("Expected the retried message to back off 30s, got %d (%v)", delay, ok)
+	}
+	if _, ok := fake.visibilityOf("untyped"); ok {
+		t.Error("Expected messages without a policy to keep the default behavior")
+	}
+
+	destinations := map[string]string{}
+	for _, sent := range fake.sentMessages() {
+		destinations[aws.ToString(sent.MessageBody)] = aws.ToString(sent.QueueUrl)
+	}
+	if destinations["exhausted"] != "orders-dlq" || destinations["once"] != "payments-dlq" || len(destinations) != 2 {
+		t.Errorf("Expected exhausted messages to be dead-lettered, got %v", destinations)
+	}
+}
@@ -0,0 +1,130 @@
+package sqs
+
+import (
+	"context"
+	"maps"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// Retry queue configuration values
+const (
+	_retryAttemptAttribute = "arrakis.retry.attempt" // Message attribute holding the failed attempts of a message
+	_maxDelaySeconds       = 900                     // SQS maximum message delay (15 minutes)
+	_defaultRetryQueueBase = 30 * time.Second        // Delay of the first retry when no backoff is set
+)
+
+// RetryQueuePolicy is a retry topology: failed messages are re-sent to dedicated retry
+// queues with a delay, instead of waiting in the main queue for their visibility timeout.
+type RetryQueuePolicy struct {
+	// QueueURLs are the retry queues. The n-th retry of a message is sent to the n-th queue,
+	// and later retries to the last one, so a single queue or one queue per attempt (e.g.
+	// "orders-retry-1m", "orders-retry-10m") can be used.
+	QueueURLs []string
+	// Backoff computes the delay of each retry from the number of failed attempts. Delays
+	// are applied as message delays, in whole seconds up to 15 minutes. Nil doubles the
+	// delay from 30 seconds.
+	Backoff NackStrategy
+	// MaxAttempts is the number of failed attempts after which a message is moved to
+	// DeadLetterQueueURL, or left in its queue when there is none. Zero retries forever.
+	MaxAttempts int
+	// DeadLetterQueueURL receives the messages that failed MaxAttempts times.
+	DeadLetterQueueURL string
+}
+
+// WithRetryQueue retries failed messages through retry queues (see RetryQueuePolicy): a
+// message whose handler fails is copied to the retry queue of its attempt with a message
+// delay computed by the backoff, and deleted from the queue it came from. The copy keeps
+// the body, attributes and FIFO group of the message, and counts the failed attempts in
+// the arrakis.retry.attempt message attribute (see Message.RetryAttempt).
+//
+// The retry queues must be consumed too, by consumers with the same handler and option,
+// so that messages failing again move on to the next retry queue. FIFO retry queues don't
+// support message delays: their copies are delivered without waiting. Messages that
+// already have 10 message attributes can't carry the attempt; they keep the default
+// behavior, and a warning is logged.
+//
+// Parameters:
+//   - policy: The retry queues and the backoff between attempts
+//
+// Example:
+//
+//	retries := WithRetryQueue(RetryQueuePolicy{
+//	    QueueURLs:          []string{retry1mURL, retry10mURL},
+//	    Backoff:            FixedNack(time.Minute),
+//	    MaxAttempts:        5,
+//	    DeadLetterQueueURL: dlqURL,
+//	})
+//	go NewConsumer(client, ordersURL, handler, retries).Start(ctx)
+//	go NewConsumer(client, retry1mURL, handler, retries).Start(ctx)
+//	go NewConsumer(client, retry10mURL, handler, retries).Start(ctx)
+func WithRetryQueue(policy RetryQueuePolicy) ConsumerOption {
+	return func(c *consumerConfig) {
+		if policy.Backoff == nil {
+			policy.Backoff = ExponentialNack(_defaultRetryQueueBase, _maxDelaySeconds*time.Second, 0)
+		}
+		c.RetryQueue = &policy
+	}
+}
+
+// RetryAttempt returns the number of failed attempts of a message re-sent by a retry
+// queue policy (see WithRetryQueue), 0 for a message that never failed.
+//
+// Returns:
+//   - int: The failed attempts recorded on the message
+func (m Message) RetryAttempt() int {
+	attempt, _ := strconv.Atoi(m.Attribute(_retryAttemptAttribute))
+	return attempt
+}
+
+// retryThroughQueue applies the retry queue policy to a failed message and reports
+// whether the message left its queue, re-sent or dead-lettered.
+func (c *Consumer) retryThroughQueue(ctx context.Context, source queueSource, msg Message, start time.Time) bool {
+	policy := c.config.RetryQueue
+	if policy == nil || len(policy.QueueURLs) == 0 {
+		return false
+	}
+
+	attempt := msg.RetryAttempt() + 1
+	if policy.MaxAttempts > 0 && attempt >= policy.MaxAttempts {
+		if policy.DeadLetterQueueURL == "" {
+			return false
+		}
+
+		if err := source.client.moveMessage(ctx, source.queueURL, policy.DeadLetterQueueURL, msg); err != nil {
+			source.client.logger().Warn("dead-lettering failed, message will be redelivered", "queue", source.queueURL, "message_id", msg.ID, "error", err)
+			return false
+		}
+		c.auditDelete(source, msg, DeleteDeadLettered, start)
+		return true
+	}
+
+	retryURL := policy.QueueURLs[min(attempt, len(policy.QueueURLs))-1]
+
+	retried := msg
+	retried.MessageAttributes = maps.Clone(msg.MessageAttributes)
+	if retried.MessageAttributes == nil {
+		retried.MessageAttributes = map[string]types.MessageAttributeValue{}
+	}
+	retried.MessageAttributes[_retryAttemptAttribute] = types.MessageAttributeValue{
+		DataType:    aws.String(_attributeDataTypeNumber),
+		StringValue: aws.String(strconv.Itoa(attempt)),
+	}
+
+	input := moveInput(retryURL, retried)
+	if !isFIFOQueue(retryURL) {
+		delay := min(max(policy.Backoff.Delay(attempt), 0), _maxDelaySeconds*time.Second)
+		input.DelaySeconds = int32(delay / time.Second)
+	}
+
+	if err := source.client.resend(ctx, source.queueURL, input, msg); err != nil {
+		source.client.logger().Warn("retry queue send failed, message will be redelivered", "queue", source.queueURL, "retry_queue", retryURL, "message_id", msg.ID, "error", err)
+		return false
+	}
+	c.auditDelete(source, msg, DeleteRetried, start)
+
+	return true
+}
@@ -0,0 +1,78 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// retriedMessage builds a message that already failed attempts times.
+func retriedMessage(id string, attempts string) types.Message {
+	m := testMessage(id, "")
+	m.MessageAttributes = map[string]types.MessageAttributeValue{
+		_retryAttemptAttribute: {DataType: aws.String(_attributeDataTypeNumber), StringValue: aws.String(attempts)},
+	}
+
+	return m
+}
+
+func TestConsumerRetryQueue(t *testing.T) {
+	fake := &fakeSQS{}
+	fake.push(testMessage("fresh", ""), retriedMessage("again", "1"), retriedMessage("exhausted", "2"))
+	client := newTestSQS(fake)
+
+	var mu sync.Mutex
+	reasons := map[string]DeleteReason{}
+	consumer := NewConsumer(client, "orders", HandlerFunc(func(ctx context.Context, msg Message) error {
+		return errors.New("failed")
+	}), WithRetryQueue(RetryQueuePolicy{
+		QueueURLs:          []string{"orders-retry-1m", "orders-retry-10m"},
+		Backoff:            LinearNack(time.Minute, 10*time.Minute),
+		MaxAttempts:        3,
+		DeadLetterQueueURL: "orders-dlq",
+	}), WithDeleteAudit(func(audit DeleteAudit) {
+		mu.Lock()
+		reasons[audit.MessageID] = audit.Reason
+		mu.Unlock()
+	}))
+	runConsumer(t, consumer, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(reasons) == 3
+	})
+
+	sends := map[string]struct {
+		queueURL string
+		delay    int32
+		attempt  int
+	}{}
+	for _, sent := range fake.sentMessages() {
+		copied := NewMessage(aws.ToString(sent.QueueUrl), types.Message{MessageAttributes: sent.MessageAttributes})
+		sends[aws.ToString(sent.MessageBody)] = struct {
+			queueURL string
+			delay    int32
+			attempt  int
+		}{aws.ToString(sent.QueueUrl), sent.DelaySeconds, copied.RetryAttempt()}
+	}
+
+	if send := sends["fresh"]; send.queueURL != "orders-retry-1m" || send.delay != 60 || send.attempt != 1 {
+		t.Errorf("Expected the first retry in the first retry queue after a minute, got %+v", send)
+	}
+	if send := sends["again"]; send.queueURL != "orders-retry-10m" || send.delay != 120 || send.attempt != 2 {
+		t.Errorf("Expected the second retry in the second retry queue after two minutes, got %+v", send)
+	}
+	if send := sends["exhausted"]; send.queueURL != "orders-dlq" {
+		t.Errorf("Expected the message out of attempts to be dead-lettered, got %+v", send)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if reasons["fresh"] != DeleteRetried || reasons["exhausted"] != DeleteDeadLettered {
+		t.Errorf("Expected retried and dead-lettered deletes to be audited, got %v", reasons)
+	}
+}
@@ -0,0 +1,109 @@
+package sqs
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// ErrNoRoute is returned by a Router when a message matches no registered route and no
+// default handler is set. The message is left in the queue, like any other handler error.
+type ErrNoRoute struct {
+	// Attribute is the message attribute the router dispatches on.
+	Attribute string
+	// Value is the attribute value of the message (empty when the attribute is missing).
+	Value string
+}
+
+func (e *ErrNoRoute) Error() string {
+	return fmt.Sprintf("no handler registered for message attribute %s=%q", e.Attribute, e.Value)
+}
+
+// Router dispatches messages to different handlers based on the value of a message
+// attribute, so a queue carrying several event types can be consumed without a
+// hand-written switch. A Router is itself a Handler and can be passed to NewConsumer.
+//
+// Routes may be registered while the consumer is running.
+type Router struct {
+	attribute string
+
+	mu       sync.RWMutex
+	routes   map[string]Handler
+	fallback Handler
+}
+
+// NewRouter creates a router dispatching on the given message attribute.
+//
+// Parameters:
+//   - attribute: Name of the message attribute holding the route key (e.g., "type")
+//
+// Returns:
+//   - *Router: A router without routes; register handlers with Route and RouteFunc
+//
+// Example:
+//
+//	router := sqs.NewRouter("type")
+//	router.RouteFunc("order.created", handleOrderCreated)
+//	router.RouteFunc("order.cancelled", handleOrderCancelled)
+//	router.Default(sqs.HandlerFunc(logUnknown))
+//
+//	consumer := sqs.NewConsumer(sqsClient, queueURL, router)
+func NewRouter(attribute string) *Router {
+	return &Router{
+		attribute: attribute,
+		routes:    map[string]Handler{},
+	}
+}
+
+// Route registers the handler for messages whose attribute equals value, replacing
+// any handler previously registered for it.
+func (r *Router) Route(value string, handler Handler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.routes[value] = handler
+}
+
+// RouteFunc registers a function as the handler for messages whose attribute equals value.
+func (r *Router) RouteFunc(value string, handler func(ctx context.Context, msg Message) error) {
+	r.Route(value, HandlerFunc(handler))
+}
+
+// Default sets the handler for messages that match no route, including messages
+// without the routing attribute. Without a default handler such messages fail with
+// *ErrNoRoute.
+func (r *Router) Default(handler Handler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.fallback = handler
+}
+
+// Handle dispatches msg to the handler registered for its attribute value.
+func (r *Router) Handle(ctx context.Context, msg Message) error {
+	value := r.routeKey(msg)
+
+	r.mu.RLock()
+	handler, ok := r.routes[value]
+	if !ok {
+		handler = r.fallback
+	}
+	r.mu.RUnlock()
+
+	if handler == nil {
+		return &ErrNoRoute{Attribute: r.attribute, Value: value}
+	}
+
+	return handler.Handle(ctx, msg)
+}
+
+// routeKey returns the routing attribute value of msg, or "" if it is missing.
+func (r *Router) routeKey(msg Message) string {
+	if value, ok := msg.MessageAttributes[r.attribute]; ok {
+		return aws.ToString(value.StringValue)
+	}
+
+	return ""
+}
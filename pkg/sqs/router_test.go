@@ -0,0 +1,71 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+func routedMessage(id, eventType string) Message {
+	msg := Message{ID: id, MessageAttributes: map[string]types.MessageAttributeValue{}}
+	if eventType != "" {
+		msg.MessageAttributes["type"] = types.MessageAttributeValue{DataType: aws.String("String"), StringValue: aws.String(eventType)}
+	}
+
+	return msg
+}
+
+func TestRouterDispatchesByAttribute(t *testing.T) {
+	var got []string
+	router := NewRouter("type")
+	router.RouteFunc("created", func(ctx context.Context, msg Message) error {
+		got = append(got, "created:"+msg.ID)
+		return nil
+	})
+	router.RouteFunc("deleted", func(ctx context.Context, msg Message) error {
+		got = append(got, "deleted:"+msg.ID)
+		return nil
+	})
+
+	_ = router.Handle(context.Background(), routedMessage("m1", "created"))
+	_ = router.Handle(context.Background(), routedMessage("m2", "deleted"))
+
+	if len(got) != 2 || got[0] != "created:m1" || got[1] != "deleted:m2" {
+		t.Errorf("Expected messages routed by type, got %v", got)
+	}
+}
+
+func TestRouterDefaultHandler(t *testing.T) {
+	var fallback []string
+	router := NewRouter("type")
+	router.RouteFunc("created", func(ctx context.Context, msg Message) error { return nil })
+	router.Default(HandlerFunc(func(ctx context.Context, msg Message) error {
+		fallback = append(fallback, msg.ID)
+		return nil
+	}))
+
+	_ = router.Handle(context.Background(), routedMessage("m1", "unknown"))
+	_ = router.Handle(context.Background(), routedMessage("m2", ""))
+
+	if len(fallback) != 2 {
+		t.Errorf("Expected 2 messages on the default handler, got %v", fallback)
+	}
+}
+
+func TestRouterNoRoute(t *testing.T) {
+	router := NewRouter("type")
+
+	err := router.Handle(context.Background(), routedMessage("m1", "unknown"))
+
+	var noRoute *ErrNoRoute
+	if !errors.As(err, &noRoute) {
+		t.Fatalf("Expected ErrNoRoute, got %v", err)
+	}
+
+	if noRoute.Value != "unknown" || noRoute.Attribute != "type" {
+		t.Errorf("Unexpected ErrNoRoute: %+v", noRoute)
+	}
+}
@@ -0,0 +1,40 @@
+package sqs
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// Run starts the consumer and blocks until the process receives SIGINT or SIGTERM. It
+// then stops polling, waits for the messages already received to be handled (see Start)
+// and returns, so a worker's main function reduces to building the consumer and calling
+// Run. A second signal during the drain terminates the process immediately.
+//
+// Returns:
+//   - error: The error returned by Start
+//
+// Example:
+//
+//	consumer := sqs.NewConsumer(sqsClient, queueURL, handler, sqs.WithWorkers(4))
+//	if err := consumer.Run(); err != nil {
+//	    log.Fatal(err)
+//	}
+func (c *Consumer) Run() error {
+	return c.run(context.Background(), os.Interrupt, syscall.SIGTERM)
+}
+
+// run implements Run, stopping on any of signals or when parent is cancelled.
+func (c *Consumer) run(parent context.Context, signals ...os.Signal) error {
+	ctx, stop := signal.NotifyContext(parent, signals...)
+	defer stop()
+
+	// Restore the default behavior once draining starts, so another signal kills the process
+	context.AfterFunc(ctx, func() {
+		stop()
+		c.client.logger().Info("shutting down consumer, draining in-flight messages", "queue", c.queueURL)
+	})
+
+	return c.Start(ctx)
+}
@@ -0,0 +1,56 @@
+package sqs
+
+import (
+	"context"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestConsumerRunDrainsOnSignal(t *testing.T) {
+	fake := &fakeSQS{}
+	fake.push(testMessage("m1", ""))
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	consumer := NewConsumer(newTestSQS(fake), "queue", HandlerFunc(func(ctx context.Context, msg Message) error {
+		close(started)
+		<-release
+		return nil
+	}))
+
+	done := make(chan error, 1)
+	go func() {
+		done <- consumer.run(context.Background(), syscall.SIGUSR1)
+	}()
+
+	select {
+	case <-started:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the message to be handled")
+	}
+
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatalf("Sending the signal failed: %v", err)
+	}
+
+	select {
+	case <-done:
+		t.Fatal("Expected Run to wait for the in-flight message")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Run returned error: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected Run to return once the message was handled")
+	}
+
+	if len(fake.deletedHandles()) != 1 {
+		t.Error("Expected the in-flight message to be acknowledged during the drain")
+	}
+}
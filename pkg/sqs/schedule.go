@@ -0,0 +1,292 @@
+package sqs
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Pause window configuration values
+const (
+	_maxPauseWindowCheck = time.Minute // Longest interval between two evaluations of the pause windows
+)
+
+// weekdayNames maps the day names accepted by ParsePauseWindow to weekdays.
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// PauseWindow is a period during which a consumer doesn't poll: either a one-off window
+// between From and To, e.g. a planned migration, or a window recurring every day (or on
+// some weekdays) between the times of day Start and End.
+type PauseWindow struct {
+	// Name identifies the window in PauseWindowChange notifications.
+	Name string
+	// From and To bound a one-off window. When set, the recurring fields are ignored.
+	From time.Time
+	To   time.Time
+	// Start and End are the times of day, as offsets from midnight, of a recurring window.
+	// A window whose End is before its Start spans midnight.
+	Start time.Duration
+	End   time.Duration
+	// Weekdays are the days a recurring window starts on. Empty means every day.
+	Weekdays []time.Weekday
+	// Location is the time zone of a recurring window. Nil means UTC.
+	Location *time.Location
+}
+
+// PauseWindowChange describes a consumer entering or leaving a pause window.
+type PauseWindowChange struct {
+	// QueueURL is the queue of the consumer.
+	QueueURL string
+	// Paused is true when the consumer entered the window, false when it left it.
+	Paused bool
+	// Window is the window entered or left.
+	Window PauseWindow
+	// Time is when the transition was observed.
+	Time time.Time
+}
+
+// ParsePauseWindow parses a recurring pause window in the "[days ]HH:MM-HH:MM" format,
+// where days is a comma-separated list of day names or ranges. The window is in UTC; set
+// Location on the result for another time zone.
+//
+// Parameters:
+//   - spec: The window, such as "02:00-04:00", "Sat,Sun 00:00-06:00" or "Mon-Fri 22:00-02:00"
+//
+// Returns:
+//   - PauseWindow: The window, named after spec
+//   - error: An error if spec is malformed
+func ParsePauseWindow(spec string) (PauseWindow, error) {
+	window := PauseWindow{Name: spec}
+
+	fields := strings.Fields(spec)
+	if len(fields) == 0 || len(fields) > 2 {
+		return window, fmt.Errorf("pause window %q: expected [days ]HH:MM-HH:MM", spec)
+	}
+
+	if len(fields) == 2 {
+		weekdays, err := parseWeekdays(fields[0])
+		if err != nil {
+			return window, fmt.Errorf("pause window %q: %w", spec, err)
+		}
+		window.Weekdays = weekdays
+	}
+
+	start, end, found := strings.Cut(fields[len(fields)-1], "-")
+	if !found {
+		return window, fmt.Errorf("pause window %q: expected a HH:MM-HH:MM time range", spec)
+	}
+
+	var err error
+	if window.Start, err = parseTimeOfDay(start); err != nil {
+		return window, fmt.Errorf("pause window %q: %w", spec, err)
+	}
+	if window.End, err = parseTimeOfDay(end); err != nil {
+		return window, fmt.Errorf("pause window %q: %w", spec, err)
+	}
+
+	return window, nil
+}
+
+// WithPauseWindows stops the consumer from polling during maintenance or quiet windows and
+// resumes it automatically afterward. Like Pause, messages already received keep being
+// handled. Scheduled pauses are independent of Pause and Resume: resuming a consumer
+// doesn't end a window, and a consumer paused by hand stays paused when a window ends.
+//
+// Parameters:
+//   - windows: The pause windows, see PauseWindow and ParsePauseWindow
+//   - onChange: Function notified when the consumer enters or leaves a window (may be nil)
+//
+// Example:
+//
+//	nightly, _ := sqs.ParsePauseWindow("Mon-Fri 02:00-03:00")
+//	nightly.Location, _ = time.LoadLocation("Europe/Paris")
+//	consumer := NewConsumer(client, queueURL, handler, WithPauseWindows([]PauseWindow{nightly}, func(change PauseWindowChange) {
+//	    log.Printf("consumer paused=%v (%s)", change.Paused, change.Window.Name)
+//	}))
+func WithPauseWindows(windows []PauseWindow, onChange func(PauseWindowChange)) ConsumerOption {
+	return func(c *consumerConfig) {
+		c.PauseWindows = windows
+		c.OnPauseWindowChange = onChange
+	}
+}
+
+// active reports whether t falls in the window.
+func (w PauseWindow) active(t time.Time) bool {
+	if w.oneOff() {
+		return !t.Before(w.From) && t.Before(w.To)
+	}
+
+	if w.Start == w.End {
+		return false
+	}
+
+	local := t.In(w.location())
+	timeOfDay := time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute + time.Duration(local.Second())*time.Second
+
+	if w.Start < w.End {
+		return timeOfDay >= w.Start && timeOfDay < w.End && w.startsOn(local.Weekday())
+	}
+
+	// Spanning midnight: after Start on a starting day, or before End the day after
+	if timeOfDay >= w.Start {
+		return w.startsOn(local.Weekday())
+	}
+
+	return timeOfDay < w.End && w.startsOn((local.Weekday()+6)%7)
+}
+
+// next returns the first time after t the window may start or end.
+func (w PauseWindow) next(t time.Time) time.Time {
+	var next time.Time
+	consider := func(boundary time.Time) {
+		if boundary.After(t) && (next.IsZero() || boundary.Before(next)) {
+			next = boundary
+		}
+	}
+
+	if w.oneOff() {
+		consider(w.From)
+		consider(w.To)
+		return next
+	}
+
+	local := t.In(w.location())
+	for day := -1; day <= 7; day++ {
+		midnight := time.Date(local.Year(), local.Month(), local.Day()+day, 0, 0, 0, 0, local.Location())
+		consider(midnight.Add(w.Start))
+		consider(midnight.Add(w.End))
+	}
+
+	return next
+}
+
+// oneOff reports whether the window is bounded by From and To.
+func (w PauseWindow) oneOff() bool {
+	return !w.From.IsZero() || !w.To.IsZero()
+}
+
+// startsOn reports whether a recurring window starts on day.
+func (w PauseWindow) startsOn(day time.Weekday) bool {
+	if len(w.Weekdays) == 0 {
+		return true
+	}
+
+	for _, weekday := range w.Weekdays {
+		if weekday == day {
+			return true
+		}
+	}
+
+	return false
+}
+
+// location returns the time zone of a recurring window.
+func (w PauseWindow) location() *time.Location {
+	if w.Location == nil {
+		return time.UTC
+	}
+
+	return w.Location
+}
+
+// followPauseWindows pauses and resumes polling as the pause windows of the consumer start
+// and end, until ctx is done.
+func (c *Consumer) followPauseWindows(ctx context.Context) {
+	for {
+		now := time.Now()
+		c.applyPauseWindows(now)
+
+		wait := _maxPauseWindowCheck
+		for _, window := range c.config.PauseWindows {
+			if next := window.next(now); !next.IsZero() {
+				wait = min(wait, next.Sub(now))
+			}
+		}
+
+		sleep(ctx, wait)
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// applyPauseWindows pauses or resumes polling according to the windows active at now and
+// notifies the transitions.
+func (c *Consumer) applyPauseWindows(now time.Time) {
+	var active *PauseWindow
+	for i, window := range c.config.PauseWindows {
+		if window.active(now) {
+			active = &c.config.PauseWindows[i]
+			break
+		}
+	}
+
+	c.scheduled.mu.Lock()
+	wasPaused := c.scheduled.paused
+	c.scheduled.mu.Unlock()
+
+	switch {
+	case active != nil && !wasPaused:
+		c.scheduled.set(true)
+		c.window = *active
+		c.notifyPauseWindow(true, now)
+	case active == nil && wasPaused:
+		c.scheduled.set(false)
+		c.notifyPauseWindow(false, now)
+	}
+}
+
+// notifyPauseWindow reports a pause window transition to the configured callback.
+func (c *Consumer) notifyPauseWindow(paused bool, now time.Time) {
+	if c.config.OnPauseWindowChange != nil {
+		c.config.OnPauseWindowChange(PauseWindowChange{QueueURL: c.queueURL, Paused: paused, Window: c.window, Time: now})
+	}
+}
+
+// parseWeekdays parses a comma-separated list of day names and day ranges.
+func parseWeekdays(spec string) ([]time.Weekday, error) {
+	var weekdays []time.Weekday
+
+	for _, part := range strings.Split(spec, ",") {
+		first, last, isRange := strings.Cut(part, "-")
+
+		from, ok := weekdayNames[strings.ToLower(first)]
+		if !ok {
+			return nil, fmt.Errorf("unknown day %q", first)
+		}
+
+		to := from
+		if isRange {
+			if to, ok = weekdayNames[strings.ToLower(last)]; !ok {
+				return nil, fmt.Errorf("unknown day %q", last)
+			}
+		}
+
+		for day := from; ; day = (day + 1) % 7 {
+			weekdays = append(weekdays, day)
+			if day == to {
+				break
+			}
+		}
+	}
+
+	return weekdays, nil
+}
+
+// parseTimeOfDay parses a HH:MM time of day into an offset from midnight.
+func parseTimeOfDay(value string) (time.Duration, error) {
+	hours, minutes, found := strings.Cut(value, ":")
+	h, hErr := strconv.Atoi(hours)
+	m, mErr := strconv.Atoi(minutes)
+
+	if !found || hErr != nil || mErr != nil || h < 0 || h > 24 || m < 0 || m > 59 || (h == 24 && m != 0) {
+		return 0, fmt.Errorf("invalid time of day %q", value)
+	}
+
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute, nil
+}
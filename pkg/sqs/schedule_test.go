@@ -0,0 +1,90 @@
+package sqs
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParsePauseWindow(t *testing.T) {
+	window, err := ParsePauseWindow("Fri-Mon 22:00-02:30")
+	if err != nil {
+		t.Fatalf("Expected a valid window, got %v", err)
+	}
+
+	expected := []time.Weekday{time.Friday, time.Saturday, time.Sunday, time.Monday}
+	if len(window.Weekdays) != len(expected) || window.Weekdays[3] != time.Monday {
+		t.Errorf("Expected weekdays %v, got %v", expected, window.Weekdays)
+	}
+	if window.Start != 22*time.Hour || window.End != 2*time.Hour+30*time.Minute {
+		t.Errorf("Expected 22:00-02:30, got %s-%s", window.Start, window.End)
+	}
+
+	for _, spec := range []string{"", "02:00", "Someday 02:00-03:00", "25:00-26:00", "a b c"} {
+		if _, err := ParsePauseWindow(spec); err == nil {
+			t.Errorf("Expected an error for %q", spec)
+		}
+	}
+}
+
+func TestPauseWindowActive(t *testing.T) {
+	overnight, _ := ParsePauseWindow("Fri 22:00-02:00")
+	friday := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC) // A Friday
+
+	tests := []struct {
+		name     string
+		at       time.Time
+		expected bool
+	}{
+		{"before start", friday.Add(21 * time.Hour), false},
+		{"after start", friday.Add(23 * time.Hour), true},
+		{"after midnight", friday.Add(25 * time.Hour), true},
+		{"after end", friday.Add(27 * time.Hour), false},
+		{"other day", friday.Add(-time.Hour), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := overnight.active(tt.at); got != tt.expected {
+				t.Errorf("Expected active=%v, got %v", tt.expected, got)
+			}
+		})
+	}
+
+	if next := overnight.next(friday.Add(21 * time.Hour)); !next.Equal(friday.Add(22 * time.Hour)) {
+		t.Errorf("Expected the next boundary at the window start, got %s", next)
+	}
+}
+
+func TestConsumerPauseWindows(t *testing.T) {
+	fake := &fakeSQS{}
+	fake.push(testMessage("m1", ""))
+
+	now := time.Now()
+	window := PauseWindow{Name: "migration", From: now.Add(-time.Second), To: now.Add(100 * time.Millisecond)}
+
+	var mu sync.Mutex
+	var changes []PauseWindowChange
+	consumer := NewConsumer(newTestSQS(fake), "queue", HandlerFunc(func(ctx context.Context, msg Message) error {
+		return nil
+	}), WithPauseWindows([]PauseWindow{window}, func(change PauseWindowChange) {
+		mu.Lock()
+		defer mu.Unlock()
+		changes = append(changes, change)
+	}))
+
+	runConsumer(t, consumer, func() bool {
+		return len(fake.deletedHandles()) == 1
+	})
+
+	if elapsed := time.Since(now); elapsed < 100*time.Millisecond {
+		t.Errorf("Expected no poll before the window ended, handled after %s", elapsed)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(changes) != 2 || !changes[0].Paused || changes[1].Paused || changes[0].Window.Name != "migration" {
+		t.Errorf("Expected the window to be entered then left, got %+v", changes)
+	}
+}
@@ -0,0 +1,55 @@
+package sqs
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// AdaptiveState is the adaptive polling state of a queue, as set by SetAdaptiveState.
+type AdaptiveState struct {
+	// Average is the EWMA average of messages per poll.
+	Average float64
+	// ConsecutiveEmpty is the number of consecutive empty receives.
+	ConsecutiveEmpty int
+	// LastUpdate is when the average was last updated, from which idle decay is measured.
+	// Zero uses the current time of the client clock (see WithClock).
+	LastUpdate time.Time
+}
+
+// SetAdaptiveState overwrites the EWMA average, the count of consecutive empty receives
+// and the last update time of a queue, so tests can start from a "high volume" or "deep
+// idle" state without replaying polls. The low volume cycles are cleared and the volume
+// class follows the new average. It is meant for tests: in production, the state is
+// driven by the receives.
+//
+// Parameters:
+//   - queueURL: The URL of the queue
+//   - state: The state to set
+//
+// Example:
+//
+//	client.SetAdaptiveState(queueURL, sqs.AdaptiveState{Average: 10})
+//	if client.CurrentVolumeClass(queueURL) != sqs.VolumeVeryHigh {
+//	    t.Fatal("expected a very high volume")
+//	}
+func (s *SQS) SetAdaptiveState(queueURL string, state AdaptiveState) {
+	s.state(queueURL).seed(state)
+}
+
+// seed overwrites the algorithm state.
+func (a *arrakis) seed(state AdaptiveState) {
+	lastUpdate := state.LastUpdate
+	if lastUpdate.IsZero() {
+		lastUpdate = a.now()
+	}
+	atomic.StoreInt64(&a.lastUpdate, lastUpdate.Unix())
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.average = state.Average
+	a.consecutiveEmptyMessages = int64(state.ConsecutiveEmpty)
+	a.variance = 0
+	a.lowVolumeCycle = 0
+	a.observeClass()
+}
@@ -0,0 +1,27 @@
+package sqs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSetAdaptiveState(t *testing.T) {
+	client := newTestSQS(&fakeSQS{}, WithIdleWaitTimeSeconds(20))
+	client.EnableArrakis()
+
+	client.SetAdaptiveState("queue", AdaptiveState{Average: 10})
+	if got := client.CurrentVolumeClass("queue"); got != VolumeVeryHigh {
+		t.Errorf("Expected a very high volume, got %v", got)
+	}
+
+	lastUpdate := time.Now().Add(-time.Hour)
+	client.SetAdaptiveState("queue", AdaptiveState{ConsecutiveEmpty: 50, LastUpdate: lastUpdate})
+
+	state := client.state("queue")
+	if state.currentAverage() != 0 || state.consecutiveEmptyMessages != 50 || state.lastUpdate != lastUpdate.Unix() {
+		t.Errorf("Expected the idle state, got average %v, %d empty receives", state.currentAverage(), state.consecutiveEmptyMessages)
+	}
+	if got := client.CurrentVolumeClass("queue"); got != VolumeIdle {
+		t.Errorf("Expected an idle volume, got %v", got)
+	}
+}
@@ -0,0 +1,36 @@
+package sqs
+
+// WithGroupSharding splits the message groups of a FIFO queue across the instances of a
+// coordinator: every MessageGroupId is owned by exactly one live instance (rendezvous
+// hashing over the instances renewing their lease, see Coordinator.OwnsGroup), and
+// messages of groups owned by another instance are released right away (visibility
+// timeout set to 0) instead of being handled. Since a single instance handles a group,
+// scaling consumers out doesn't interleave the processing of a group across instances.
+//
+// The coordinator must be running (see Coordinator.Run) for ownership to follow
+// instances joining and leaving; until its first renewal every group is handled locally.
+// Messages without a group are always handled. Like filtered messages, every release
+// counts as a receive, so size maxReceiveCount of the redrive policy for the releases a
+// message may go through while ownership settles.
+//
+// Parameters:
+//   - coordinator: The coordinator of this instance; queues in it are optional
+//
+// Example:
+//
+//	coordinator := sqs.NewCoordinator(redislease.New(redisClient, "orders:members"), podName, nil)
+//	go coordinator.Run(ctx)
+//
+//	consumer := sqs.NewConsumer(client, "https://sqs.us-east-1.amazonaws.com/123456789012/orders.fifo", handler,
+//	    sqs.WithGroupSharding(coordinator),
+//	)
+func WithGroupSharding(coordinator *Coordinator) ConsumerOption {
+	return func(c *consumerConfig) {
+		c.GroupSharding = coordinator
+	}
+}
+
+// ownsGroup reports whether this instance handles the message group of msg.
+func (c *Consumer) ownsGroup(msg Message) bool {
+	return c.config.GroupSharding == nil || msg.GroupID == "" || c.config.GroupSharding.OwnsGroup(msg.GroupID)
+}
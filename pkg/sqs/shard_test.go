@@ -0,0 +1,97 @@
+package sqs
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestCoordinatorOwnsEachGroupOnce(t *testing.T) {
+	store := &memoryLeaseStore{}
+	coordinators := []*Coordinator{
+		NewCoordinator(store, "instance-0", nil),
+		NewCoordinator(store, "instance-1", nil),
+		NewCoordinator(store, "instance-2", nil),
+	}
+
+	if !coordinators[0].OwnsGroup("g") {
+		t.Error("Expected every group to be owned before the first renewal")
+	}
+
+	for _, coordinator := range coordinators {
+		_ = coordinator.RebalanceOnce(context.Background())
+	}
+	for _, coordinator := range coordinators {
+		_ = coordinator.RebalanceOnce(context.Background())
+	}
+
+	owned := map[string]int{}
+	for i := range 60 {
+		group := fmt.Sprintf("customer-%d", i)
+		owners := 0
+		for _, coordinator := range coordinators {
+			if coordinator.OwnsGroup(group) {
+				owners++
+				owned[coordinator.instanceID]++
+			}
+		}
+		if owners != 1 {
+			t.Errorf("Expected %s to have one owner, got %d", group, owners)
+		}
+	}
+
+	if len(owned) != len(coordinators) {
+		t.Errorf("Expected groups spread over every instance, got %v", owned)
+	}
+}
+
+func TestConsumerReleasesForeignGroups(t *testing.T) {
+	store := &memoryLeaseStore{}
+	local := NewCoordinator(store, "instance-0", nil)
+	remote := NewCoordinator(store, "instance-1", nil)
+	_ = local.RebalanceOnce(context.Background())
+	_ = remote.RebalanceOnce(context.Background())
+	_ = local.RebalanceOnce(context.Background())
+
+	fake := &fakeSQS{}
+	var owned []string
+	for i := range 10 {
+		group := fmt.Sprintf("customer-%d", i)
+		fake.push(testMessage(group, group))
+		if local.OwnsGroup(group) {
+			owned = append(owned, group)
+		}
+	}
+	fake.push(testMessage("ungrouped", ""))
+
+	var mu sync.Mutex
+	handled := map[string]bool{}
+	handler := HandlerFunc(func(ctx context.Context, msg Message) error {
+		mu.Lock()
+		handled[msg.ID] = true
+		mu.Unlock()
+		return nil
+	})
+
+	consumer := NewConsumer(newTestSQS(fake), "queue", handler, WithGroupSharding(local))
+	runConsumer(t, consumer, func() bool { return len(fake.deletedHandles()) == len(owned)+1 })
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !handled["ungrouped"] {
+		t.Error("Expected the message without a group to be handled")
+	}
+	for i := range 10 {
+		group := fmt.Sprintf("customer-%d", i)
+		if local.OwnsGroup(group) {
+			continue
+		}
+		if handled[group] {
+			t.Errorf("Expected %s, owned by another instance, not to be handled", group)
+		}
+		if visibility, ok := fake.visibilityOf(group); !ok || visibility != 0 {
+			t.Errorf("Expected %s to be released with visibility 0, got %d (set: %v)", group, visibility, ok)
+		}
+	}
+}
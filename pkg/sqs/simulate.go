@@ -0,0 +1,234 @@
+package sqs
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// Simulation configuration
+const (
+	_simulationRoundTrip = 20 * time.Millisecond // Duration of a ReceiveMessage call that returns right away
+)
+
+// ErrEmptyTrace is returned by ParseTrace when the document contains no message arrivals.
+var ErrEmptyTrace = errors.New("sqs: trace contains no messages")
+
+// TraceEvent records messages arriving on a queue at a given time. Its fields match
+// Decision, so the decisions of a DumpState document can be replayed as a trace.
+type TraceEvent struct {
+	// Time is when the messages arrived.
+	Time time.Time
+	// Messages is the number of messages that arrived.
+	Messages int
+}
+
+// Trace is recorded queue traffic that Simulate replays through the adaptive polling
+// algorithm.
+type Trace struct {
+	// Events are the message arrivals, in any order.
+	Events []TraceEvent
+}
+
+// ParseTrace reads a trace from a JSON document. Two layouts are accepted: an array of
+// TraceEvent objects, or a document produced by DumpState, whose recorded decisions
+// (of every queue) are replayed as arrivals.
+//
+// Parameters:
+//   - r: Reader of the JSON document
+//
+// Returns:
+//   - Trace: The trace, with events sorted by time
+//   - error: A decoding error, or ErrEmptyTrace if no message arrived
+//
+// Example:
+//
+//	file, _ := os.Open("traffic.json")
+//	trace, err := sqs.ParseTrace(file)
+func ParseTrace(r io.Reader) (Trace, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return Trace{}, err
+	}
+
+	var trace Trace
+	if data = bytes.TrimSpace(data); len(data) > 0 && data[0] == '[' {
+		err = json.Unmarshal(data, &trace.Events)
+	} else {
+		var dump struct {
+			Queues []struct {
+				Decisions []TraceEvent
+			}
+		}
+		err = json.Unmarshal(data, &dump)
+		for _, queue := range dump.Queues {
+			trace.Events = append(trace.Events, queue.Decisions...)
+		}
+	}
+	if err != nil {
+		return Trace{}, err
+	}
+
+	sort.SliceStable(trace.Events, func(i, j int) bool { return trace.Events[i].Time.Before(trace.Events[j].Time) })
+
+	if trace.Messages() == 0 {
+		return Trace{}, ErrEmptyTrace
+	}
+
+	return trace, nil
+}
+
+// Messages returns the total number of messages in the trace.
+func (t Trace) Messages() int {
+	total := 0
+	for _, event := range t.Events {
+		total += max(event.Messages, 0)
+	}
+
+	return total
+}
+
+// Duration returns the time between the first and the last arrival of the trace.
+func (t Trace) Duration() time.Duration {
+	if len(t.Events) == 0 {
+		return 0
+	}
+
+	return t.Events[len(t.Events)-1].Time.Sub(t.Events[0].Time)
+}
+
+// SimulationResult summarizes how the adaptive polling algorithm handled a trace.
+type SimulationResult struct {
+	// Polls is the number of ReceiveMessage calls, which drives the SQS cost.
+	Polls int
+	// EmptyPolls is the number of ReceiveMessage calls that returned no message.
+	EmptyPolls int
+	// Messages is the number of messages received.
+	Messages int
+	// MeanLatency is the average time messages waited in the queue before being received.
+	MeanLatency time.Duration
+	// MaxLatency is the longest time a message waited in the queue before being received.
+	MaxLatency time.Duration
+	// Duration is the simulated time, from the first arrival to the last receive.
+	Duration time.Duration
+	// Events counts the algorithm events that occurred during the simulation.
+	Events AlgorithmEvents
+}
+
+// Simulate replays a trace through the adaptive polling algorithm on a virtual clock and
+// reports the API calls and latency it would have produced, so settings can be compared
+// offline. A single consumer polls continuously with adaptive polling enabled, using the
+// wait times the algorithm computes.
+//
+// A simulated poll returns as soon as a full batch of 10 messages is available, or when
+// its wait time elapses. SQS may return a partial batch earlier, so the simulated
+// latencies are an upper bound of what long polling adds.
+//
+// Parameters:
+//   - trace: The recorded traffic to replay
+//   - options: The client options to evaluate, as passed to NewSQSWithOptions
+//
+// Returns:
+//   - SimulationResult: Cost and latency of the simulated consumer
+//
+// Example:
+//
+//	result := sqs.Simulate(trace, sqs.WithEwmaAlpha(0.5), sqs.WithVeryHighVolumeWaitTimeSeconds(0))
+//	fmt.Println(result.Polls, result.MeanLatency)
+func Simulate(trace Trace, options ...Option) SimulationResult {
+	var config config
+
+	setDefaults(&config)
+	for _, opt := range options {
+		opt(&config)
+	}
+	config.AdaptivePolling.EnableAdaptivePolling = true
+
+	events := make([]TraceEvent, 0, len(trace.Events))
+	for _, event := range trace.Events {
+		if event.Messages > 0 {
+			events = append(events, event)
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Time.Before(events[j].Time) })
+
+	var result SimulationResult
+	if len(events) == 0 {
+		return result
+	}
+
+	start := events[0].Time
+	now := start
+	state := newArrakis(&config)
+	state.clock = func() time.Time { return now }
+
+	var (
+		pending      []TraceEvent // Arrived messages not yet received, grouped by arrival
+		pendingCount int
+		totalLatency time.Duration
+		batchSize    = config.batchSize()
+	)
+
+	take := func() {
+		pending = append(pending, events[0])
+		pendingCount += events[0].Messages
+		events = events[1:]
+	}
+
+	for len(events) > 0 || pendingCount > 0 {
+		waitTime := state.clampWaitTime(state.adjustedWaitTime(""))
+		deadline := now.Add(time.Duration(waitTime)*time.Second + _simulationRoundTrip)
+		now = now.Add(_simulationRoundTrip)
+
+		// Messages that arrived before the poll are available right away
+		for len(events) > 0 && !events[0].Time.After(now) {
+			take()
+		}
+
+		// The long poll waits for a full batch until its wait time elapses
+		for pendingCount < batchSize && len(events) > 0 && !events[0].Time.After(deadline) {
+			now = events[0].Time
+			take()
+		}
+		if pendingCount < batchSize {
+			now = deadline
+		}
+
+		received := 0
+		for received < batchSize && len(pending) > 0 {
+			batch := min(pending[0].Messages, batchSize-received)
+			latency := now.Sub(pending[0].Time)
+			totalLatency += latency * time.Duration(batch)
+			result.MaxLatency = max(result.MaxLatency, latency)
+
+			received += batch
+			pending[0].Messages -= batch
+			if pending[0].Messages == 0 {
+				pending = pending[1:]
+			}
+		}
+		pendingCount -= received
+
+		result.Polls++
+		result.Messages += received
+		if received == 0 {
+			result.EmptyPolls++
+		}
+
+		state.handleReceiveResponse(&sqs.ReceiveMessageOutput{Messages: make([]types.Message, received)})
+	}
+
+	if result.Messages > 0 {
+		result.MeanLatency = totalLatency / time.Duration(result.Messages)
+	}
+	result.Duration = now.Sub(start)
+	result.Events = state.stats().Events
+
+	return result
+}
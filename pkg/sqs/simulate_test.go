@@ -0,0 +1,111 @@
+package sqs
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// steadyTrace returns a trace with messages arriving every interval for the given duration.
+func steadyTrace(start time.Time, interval, duration time.Duration, messages int) Trace {
+	var trace Trace
+	for at := start; at.Before(start.Add(duration)); at = at.Add(interval) {
+		trace.Events = append(trace.Events, TraceEvent{Time: at, Messages: messages})
+	}
+
+	return trace
+}
+
+func TestParseTraceEventArray(t *testing.T) {
+	trace, err := ParseTrace(strings.NewReader(`[
+		{"Time": "2026-01-01T00:00:10Z", "Messages": 2},
+		{"Time": "2026-01-01T00:00:00Z", "Messages": 3}
+	]`))
+	if err != nil {
+		t.Fatalf("ParseTrace returned error: %v", err)
+	}
+
+	if trace.Messages() != 5 {
+		t.Errorf("Expected 5 messages, got %d", trace.Messages())
+	}
+	if trace.Duration() != 10*time.Second || trace.Events[0].Messages != 3 {
+		t.Errorf("Expected events sorted by time, got %+v", trace.Events)
+	}
+}
+
+func TestParseTraceStateDump(t *testing.T) {
+	client := newTestSQS(&fakeSQS{})
+	state := client.state("queue")
+	state.recordDecision(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), 20, 4)
+	state.recordDecision(time.Date(2026, 1, 1, 0, 0, 20, 0, time.UTC), 15, 0)
+
+	dump, err := client.DumpState()
+	if err != nil {
+		t.Fatalf("DumpState returned error: %v", err)
+	}
+
+	trace, err := ParseTrace(strings.NewReader(string(dump)))
+	if err != nil {
+		t.Fatalf("ParseTrace returned error: %v", err)
+	}
+	if trace.Messages() != 4 || len(trace.Events) != 2 {
+		t.Errorf("Expected the decisions replayed as arrivals, got %+v", trace.Events)
+	}
+}
+
+func TestParseTraceEmpty(t *testing.T) {
+	if _, err := ParseTrace(strings.NewReader(`[]`)); !errors.Is(err, ErrEmptyTrace) {
+		t.Errorf("Expected ErrEmptyTrace, got %v", err)
+	}
+
+	if _, err := ParseTrace(strings.NewReader(`{`)); err == nil {
+		t.Error("Expected a decoding error")
+	}
+}
+
+func TestSimulateReceivesEveryMessage(t *testing.T) {
+	trace := steadyTrace(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), time.Second, 10*time.Minute, 3)
+
+	result := Simulate(trace)
+
+	if result.Messages != trace.Messages() {
+		t.Errorf("Expected %d messages received, got %d", trace.Messages(), result.Messages)
+	}
+	if result.Polls == 0 || result.MeanLatency <= 0 || result.MaxLatency < result.MeanLatency {
+		t.Errorf("Expected polls and latencies to be reported, got %+v", result)
+	}
+}
+
+func TestSimulateTradesLatencyForCost(t *testing.T) {
+	trace := steadyTrace(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), 2*time.Second, 10*time.Minute, 1)
+
+	short := Simulate(trace, WithAdaptivePolling(1, 30, 1, 1, 1, 1, 0.3, 10))
+	long := Simulate(trace, WithAdaptivePolling(20, 30, 20, 20, 20, 20, 0.3, 10))
+
+	if short.MeanLatency >= long.MeanLatency {
+		t.Errorf("Expected short waits to lower latency, got %v and %v", short.MeanLatency, long.MeanLatency)
+	}
+	if short.Polls <= long.Polls {
+		t.Errorf("Expected short waits to cost more polls, got %d and %d", short.Polls, long.Polls)
+	}
+}
+
+func TestSimulateUsesVirtualClock(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	trace := steadyTrace(start, time.Second, time.Minute, 5)
+	trace.Events = append(trace.Events, TraceEvent{Time: start.Add(time.Hour), Messages: 1})
+
+	begin := time.Now()
+	result := Simulate(trace)
+
+	if result.Duration < time.Hour {
+		t.Errorf("Expected an hour of simulated time, got %v", result.Duration)
+	}
+	if result.Events.Decays == 0 {
+		t.Error("Expected the idle hour to decay the average")
+	}
+	if time.Since(begin) > 5*time.Second {
+		t.Error("Expected the simulation not to wait in real time")
+	}
+}
@@ -0,0 +1,142 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// SinkAction is what a failure sink does with the messages a handler failed.
+type SinkAction int
+
+// Actions of failure sinks.
+const (
+	// SinkDeadLetter moves failed messages, unchanged, to the queue of the sink
+	SinkDeadLetter SinkAction = iota
+	// SinkQuarantine copies failed messages to the queue of the sink with the quarantine
+	// attributes (see WithQuarantine), reason "handler", and deletes them
+	SinkQuarantine
+	// SinkDrop deletes failed messages
+	SinkDrop
+)
+
+// FailureSink is where the messages failed by a handler go, instead of waiting in the
+// queue for its redrive policy.
+type FailureSink struct {
+	// Action is what happens to the failed messages.
+	Action SinkAction
+	// QueueURL is the destination of SinkDeadLetter and SinkQuarantine.
+	QueueURL string
+	// MaxAttempts is the number of deliveries after which a failed message goes to the
+	// sink; earlier failures follow the usual retry behavior of the consumer. Zero sends
+	// messages to the sink at their first failure.
+	MaxAttempts int
+}
+
+// DeadLetterSink returns a sink moving failed messages to a dead-letter queue.
+func DeadLetterSink(queueURL string) FailureSink {
+	return FailureSink{Action: SinkDeadLetter, QueueURL: queueURL}
+}
+
+// QuarantineSink returns a sink moving failed messages to a quarantine queue, with the
+// failure recorded in their attributes.
+func QuarantineSink(queueURL string) FailureSink {
+	return FailureSink{Action: SinkQuarantine, QueueURL: queueURL}
+}
+
+// DropSink returns a sink deleting failed messages.
+func DropSink() FailureSink {
+	return FailureSink{Action: SinkDrop}
+}
+
+// sinkError marks a handler error with the failure sink of the handler.
+type sinkError struct {
+	sink       FailureSink
+	quarantine *quarantine // Quarantine of SinkQuarantine sinks
+	err        error
+}
+
+func (e *sinkError) Error() string {
+	return e.err.Error()
+}
+
+func (e *sinkError) Unwrap() error {
+	return e.err
+}
+
+// SinkFailures returns a middleware declaring the failure sink of the handlers it wraps,
+// overriding the queue-level behavior for their failures: the consumer sends the messages
+// they fail to the sink (once out of attempts, see FailureSink.MaxAttempts) instead of
+// leaving them for the redrive policy of the queue. Wrap a whole consumer handler for a
+// per-queue sink, or the handlers of a Router or TypeRegistry for per-type sinks.
+//
+// Sinks take precedence over retry policies (see WithRetryPolicies) once a message is out
+// of attempts. A message the sink fails to move stays in the queue and is redelivered.
+//
+// Parameters:
+//   - sink: Where the failed messages go
+//
+// Returns:
+//   - Middleware: The middleware to wrap handlers with (see Chain)
+//
+// Example:
+//
+//	router := sqs.NewRouter("type")
+//	router.Route("payment.charged", sqs.Chain(paymentHandler, sqs.SinkFailures(sqs.DeadLetterSink(paymentsDLQ))))
+//	router.Route("audit.logged", sqs.Chain(auditHandler, sqs.SinkFailures(sqs.DropSink())))
+//	router.Route("order.created", sqs.Chain(orderHandler, sqs.SinkFailures(sqs.FailureSink{
+//	    Action: sqs.SinkQuarantine, QueueURL: quarantineURL, MaxAttempts: 3,
+//	})))
+func SinkFailures(sink FailureSink) Middleware {
+	var q *quarantine
+	if sink.Action == SinkQuarantine {
+		q = newQuarantine(&QuarantinePolicy{QueueURL: sink.QueueURL})
+	}
+
+	return func(next Handler) Handler {
+		return HandlerFunc(func(ctx context.Context, msg Message) error {
+			err := next.Handle(ctx, msg)
+			if err == nil {
+				return nil
+			}
+
+			// The innermost sink wins when sinks are nested
+			var marked *sinkError
+			if errors.As(err, &marked) {
+				return err
+			}
+
+			return &sinkError{sink: sink, quarantine: q, err: err}
+		})
+	}
+}
+
+// sinkFailure sends a failed message to the failure sink of its handler, if it has one and
+// the message is out of attempts. It reports whether the sink took over the message.
+func (c *Consumer) sinkFailure(ctx context.Context, source queueSource, msg Message, err error, start time.Time) bool {
+	var marked *sinkError
+	if !errors.As(err, &marked) || max(msg.ReceiveCount, 1) < marked.sink.MaxAttempts {
+		return false
+	}
+
+	switch marked.sink.Action {
+	case SinkDeadLetter:
+		if moveErr := source.client.moveMessage(ctx, source.queueURL, marked.sink.QueueURL, msg); moveErr != nil {
+			source.client.logger().Warn("dead-lettering failed, message will be redelivered", "queue", source.queueURL, "message_id", msg.ID, "error", moveErr)
+			return true
+		}
+		c.auditDelete(source, msg, DeleteDeadLettered, start)
+	case SinkQuarantine:
+		if marked.quarantine.move(ctx, source, msg, QuarantineHandler, marked.err) {
+			c.auditDelete(source, msg, DeleteQuarantined, start)
+		}
+	case SinkDrop:
+		if _, deleteErr := source.client.DeleteMessage(ctx, source.queueURL, msg.ReceiptHandle); deleteErr != nil {
+			source.client.logger().Warn("delete failed, message will be redelivered", "queue", source.queueURL, "message_id", msg.ID, "error", deleteErr)
+			return true
+		}
+		c.auditDelete(source, msg, DeleteDropped, start)
+	}
+
+	return true
+}
@@ -0,0 +1,83 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+func TestConsumerFailureSinks(t *testing.T) {
+	fake := &fakeSQS{}
+	fake.push(
+		typedMessage("charged", "payment.charged", "1"),
+		typedMessage("logged", "audit.logged", "1"),
+		typedMessage("early", "order.created", "1"),
+		typedMessage("late", "order.created", "3"),
+		typedMessage("other", "other", "1"),
+	)
+	client := newTestSQS(fake)
+
+	failing := HandlerFunc(func(ctx context.Context, msg Message) error {
+		return errors.New("failed")
+	})
+	router := NewRouter("type")
+	router.Route("payment.charged", Chain(failing, SinkFailures(DeadLetterSink("payments-dlq"))))
+	router.Route("audit.logged", Chain(failing, SinkFailures(DropSink())))
+	router.Route("order.created", Chain(failing, SinkFailures(FailureSink{Action: SinkQuarantine, QueueURL: "orders-quarantine", MaxAttempts: 3})))
+	router.Default(failing)
+
+	var mu sync.Mutex
+	reasons := map[string]DeleteReason{}
+	consumer := NewConsumer(client, "queue", router, WithDeleteAudit(func(audit DeleteAudit) {
+		mu.Lock()
+		reasons[audit.MessageID] = audit.Reason
+		mu.Unlock()
+	}))
+	runConsumer(t, consumer, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(reasons) == 3
+	})
+
+	destinations := map[string]string{}
+	for _, sent := range fake.sentMessages() {
+		destinations[aws.ToString(sent.MessageBody)] = aws.ToString(sent.QueueUrl)
+		if aws.ToString(sent.MessageBody) == "late" {
+			if reason := aws.ToString(sent.MessageAttributes[_quarantineAttributeReason].StringValue); reason != string(QuarantineHandler) {
+				t.Errorf("Expected the handler quarantine reason, got %q", reason)
+			}
+		}
+	}
+	if destinations["charged"] != "payments-dlq" || destinations["late"] != "orders-quarantine" || len(destinations) != 2 {
+		t.Errorf("Expected failed messages routed to the sinks of their handlers, got %v", destinations)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	expected := map[string]DeleteReason{"charged": DeleteDeadLettered, "logged": DeleteDropped, "late": DeleteQuarantined}
+	for id, reason := range expected {
+		if reasons[id] != reason {
+			t.Errorf("Expected %s to be deleted as %s, got %q", id, reason, reasons[id])
+		}
+	}
+	if _, ok := reasons["early"]; ok {
+		t.Error("Expected a message with attempts left to stay in the queue")
+	}
+}
+
+func TestSinkFailuresInnermostWins(t *testing.T) {
+	handler := Chain(HandlerFunc(func(ctx context.Context, msg Message) error {
+		return errors.New("failed")
+	}), SinkFailures(DropSink()), SinkFailures(DeadLetterSink("dlq")))
+
+	var marked *sinkError
+	if err := handler.Handle(context.Background(), Message{}); !errors.As(err, &marked) || marked.sink.QueueURL != "dlq" {
+		t.Errorf("Expected the innermost sink to be kept, got %v", err)
+	}
+	if err := handler.Handle(context.Background(), Message{}); err.Error() != "failed" {
+		t.Errorf("Expected the handler error message to be kept, got %q", err)
+	}
+}
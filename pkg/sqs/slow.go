@@ -0,0 +1,71 @@
+package sqs
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// SlowHandler describes a handler still running after the slow handler threshold, i.e. a
+// message at risk of becoming visible again (and being delivered twice) before it is
+// acknowledged.
+type SlowHandler struct {
+	// QueueURL is the queue the message was received from.
+	QueueURL string
+	// MessageID is the SQS MessageId of the message being handled.
+	MessageID string
+	// Elapsed is how long the handler has been running.
+	Elapsed time.Duration
+	// VisibilityTimeout is the visibility timeout the message was received with.
+	VisibilityTimeout time.Duration
+}
+
+// WithSlowHandlerThreshold warns about handlers running longer than fraction times the
+// visibility timeout: a warning is logged with the message ID and the elapsed time, the
+// SlowHandlers counter of the queue statistics (and the arrakis.handler.slow metric of
+// WithMetrics) is incremented and callback, if not nil, is notified. A handler is
+// reported at most once per message.
+//
+// Duplicates caused by visibility expiry are hard to diagnose after the fact; this makes
+// them visible before they happen.
+//
+// Parameters:
+//   - fraction: Fraction of the visibility timeout after which a handler is slow (e.g., 0.8)
+//   - callback: Optional function notified of each slow handler; it runs on its own goroutine
+//
+// Example:
+//
+//	consumer := NewConsumer(client, queueURL, handler, WithSlowHandlerThreshold(0.8, func(slow SlowHandler) {
+//	    slowHandlers.Inc()
+//	}))
+func WithSlowHandlerThreshold(fraction float64, callback func(SlowHandler)) ConsumerOption {
+	return func(c *consumerConfig) {
+		c.SlowHandlerFraction = fraction
+		c.OnSlowHandler = callback
+	}
+}
+
+// watchSlow starts watching the handling of msg and returns the function to call once the
+// handler returned. It does nothing when slow handler detection is disabled.
+func (c *Consumer) watchSlow(source queueSource, msg Message) (stop func()) {
+	if c.config.SlowHandlerFraction <= 0 {
+		return func() {}
+	}
+
+	visibility := time.Duration(source.client.config.visibilityTimeout()) * time.Second
+	threshold := time.Duration(c.config.SlowHandlerFraction * float64(visibility))
+	start := time.Now()
+
+	timer := time.AfterFunc(threshold, func() {
+		slow := SlowHandler{QueueURL: source.queueURL, MessageID: msg.ID, Elapsed: time.Since(start), VisibilityTimeout: visibility}
+
+		atomic.AddInt64(&source.state.slowHandlers, 1)
+		source.client.recordSlowHandler(source.queueURL)
+		source.client.logger().Warn("handler is slow, message may become visible again", "queue", slow.QueueURL, "message_id", slow.MessageID, "elapsed", slow.Elapsed, "visibility_timeout", visibility)
+
+		if c.config.OnSlowHandler != nil {
+			c.config.OnSlowHandler(slow)
+		}
+	})
+
+	return func() { timer.Stop() }
+}
@@ -0,0 +1,62 @@
+package sqs
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConsumerReportsSlowHandlers(t *testing.T) {
+	fake := &fakeSQS{}
+	fake.push(testMessage("slow", ""), testMessage("fast", ""))
+	client := newTestSQS(fake)
+	client.config.VisibilityTimeout = 1
+
+	var mu sync.Mutex
+	var reported []SlowHandler
+
+	handler := HandlerFunc(func(ctx context.Context, msg Message) error {
+		if msg.ID == "slow" {
+			time.Sleep(100 * time.Millisecond)
+		}
+		return nil
+	})
+
+	consumer := NewConsumer(client, "queue", handler, WithWorkers(2), WithSlowHandlerThreshold(0.05, func(slow SlowHandler) {
+		mu.Lock()
+		defer mu.Unlock()
+		reported = append(reported, slow)
+	}))
+	runConsumer(t, consumer, func() bool { return len(fake.deletedHandles()) == 2 })
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(reported) != 1 || reported[0].MessageID != "slow" {
+		t.Fatalf("Expected only the slow handler to be reported, got %+v", reported)
+	}
+	if reported[0].Elapsed < 50*time.Millisecond || reported[0].VisibilityTimeout != time.Second {
+		t.Errorf("Expected the elapsed time and visibility timeout, got %+v", reported[0])
+	}
+	if got := client.Stats()["queue"].SlowHandlers; got != 1 {
+		t.Errorf("Expected 1 slow handler in the stats, got %d", got)
+	}
+}
+
+func TestConsumerSlowHandlerDetectionDisabled(t *testing.T) {
+	fake := &fakeSQS{}
+	fake.push(testMessage("m1", ""))
+	client := newTestSQS(fake)
+	client.config.VisibilityTimeout = 1
+
+	consumer := NewConsumer(client, "queue", HandlerFunc(func(ctx context.Context, msg Message) error {
+		time.Sleep(20 * time.Millisecond)
+		return nil
+	}))
+	runConsumer(t, consumer, func() bool { return len(fake.deletedHandles()) == 1 })
+
+	if got := client.Stats()["queue"].SlowHandlers; got != 0 {
+		t.Errorf("Expected no slow handler without a threshold, got %d", got)
+	}
+}
@@ -0,0 +1,101 @@
+package sqs
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// SNS envelope values
+const (
+	_snsTypeNotification = "Notification" // Type of SNS envelopes carrying a message
+	_snsTopicArnField    = `"TopicArn"`   // Field present in every SNS envelope
+	_snsArnService       = ":sns:"        // Service segment of SNS topic ARNs, in every partition
+)
+
+// snsEnvelope is the JSON document SNS delivers to SQS when raw message delivery is off.
+type snsEnvelope struct {
+	Type              string                          `json:"Type"`
+	MessageID         string                          `json:"MessageId"`
+	TopicArn          string                          `json:"TopicArn"`
+	Message           *string                         `json:"Message"`
+	MessageAttributes map[string]snsEnvelopeAttribute `json:"MessageAttributes"`
+}
+
+// snsEnvelopeAttribute is a message attribute as encoded inside an SNS envelope.
+type snsEnvelopeAttribute struct {
+	Type  string `json:"Type"`
+	Value string `json:"Value"`
+}
+
+// UnwrapSNS detects SNS notification envelopes per message and replaces them with the
+// published message, so queues subscribed to topics with and without raw message delivery
+// (or receiving both direct sends and SNS fan-out) need no configuration. Bodies that are
+// not SNS envelopes pass through untouched.
+//
+// The message attributes published to the topic are copied from the envelope into
+// MessageAttributes, without overriding attributes already set on the SQS message.
+//
+// Example:
+//
+//	consumer := sqs.NewConsumer(client, queueURL, handler, sqs.WithTransforms(sqs.UnwrapSNS()))
+func UnwrapSNS() Transform {
+	return func(ctx context.Context, msg Message) (Message, error) {
+		envelope, ok := parseSNSEnvelope(msg.Body)
+		if !ok {
+			return msg, nil
+		}
+
+		msg.Body = *envelope.Message
+
+		if len(envelope.MessageAttributes) > 0 {
+			attributes := make(map[string]types.MessageAttributeValue, len(msg.MessageAttributes)+len(envelope.MessageAttributes))
+			for name, value := range envelope.MessageAttributes {
+				attributes[name] = snsAttributeValue(value)
+			}
+			for name, value := range msg.MessageAttributes {
+				attributes[name] = value
+			}
+			msg.MessageAttributes = attributes
+		}
+
+		return msg, nil
+	}
+}
+
+// parseSNSEnvelope reports whether body is an SNS notification envelope and decodes it.
+func parseSNSEnvelope(body string) (*snsEnvelope, bool) {
+	// Cheap pre-check so raw bodies are not parsed as JSON needlessly
+	if !strings.HasPrefix(strings.TrimSpace(body), "{") || !strings.Contains(body, _snsTopicArnField) {
+		return nil, false
+	}
+
+	var envelope snsEnvelope
+	if err := json.Unmarshal([]byte(body), &envelope); err != nil {
+		return nil, false
+	}
+
+	if envelope.Type != _snsTypeNotification || envelope.Message == nil || envelope.MessageID == "" ||
+		!strings.HasPrefix(envelope.TopicArn, "arn:") || !strings.Contains(envelope.TopicArn, _snsArnService) {
+		return nil, false
+	}
+
+	return &envelope, true
+}
+
+// snsAttributeValue converts an SNS envelope attribute into an SQS message attribute.
+// Binary values are kept base64-encoded, as SNS delivers them.
+func snsAttributeValue(attribute snsEnvelopeAttribute) types.MessageAttributeValue {
+	dataType := attribute.Type
+	if dataType == "" {
+		dataType = _attributeDataTypeString
+	}
+
+	return types.MessageAttributeValue{
+		DataType:    aws.String(dataType),
+		StringValue: aws.String(attribute.Value),
+	}
+}
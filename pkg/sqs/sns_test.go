@@ -0,0 +1,64 @@
+package sqs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+func TestUnwrapSNSDetectsEnvelopes(t *testing.T) {
+	envelope := `{
+		"Type": "Notification",
+		"MessageId": "b9d5e5a2",
+		"TopicArn": "arn:aws-cn:sns:cn-north-1:123456789012:orders",
+		"Message": "{\"order\":42}",
+		"MessageAttributes": {"type": {"Type": "String", "Value": "order.created"}}
+	}`
+
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"sns envelope", envelope, `{"order":42}`},
+		{"raw json", `{"order":42}`, `{"order":42}`},
+		{"raw text", "hello", "hello"},
+		{"look-alike", `{"Type":"Notification","TopicArn":"orders","Message":"x"}`, `{"Type":"Notification","TopicArn":"orders","Message":"x"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := UnwrapSNS()(context.Background(), Message{Body: tt.body})
+			if err != nil {
+				t.Fatalf("UnwrapSNS returned error: %v", err)
+			}
+			if out.Body != tt.want {
+				t.Errorf("Expected body %q, got %q", tt.want, out.Body)
+			}
+		})
+	}
+}
+
+func TestUnwrapSNSCopiesAttributes(t *testing.T) {
+	msg := Message{
+		Body: `{"Type":"Notification","MessageId":"1","TopicArn":"arn:aws:sns:us-east-1:1:t","Message":"hi",
+			"MessageAttributes":{"type":{"Type":"String","Value":"sns"},"tenant":{"Type":"String","Value":"acme"}}}`,
+		MessageAttributes: map[string]types.MessageAttributeValue{
+			"type": {DataType: aws.String("String"), StringValue: aws.String("sqs")},
+		},
+	}
+
+	out, err := UnwrapSNS()(context.Background(), msg)
+	if err != nil {
+		t.Fatalf("UnwrapSNS returned error: %v", err)
+	}
+
+	if aws.ToString(out.MessageAttributes["tenant"].StringValue) != "acme" {
+		t.Error("Expected envelope attributes to be copied")
+	}
+	if aws.ToString(out.MessageAttributes["type"].StringValue) != "sqs" {
+		t.Error("Expected SQS attributes to take precedence")
+	}
+}
@@ -0,0 +1,82 @@
+package sqs
+
+// Volume spike detection defaults
+const (
+	_defaultVolumeSpikePolls = 3 // Consecutive polls above the threshold before the spike callback fires
+)
+
+// VolumeSpike describes a sustained surge of the volume of a queue above its EWMA.
+type VolumeSpike struct {
+	// QueueURL is the queue whose volume surged.
+	QueueURL string
+	// Observed is the number of messages returned by the poll that confirmed the spike.
+	Observed int
+	// Average is the EWMA average the observation was compared to.
+	Average float64
+	// Polls is the number of consecutive polls that exceeded the threshold.
+	Polls int
+}
+
+// WithOnVolumeSpike registers a callback fired when the number of messages per poll stays
+// above factor times the EWMA average for several consecutive polls (3 by default, see
+// WithVolumeSpikePolls). It fires once per spike: the observed volume has to fall back
+// below the threshold before the next spike is reported. Applications can use it to
+// pre-warm downstream resources or alert before a backlog builds up.
+//
+// The average is taken as at least one message per poll, so a burst on an idle queue is
+// reported too. Spikes are only tracked while adaptive polling is enabled for the queue,
+// since the EWMA is not updated otherwise. The callback runs on the polling goroutine and
+// should return quickly.
+//
+// Parameters:
+//   - factor: How many times the average a poll must return to count as a spike (e.g., 3)
+//   - callback: Function notified of each spike
+//
+// Example:
+//
+//	option := WithOnVolumeSpike(3, func(spike VolumeSpike) {
+//	    log.Printf("%s: %d messages per poll, average %.1f", spike.QueueURL, spike.Observed, spike.Average)
+//	})
+func WithOnVolumeSpike(factor float64, callback func(VolumeSpike)) Option {
+	return func(c *config) {
+		c.VolumeSpikeFactor = factor
+		c.OnVolumeSpike = callback
+	}
+}
+
+// WithVolumeSpikePolls sets how many consecutive polls must exceed the spike threshold
+// before the WithOnVolumeSpike callback fires (default 3).
+func WithVolumeSpikePolls(polls int) Option {
+	return func(c *config) {
+		c.VolumeSpikePolls = polls
+	}
+}
+
+// observeSpike compares the size of a poll with the current EWMA average, before the
+// average is updated with it. It reports the average and true when the poll completes a
+// spike, i.e. it is the polls-th consecutive poll above factor times the average.
+func (a *arrakis) observeSpike(observed int, factor float64, polls int) (float64, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	average := a.average
+	if float64(observed) <= factor*max(average, 1) {
+		a.spikePolls = 0
+		return average, false
+	}
+
+	a.spikePolls++
+	return average, a.spikePolls == max(polls, 1)
+}
+
+// detectSpike runs spike detection on a poll of queueURL and notifies the callback.
+func (s *SQS) detectSpike(state *arrakis, queueURL string, observed int) {
+	if s.config.OnVolumeSpike == nil || s.config.VolumeSpikeFactor <= 0 {
+		return
+	}
+
+	polls := s.config.VolumeSpikePolls
+	if average, spike := state.observeSpike(observed, s.config.VolumeSpikeFactor, polls); spike {
+		s.config.OnVolumeSpike(VolumeSpike{QueueURL: queueURL, Observed: observed, Average: average, Polls: max(polls, 1)})
+	}
+}
@@ -0,0 +1,60 @@
+package sqs
+
+import (
+	"context"
+	"testing"
+)
+
+func TestObserveSpikeRequiresConsecutivePolls(t *testing.T) {
+	state := newArrakis(newTestSQS(&fakeSQS{}).config)
+	state.average = 4
+
+	if _, spike := state.observeSpike(20, 3, 2); spike {
+		t.Error("Expected no spike after a single poll")
+	}
+	if _, spike := state.observeSpike(12, 3, 2); spike {
+		t.Error("Expected a poll at the threshold to reset the count")
+	}
+	if _, spike := state.observeSpike(20, 3, 2); spike {
+		t.Error("Expected no spike after the count was reset")
+	}
+
+	average, spike := state.observeSpike(20, 3, 2)
+	if !spike || average != 4 {
+		t.Errorf("Expected a spike against average 4, got %v (average %v)", spike, average)
+	}
+
+	if _, spike := state.observeSpike(20, 3, 2); spike {
+		t.Error("Expected a single notification per spike")
+	}
+}
+
+func TestObserveSpikeOnIdleQueue(t *testing.T) {
+	state := newArrakis(newTestSQS(&fakeSQS{}).config)
+
+	if _, spike := state.observeSpike(2, 3, 1); spike {
+		t.Error("Expected small polls on an idle queue not to count as spikes")
+	}
+	if _, spike := state.observeSpike(5, 3, 1); !spike {
+		t.Error("Expected a burst on an idle queue to count as a spike")
+	}
+}
+
+func TestReceiveReportsVolumeSpike(t *testing.T) {
+	fake := &fakeSQS{}
+	fake.push(testMessage("m1", ""), testMessage("m2", ""), testMessage("m3", ""), testMessage("m4", ""))
+
+	var spikes []VolumeSpike
+	client := newTestSQS(fake, WithOnVolumeSpike(3, func(spike VolumeSpike) {
+		spikes = append(spikes, spike)
+	}), WithVolumeSpikePolls(1))
+	client.EnableArrakis()
+
+	if _, err := client.ReceiveMessage(context.Background(), "queue", 10, nil); err != nil {
+		t.Fatalf("ReceiveMessage returned error: %v", err)
+	}
+
+	if len(spikes) != 1 || spikes[0].QueueURL != "queue" || spikes[0].Observed != 4 {
+		t.Errorf("Expected one spike of 4 messages on queue, got %+v", spikes)
+	}
+}
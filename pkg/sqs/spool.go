@@ -0,0 +1,370 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+)
+
+// Default spool configuration values
+const (
+	_defaultSpoolSize      = 1000             // Messages held by a spool without MaxMessages
+	_defaultSpoolBaseDelay = time.Second      // Delay of the first retry when no backoff is set
+	_defaultSpoolMaxDelay  = 30 * time.Second // Longest delay between retries when no backoff is set
+)
+
+// ErrSpooled is returned, wrapping the send error, by Producer sends that failed with a
+// transient error and were spooled for a background retry (see WithSpool). Fire-and-forget
+// callers can treat it as a success.
+var ErrSpooled = errors.New("message spooled for retry")
+
+// SpoolPolicy configures the in-memory spool of a Producer.
+type SpoolPolicy struct {
+	// MaxMessages bounds the spool (default: 1000). Messages failing while it is full are
+	// passed to OnOverflow, and their send returns the error as without a spool.
+	MaxMessages int
+	// Backoff computes the delay before each retry from the number of failed attempts. Nil
+	// doubles the delay from 1 second up to 30 seconds.
+	Backoff NackStrategy
+	// MaxAttempts is the number of failed attempts, the original send included, after
+	// which a message is passed to OnGiveUp and dropped. Zero retries until Close.
+	MaxAttempts int
+	// OnOverflow is notified of the messages dropped because the spool was full.
+	OnOverflow func(msg OutgoingMessage, err error)
+	// OnGiveUp is notified of the messages dropped after MaxAttempts attempts, failing with
+	// a permanent error, or still spooled when Close gives up on an in-memory spool.
+	OnGiveUp func(msg OutgoingMessage, err error)
+	// Dir, when set, persists the spool in an append log in this directory, so spooled
+	// messages survive a restart: the next producer spooling to Dir sends them again. A
+	// directory must be used by one producer at a time.
+	Dir string
+	// MaxBytes caps the size of the spool log of Dir (default: 64 MiB). Messages that don't
+	// fit are handled as when the spool is full.
+	MaxBytes int64
+}
+
+// WithSpool keeps the messages whose send fails with a transient error (throttling, 5xx
+// responses, network errors) in a bounded in-memory spool, and retries them in the
+// background until SQS accepts them, so brief SQS or network blips don't lose
+// fire-and-forget events. Send returns an error wrapping ErrSpooled for these messages, and
+// SendBatch reports them as failed with it.
+//
+// Spooled messages are retried one at a time, oldest first: a failing retry holds the rest
+// of the spool back for its backoff. New messages are still sent right away, so they can
+// overtake spooled ones. By default the spool lives in memory: call Producer.Close on
+// shutdown to give it a chance to drain, as anything still spooled when the process exits
+// is lost. With SpoolPolicy.Dir the spool is kept on disk instead, and NewProducer resends
+// the messages spooled by a previous process. A damaged log, e.g. after a crash during a
+// write, is replayed up to the damaged record, and the rest is dropped with a warning.
+//
+// Parameters:
+//   - policy: The bounds of the spool, the retry backoff and the callbacks
+//
+// Example:
+//
+//	producer := sqs.NewProducer(sqsClient, queueURL, sqs.WithSpool(sqs.SpoolPolicy{
+//	    MaxMessages: 10000,
+//	    OnOverflow: func(msg sqs.OutgoingMessage, err error) {
+//	        log.Printf("event lost, spool full: %v", err)
+//	    },
+//	}))
+//	defer producer.Close(shutdownCtx)
+func WithSpool(policy SpoolPolicy) ProducerOption {
+	return func(c *producerConfig) {
+		if policy.MaxMessages <= 0 {
+			policy.MaxMessages = _defaultSpoolSize
+		}
+		if policy.Backoff == nil {
+			policy.Backoff = ExponentialNack(_defaultSpoolBaseDelay, _defaultSpoolMaxDelay, 0)
+		}
+		if policy.MaxBytes <= 0 {
+			policy.MaxBytes = _defaultSpoolMaxBytes
+		}
+		c.Spool = &policy
+	}
+}
+
+// Spooled returns the number of messages waiting in the spool of the producer.
+func (p *Producer) Spooled() int {
+	return p.spool.len()
+}
+
+// Close waits for the spool of the producer to drain, or for ctx to be done. Messages
+// still spooled then are passed to OnGiveUp, or stay on disk for the next producer with
+// SpoolPolicy.Dir. Sends failing after Close are no longer spooled. Without WithSpool,
+// Close returns at once.
+//
+// Parameters:
+//   - ctx: Bounds how long the spool may take to drain
+//
+// Returns:
+//   - error: ctx.Err() when messages had to be given up, nil otherwise
+func (p *Producer) Close(ctx context.Context) error {
+	return p.spool.close(ctx)
+}
+
+// isTransient reports whether a send error is worth retrying later: SQS pushed back, or
+// the request didn't get a response.
+func isTransient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	return isThrottled(err) || retry.IsErrorRetryables(retry.DefaultRetryables).IsErrorRetryable(err) == aws.TrueTernary
+}
+
+// spoolEntry is a message waiting in the spool.
+type spoolEntry struct {
+	msg      OutgoingMessage
+	input    *sqs.SendMessageInput
+	seq      uint64    // Sequence number in the spool log (0 without one)
+	attempts int       // Failed attempts so far
+	retryAt  time.Time // When the next attempt is due
+}
+
+// producerSpool retries the messages of a producer in the background. A nil spool never
+// takes messages.
+type producerSpool struct {
+	policy SpoolPolicy
+	send   func(ctx context.Context, input *sqs.SendMessageInput) error
+	logger Logger
+	ctx    context.Context
+	cancel context.CancelFunc
+	log    *spoolLog // Persists the entries (nil for an in-memory spool)
+
+	mu      sync.Mutex
+	entries []spoolEntry
+	drained chan struct{} // Closed when the retry goroutine exits; nil while none runs
+	closed  bool
+}
+
+// newProducerSpool creates the spool of a producer, or returns nil without a policy. A
+// spool on disk starts resending the messages it holds right away; when its log can't be
+// opened, the spool falls back to memory.
+func newProducerSpool(policy *SpoolPolicy, send func(ctx context.Context, input *sqs.SendMessageInput) error, logger Logger) *producerSpool {
+	if policy == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &producerSpool{policy: *policy, send: send, logger: logger, ctx: ctx, cancel: cancel}
+	if policy.Dir == "" {
+		return s
+	}
+
+	log, entries, err := openSpoolLog(policy.Dir, policy.MaxBytes)
+	switch {
+	case log == nil:
+		logger.Warn("spool log unavailable, spooling in memory", "dir", policy.Dir, "error", err)
+		return s
+	case err != nil:
+		logger.Warn("spool log damaged, later records dropped", "dir", policy.Dir, "error", err)
+	}
+	s.log = log
+
+	if len(entries) > 0 {
+		now := time.Now()
+		for i := range entries {
+			entries[i].attempts, entries[i].retryAt = 1, now
+		}
+		s.entries = entries
+		s.drained = make(chan struct{})
+		go s.run(s.drained)
+	}
+
+	return s
+}
+
+// offer spools a message whose send failed with err, and reports whether it was spooled.
+// Messages the full spool can't take are passed to OnOverflow.
+func (s *producerSpool) offer(msg OutgoingMessage, input *sqs.SendMessageInput, err error) bool {
+	if s == nil || !isTransient(err) || s.exhausted(1) {
+		return false
+	}
+
+	s.mu.Lock()
+	switch {
+	case s.closed:
+		s.mu.Unlock()
+		return false
+	case len(s.entries) >= s.policy.MaxMessages:
+		s.mu.Unlock()
+		if s.policy.OnOverflow != nil {
+			s.policy.OnOverflow(msg, err)
+		}
+		return false
+	}
+
+	entry := spoolEntry{msg: msg, input: input, attempts: 1, retryAt: time.Now().Add(s.policy.Backoff.Delay(1))}
+	if !s.persist(&entry) {
+		s.mu.Unlock()
+		if s.policy.OnOverflow != nil {
+			s.policy.OnOverflow(msg, err)
+		}
+		return false
+	}
+
+	s.entries = append(s.entries, entry)
+	if s.drained == nil {
+		s.drained = make(chan struct{})
+		go s.run(s.drained)
+	}
+	s.mu.Unlock()
+
+	return true
+}
+
+// run retries the spooled messages until the spool is empty or closed.
+func (s *producerSpool) run(drained chan struct{}) {
+	defer close(drained)
+
+	for {
+		s.mu.Lock()
+		if len(s.entries) == 0 {
+			s.drained = nil
+			s.truncate()
+			s.mu.Unlock()
+			return
+		}
+		entry := s.entries[0]
+		s.mu.Unlock()
+
+		sleep(s.ctx, time.Until(entry.retryAt))
+		if s.ctx.Err() != nil {
+			s.giveUpAll(s.ctx.Err())
+			return
+		}
+
+		err := s.send(s.ctx, entry.input)
+		entry.attempts++
+		retry := isTransient(err) && !s.exhausted(entry.attempts)
+
+		s.mu.Lock()
+		if retry {
+			s.entries[0].attempts = entry.attempts
+			s.entries[0].retryAt = time.Now().Add(s.policy.Backoff.Delay(entry.attempts))
+		} else {
+			s.entries = s.entries[1:]
+			s.remove(entry.seq)
+		}
+		s.mu.Unlock()
+
+		if err != nil && !retry && s.policy.OnGiveUp != nil {
+			s.policy.OnGiveUp(entry.msg, err)
+		}
+	}
+}
+
+// exhausted reports whether a message failing attempts times is given up.
+func (s *producerSpool) exhausted(attempts int) bool {
+	return s.policy.MaxAttempts > 0 && attempts >= s.policy.MaxAttempts
+}
+
+// persist writes a new entry to the spool log, and reports whether it was spooled: false
+// when the log is full, even once compacted, or can't be written. Must be called with the
+// mutex held.
+func (s *producerSpool) persist(entry *spoolEntry) bool {
+	if s.log == nil {
+		return true
+	}
+
+	err := s.log.add(entry)
+	if errors.Is(err, errSpoolFull) {
+		if err = s.log.rewrite(s.entries); err == nil {
+			err = s.log.add(entry)
+		}
+	}
+	if err != nil && !errors.Is(err, errSpoolFull) {
+		s.logger.Warn("spool log write failed", "dir", s.policy.Dir, "error", err)
+	}
+
+	return err == nil
+}
+
+// remove records in the spool log that an entry left the spool. Must be called with the
+// mutex held.
+func (s *producerSpool) remove(seq uint64) {
+	if s.log == nil {
+		return
+	}
+
+	if err := s.log.done(seq); err != nil {
+		s.logger.Warn("spool log write failed, the message may be sent again after a restart", "dir", s.policy.Dir, "error", err)
+	}
+}
+
+// truncate empties the spool log once the spool drained. Must be called with the mutex held.
+func (s *producerSpool) truncate() {
+	if s.log == nil {
+		return
+	}
+
+	if err := s.log.rewrite(nil); err != nil {
+		s.logger.Warn("spool log truncation failed", "dir", s.policy.Dir, "error", err)
+	}
+}
+
+// giveUpAll empties the spool, passing its messages to OnGiveUp. The messages of a spool
+// on disk stay in its log instead.
+func (s *producerSpool) giveUpAll(err error) {
+	s.mu.Lock()
+	entries := s.entries
+	s.entries = nil
+	s.drained = nil
+	s.mu.Unlock()
+
+	if s.policy.OnGiveUp == nil || s.log != nil {
+		return
+	}
+	for _, entry := range entries {
+		s.policy.OnGiveUp(entry.msg, fmt.Errorf("spool closed: %w", err))
+	}
+}
+
+// len returns the number of spooled messages.
+func (s *producerSpool) len() int {
+	if s == nil {
+		return 0
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.entries)
+}
+
+// close stops taking messages and waits for the spool to drain, or gives up on it once
+// ctx is done.
+func (s *producerSpool) close(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	s.closed = true
+	drained := s.drained
+	s.mu.Unlock()
+
+	var err error
+	if drained != nil {
+		select {
+		case <-drained:
+		case <-ctx.Done():
+			s.cancel()
+			<-drained
+			err = ctx.Err()
+		}
+	}
+
+	if s.log != nil {
+		s.log.close()
+	}
+
+	return err
+}
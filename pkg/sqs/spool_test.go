@@ -0,0 +1,96 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/smithy-go"
+)
+
+var errThrottled = &smithy.GenericAPIError{Code: "ThrottlingException", Message: "slow down"}
+
+func fastSpool(policy SpoolPolicy) ProducerOption {
+	policy.Backoff = NackStrategyFunc(func(attempt int) time.Duration { return time.Millisecond })
+	return WithSpool(policy)
+}
+
+func TestSpoolRetriesAfterOutage(t *testing.T) {
+	fake := &fakeSQS{sendErr: errThrottled}
+	producer := NewProducer(newTestSQS(fake), "queue", WithAdaptiveThrottling(0), fastSpool(SpoolPolicy{}))
+
+	if _, err := producer.Send(context.Background(), "event"); !errors.Is(err, ErrSpooled) {
+		t.Fatalf("Expected the send to be spooled, got %v", err)
+	}
+
+	fake.mu.Lock()
+	fake.sendErr = nil
+	fake.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := producer.Close(ctx); err != nil {
+		t.Fatalf("Expected the spool to drain, got %v", err)
+	}
+
+	if sent := fake.sentMessages(); len(sent) != 1 || producer.Spooled() != 0 {
+		t.Errorf("Expected the spooled message sent, got %d sent and %d spooled", len(sent), producer.Spooled())
+	}
+}
+
+func TestSpoolOverflowAndGiveUp(t *testing.T) {
+	var (
+		mu                sync.Mutex
+		overflow, givenUp []string
+	)
+	record := func(list *[]string) func(OutgoingMessage, error) {
+		return func(msg OutgoingMessage, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			*list = append(*list, msg.Body)
+		}
+	}
+
+	fake := &fakeSQS{sendErr: errThrottled}
+	producer := NewProducer(newTestSQS(fake), "queue", WithAdaptiveThrottling(0), fastSpool(SpoolPolicy{
+		MaxMessages: 1,
+		OnOverflow:  record(&overflow),
+		OnGiveUp:    record(&givenUp),
+	}))
+
+	if _, err := producer.Send(context.Background(), "first"); !errors.Is(err, ErrSpooled) {
+		t.Fatalf("Expected the first send to be spooled, got %v", err)
+	}
+	if _, err := producer.Send(context.Background(), "second"); err == nil || errors.Is(err, ErrSpooled) {
+		t.Errorf("Expected the send error once the spool is full, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := producer.Close(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected Close to give up on the spool, got %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(overflow) != 1 || overflow[0] != "second" {
+		t.Errorf("Expected the second message to overflow, got %v", overflow)
+	}
+	if len(givenUp) != 1 || givenUp[0] != "first" {
+		t.Errorf("Expected the first message to be given up, got %v", givenUp)
+	}
+}
+
+func TestSpoolIgnoresPermanentErrors(t *testing.T) {
+	fake := &fakeSQS{sendErr: &smithy.GenericAPIError{Code: "AccessDenied", Message: "denied"}}
+	producer := NewProducer(newTestSQS(fake), "queue", fastSpool(SpoolPolicy{}))
+
+	if _, err := producer.Send(context.Background(), "event"); err == nil || errors.Is(err, ErrSpooled) {
+		t.Errorf("Expected the permanent error, got %v", err)
+	}
+	if producer.Spooled() != 0 {
+		t.Errorf("Expected nothing spooled, got %d", producer.Spooled())
+	}
+}
@@ -0,0 +1,264 @@
+package sqs
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// Disk spool configuration values
+const (
+	_spoolLogFile         = "spool.log" // Append log of a spool directory
+	_spoolLogHeaderSize   = 8           // Length and CRC-32 preceding every record
+	_defaultSpoolMaxBytes = 64 << 20    // Size cap of a spool log without MaxBytes
+)
+
+// errSpoolFull is returned when a message doesn't fit in the spool log.
+var errSpoolFull = errors.New("spool log full")
+
+// spoolRecord is a record of the spool log: a spooled message, or the removal of one.
+type spoolRecord struct {
+	Seq             uint64                                 `json:"seq"`
+	Done            bool                                   `json:"done,omitempty"`
+	ID              string                                 `json:"id,omitempty"`
+	QueueURL        string                                 `json:"queue_url,omitempty"`
+	Body            string                                 `json:"body,omitempty"`
+	GroupID         string                                 `json:"group_id,omitempty"`
+	DeduplicationID string                                 `json:"deduplication_id,omitempty"`
+	DelaySeconds    int32                                  `json:"delay_seconds,omitempty"`
+	Attributes      map[string]types.MessageAttributeValue `json:"attributes,omitempty"`
+}
+
+// spoolLog persists the messages of a spool in an append log, so they survive a restart.
+// Every record is framed by its length and CRC-32; a damaged record ends the replay, and
+// the log is rewritten with the records read before it. The log is compacted when it
+// fills up, and emptied whenever the spool drains.
+type spoolLog struct {
+	path     string
+	maxBytes int64
+	file     *os.File
+	size     int64
+	next     uint64 // Sequence number of the next spooled message, from 1
+}
+
+// openSpoolLog opens the spool log of dir, creating it if needed, and returns the
+// messages it still holds, oldest first.
+func openSpoolLog(dir string, maxBytes int64) (*spoolLog, []spoolEntry, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, nil, err
+	}
+
+	l := &spoolLog{path: filepath.Join(dir, _spoolLogFile), maxBytes: maxBytes, next: 1}
+
+	entries, damaged, err := l.replay()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Drop the replayed removals, and whatever followed a damaged record
+	if err := l.rewrite(entries); err != nil {
+		return nil, nil, err
+	}
+	if damaged != nil {
+		return l, entries, damaged
+	}
+
+	return l, entries, nil
+}
+
+// replay reads the log and returns the messages not removed yet. A damaged record is
+// reported as damaged; the records after it are ignored.
+func (l *spoolLog) replay() (entries []spoolEntry, damaged error, err error) {
+	file, err := os.Open(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	var (
+		reader  = bufio.NewReader(file)
+		header  [_spoolLogHeaderSize]byte
+		offset  int64
+		pending = map[uint64]int{} // Position in entries of each live message
+	)
+	for {
+		if _, err := io.ReadFull(reader, header[:]); err != nil {
+			if err != io.EOF {
+				damaged = fmt.Errorf("spool log %s: truncated record at offset %d", l.path, offset)
+			}
+			break
+		}
+
+		payload := make([]byte, binary.BigEndian.Uint32(header[:4]))
+		if _, err := io.ReadFull(reader, payload); err != nil {
+			damaged = fmt.Errorf("spool log %s: truncated record at offset %d", l.path, offset)
+			break
+		}
+
+		var record spoolRecord
+		if crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(header[4:]) || json.Unmarshal(payload, &record) != nil {
+			damaged = fmt.Errorf("spool log %s: corrupted record at offset %d", l.path, offset)
+			break
+		}
+		offset += int64(_spoolLogHeaderSize + len(payload))
+
+		l.next = max(l.next, record.Seq+1)
+		if record.Done {
+			if i, ok := pending[record.Seq]; ok {
+				entries[i].seq = 0
+				delete(pending, record.Seq)
+			}
+			continue
+		}
+		pending[record.Seq] = len(entries)
+		entries = append(entries, record.entry())
+	}
+
+	live := entries[:0]
+	for _, entry := range entries {
+		if entry.seq != 0 {
+			live = append(live, entry)
+		}
+	}
+
+	return live, damaged, nil
+}
+
+// add appends a spooled message to the log and assigns its sequence number.
+func (l *spoolLog) add(entry *spoolEntry) error {
+	entry.seq = l.next
+	if err := l.append(newSpoolRecord(*entry)); err != nil {
+		return err
+	}
+	l.next++
+
+	return nil
+}
+
+// done appends the removal of a spooled message to the log.
+func (l *spoolLog) done(seq uint64) error {
+	return l.append(spoolRecord{Seq: seq, Done: true})
+}
+
+// append writes a record and syncs it to disk. Spooled messages that would grow the log
+// past its size cap fail with errSpoolFull.
+func (l *spoolLog) append(record spoolRecord) error {
+	frame, err := encodeSpoolRecord(record)
+	if err != nil {
+		return err
+	}
+	if !record.Done && l.size+int64(len(frame)) > l.maxBytes {
+		return errSpoolFull
+	}
+
+	if _, err := l.file.Write(frame); err != nil {
+		return err
+	}
+	l.size += int64(len(frame))
+
+	return l.file.Sync()
+}
+
+// rewrite replaces the log with one holding entries only, through a temporary file so a
+// crash leaves either log whole.
+func (l *spoolLog) rewrite(entries []spoolEntry) error {
+	tmp := l.path + ".tmp"
+	file, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+
+	var size int64
+	for _, entry := range entries {
+		frame, err := encodeSpoolRecord(newSpoolRecord(entry))
+		if err == nil {
+			_, err = file.Write(frame)
+		}
+		if err != nil {
+			file.Close()
+			return err
+		}
+		size += int64(len(frame))
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	file.Close()
+
+	if err := os.Rename(tmp, l.path); err != nil {
+		return err
+	}
+
+	if l.file != nil {
+		l.file.Close()
+	}
+	l.file, err = os.OpenFile(l.path, os.O_APPEND|os.O_WRONLY, 0o600)
+	l.size = size
+
+	return err
+}
+
+// close closes the log file.
+func (l *spoolLog) close() error {
+	return l.file.Close()
+}
+
+// newSpoolRecord returns the log record of a spooled message.
+func newSpoolRecord(entry spoolEntry) spoolRecord {
+	return spoolRecord{
+		Seq:             entry.seq,
+		ID:              entry.msg.ID,
+		QueueURL:        aws.ToString(entry.input.QueueUrl),
+		Body:            aws.ToString(entry.input.MessageBody),
+		GroupID:         aws.ToString(entry.input.MessageGroupId),
+		DeduplicationID: aws.ToString(entry.input.MessageDeduplicationId),
+		DelaySeconds:    entry.input.DelaySeconds,
+		Attributes:      entry.input.MessageAttributes,
+	}
+}
+
+// entry returns the spooled message of a log record.
+func (r spoolRecord) entry() spoolEntry {
+	input := &sqs.SendMessageInput{
+		QueueUrl:          aws.String(r.QueueURL),
+		MessageBody:       aws.String(r.Body),
+		DelaySeconds:      r.DelaySeconds,
+		MessageAttributes: r.Attributes,
+	}
+	if r.GroupID != "" {
+		input.MessageGroupId = aws.String(r.GroupID)
+	}
+	if r.DeduplicationID != "" {
+		input.MessageDeduplicationId = aws.String(r.DeduplicationID)
+	}
+
+	return spoolEntry{msg: OutgoingMessage{ID: r.ID, Body: r.Body}, input: input, seq: r.Seq}
+}
+
+// encodeSpoolRecord frames a record for the log.
+func encodeSpoolRecord(record spoolRecord) ([]byte, error) {
+	payload, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+
+	frame := make([]byte, _spoolLogHeaderSize, _spoolLogHeaderSize+len(payload))
+	binary.BigEndian.PutUint32(frame[:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(frame[4:], crc32.ChecksumIEEE(payload))
+
+	return append(frame, payload...), nil
+}
@@ -0,0 +1,111 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// spoolAndStop spools bodies to dir while SQS is throttling, then stops the producer.
+func spoolAndStop(t *testing.T, dir string, bodies ...string) {
+	t.Helper()
+
+	producer := NewProducer(newTestSQS(&fakeSQS{sendErr: errThrottled}), "queue", WithAdaptiveThrottling(0), fastSpool(SpoolPolicy{Dir: dir}))
+	for _, body := range bodies {
+		if _, err := producer.Send(context.Background(), body, WithMessageAttributes(map[string]types.MessageAttributeValue{
+			"type": {DataType: aws.String(_attributeDataTypeString), StringValue: aws.String("event")},
+		})); !errors.Is(err, ErrSpooled) {
+			t.Fatalf("Expected %s to be spooled, got %v", body, err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	producer.Close(ctx)
+}
+
+// replaySpool starts a producer on dir and returns the messages it resent.
+func replaySpool(t *testing.T, dir string) *fakeSQS {
+	t.Helper()
+
+	fake := &fakeSQS{}
+	producer := NewProducer(newTestSQS(fake), "queue", fastSpool(SpoolPolicy{Dir: dir}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := producer.Close(ctx); err != nil {
+		t.Fatalf("Expected the replayed spool to drain, got %v", err)
+	}
+
+	return fake
+}
+
+func TestDiskSpoolSurvivesRestart(t *testing.T) {
+	dir := t.TempDir()
+	spoolAndStop(t, dir, "first", "second")
+
+	sent := replaySpool(t, dir).sentMessages()
+	if len(sent) != 2 || aws.ToString(sent[0].MessageBody) != "first" || aws.ToString(sent[1].MessageBody) != "second" {
+		t.Fatalf("Expected both spooled messages resent in order, got %d", len(sent))
+	}
+	if got := aws.ToString(sent[0].MessageAttributes["type"].StringValue); got != "event" {
+		t.Errorf("Expected the attributes to be restored, got %q", got)
+	}
+
+	// The drained spool leaves an empty log behind
+	if sent := replaySpool(t, dir).sentMessages(); len(sent) != 0 {
+		t.Errorf("Expected nothing left to resend, got %d", len(sent))
+	}
+}
+
+func TestDiskSpoolDamagedLog(t *testing.T) {
+	dir := t.TempDir()
+	spoolAndStop(t, dir, "first", "second")
+
+	// A crash in the middle of the last write
+	path := filepath.Join(dir, _spoolLogFile)
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Truncate(path, info.Size()-3); err != nil {
+		t.Fatal(err)
+	}
+
+	sent := replaySpool(t, dir).sentMessages()
+	if len(sent) != 1 || aws.ToString(sent[0].MessageBody) != "first" {
+		t.Errorf("Expected the records before the damaged one resent, got %d", len(sent))
+	}
+}
+
+func TestDiskSpoolSizeCap(t *testing.T) {
+	var overflow []string
+	producer := NewProducer(newTestSQS(&fakeSQS{sendErr: errThrottled}), "queue", WithAdaptiveThrottling(0), fastSpool(SpoolPolicy{
+		Dir:        t.TempDir(),
+		MaxBytes:   150,
+		OnOverflow: func(msg OutgoingMessage, err error) { overflow = append(overflow, msg.Body) },
+	}))
+	defer func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		producer.Close(ctx)
+	}()
+
+	if _, err := producer.Send(context.Background(), "small"); !errors.Is(err, ErrSpooled) {
+		t.Fatalf("Expected the first message to be spooled, got %v", err)
+	}
+	if _, err := producer.Send(context.Background(), strings.Repeat("x", 100)); errors.Is(err, ErrSpooled) {
+		t.Error("Expected the message exceeding the log size cap not to be spooled")
+	}
+
+	if len(overflow) != 1 {
+		t.Errorf("Expected 1 overflow, got %v", overflow)
+	}
+}
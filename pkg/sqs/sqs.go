@@ -23,16 +23,28 @@ package sqs
 
 import (
 	"context"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
 	"github.com/elissonalvesilva/arrakis/pkg/internal/infra/utils"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Default SQS configuration values
 const (
 	// _defaultNumberOfMessages is the default maximum number of messages to retrieve in a single poll
 	_defaultNumberOfMessages = 10
+	// _maxNumberOfMessages is the largest number of messages SQS returns in a single poll
+	_maxNumberOfMessages = 10
+
+	// SQS long polling limits for WaitTimeSeconds
+	_minWaitTimeSeconds = 0
+	_maxWaitTimeSeconds = 20
 )
 
 // Default adaptive polling configuration values
@@ -51,10 +63,10 @@ const (
 	_defaultEnableAdaptivePolling  = false // Adaptive polling disabled by default
 
 	// EWMA calculation thresholds
-	_lowVolumeMessageThreshold = 2   // Threshold to consider a cycle as low volume
-	_ewmaResetAverageThreshold = 1.0 // EWMA average threshold for reset eligibility
-	_minResetIntervalMinutes   = 1   // Minimum time between EWMA resets
-	_consecutiveEmptyThreshold = 2   // Empty responses before triggering EWMA decay
+	_defaultLowVolumeMessageThreshold = 2           // Threshold to consider a cycle as low volume
+	_defaultEwmaResetAverageThreshold = 1.0         // EWMA average threshold for reset eligibility
+	_defaultMinResetInterval          = time.Minute // Minimum time between EWMA resets
+	_defaultConsecutiveEmptyThreshold = 2           // Empty responses before triggering EWMA decay
 
 	// Volume classification thresholds for wait time calculation
 	_lowVolumeThreshold    = 2  // Threshold between idle and low volume
@@ -70,9 +82,33 @@ const (
 // SQS represents an enhanced Amazon SQS client with adaptive polling capabilities.
 // It wraps the standard AWS SQS client and adds intelligent polling features through
 // the Arrakis adaptive polling algorithm.
+//
+// An SQS client is safe for concurrent use. In particular, the runtime toggles
+// (EnableArrakis, DisableArrakis and their per-queue variants) and the configuration
+// changes (UpdateConfig, WatchConfigFile) can be called from any goroutine, e.g. an admin
+// endpoint or a feature flag listener, while queues are being polled. Settings are read as
+// consistent snapshots: a wait time is computed from a change entirely or not at all.
 type SQS struct {
-	client *sqs.Client // The underlying AWS SQS client
-	config config      // Configuration for SQS operations and adaptive polling
+	client sqsAPI  // The underlying AWS SQS client
+	config *config // Configuration for SQS operations and adaptive polling
+
+	statesMu sync.RWMutex        // Protects states
+	states   map[string]*arrakis // Adaptive polling state per queue URL
+
+	closed atomic.Bool // Set by Close, fails later receives
+}
+
+// sqsAPI is the subset of the AWS SQS client used by this package. It allows the
+// underlying client to be replaced in tests.
+type sqsAPI interface {
+	ReceiveMessage(ctx context.Context, params *sqs.ReceiveMessageInput, optFns ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error)
+	DeleteMessage(ctx context.Context, params *sqs.DeleteMessageInput, optFns ...func(*sqs.Options)) (*sqs.DeleteMessageOutput, error)
+	ChangeMessageVisibility(ctx context.Context, params *sqs.ChangeMessageVisibilityInput, optFns ...func(*sqs.Options)) (*sqs.ChangeMessageVisibilityOutput, error)
+	ChangeMessageVisibilityBatch(ctx context.Context, params *sqs.ChangeMessageVisibilityBatchInput, optFns ...func(*sqs.Options)) (*sqs.ChangeMessageVisibilityBatchOutput, error)
+	GetQueueAttributes(ctx context.Context, params *sqs.GetQueueAttributesInput, optFns ...func(*sqs.Options)) (*sqs.GetQueueAttributesOutput, error)
+	GetQueueUrl(ctx context.Context, params *sqs.GetQueueUrlInput, optFns ...func(*sqs.Options)) (*sqs.GetQueueUrlOutput, error)
+	SendMessage(ctx context.Context, params *sqs.SendMessageInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageOutput, error)
+	SendMessageBatch(ctx context.Context, params *sqs.SendMessageBatchInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageBatchOutput, error)
 }
 
 // NewSQS creates a new enhanced SQS client with adaptive polling capabilities.
@@ -102,7 +138,8 @@ func NewSQS(awsconfig *aws.Config) *SQS {
 
 	return &SQS{
 		client: sqs.NewFromConfig(*awsconfig),
-		config: config,
+		config: &config,
+		states: map[string]*arrakis{},
 	}
 }
 
@@ -136,9 +173,15 @@ func NewSQSWithOptions(awsconfig *aws.Config, options ...Option) *SQS {
 		opt(&config)
 	}
 
+	clientConfig := *awsconfig
+	if config.AssumeRole != nil {
+		clientConfig = assumeRoleCredentials(clientConfig, config.AssumeRole)
+	}
+
 	return &SQS{
-		client: sqs.NewFromConfig(*awsconfig),
-		config: config,
+		client: sqs.NewFromConfig(clientConfig, config.ClientOptions...),
+		config: &config,
+		states: map[string]*arrakis{},
 	}
 }
 
@@ -148,15 +191,22 @@ func NewSQSWithOptions(awsconfig *aws.Config, options ...Option) *SQS {
 //
 // This should be called after creating the SQS client if you want to use adaptive polling.
 // The algorithm starts learning message patterns immediately upon activation.
+// Queues configured with EnableArrakisFor or DisableArrakisFor keep their own setting.
 func (s *SQS) EnableArrakis() {
-	s.config.AdaptivePolling.EnableAdaptivePolling = true
+	s.config.setAdaptivePollingEnabled(true)
 }
 
 // DisableArrakis deactivates the adaptive polling algorithm for this SQS client.
 // When disabled, the client will use standard SQS polling without any wait time optimizations.
 // The EWMA state is preserved and will resume if adaptive polling is re-enabled.
+// Queues configured with EnableArrakisFor or DisableArrakisFor keep their own setting.
+//
+// Adaptive long polls in progress on the queues this disables are cancelled, so consumers
+// poll again at once with their own wait time instead of blocking for up to 20 seconds.
 func (s *SQS) DisableArrakis() {
-	s.config.AdaptivePolling.EnableAdaptivePolling = false
+	s.interruptDisabled(func() {
+		s.config.setAdaptivePollingEnabled(false)
+	})
 }
 
 // IsArrakisEnabled returns the current state of the adaptive polling algorithm.
@@ -164,7 +214,142 @@ func (s *SQS) DisableArrakis() {
 // Returns:
 //   - bool: true if adaptive polling is active, false otherwise
 func (s *SQS) IsArrakisEnabled() bool {
-	return s.config.AdaptivePolling.EnableAdaptivePolling
+	return s.config.adaptivePolling().EnableAdaptivePolling
+}
+
+// EnableArrakisFor activates adaptive polling for a single queue, regardless of the
+// client-wide setting. This allows mixing adaptive queues with latency-critical queues
+// that keep a fixed wait time on the same client.
+//
+// Parameters:
+//   - queueURL: The URL of the queue to enable adaptive polling for
+//
+// Example:
+//
+//	sqsClient.EnableArrakisFor(reportsQueueURL) // adaptive, low traffic
+//	// paymentsQueueURL keeps the client-wide setting (disabled by default)
+func (s *SQS) EnableArrakisFor(queueURL string) {
+	enabled := true
+	s.state(queueURL).setOverride(&enabled)
+}
+
+// DisableArrakisFor deactivates adaptive polling for a single queue, regardless of the
+// client-wide setting. ReceiveMessage calls for the queue then use the wait time given
+// by the caller. The EWMA state of the queue is preserved, and an adaptive long poll in
+// progress on the queue is cancelled (see DisableArrakis).
+//
+// Parameters:
+//   - queueURL: The URL of the queue to disable adaptive polling for
+func (s *SQS) DisableArrakisFor(queueURL string) {
+	enabled := false
+	state := s.state(queueURL)
+	s.interruptDisabled(func() {
+		state.setOverride(&enabled)
+	})
+}
+
+// ResetArrakisFor removes the per-queue setting made by EnableArrakisFor or
+// DisableArrakisFor, so the queue follows the client-wide setting again.
+//
+// Parameters:
+//   - queueURL: The URL of the queue
+func (s *SQS) ResetArrakisFor(queueURL string) {
+	if state, ok := s.lookupState(queueURL); ok {
+		s.interruptDisabled(func() {
+			state.setOverride(nil)
+		})
+	}
+}
+
+// IsArrakisEnabledFor reports whether adaptive polling applies to a queue, taking both
+// the per-queue and the client-wide settings into account.
+//
+// Parameters:
+//   - queueURL: The URL of the queue
+//
+// Returns:
+//   - bool: true if ReceiveMessage calls for the queue use adaptive wait times
+func (s *SQS) IsArrakisEnabledFor(queueURL string) bool {
+	if state, ok := s.lookupState(queueURL); ok {
+		return state.enabled()
+	}
+
+	return s.IsArrakisEnabled()
+}
+
+// CurrentVolumeClass returns how Arrakis currently classifies a queue's message volume.
+// Queues that haven't been polled yet are reported as VolumeIdle.
+//
+// Parameters:
+//   - queueURL: The URL of the queue
+//
+// Returns:
+//   - VolumeClass: The volume class driving the queue's wait time
+//
+// Example:
+//
+//	if sqsClient.CurrentVolumeClass(queueURL) >= sqs.VolumeHigh {
+//	    batchSize = 10
+//	}
+func (s *SQS) CurrentVolumeClass(queueURL string) VolumeClass {
+	state, ok := s.lookupState(queueURL)
+	if !ok {
+		return VolumeIdle
+	}
+
+	return state.volumeClass()
+}
+
+// CurrentAverage returns the current EWMA average of messages per poll for a queue.
+// Queues that haven't been polled yet report 0.
+//
+// Parameters:
+//   - queueURL: The URL of the queue
+//
+// Returns:
+//   - float64: The EWMA average message count
+func (s *SQS) CurrentAverage(queueURL string) float64 {
+	state, ok := s.lookupState(queueURL)
+	if !ok {
+		return 0
+	}
+
+	return state.currentAverage()
+}
+
+// LastWaitTime returns the WaitTimeSeconds used by the latest adaptive ReceiveMessage
+// call on a queue. It reports 0 if the queue hasn't been polled with Arrakis enabled.
+//
+// Parameters:
+//   - queueURL: The URL of the queue
+//
+// Returns:
+//   - int64: The last wait time in seconds
+func (s *SQS) LastWaitTime(queueURL string) int64 {
+	state, ok := s.lookupState(queueURL)
+	if !ok {
+		return 0
+	}
+
+	return atomic.LoadInt64(&state.lastWaitTime)
+}
+
+// NextWaitTime returns the WaitTimeSeconds the next adaptive ReceiveMessage call on a queue
+// would use, given its current state, without polling or changing that state. The wait time
+// hook, if any, is applied.
+//
+// Parameters:
+//   - queueURL: The URL of the queue
+//
+// Returns:
+//   - int64: The next wait time in seconds
+func (s *SQS) NextWaitTime(queueURL string) int64 {
+	state, ok := s.lookupState(queueURL)
+	if !ok {
+		state = newArrakis(s.config)
+	}
+
+	return min(max(state.adjustedWaitTime(queueURL), _minWaitTimeSeconds), _maxWaitTimeSeconds)
 }
 
 // ReceiveMessage retrieves messages from the specified SQS queue with optional adaptive polling.
@@ -175,12 +360,16 @@ func (s *SQS) IsArrakisEnabled() bool {
 // Parameters:
 //   - ctx: Context for request cancellation and timeouts
 //   - queueURL: The URL of the SQS queue to receive messages from
-//   - maxMsg: Maximum number of messages to retrieve (1-10). If 0, defaults to 10
+//   - maxMsg: Maximum number of messages to retrieve (1-10). If 0, defaults to the configured
+//     batch size (see WithMaxNumberOfMessages)
 //   - messageAttributes: Map of message attribute names to retrieve. Keys become attribute names
+//     (nil with WithAllMessageAttributes)
+//   - options: Optional per-call settings such as WithReceiveRequestAttemptID or WithAllMessageAttributes
 //
 // Returns:
 //   - *sqs.ReceiveMessageOutput: The SQS response containing received messages
-//   - error: Any error that occurred during the operation
+//   - error: *ErrInvalidMaxMessages when maxMsg is out of range, or any error that occurred
+//     during the operation
 //
 // Example:
 //
@@ -190,26 +379,240 @@ func (s *SQS) IsArrakisEnabled() bool {
 //	    return
 //	}
 //	fmt.Printf("Received %d messages\n", len(messages.Messages))
-func (s *SQS) ReceiveMessage(ctx context.Context, queueURL string, maxMsg int32, messageAttributes map[string]string) (*sqs.ReceiveMessageOutput, error) {
+func (s *SQS) ReceiveMessage(ctx context.Context, queueURL string, maxMsg int32, messageAttributes map[string]string, options ...ReceiveOption) (*sqs.ReceiveMessageOutput, error) {
+	maxMsg, err := s.maxMessages(maxMsg)
+	if err != nil {
+		return nil, err
+	}
+
 	input := &sqs.ReceiveMessageInput{
 		QueueUrl:              aws.String(queueURL),
-		MaxNumberOfMessages:   utils.GetOrDefault(maxMsg, _defaultNumberOfMessages).(int32),
-		VisibilityTimeout:     int32(s.config.VisibilityTimeout),
+		MaxNumberOfMessages:   maxMsg,
+		VisibilityTimeout:     int32(s.config.visibilityTimeout()),
 		MessageAttributeNames: utils.MapKeys(messageAttributes),
 	}
 
-	// Apply adaptive polling wait time if Arrakis is enabled
-	if s.IsArrakisEnabled() {
-		input.WaitTimeSeconds = int32(s.calculateWaitTime())
+	for _, opt := range options {
+		opt(input)
+	}
+
+	return s.receive(ctx, input)
+}
+
+// ReceiveMessages is ReceiveMessage returning Messages instead of the SDK response: bodies
+// and IDs dereferenced, receive count, sent time and group ID parsed, and every message
+// attribute included, so callers don't deal with SDK types. Adaptive polling applies
+// exactly as for ReceiveMessage.
+//
+// Parameters:
+//   - ctx: Context for request cancellation and timeouts
+//   - queueURL: The URL of the SQS queue to receive messages from
+//   - maxMsg: Maximum number of messages to retrieve (1-10). If 0, defaults to the configured
+//     batch size (see WithMaxNumberOfMessages)
+//   - options: Optional per-call settings such as WithReceiveRequestAttemptID
+//
+// Returns:
+//   - []Message: The received messages, possibly none
+//   - error: *ErrInvalidMaxMessages when maxMsg is out of range, or any error that occurred
+//     during the operation
+//
+// Example:
+//
+//	messages, err := sqsClient.ReceiveMessages(ctx, queueURL, 10)
+//	for _, msg := range messages {
+//	    log.Printf("%s received %d times, type %s", msg.ID, msg.ReceiveCount, msg.Attribute("type"))
+//	}
+func (s *SQS) ReceiveMessages(ctx context.Context, queueURL string, maxMsg int32, options ...ReceiveOption) ([]Message, error) {
+	maxMsg, err := s.maxMessages(maxMsg)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &sqs.ReceiveMessageInput{
+		QueueUrl:                    aws.String(queueURL),
+		MaxNumberOfMessages:         maxMsg,
+		VisibilityTimeout:           int32(s.config.visibilityTimeout()),
+		MessageAttributeNames:       []string{_allMessageAttributes},
+		MessageSystemAttributeNames: []types.MessageSystemAttributeName{types.MessageSystemAttributeNameAll},
+	}
+
+	for _, opt := range options {
+		opt(input)
+	}
+
+	output, err := s.receive(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]Message, len(output.Messages))
+	for i, m := range output.Messages {
+		messages[i] = NewMessage(queueURL, m)
+	}
+
+	return messages, nil
+}
+
+// ReceiveOption customizes a single ReceiveMessage call.
+type ReceiveOption func(*sqs.ReceiveMessageInput)
+
+// WithWaitTimeSeconds sets a fixed long polling wait time (0-20 seconds) for queues that
+// don't use adaptive polling. When adaptive polling applies to the queue, the computed
+// wait time takes precedence.
+func WithWaitTimeSeconds(waitTimeSeconds int32) ReceiveOption {
+	return func(input *sqs.ReceiveMessageInput) {
+		input.WaitTimeSeconds = waitTimeSeconds
+	}
+}
+
+// WithAllMessageAttributes requests every message attribute, so callers don't need to
+// enumerate attribute names. It replaces the names given to ReceiveMessage, which can then
+// be nil.
+//
+// Example:
+//
+//	output, err := sqsClient.ReceiveMessage(ctx, queueURL, 10, nil, sqs.WithAllMessageAttributes(), sqs.WithAllSystemAttributes())
+func WithAllMessageAttributes() ReceiveOption {
+	return func(input *sqs.ReceiveMessageInput) {
+		input.MessageAttributeNames = []string{_allMessageAttributes}
 	}
+}
+
+// WithAllSystemAttributes requests every message system attribute, such as
+// ApproximateReceiveCount, SentTimestamp and MessageGroupId.
+func WithAllSystemAttributes() ReceiveOption {
+	return func(input *sqs.ReceiveMessageInput) {
+		input.MessageSystemAttributeNames = []types.MessageSystemAttributeName{types.MessageSystemAttributeNameAll}
+	}
+}
 
-	output, err := s.client.ReceiveMessage(ctx, input)
+// TryReceive performs an immediate, non-blocking receive: WaitTimeSeconds is forced to 0
+// so the call returns right away with whatever is available. The adaptive polling
+// algorithm is bypassed entirely, so these checks don't pollute the EWMA state used by
+// regular polling.
+//
+// Parameters:
+//   - ctx: Context for request cancellation and timeouts
+//   - queueURL: The URL of the SQS queue to receive messages from
+//   - n: Maximum number of messages to retrieve (1-10). If 0, defaults to the configured
+//     batch size (see WithMaxNumberOfMessages)
+//
+// Returns:
+//   - *sqs.ReceiveMessageOutput: The SQS response, possibly without messages
+//   - error: *ErrInvalidMaxMessages when n is out of range, or any error that occurred
+//     during the operation
+//
+// Example:
+//
+//	output, err := sqsClient.TryReceive(ctx, queueURL, 1)
+//	if err == nil && len(output.Messages) > 0 {
+//	    fmt.Println("queue has pending work")
+//	}
+func (s *SQS) TryReceive(ctx context.Context, queueURL string, n int32) (*sqs.ReceiveMessageOutput, error) {
+	n, err := s.maxMessages(n)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := s.receiveMessage(ctx, &sqs.ReceiveMessageInput{
+		QueueUrl:            aws.String(queueURL),
+		MaxNumberOfMessages: n,
+		VisibilityTimeout:   int32(s.config.visibilityTimeout()),
+		WaitTimeSeconds:     0,
+	})
 	if err != nil {
 		return nil, err
 	}
 
+	return output, nil
+}
+
+// receive performs a ReceiveMessage call with a prepared input, applying the adaptive
+// wait time and feeding the response back into the algorithm. It is shared by
+// ReceiveMessage and the Consumer, which needs to request additional system attributes.
+func (s *SQS) receive(ctx context.Context, input *sqs.ReceiveMessageInput) (*sqs.ReceiveMessageOutput, error) {
+	return s.receiveMany(ctx, input, 0)
+}
+
+// receiveMany is receive fanned out over the concurrent calls needed to ask for n
+// messages (a single call of input when n is 0). The calls share one adaptive wait time
+// and their merged response is fed back into the algorithm as a single poll.
+func (s *SQS) receiveMany(ctx context.Context, input *sqs.ReceiveMessageInput, n int) (output *sqs.ReceiveMessageOutput, err error) {
+	queueURL := aws.ToString(input.QueueUrl)
+	state := s.state(queueURL)
+	adaptive := state.enabled()
+	issuedAt := state.now()
+
+	ctx, span := s.startSpan(ctx, _operationReceive, _operationNameReceive, semconv.MessagingOperationTypeReceive, trace.SpanKindClient, queueURL)
+	defer func() { endSpan(span, err) }()
+
+	settings := s.config.adaptivePolling()
+	sampleAge := settings.MessageAgeThreshold > 0 || settings.MessageAgeTarget > 0
+
+	// Apply adaptive polling wait time if Arrakis is enabled
+	if adaptive {
+		waitTime := state.clampWaitTime(state.adjustedWaitTime(queueURL))
+		state.recordWaitTime(waitTime)
+
+		if waitTime == 0 && settings.BackToBackPolling && settings.PollPacing > 0 {
+			state.pace(ctx, settings.PollPacing)
+		}
+		input.WaitTimeSeconds = int32(waitTime)
+		span.SetAttributes(_attributeWaitTime.Int64(waitTime))
+
+		if sampleAge {
+			requestSentTimestamp(input)
+		}
+	}
+
+	span.SetAttributes(_attributeAdaptive.Bool(adaptive))
+
+	// Long polls are cancelled when the client is closed, and when adaptive polling is
+	// disabled for the queue so the next poll uses the caller's wait time
+	pollCtx, done := state.polls.track(ctx)
+	defer done()
+	if s.closed.Load() {
+		return nil, ErrClosed
+	}
+
+	output, err = s.receiveParallel(pollCtx, input, n)
+	switch {
+	case err == nil:
+	case interrupted(ctx, pollCtx, ErrClosed):
+		return nil, ErrClosed
+	case interrupted(ctx, pollCtx, errPollInterrupted):
+		return &sqs.ReceiveMessageOutput{}, nil
+	default:
+		return nil, err
+	}
+
+	s.verifyReceived(queueURL, output)
+	span.SetAttributes(semconv.MessagingBatchMessageCount(len(output.Messages)))
+
+	s.recordLatencies(ctx, queueURL, state.observeLatency(output.Messages, time.Now()))
+
+	if adaptive && sampleAge {
+		state.observeAge(output.Messages, time.Now())
+	}
+
+	if adaptive {
+		s.detectSpike(state, queueURL, len(output.Messages))
+	}
+	s.detectAnomaly(state, queueURL, len(output.Messages))
+
 	// Update adaptive polling algorithm with the response
-	s.handleReceiveResponse(output)
+	previousClass := state.volumeClass()
+	state.handleReceiveResponse(output)
+	s.recordEvents(ctx, queueURL, state)
+	if currentClass := state.volumeClass(); currentClass != previousClass {
+		recordClassChange(span, previousClass, currentClass)
+		s.logger().Debug("volume class changed", "queue", queueURL, "from", previousClass.String(), "to", currentClass.String())
+	}
+
+	if adaptive {
+		s.detectActivityChange(state, queueURL)
+		state.recordDecision(issuedAt, int64(input.WaitTimeSeconds), len(output.Messages))
+	}
 
 	return output, nil
 }
@@ -222,6 +625,8 @@ func (s *SQS) ReceiveMessage(ctx context.Context, queueURL string, maxMsg int32,
 //   - ctx: Context for request cancellation and timeouts
 //   - queueURL: The URL of the SQS queue containing the message
 //   - receiptHandle: The receipt handle of the message to delete (obtained from ReceiveMessage)
+//   - optFns: Optional AWS SDK options for this call only, applied after the ones of
+//     WithAPIOptions
 //
 // Returns:
 //   - *sqs.DeleteMessageOutput: The SQS response confirming message deletion
@@ -236,11 +641,45 @@ func (s *SQS) ReceiveMessage(ctx context.Context, queueURL string, maxMsg int32,
 //	        log.Printf("Error deleting message: %v", err)
 //	    }
 //	}
-func (s *SQS) DeleteMessage(ctx context.Context, queueURL string, receiptHandle string) (*sqs.DeleteMessageOutput, error) {
+func (s *SQS) DeleteMessage(ctx context.Context, queueURL string, receiptHandle string, optFns ...func(*sqs.Options)) (*sqs.DeleteMessageOutput, error) {
 	output, err := s.client.DeleteMessage(ctx, &sqs.DeleteMessageInput{
 		QueueUrl:      aws.String(queueURL),
 		ReceiptHandle: aws.String(receiptHandle),
-	})
+	}, append(apiOptions(ctx), optFns...)...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return output, nil
+}
+
+// ChangeMessageVisibility changes how long a received message stays invisible to other
+// consumers. Setting the timeout to 0 makes the message immediately available again.
+// This is a standard SQS operation that is not affected by the adaptive polling algorithm.
+//
+// Parameters:
+//   - ctx: Context for request cancellation and timeouts
+//   - queueURL: The URL of the SQS queue containing the message
+//   - receiptHandle: The receipt handle of the message (obtained from ReceiveMessage)
+//   - visibilityTimeout: New visibility timeout in seconds, counted from now (0-43200)
+//   - optFns: Optional AWS SDK options for this call only, applied after the ones of
+//     WithAPIOptions
+//
+// Returns:
+//   - *sqs.ChangeMessageVisibilityOutput: The SQS response confirming the change
+//   - error: Any error that occurred during the operation
+//
+// Example:
+//
+//	// Retry the message in 30 seconds instead of waiting for the full visibility timeout
+//	_, err := sqsClient.ChangeMessageVisibility(ctx, queueURL, *message.ReceiptHandle, 30)
+func (s *SQS) ChangeMessageVisibility(ctx context.Context, queueURL string, receiptHandle string, visibilityTimeout int32, optFns ...func(*sqs.Options)) (*sqs.ChangeMessageVisibilityOutput, error) {
+	output, err := s.client.ChangeMessageVisibility(ctx, &sqs.ChangeMessageVisibilityInput{
+		QueueUrl:          aws.String(queueURL),
+		ReceiptHandle:     aws.String(receiptHandle),
+		VisibilityTimeout: visibilityTimeout,
+	}, append(apiOptions(ctx), optFns...)...)
 
 	if err != nil {
 		return nil, err
@@ -248,3 +687,94 @@ func (s *SQS) DeleteMessage(ctx context.Context, queueURL string, receiptHandle
 
 	return output, nil
 }
+
+// SendOption customizes a single SendMessage call.
+type SendOption func(*sqs.SendMessageInput)
+
+// WithMessageAttributes attaches user-defined message attributes to the message.
+func WithMessageAttributes(attributes map[string]types.MessageAttributeValue) SendOption {
+	return func(input *sqs.SendMessageInput) {
+		input.MessageAttributes = attributes
+	}
+}
+
+// WithMessageGroupID sets the MessageGroupId, required when sending to FIFO queues.
+func WithMessageGroupID(groupID string) SendOption {
+	return func(input *sqs.SendMessageInput) {
+		input.MessageGroupId = aws.String(groupID)
+	}
+}
+
+// WithDeduplicationID sets the MessageDeduplicationId of a FIFO message.
+func WithDeduplicationID(deduplicationID string) SendOption {
+	return func(input *sqs.SendMessageInput) {
+		input.MessageDeduplicationId = aws.String(deduplicationID)
+	}
+}
+
+// WithDelaySeconds delays the delivery of the message (0-900 seconds, standard queues only).
+func WithDelaySeconds(delaySeconds int32) SendOption {
+	return func(input *sqs.SendMessageInput) {
+		input.DelaySeconds = delaySeconds
+	}
+}
+
+// SendMessage sends a message to the specified SQS queue.
+// This is a standard SQS operation that is not affected by the adaptive polling algorithm.
+//
+// Parameters:
+//   - ctx: Context for request cancellation and timeouts
+//   - queueURL: The URL of the SQS queue to send the message to
+//   - body: The message payload
+//   - options: Optional settings such as attributes or the FIFO group ID
+//
+// Returns:
+//   - *sqs.SendMessageOutput: The SQS response containing the message ID
+//   - error: *ErrMessageTooLarge, *ErrInvalidMessageAttribute or *ErrInvalidFIFOMessage when
+//     the message fails local validation, *ErrChecksumMismatch (along with the output) with
+//     WithChecksumVerification, or any error that occurred during the operation
+//
+// Example:
+//
+//	_, err := sqsClient.SendMessage(ctx, queueURL, `{"order":42}`, sqs.WithMessageGroupID("customer-7"))
+func (s *SQS) SendMessage(ctx context.Context, queueURL string, body string, options ...SendOption) (*sqs.SendMessageOutput, error) {
+	return s.send(ctx, s.sendInput(queueURL, body, options))
+}
+
+// sendInput builds the SendMessage request of a message, with its options applied.
+func (s *SQS) sendInput(queueURL string, body string, options []SendOption) *sqs.SendMessageInput {
+	input := &sqs.SendMessageInput{
+		QueueUrl:    aws.String(queueURL),
+		MessageBody: aws.String(body),
+	}
+
+	for _, opt := range options {
+		opt(input)
+	}
+	s.deduplicate(input)
+
+	return input
+}
+
+// send performs a SendMessage call with a prepared input. It is shared by SendMessage
+// and the helpers that forward existing messages. The message is validated against the
+// SQS limits first, so oversized or malformed messages fail without an API call.
+func (s *SQS) send(ctx context.Context, input *sqs.SendMessageInput) (output *sqs.SendMessageOutput, err error) {
+	ctx, span := s.startSpan(ctx, _operationSend, _operationNameSend, semconv.MessagingOperationTypeSend, trace.SpanKindProducer, aws.ToString(input.QueueUrl))
+	defer func() { endSpan(span, err) }()
+
+	span.SetAttributes(semconv.MessagingMessageBodySize(len(aws.ToString(input.MessageBody))))
+
+	if err := validateSendInput(input); err != nil {
+		return nil, err
+	}
+
+	output, err = s.client.SendMessage(ctx, input, apiOptions(ctx)...)
+	if err != nil {
+		return nil, err
+	}
+
+	span.SetAttributes(semconv.MessagingMessageID(aws.ToString(output.MessageId)))
+
+	return output, s.verifySent(input, output)
+}
@@ -2,9 +2,13 @@ package sqs
 
 import (
 	"context"
+	"errors"
+	"sync"
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
 )
 
 // Basic SQS configuration tests
@@ -292,3 +296,262 @@ func TestArrakisIntegration(t *testing.T) {
 		_, _ = client.ReceiveMessage(context.Background(), "test-queue", 1, nil)
 	})
 }
+
+// Test that computed wait times are always within SQS limits
+func TestReceiveClampsWaitTime(t *testing.T) {
+	fake := &fakeSQS{}
+
+	var computed, clamped int64
+	client := newTestSQS(fake, WithIdleWaitTimeSeconds(60), WithOnWaitTimeClamped(func(c, cl int64) {
+		computed, clamped = c, cl
+	}))
+	client.EnableArrakis()
+
+	_, _ = client.ReceiveMessage(context.Background(), "queue", 1, nil)
+
+	if got := fake.receiveInputs[0].WaitTimeSeconds; got != _maxWaitTimeSeconds {
+		t.Errorf("Expected wait time clamped to %d, got %d", _maxWaitTimeSeconds, got)
+	}
+	if computed != 60 || clamped != _maxWaitTimeSeconds {
+		t.Errorf("Expected hook called with (60, %d), got (%d, %d)", _maxWaitTimeSeconds, computed, clamped)
+	}
+}
+
+// Test that the wait time hook adjusts computed wait times before they are clamped
+func TestReceiveAppliesWaitTimeHook(t *testing.T) {
+	fake := &fakeSQS{}
+
+	var hookedQueue string
+	var proposed int64
+	client := newTestSQS(fake, WithIdleWaitTimeSeconds(20), WithWaitTimeHook(func(queueURL string, wait int64) int64 {
+		hookedQueue, proposed = queueURL, wait
+		return min(wait, 5)
+	}))
+	client.EnableArrakis()
+
+	_, _ = client.ReceiveMessage(context.Background(), "queue", 1, nil)
+
+	if hookedQueue != "queue" || proposed != 20 {
+		t.Errorf("Expected hook called with (queue, 20), got (%s, %d)", hookedQueue, proposed)
+	}
+	if got := fake.receiveInputs[0].WaitTimeSeconds; got != 5 {
+		t.Errorf("Expected the wait time capped by the hook to 5, got %d", got)
+	}
+}
+
+// Test that TryReceive never waits and leaves the adaptive state untouched
+func TestTryReceiveBypassesArrakis(t *testing.T) {
+	fake := &fakeSQS{}
+	fake.push(testMessage("m1", ""), testMessage("m2", ""))
+	client := newTestSQS(fake)
+	client.EnableArrakis()
+
+	output, err := client.TryReceive(context.Background(), "queue", 0)
+	if err != nil {
+		t.Fatalf("TryReceive returned error: %v", err)
+	}
+
+	if len(output.Messages) != 2 {
+		t.Errorf("Expected 2 messages, got %d", len(output.Messages))
+	}
+	if input := fake.receiveInputs[0]; input.WaitTimeSeconds != 0 || input.MaxNumberOfMessages != 10 {
+		t.Errorf("Expected wait 0 and batch 10, got wait %d and batch %d", input.WaitTimeSeconds, input.MaxNumberOfMessages)
+	}
+	if client.state("queue").average != 0 || client.state("queue").messageCounts != 0 {
+		t.Error("Expected TryReceive not to update the EWMA state")
+	}
+}
+
+func TestReceiveMessages(t *testing.T) {
+	fake := &fakeSQS{}
+	first := testMessage("m1", "customer-7")
+	first.Attributes[_attributeApproximateReceiveCount] = "2"
+	first.MessageAttributes = map[string]types.MessageAttributeValue{"type": stringAttribute("order.created")}
+	fake.push(first, testMessage("m2", ""))
+	client := newTestSQS(fake)
+	client.EnableArrakis()
+
+	messages, err := client.ReceiveMessages(context.Background(), "queue", 0)
+	if err != nil {
+		t.Fatalf("ReceiveMessages returned error: %v", err)
+	}
+
+	if len(messages) != 2 || messages[0].ID != "m1" || messages[0].Body != "m1" || messages[0].QueueURL != "queue" {
+		t.Fatalf("Expected the two messages converted, got %+v", messages)
+	}
+	if messages[0].ReceiveCount != 2 || messages[0].GroupID != "customer-7" || messages[0].Attribute("type") != "order.created" {
+		t.Errorf("Expected parsed attributes, got %+v", messages[0])
+	}
+	if messages[1].Attribute("type") != "" {
+		t.Error("Expected a missing attribute to be empty")
+	}
+
+	input := fake.receiveInputs[0]
+	if len(input.MessageAttributeNames) != 1 || input.MessageAttributeNames[0] != _allMessageAttributes || len(input.MessageSystemAttributeNames) != 1 {
+		t.Errorf("Expected every attribute to be requested, got %v and %v", input.MessageAttributeNames, input.MessageSystemAttributeNames)
+	}
+	if client.state("queue").messageCounts == 0 {
+		t.Error("Expected the response to feed adaptive polling")
+	}
+}
+
+func TestReceiveMessageAllAttributes(t *testing.T) {
+	fake := &fakeSQS{}
+	client := newTestSQS(fake)
+
+	if _, err := client.ReceiveMessage(context.Background(), "queue", 1, nil, WithAllMessageAttributes(), WithAllSystemAttributes()); err != nil {
+		t.Fatalf("ReceiveMessage returned error: %v", err)
+	}
+
+	input := fake.receiveInputs[0]
+	if len(input.MessageAttributeNames) != 1 || input.MessageAttributeNames[0] != "All" {
+		t.Errorf("Expected every message attribute to be requested, got %v", input.MessageAttributeNames)
+	}
+	if len(input.MessageSystemAttributeNames) != 1 || input.MessageSystemAttributeNames[0] != types.MessageSystemAttributeNameAll {
+		t.Errorf("Expected every system attribute to be requested, got %v", input.MessageSystemAttributeNames)
+	}
+}
+
+// Test that FIFO receives retry network failures with the same attempt ID
+func TestReceiveRetriesFIFOWithSameAttemptID(t *testing.T) {
+	fake := &fakeSQS{receiveErrs: []error{&smithyhttp.RequestSendError{Err: errors.New("connection reset")}}}
+	fake.push(testMessage("m1", "group"))
+	client := newTestSQS(fake)
+
+	output, err := client.ReceiveMessage(context.Background(), "orders.fifo", 1, nil)
+	if err != nil {
+		t.Fatalf("Expected retry to succeed, got %v", err)
+	}
+	if len(output.Messages) != 1 {
+		t.Errorf("Expected 1 message, got %d", len(output.Messages))
+	}
+
+	if len(fake.receiveInputs) != 2 {
+		t.Fatalf("Expected 2 receive attempts, got %d", len(fake.receiveInputs))
+	}
+	first, second := fake.receiveInputs[0].ReceiveRequestAttemptId, fake.receiveInputs[1].ReceiveRequestAttemptId
+	if first == nil || second == nil || *first != *second {
+		t.Error("Expected both attempts to share a generated ReceiveRequestAttemptId")
+	}
+}
+
+// Test that an explicit attempt ID is used and standard queues aren't retried
+func TestReceiveRequestAttemptID(t *testing.T) {
+	fake := &fakeSQS{}
+	client := newTestSQS(fake)
+
+	_, _ = client.ReceiveMessage(context.Background(), "orders.fifo", 1, nil, WithReceiveRequestAttemptID("attempt-1"))
+	if id := fake.receiveInputs[0].ReceiveRequestAttemptId; id == nil || *id != "attempt-1" {
+		t.Errorf("Expected attempt ID attempt-1, got %v", id)
+	}
+
+	fake.receiveErrs = []error{&smithyhttp.RequestSendError{Err: errors.New("connection reset")}}
+	if _, err := client.ReceiveMessage(context.Background(), "standard", 1, nil); err == nil {
+		t.Error("Expected standard queue receive not to be retried")
+	}
+	if fake.receiveInputs[1].ReceiveRequestAttemptId != nil {
+		t.Error("Expected no attempt ID on standard queues")
+	}
+}
+
+// Test the per-queue accessors for the algorithm state
+func TestPerQueueStateAccessors(t *testing.T) {
+	fake := &fakeSQS{}
+	batch := make([]types.Message, 0, 10)
+	for i := 0; i < 10; i++ {
+		batch = append(batch, testMessage("m", ""))
+	}
+	fake.push(batch...)
+	client := newTestSQS(fake)
+	client.EnableArrakis()
+
+	if client.CurrentVolumeClass("busy") != VolumeIdle || client.CurrentAverage("busy") != 0 || client.LastWaitTime("busy") != 0 {
+		t.Error("Expected unknown queues to report an idle state")
+	}
+
+	_, _ = client.ReceiveMessage(context.Background(), "busy", 10, nil)
+	_, _ = client.ReceiveMessage(context.Background(), "quiet", 10, nil)
+
+	if avg := client.CurrentAverage("busy"); avg != 3 {
+		t.Errorf("Expected busy queue average 3, got %f", avg)
+	}
+	if class := client.CurrentVolumeClass("busy"); class != VolumeMedium {
+		t.Errorf("Expected busy queue to be medium volume, got %s", class)
+	}
+	if class := client.CurrentVolumeClass("quiet"); class != VolumeIdle {
+		t.Errorf("Expected quiet queue to stay idle, got %s", class)
+	}
+	if wait := client.LastWaitTime("busy"); wait != _defaultIdleWaitTimeSeconds {
+		t.Errorf("Expected first poll to use the idle wait time, got %d", wait)
+	}
+}
+
+func TestPerQueueArrakisToggle(t *testing.T) {
+	fake := &fakeSQS{}
+	client := newTestSQS(fake)
+	client.EnableArrakisFor("adaptive")
+
+	if !client.IsArrakisEnabledFor("adaptive") || client.IsArrakisEnabledFor("fixed") {
+		t.Fatal("Expected adaptive polling only for the enabled queue")
+	}
+
+	_, _ = client.ReceiveMessage(context.Background(), "adaptive", 10, nil)
+	_, _ = client.ReceiveMessage(context.Background(), "fixed", 10, nil, WithWaitTimeSeconds(1))
+
+	if wait := fake.receiveInputs[0].WaitTimeSeconds; wait != _defaultIdleWaitTimeSeconds {
+		t.Errorf("Expected adaptive queue to use the idle wait time, got %d", wait)
+	}
+	if wait := fake.receiveInputs[1].WaitTimeSeconds; wait != 1 {
+		t.Errorf("Expected fixed queue to keep its wait time, got %d", wait)
+	}
+
+	client.EnableArrakis()
+	client.DisableArrakisFor("fixed")
+
+	if client.IsArrakisEnabledFor("fixed") {
+		t.Error("Expected the per-queue setting to win over the client-wide setting")
+	}
+
+	client.ResetArrakisFor("fixed")
+
+	if !client.IsArrakisEnabledFor("fixed") {
+		t.Error("Expected the queue to follow the client-wide setting after a reset")
+	}
+}
+
+// Test that runtime toggles and configuration changes are safe while queues are polled
+func TestConcurrentToggleAndConfig(t *testing.T) {
+	fake := &fakeSQS{}
+	client := newTestSQS(fake)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				_, _ = client.ReceiveMessage(ctx, "queue", 10, nil)
+				_ = client.Stats()
+			}
+		}()
+	}
+
+	for i := range 200 {
+		if i%2 == 0 {
+			client.EnableArrakis()
+			client.DisableArrakisFor("queue")
+		} else {
+			client.DisableArrakis()
+			client.ResetArrakisFor("queue")
+		}
+		client.UpdateConfig(FileConfig{IdleWaitTimeSeconds: 10 + i%10})
+		_ = client.Config()
+		_ = client.IsArrakisEnabledFor("queue")
+	}
+
+	cancel()
+	wg.Wait()
+}
@@ -0,0 +1,114 @@
+package sqs
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// AlgorithmEvents counts the notable events of the adaptive polling algorithm for a
+// queue, so unexpected polling behavior can be correlated with what the algorithm did.
+type AlgorithmEvents struct {
+	// EWMAResets counts resets of the average after a sustained volume drop.
+	EWMAResets int64
+	// Decays counts idle-period decays applied to the average.
+	Decays int64
+	// SpikeClamps counts observations capped by spike protection.
+	SpikeClamps int64
+	// ClassTransitions counts changes of the volume class.
+	ClassTransitions int64
+}
+
+// QueueStats is a snapshot of the adaptive polling state of a queue.
+type QueueStats struct {
+	// VolumeClass is the current volume classification.
+	VolumeClass VolumeClass
+	// Average is the current EWMA average.
+	Average float64
+	// StdDev is the exponentially weighted standard deviation of the message counts around
+	// the average: how noisy the traffic of the queue is.
+	StdDev float64
+	// ClassConfidence is how firmly the average sits in its volume class, from 0 (on a
+	// class threshold) to 1 (at least one standard deviation away from any threshold).
+	// Below 0.5, VolumeClass keeps its previous value rather than following the average
+	// into a neighboring class.
+	ClassConfidence float64
+	// LastWaitTimeSeconds is the wait time used by the last adaptive receive.
+	LastWaitTimeSeconds int64
+	// InFlight is the number of messages being processed locally (see SetInFlight).
+	InFlight int64
+	// OldestMessageAge is the age of the oldest message of the last poll, when sampled
+	// (see WithMessageAgeThreshold).
+	OldestMessageAge time.Duration
+	// SlowHandlers counts the handler runs that exceeded the slow handler threshold (see
+	// WithSlowHandlerThreshold).
+	SlowHandlers int64
+	// DeliveryLatency summarizes the delivery latency of recent messages (see
+	// DeliveryLatency).
+	DeliveryLatency LatencyPercentiles
+	// Events counts the algorithm events since the queue was first polled.
+	Events AlgorithmEvents
+}
+
+// Stats returns a snapshot of the adaptive polling state of every queue polled so far,
+// keyed by queue URL, including the algorithm event counters.
+//
+// Returns:
+//   - map[string]QueueStats: Per-queue statistics
+//
+// Example:
+//
+//	for queueURL, stats := range sqsClient.Stats() {
+//	    log.Printf("%s: class=%s resets=%d decays=%d", queueURL, stats.VolumeClass, stats.Events.EWMAResets, stats.Events.Decays)
+//	}
+func (s *SQS) Stats() map[string]QueueStats {
+	s.statesMu.RLock()
+	defer s.statesMu.RUnlock()
+
+	stats := make(map[string]QueueStats, len(s.states))
+	for queueURL, state := range s.states {
+		stats[queueURL] = state.stats()
+	}
+
+	return stats
+}
+
+// stats captures a consistent snapshot of the queue statistics.
+//
+// Thread-safe operation using mutex protection.
+func (a *arrakis) stats() QueueStats {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return QueueStats{
+		VolumeClass:         a.class,
+		Average:             a.average,
+		StdDev:              a.stdDev(),
+		ClassConfidence:     a.classConfidence(),
+		LastWaitTimeSeconds: atomic.LoadInt64(&a.lastWaitTime),
+		InFlight:            atomic.LoadInt64(&a.inFlight),
+		OldestMessageAge:    a.oldestMessageAge(),
+		SlowHandlers:        atomic.LoadInt64(&a.slowHandlers),
+		DeliveryLatency:     percentiles(a.latencies.Items()),
+		Events:              a.events,
+	}
+}
+
+// observeClass moves the queue to the volume class of the average, counting the
+// transition. Noisy averages crossing into a neighboring class only move it once the class
+// confidence reaches _classConfidenceThreshold, so the wait time doesn't flap with every
+// poll around a threshold; averages further away always move it. Must be called with the
+// mutex held.
+func (a *arrakis) observeClass() {
+	class := a.classify(a.average)
+	if class == a.class {
+		return
+	}
+
+	neighbor := class == a.class+1 || class == a.class-1
+	if neighbor && class != VolumeIdle && a.classConfidence() < _classConfidenceThreshold {
+		return
+	}
+
+	a.class = class
+	a.events.ClassTransitions++
+}
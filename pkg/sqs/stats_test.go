@@ -0,0 +1,116 @@
+package sqs
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestStatsCountsAlgorithmEvents(t *testing.T) {
+	fake := &fakeSQS{}
+	fake.push(testMessage("m1", ""))
+	fake.push(testMessage("m2", ""), testMessage("m3", ""), testMessage("m4", ""), testMessage("m5", ""),
+		testMessage("m6", ""), testMessage("m7", ""), testMessage("m8", ""), testMessage("m9", ""))
+	client := newTestSQS(fake)
+	client.EnableArrakis()
+
+	_, _ = client.ReceiveMessage(context.Background(), "queue", 10, nil)
+	_, _ = client.ReceiveMessage(context.Background(), "queue", 10, nil)
+
+	stats, ok := client.Stats()["queue"]
+	if !ok {
+		t.Fatal("Expected stats for the polled queue")
+	}
+
+	if stats.Events.SpikeClamps != 1 {
+		t.Errorf("Expected 1 spike clamp, got %d", stats.Events.SpikeClamps)
+	}
+
+	if stats.Events.ClassTransitions == 0 {
+		t.Error("Expected at least one class transition")
+	}
+
+	if stats.VolumeClass != classifyVolume(stats.Average, _defaultNumberOfMessages) {
+		t.Errorf("Expected class %s for average %f, got %s", classifyVolume(stats.Average, _defaultNumberOfMessages), stats.Average, stats.VolumeClass)
+	}
+}
+
+func TestStatsCountsDecaysAndResets(t *testing.T) {
+	client := newTestSQS(&fakeSQS{})
+	client.EnableArrakis()
+	state := client.state("queue")
+
+	state.mu.Lock()
+	state.average = 8
+	state.class = state.classify(state.average)
+	state.resetEWMA()
+	state.average = 4
+	state.mu.Unlock()
+
+	atomic.StoreInt64(&state.lastUpdate, time.Now().Add(-time.Hour).Unix())
+	state.decayEWMA()
+
+	events := client.Stats()["queue"].Events
+	if events.EWMAResets != 1 {
+		t.Errorf("Expected 1 EWMA reset, got %d", events.EWMAResets)
+	}
+
+	if events.Decays != 1 {
+		t.Errorf("Expected 1 decay, got %d", events.Decays)
+	}
+
+	if events.ClassTransitions != 1 {
+		t.Errorf("Expected the decay to idle to count as a transition, got %d", events.ClassTransitions)
+	}
+}
+
+func TestThresholdOptions(t *testing.T) {
+	client := newTestSQS(&fakeSQS{},
+		WithLowVolumeMessageThreshold(1),
+		WithEwmaResetAverageThreshold(0.5),
+		WithMinResetInterval(time.Hour),
+		WithConsecutiveEmptyThreshold(3),
+	)
+	client.EnableArrakis()
+
+	settings := client.config.adaptivePolling()
+	if settings.LowVolumeMessageThreshold != 1 || settings.EwmaResetAverageThreshold != 0.5 ||
+		settings.MinResetInterval != time.Hour || settings.ConsecutiveEmptyThreshold != 3 {
+		t.Fatalf("Expected threshold options to be applied, got %+v", settings)
+	}
+
+	state := client.state("queue")
+	atomic.StoreInt64(&state.lastUpdate, time.Now().Add(-time.Hour).Unix())
+	state.mu.Lock()
+	state.average = 4
+	state.mu.Unlock()
+
+	// Two empty responses no longer trigger a decay
+	for i := 0; i < 2; i++ {
+		_, _ = client.ReceiveMessage(context.Background(), "queue", 10, nil)
+	}
+	if decays := client.Stats()["queue"].Events.Decays; decays != 0 {
+		t.Errorf("Expected no decay before 3 empty responses, got %d", decays)
+	}
+
+	_, _ = client.ReceiveMessage(context.Background(), "queue", 10, nil)
+	if decays := client.Stats()["queue"].Events.Decays; decays != 1 {
+		t.Errorf("Expected a decay after 3 empty responses, got %d", decays)
+	}
+}
+
+func TestMinResetIntervalPreventsResets(t *testing.T) {
+	client := newTestSQS(&fakeSQS{}, WithMinResetInterval(time.Hour), WithDropDetectionThreshold(1))
+	state := client.state("queue")
+
+	state.mu.Lock()
+	state.lastReset = time.Now().Add(-30 * time.Minute)
+	state.lowVolumeCycle = 5
+	shouldReset := state.shouldResetEWMA()
+	state.mu.Unlock()
+
+	if shouldReset {
+		t.Error("Expected no reset within the minimum reset interval")
+	}
+}
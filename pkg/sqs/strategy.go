@@ -0,0 +1,161 @@
+package sqs
+
+import (
+	"math"
+)
+
+// WaitTimeStrategy computes the long polling wait time of a queue from the one proposed by
+// the adaptive polling algorithm. Strategies are composed with MinWaitTime, MaxWaitTime,
+// WeightedWaitTime and FallbackWaitTime, and installed with WithWaitTimeStrategy.
+type WaitTimeStrategy interface {
+	// WaitTime returns the wait time, in seconds, of the next receive on queueURL, given the
+	// wait time proposed by the adaptive polling algorithm. ok is false when the strategy
+	// has no opinion, e.g. because its data source is unavailable.
+	WaitTime(queueURL string, proposed int64) (seconds int64, ok bool)
+}
+
+// WaitTimeStrategyFunc adapts an ordinary function to WaitTimeStrategy.
+type WaitTimeStrategyFunc func(queueURL string, proposed int64) (int64, bool)
+
+// WaitTime calls f(queueURL, proposed).
+func (f WaitTimeStrategyFunc) WaitTime(queueURL string, proposed int64) (int64, bool) {
+	return f(queueURL, proposed)
+}
+
+// WeightedStrategy is a strategy and its weight in WeightedWaitTime.
+type WeightedStrategy struct {
+	Strategy WaitTimeStrategy
+	Weight   float64
+}
+
+// AdaptiveWaitTime keeps the wait time of the adaptive polling algorithm (EWMA of the
+// received volume), to be composed with other strategies.
+func AdaptiveWaitTime() WaitTimeStrategy {
+	return WaitTimeStrategyFunc(func(_ string, proposed int64) (int64, bool) {
+		return proposed, true
+	})
+}
+
+// FixedWaitTime always waits the same number of seconds.
+//
+// Parameters:
+//   - seconds: The wait time
+func FixedWaitTime(seconds int64) WaitTimeStrategy {
+	return WaitTimeStrategyFunc(func(string, int64) (int64, bool) {
+		return seconds, true
+	})
+}
+
+// MinWaitTime uses the shortest wait time of the strategies with an opinion, so that the
+// most eager strategy wins.
+//
+// Example:
+//
+//	// Poll fast when either the volume or the queue depth calls for it
+//	strategy := MinWaitTime(AdaptiveWaitTime(), queueDepthStrategy)
+func MinWaitTime(strategies ...WaitTimeStrategy) WaitTimeStrategy {
+	return WaitTimeStrategyFunc(func(queueURL string, proposed int64) (int64, bool) {
+		return reduceWaitTime(strategies, queueURL, proposed, func(a, b int64) int64 { return min(a, b) })
+	})
+}
+
+// MaxWaitTime uses the longest wait time of the strategies with an opinion, so that the
+// most conservative strategy wins.
+func MaxWaitTime(strategies ...WaitTimeStrategy) WaitTimeStrategy {
+	return WaitTimeStrategyFunc(func(queueURL string, proposed int64) (int64, bool) {
+		return reduceWaitTime(strategies, queueURL, proposed, func(a, b int64) int64 { return max(a, b) })
+	})
+}
+
+// WeightedWaitTime uses the weighted average of the wait times of the strategies with an
+// opinion, rounded to the nearest second. Strategies without an opinion, or with a weight
+// that isn't positive, are left out of the average.
+//
+// Example:
+//
+//	strategy := WeightedWaitTime(
+//	    WeightedStrategy{Strategy: AdaptiveWaitTime(), Weight: 3},
+//	    WeightedStrategy{Strategy: queueDepthStrategy, Weight: 1},
+//	)
+func WeightedWaitTime(strategies ...WeightedStrategy) WaitTimeStrategy {
+	return WaitTimeStrategyFunc(func(queueURL string, proposed int64) (int64, bool) {
+		var sum, weights float64
+		for _, weighted := range strategies {
+			if weighted.Weight <= 0 {
+				continue
+			}
+
+			if seconds, ok := weighted.Strategy.WaitTime(queueURL, proposed); ok {
+				sum += float64(seconds) * weighted.Weight
+				weights += weighted.Weight
+			}
+		}
+
+		if weights == 0 {
+			return 0, false
+		}
+
+		return int64(math.Round(sum / weights)), true
+	})
+}
+
+// FallbackWaitTime uses the wait time of the first strategy with an opinion, e.g. a
+// strategy based on an external metric, falling back to the adaptive algorithm when the
+// metric is unavailable.
+//
+// Example:
+//
+//	strategy := FallbackWaitTime(cloudWatchStrategy, AdaptiveWaitTime())
+func FallbackWaitTime(strategies ...WaitTimeStrategy) WaitTimeStrategy {
+	return WaitTimeStrategyFunc(func(queueURL string, proposed int64) (int64, bool) {
+		for _, strategy := range strategies {
+			if seconds, ok := strategy.WaitTime(queueURL, proposed); ok {
+				return seconds, true
+			}
+		}
+
+		return 0, false
+	})
+}
+
+// WithWaitTimeStrategy computes wait times with a strategy instead of using the adaptive
+// polling algorithm directly. The strategy receives the wait time of the algorithm and can
+// keep it with AdaptiveWaitTime; when it has no opinion, that wait time is used. It is a
+// WaitTimeHook (see WithWaitTimeHook) and replaces any hook set before.
+//
+// Parameters:
+//   - strategy: The strategy, usually a composition of strategies
+//
+// Example:
+//
+//	client := NewSQSWithOptions(&cfg, WithWaitTimeStrategy(MinWaitTime(AdaptiveWaitTime(), queueDepthStrategy)))
+func WithWaitTimeStrategy(strategy WaitTimeStrategy) Option {
+	return WithWaitTimeHook(func(queueURL string, proposed int64) int64 {
+		if seconds, ok := strategy.WaitTime(queueURL, proposed); ok {
+			return seconds
+		}
+
+		return proposed
+	})
+}
+
+// reduceWaitTime combines the wait times of the strategies with an opinion with pick.
+func reduceWaitTime(strategies []WaitTimeStrategy, queueURL string, proposed int64, pick func(a, b int64) int64) (int64, bool) {
+	var result int64
+	found := false
+
+	for _, strategy := range strategies {
+		seconds, ok := strategy.WaitTime(queueURL, proposed)
+		if !ok {
+			continue
+		}
+
+		if found {
+			result = pick(result, seconds)
+		} else {
+			result, found = seconds, true
+		}
+	}
+
+	return result, found
+}
@@ -0,0 +1,50 @@
+package sqs
+
+import (
+	"context"
+	"testing"
+)
+
+func TestWaitTimeCombinators(t *testing.T) {
+	unavailable := WaitTimeStrategyFunc(func(string, int64) (int64, bool) { return 0, false })
+
+	tests := []struct {
+		name     string
+		strategy WaitTimeStrategy
+		expected int64
+		ok       bool
+	}{
+		{"adaptive", AdaptiveWaitTime(), 12, true},
+		{"min", MinWaitTime(AdaptiveWaitTime(), FixedWaitTime(4), unavailable), 4, true},
+		{"max", MaxWaitTime(FixedWaitTime(4), AdaptiveWaitTime()), 12, true},
+		{"weighted", WeightedWaitTime(
+			WeightedStrategy{Strategy: AdaptiveWaitTime(), Weight: 3},
+			WeightedStrategy{Strategy: FixedWaitTime(0), Weight: 1},
+			WeightedStrategy{Strategy: unavailable, Weight: 10},
+		), 9, true},
+		{"fallback", FallbackWaitTime(unavailable, FixedWaitTime(2), AdaptiveWaitTime()), 2, true},
+		{"no opinion", MinWaitTime(unavailable), 0, false},
+		{"no weight", WeightedWaitTime(WeightedStrategy{Strategy: FixedWaitTime(1)}), 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seconds, ok := tt.strategy.WaitTime("queue", 12)
+			if seconds != tt.expected || ok != tt.ok {
+				t.Errorf("Expected (%d, %v), got (%d, %v)", tt.expected, tt.ok, seconds, ok)
+			}
+		})
+	}
+}
+
+func TestReceiveUsesWaitTimeStrategy(t *testing.T) {
+	fake := &fakeSQS{}
+	client := newTestSQS(fake, WithIdleWaitTimeSeconds(20), WithWaitTimeStrategy(MinWaitTime(AdaptiveWaitTime(), FixedWaitTime(3))))
+	client.EnableArrakis()
+
+	_, _ = client.ReceiveMessage(context.Background(), "queue", 1, nil)
+
+	if got := fake.receiveInputs[0].WaitTimeSeconds; got != 3 {
+		t.Errorf("Expected the wait time of the strategy, got %d", got)
+	}
+}
@@ -0,0 +1,128 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/smithy-go"
+)
+
+// Producer throttling configuration
+const (
+	_defaultProducerMaxDelay  = 5 * time.Second // Pause between sends under sustained throttling
+	_producerPressureFloor    = 0.05            // Pressure below which sends are no longer delayed
+	_throttledErrorCodeSuffix = "Throttled"     // Suffix of the SQS throttling codes (e.g., KmsThrottled)
+)
+
+// WithAdaptiveThrottling sets the longest pause the producer inserts between sends while
+// SQS pushes back. The producer tracks an EWMA of the sends that were throttled or failed
+// with a 5xx response, smoothed with the EWMA alpha of the client like the adaptive
+// polling of consumers, and pauses before every send for that fraction of maxDelay: the
+// pause grows while throttling persists and fades away as sends succeed again. Entries of
+// a batch rejected by SQS through no fault of the sender count as pressure too.
+//
+// Throttling is enabled by default with a 5 second maximum; this pacing comes on top of
+// the retries of the AWS SDK, which only space out the attempts of a single call.
+//
+// Parameters:
+//   - maxDelay: Pause between sends under sustained throttling (0 disables throttling)
+//
+// Example:
+//
+//	producer := sqs.NewProducer(sqsClient, queueURL, sqs.WithAdaptiveThrottling(10*time.Second))
+func WithAdaptiveThrottling(maxDelay time.Duration) ProducerOption {
+	return func(c *producerConfig) {
+		c.MaxThrottleDelay = maxDelay
+	}
+}
+
+// producerThrottle paces the sends of a producer to the pressure SQS reports. A nil
+// throttle never delays.
+type producerThrottle struct {
+	maxDelay time.Duration
+	alpha    func() float64
+
+	mu       sync.Mutex
+	pressure float64 // EWMA of the throttled sends, between 0 and 1
+}
+
+// newProducerThrottle creates the throttle of a producer, or returns nil when disabled.
+func newProducerThrottle(maxDelay time.Duration, client *SQS) *producerThrottle {
+	if maxDelay <= 0 {
+		return nil
+	}
+
+	return &producerThrottle{
+		maxDelay: maxDelay,
+		alpha:    func() float64 { return client.config.adaptivePolling().EwmaAlpha },
+	}
+}
+
+// wait pauses for the current delay, or until ctx is done.
+func (t *producerThrottle) wait(ctx context.Context) {
+	if delay := t.delay(); delay > 0 {
+		sleep(ctx, delay)
+	}
+}
+
+// delay returns the pause due before the next send.
+func (t *producerThrottle) delay() time.Duration {
+	if t == nil {
+		return 0
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return time.Duration(t.pressure * float64(t.maxDelay))
+}
+
+// observe feeds the outcome of a send into the pressure average.
+func (t *producerThrottle) observe(throttled bool) {
+	if t == nil {
+		return
+	}
+
+	sample := 0.0
+	if throttled {
+		sample = 1
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	alpha := t.alpha()
+	t.pressure = alpha*sample + (1-alpha)*t.pressure
+	if t.pressure < _producerPressureFloor {
+		t.pressure = 0
+	}
+}
+
+// isThrottled reports whether err means SQS pushed back: a throttling error code, a
+// server-side batch entry failure or a 5xx response.
+func isThrottled(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var entryErr *ErrBatchEntry
+	if errors.As(err, &entryErr) {
+		return !entryErr.SenderFault
+	}
+
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		code := apiErr.ErrorCode()
+		if _, ok := retry.DefaultThrottleErrorCodes[code]; ok || strings.HasSuffix(code, _throttledErrorCodeSuffix) {
+			return true
+		}
+	}
+
+	var statusErr interface{ HTTPStatusCode() int }
+	return errors.As(err, &statusErr) && statusErr.HTTPStatusCode() >= http.StatusInternalServerError
+}
@@ -0,0 +1,88 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+func TestIsThrottled(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "throttling code", err: &smithy.GenericAPIError{Code: "RequestThrottled"}, want: true},
+		{name: "kms throttling", err: &smithy.GenericAPIError{Code: "KmsThrottled"}, want: true},
+		{name: "client error", err: &smithy.GenericAPIError{Code: "InvalidParameterValue"}, want: false},
+		{name: "server error", err: &smithyhttp.ResponseError{Response: &smithyhttp.Response{Response: &http.Response{StatusCode: http.StatusServiceUnavailable}}, Err: errors.New("unavailable")}, want: true},
+		{name: "bad request", err: &smithyhttp.ResponseError{Response: &smithyhttp.Response{Response: &http.Response{StatusCode: http.StatusBadRequest}}, Err: errors.New("bad")}, want: false},
+		{name: "server-side entry failure", err: &ErrBatchEntry{Code: "InternalError"}, want: true},
+		{name: "sender entry failure", err: &ErrBatchEntry{Code: "InvalidMessageContents", SenderFault: true}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isThrottled(tt.err); got != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestProducerThrottleDelay(t *testing.T) {
+	throttle := newProducerThrottle(time.Second, newTestSQS(&fakeSQS{}))
+
+	throttle.observe(true)
+	first := throttle.delay()
+	if first != time.Duration(_defaultEwmaAlpha*float64(time.Second)) {
+		t.Errorf("Expected the first throttled send to delay by alpha of the maximum, got %s", first)
+	}
+
+	throttle.observe(true)
+	if throttle.delay() <= first {
+		t.Errorf("Expected the delay to grow while throttling persists, got %s", throttle.delay())
+	}
+
+	for range 20 {
+		throttle.observe(false)
+	}
+	if throttle.delay() != 0 {
+		t.Errorf("Expected the delay to vanish once sends succeed, got %s", throttle.delay())
+	}
+
+	if newProducerThrottle(0, nil).delay() != 0 {
+		t.Error("Expected a disabled throttle never to delay")
+	}
+}
+
+func TestProducerSlowsDownWhenThrottled(t *testing.T) {
+	fake := &fakeSQS{sendErr: &smithy.GenericAPIError{Code: "RequestThrottled"}}
+	producer := NewProducer(newTestSQS(fake), "queue", WithAdaptiveThrottling(100*time.Millisecond))
+	ctx := context.Background()
+
+	if _, err := producer.Send(ctx, "body"); err == nil {
+		t.Fatal("Expected the throttling error to be returned")
+	}
+
+	fake.mu.Lock()
+	fake.sendErr = nil
+	fake.mu.Unlock()
+
+	start := time.Now()
+	if _, err := producer.SendBatch(ctx, []OutgoingMessage{{ID: "1", Body: "body"}}); err != nil {
+		t.Fatalf("SendBatch returned error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 25*time.Millisecond {
+		t.Errorf("Expected the send after throttling to be delayed, took %s", elapsed)
+	}
+	if producer.throttle.delay() >= 30*time.Millisecond {
+		t.Errorf("Expected the delay to shrink after a successful send, got %s", producer.throttle.delay())
+	}
+}
@@ -0,0 +1,100 @@
+package sqs
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracing configuration values
+const (
+	_tracerName             = "github.com/elissonalvesilva/arrakis/pkg/sqs"
+	_spanEventClassChanged  = "arrakis.volume_class_changed" // Span event added when the volume class changes
+	_attributeVolumeClass   = attribute.Key("arrakis.volume_class")
+	_attributePreviousClass = attribute.Key("arrakis.volume_class.previous")
+	_attributeWaitTime      = attribute.Key("arrakis.wait_time_seconds")
+	_attributeAdaptive      = attribute.Key("arrakis.adaptive")
+	_operationReceive       = "receive"
+	_operationSend          = "send"
+	_operationProcess       = "process"
+	_operationNameReceive   = "ReceiveMessage"
+	_operationNameSend      = "SendMessage"
+	_operationNameProcess   = "process"
+)
+
+// WithTracerProvider sets the OpenTelemetry tracer provider used to trace receives, sends
+// and consumer message processing (default: the global provider, a no-op unless one was
+// registered with otel.SetTracerProvider).
+//
+// Spans follow the OpenTelemetry messaging semantic conventions (messaging.system aws_sqs,
+// destination name, operation type and batch size). Receive spans also carry the adaptive
+// wait time, and get an "arrakis.volume_class_changed" event whenever the response moves the
+// queue to another volume class, so polling behavior is visible inline in traces.
+//
+// Parameters:
+//   - provider: The tracer provider
+//
+// Example:
+//
+//	client := sqs.NewSQSWithOptions(&cfg, sqs.WithTracerProvider(tracerProvider))
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.TracerProvider = provider
+	}
+}
+
+// tracer returns the tracer of the client.
+func (s *SQS) tracer() trace.Tracer {
+	provider := s.config.TracerProvider
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+
+	return provider.Tracer(_tracerName)
+}
+
+// startSpan starts a messaging span named "<operation> <queue name>".
+func (s *SQS) startSpan(ctx context.Context, operation, operationName string, operationType attribute.KeyValue, kind trace.SpanKind, queueURL string) (context.Context, trace.Span) {
+	name := queueName(queueURL)
+
+	return s.tracer().Start(ctx, operation+" "+name,
+		trace.WithSpanKind(kind),
+		trace.WithAttributes(
+			semconv.MessagingSystemAWSSQS,
+			semconv.MessagingDestinationName(name),
+			semconv.MessagingOperationName(operationName),
+			operationType,
+		))
+}
+
+// endSpan records err on span, if any, and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
+// recordClassChange adds a span event for a change of the volume class of a queue.
+func recordClassChange(span trace.Span, previous, current VolumeClass) {
+	span.AddEvent(_spanEventClassChanged, trace.WithAttributes(
+		_attributePreviousClass.String(previous.String()),
+		_attributeVolumeClass.String(current.String()),
+	))
+}
+
+// queueName extracts the queue name from a queue URL (the URL itself if it has no path).
+func queueName(queueURL string) string {
+	if i := strings.LastIndex(queueURL, "/"); i >= 0 && i < len(queueURL)-1 {
+		return queueURL[i+1:]
+	}
+
+	return queueURL
+}
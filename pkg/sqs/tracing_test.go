@@ -0,0 +1,107 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// newTracedSQS builds a test client recording its spans.
+func newTracedSQS(fake *fakeSQS) (*SQS, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	client := newTestSQS(fake, WithTracerProvider(provider))
+	client.EnableArrakis()
+
+	return client, recorder
+}
+
+// spanAttribute returns the value of an attribute of a span.
+func spanAttribute(span sdktrace.ReadOnlySpan, key attribute.Key) (attribute.Value, bool) {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+
+	return attribute.Value{}, false
+}
+
+func TestReceiveSpanFollowsMessagingConventions(t *testing.T) {
+	fake := &fakeSQS{}
+	var batch []types.Message
+	for i := 0; i < 10; i++ {
+		batch = append(batch, testMessage(fmt.Sprintf("m%d", i), ""))
+	}
+	fake.push(batch...)
+	client, recorder := newTracedSQS(fake)
+
+	if _, err := client.ReceiveMessage(context.Background(), "https://sqs.us-east-1.amazonaws.com/1/orders", 10, nil); err != nil {
+		t.Fatalf("ReceiveMessage returned error: %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("Expected 1 span, got %d", len(spans))
+	}
+
+	span := spans[0]
+	if span.Name() != "receive orders" || span.SpanKind() != trace.SpanKindClient {
+		t.Errorf("Expected client span 'receive orders', got %q (%v)", span.Name(), span.SpanKind())
+	}
+
+	expected := map[attribute.Key]string{
+		"messaging.system":           "aws_sqs",
+		"messaging.destination.name": "orders",
+		"messaging.operation.type":   "receive",
+	}
+	for key, want := range expected {
+		if value, ok := spanAttribute(span, key); !ok || value.AsString() != want {
+			t.Errorf("Expected %s=%s, got %v", key, want, value.Emit())
+		}
+	}
+
+	if value, ok := spanAttribute(span, "messaging.batch.message_count"); !ok || value.AsInt64() != 10 {
+		t.Errorf("Expected batch size 10, got %v", value.Emit())
+	}
+	if _, ok := spanAttribute(span, _attributeWaitTime); !ok {
+		t.Error("Expected the adaptive wait time attribute")
+	}
+
+	if len(span.Events()) != 1 || span.Events()[0].Name != _spanEventClassChanged {
+		t.Errorf("Expected a volume class change event, got %v", span.Events())
+	}
+}
+
+func TestSendSpanRecordsErrors(t *testing.T) {
+	fake := &fakeSQS{sendErr: errors.New("unavailable")}
+	client, recorder := newTracedSQS(fake)
+
+	_, _ = client.SendMessage(context.Background(), "orders", "body")
+
+	spans := recorder.Ended()
+	if len(spans) != 1 || spans[0].Name() != "send orders" || spans[0].SpanKind() != trace.SpanKindProducer {
+		t.Fatalf("Expected a producer span 'send orders', got %v", spans)
+	}
+	if spans[0].Status().Code != codes.Error {
+		t.Error("Expected the span status to record the error")
+	}
+}
+
+func TestQueueName(t *testing.T) {
+	if name := queueName("https://sqs.us-east-1.amazonaws.com/1/orders.fifo"); name != "orders.fifo" {
+		t.Errorf("Expected orders.fifo, got %s", name)
+	}
+	if name := queueName("orders"); name != "orders" {
+		t.Errorf("Expected orders, got %s", name)
+	}
+}
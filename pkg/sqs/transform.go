@@ -0,0 +1,161 @@
+package sqs
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Transform rewrites a message before it reaches the handler, typically decoding its body.
+// Transforms are chained into a pipeline where each stage receives the output of the
+// previous one, e.g. UnwrapJSON, then Base64Decode, then Gunzip.
+type Transform func(ctx context.Context, msg Message) (Message, error)
+
+// ErrTransform is returned when a stage of a transform pipeline fails. The message is
+// not handled and stays in the queue, eventually moving to the dead-letter queue.
+type ErrTransform struct {
+	// Stage is the position of the failing stage in the pipeline (0-based).
+	Stage int
+	// Err is the error returned by the stage.
+	Err error
+}
+
+func (e *ErrTransform) Error() string {
+	return fmt.Sprintf("transform stage %d failed: %v", e.Stage, e.Err)
+}
+
+func (e *ErrTransform) Unwrap() error {
+	return e.Err
+}
+
+// WithTransforms appends stages to the consumer transform pipeline. Stages run in order
+// on every message, after the receive filter and before the handler.
+//
+// Parameters:
+//   - stages: The transforms to apply, in order
+//
+// Example:
+//
+//	consumer := sqs.NewConsumer(client, queueURL, handler,
+//	    sqs.WithTransforms(sqs.Base64Decode(), sqs.Gunzip()))
+func WithTransforms(stages ...Transform) ConsumerOption {
+	return func(c *consumerConfig) {
+		c.Transforms = append(c.Transforms, stages...)
+	}
+}
+
+// Transformed wraps handler so every message goes through stages first. It applies the
+// same pipeline as WithTransforms to handlers used outside a Consumer, e.g. with Drain.
+//
+// Parameters:
+//   - handler: The handler receiving transformed messages
+//   - stages: The transforms to apply, in order
+//
+// Returns:
+//   - Handler: A handler returning *ErrTransform when a stage fails
+func Transformed(handler Handler, stages ...Transform) Handler {
+	return HandlerFunc(func(ctx context.Context, msg Message) error {
+		msg, err := applyTransforms(ctx, msg, stages)
+		if err != nil {
+			return err
+		}
+
+		return handler.Handle(ctx, msg)
+	})
+}
+
+// applyTransforms runs msg through stages in order.
+func applyTransforms(ctx context.Context, msg Message, stages []Transform) (Message, error) {
+	for i, stage := range stages {
+		var err error
+		if msg, err = stage(ctx, msg); err != nil {
+			return msg, &ErrTransform{Stage: i, Err: err}
+		}
+	}
+
+	return msg, nil
+}
+
+// Base64Decode decodes standard base64 bodies, as produced by producers sending binary
+// payloads through the string-only message body.
+func Base64Decode() Transform {
+	return func(ctx context.Context, msg Message) (Message, error) {
+		decoded, err := base64.StdEncoding.DecodeString(msg.Body)
+		if err != nil {
+			return msg, err
+		}
+
+		msg.Body = string(decoded)
+		return msg, nil
+	}
+}
+
+// Gunzip decompresses gzip bodies. Compressed bodies are binary, so this stage usually
+// follows Base64Decode.
+func Gunzip() Transform {
+	return func(ctx context.Context, msg Message) (Message, error) {
+		reader, err := gzip.NewReader(bytes.NewReader([]byte(msg.Body)))
+		if err != nil {
+			return msg, err
+		}
+		defer reader.Close()
+
+		decompressed, err := io.ReadAll(reader)
+		if err != nil {
+			return msg, err
+		}
+
+		msg.Body = string(decompressed)
+		return msg, nil
+	}
+}
+
+// Decrypt decrypts bodies with the given function, e.g. a KMS or envelope-encryption
+// client. The message is passed along so decrypt can read key IDs from its attributes.
+//
+// Example:
+//
+//	sqs.Decrypt(func(ctx context.Context, msg sqs.Message, ciphertext []byte) ([]byte, error) {
+//	    return aead.Open(nil, nonce, ciphertext, nil)
+//	})
+func Decrypt(decrypt func(ctx context.Context, msg Message, ciphertext []byte) ([]byte, error)) Transform {
+	return func(ctx context.Context, msg Message) (Message, error) {
+		plaintext, err := decrypt(ctx, msg, []byte(msg.Body))
+		if err != nil {
+			return msg, err
+		}
+
+		msg.Body = string(plaintext)
+		return msg, nil
+	}
+}
+
+// UnwrapJSON replaces a JSON envelope body with the string value of one of its fields,
+// e.g. UnwrapJSON("Message") for SNS notifications or UnwrapJSON("detail") for
+// EventBridge events. Non-string fields are passed along as raw JSON.
+func UnwrapJSON(field string) Transform {
+	return func(ctx context.Context, msg Message) (Message, error) {
+		var envelope map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(msg.Body), &envelope); err != nil {
+			return msg, err
+		}
+
+		raw, ok := envelope[field]
+		if !ok {
+			return msg, fmt.Errorf("envelope field %q not found", field)
+		}
+
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil {
+			// Not a string: hand over the JSON value itself
+			value = string(raw)
+		}
+
+		msg.Body = value
+		return msg, nil
+	}
+}
@@ -0,0 +1,87 @@
+package sqs
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestTransformPipelineOrder(t *testing.T) {
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	_, _ = writer.Write([]byte(`{"order":42}`))
+	_ = writer.Close()
+
+	encoded := base64.StdEncoding.EncodeToString(compressed.Bytes())
+	msg := Message{Body: `{"Type":"Notification","Message":"` + encoded + `"}`}
+
+	upper := Decrypt(func(ctx context.Context, msg Message, ciphertext []byte) ([]byte, error) {
+		return []byte(strings.ToUpper(string(ciphertext))), nil
+	})
+
+	out, err := applyTransforms(context.Background(), msg, []Transform{UnwrapJSON("Message"), Base64Decode(), Gunzip(), upper})
+	if err != nil {
+		t.Fatalf("applyTransforms returned error: %v", err)
+	}
+
+	if out.Body != `{"ORDER":42}` {
+		t.Errorf("Expected decoded body, got %q", out.Body)
+	}
+}
+
+func TestTransformErrorReportsStage(t *testing.T) {
+	_, err := applyTransforms(context.Background(), Message{Body: "not base64!"}, []Transform{UnwrapJSON("x")})
+
+	var transformErr *ErrTransform
+	if !errors.As(err, &transformErr) || transformErr.Stage != 0 {
+		t.Errorf("Expected ErrTransform at stage 0, got %v", err)
+	}
+
+	_, err = applyTransforms(context.Background(), Message{Body: "not base64!"}, []Transform{Base64Decode(), Gunzip()})
+	if !errors.As(err, &transformErr) || transformErr.Stage != 0 {
+		t.Errorf("Expected ErrTransform at stage 0, got %v", err)
+	}
+}
+
+func TestUnwrapJSONKeepsNonStringFields(t *testing.T) {
+	out, err := UnwrapJSON("detail")(context.Background(), Message{Body: `{"detail":{"id":1}}`})
+	if err != nil || out.Body != `{"id":1}` {
+		t.Errorf("Expected raw JSON detail, got %q (%v)", out.Body, err)
+	}
+}
+
+func TestConsumerAppliesTransforms(t *testing.T) {
+	fake := &fakeSQS{}
+	m := testMessage("m1", "")
+	body := base64.StdEncoding.EncodeToString([]byte("hello"))
+	m.Body = &body
+	fake.push(m, testMessage("m2", ""))
+	client := newTestSQS(fake)
+
+	var mu sync.Mutex
+	var bodies []string
+	handler := HandlerFunc(func(ctx context.Context, msg Message) error {
+		mu.Lock()
+		bodies = append(bodies, msg.Body)
+		mu.Unlock()
+		return nil
+	})
+
+	consumer := NewConsumer(client, "queue", handler, WithTransforms(Base64Decode()))
+	runConsumer(t, consumer, func() bool {
+		fake.mu.Lock()
+		defer fake.mu.Unlock()
+		return len(fake.deleted) == 1 && len(fake.receiveInputs) > 2
+	})
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(bodies) != 1 || bodies[0] != "hello" {
+		t.Errorf("Expected only the decodable message to be handled, got %v", bodies)
+	}
+}
@@ -0,0 +1,39 @@
+package sqs
+
+import (
+	"context"
+	"time"
+)
+
+// returnUndispatched makes the messages received but never handed to a worker visible
+// again right away, with ChangeMessageVisibilityBatch calls of up to 10 messages, instead
+// of leaving them hidden until their visibility timeout expires. It runs once polling
+// stopped, so another consumer can pick them up while this one shuts down.
+func (c *Consumer) returnUndispatched(ctx context.Context, messages []Message) {
+	queues := map[string][]Message{}
+	for _, msg := range messages {
+		queues[msg.QueueURL] = append(queues[msg.QueueURL], msg)
+	}
+
+	for queueURL, messages := range queues {
+		source := c.sourceOf(messages[0])
+
+		for start := 0; start < len(messages); start += _maxBatchEntries {
+			batch := messages[start:min(start+_maxBatchEntries, len(messages))]
+
+			changed, err := source.client.changeVisibilityBatch(ctx, queueURL, batch, 0)
+			if err != nil || len(changed) < len(batch) {
+				source.client.logger().Warn("returning undispatched messages failed, they will be redelivered after their visibility timeout", "queue", queueURL, "messages", len(batch)-len(changed), "error", err)
+			}
+		}
+	}
+
+	for _, msg := range messages {
+		c.dedup.finish(msg.ID, false, time.Now())
+		c.inFlight.remove(msg)
+		c.sourceOf(msg).state.addInFlight(-1)
+		c.budget.release(payloadSize(msg))
+		c.groups.release(msg.GroupID)
+		c.limiter.release(1)
+	}
+}
@@ -0,0 +1,71 @@
+package sqs
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConsumerReturnsUndispatchedMessages(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		queueURL string
+		option   ConsumerOption
+	}{
+		{"shared pool", "queue", WithWorkers(1)},
+		{"group partitions", "queue.fifo", WithGroupPartitioning(1, 1)},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeSQS{}
+			fake.push(testMessage("m1", "g"), testMessage("m2", "g"), testMessage("m3", "g"), testMessage("m4", "g"))
+
+			var (
+				mu      sync.Mutex
+				handled []string
+			)
+			started, release := make(chan struct{}), make(chan struct{})
+			handler := HandlerFunc(func(ctx context.Context, msg Message) error {
+				mu.Lock()
+				handled = append(handled, msg.ID)
+				mu.Unlock()
+				if msg.ID == "m1" {
+					close(started)
+					<-release
+				}
+				return nil
+			})
+
+			consumer := NewConsumer(newTestSQS(fake), tt.queueURL, handler, tt.option)
+			ctx, cancel := context.WithCancel(context.Background())
+			result := make(chan error, 1)
+			go func() { result <- consumer.Start(ctx) }()
+
+			// Shut down while m1 is handled and the rest waits for the busy worker
+			<-started
+			time.Sleep(20 * time.Millisecond)
+			cancel()
+			close(release)
+			if err := <-result; err != nil {
+				t.Fatalf("Start returned error: %v", err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if len(handled) != 1 {
+				t.Errorf("Expected only the message being handled at shutdown to be handled, got %v", handled)
+			}
+			for _, id := range []string{"m2", "m3", "m4"} {
+				if visibility, ok := fake.visibilityOf(id); !ok || visibility != 0 {
+					t.Errorf("Expected %s to be made visible again, got %d (set: %v)", id, visibility, ok)
+				}
+			}
+			if fake.visibilityBatches == 0 {
+				t.Error("Expected the messages to be returned with ChangeMessageVisibilityBatch")
+			}
+			if deleted := fake.deletedHandles(); len(deleted) != 1 || deleted[0] != "m1" {
+				t.Errorf("Expected only m1 deleted, got %v", deleted)
+			}
+		})
+	}
+}
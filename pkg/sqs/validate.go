@@ -0,0 +1,146 @@
+package sqs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// SQS message limits enforced before sending
+const (
+	_maxMessageSizeBytes     = 262144 // 256 KiB, body plus message attributes
+	_maxMessageAttributes    = 10     // Maximum number of message attributes per message
+	_maxAttributeNameLength  = 256    // Maximum length of a message attribute name
+	_attributeDataTypeString = "String"
+	_attributeDataTypeNumber = "Number"
+	_attributeDataTypeBinary = "Binary"
+)
+
+// _reservedAttributePrefixes are attribute name prefixes reserved by AWS (case-insensitive).
+var _reservedAttributePrefixes = []string{"aws.", "amazon."}
+
+// ErrMessageTooLarge is returned when a message (body plus attributes) exceeds the SQS
+// size limit. It is detected locally, before any request is sent.
+type ErrMessageTooLarge struct {
+	// Size is the computed message size in bytes.
+	Size int
+	// Limit is the maximum size accepted by SQS in bytes.
+	Limit int
+}
+
+func (e *ErrMessageTooLarge) Error() string {
+	return fmt.Sprintf("message size %d bytes exceeds the SQS limit of %d bytes", e.Size, e.Limit)
+}
+
+// ErrInvalidMessageAttribute is returned when a message attribute breaks the SQS naming
+// or format rules, or when a message carries too many attributes.
+type ErrInvalidMessageAttribute struct {
+	// Name is the offending attribute name (empty when the attribute count is exceeded).
+	Name string
+	// Reason describes the rule that was broken.
+	Reason string
+}
+
+func (e *ErrInvalidMessageAttribute) Error() string {
+	if e.Name == "" {
+		return fmt.Sprintf("invalid message attributes: %s", e.Reason)
+	}
+
+	return fmt.Sprintf("invalid message attribute %q: %s", e.Name, e.Reason)
+}
+
+// validateSendInput checks a message against the SQS limits so producers fail fast
+// locally instead of getting a rejected API call.
+//
+// Returns:
+//   - error: *ErrMessageTooLarge, *ErrInvalidMessageAttribute, *ErrInvalidFIFOMessage or nil
+func validateSendInput(input *sqs.SendMessageInput) error {
+	if err := validateFIFOInput(input); err != nil {
+		return err
+	}
+
+	if len(input.MessageAttributes) > _maxMessageAttributes {
+		return &ErrInvalidMessageAttribute{Reason: fmt.Sprintf("%d attributes exceed the limit of %d", len(input.MessageAttributes), _maxMessageAttributes)}
+	}
+
+	for name, value := range input.MessageAttributes {
+		if err := validateAttribute(name, value); err != nil {
+			return err
+		}
+	}
+
+	if size := messageSize(aws.ToString(input.MessageBody), input.MessageAttributes); size > _maxMessageSizeBytes {
+		return &ErrMessageTooLarge{Size: size, Limit: _maxMessageSizeBytes}
+	}
+
+	return nil
+}
+
+// messageSize computes the size SQS accounts for a message: the body plus, for every
+// attribute, its name, data type and value.
+func messageSize(body string, attributes map[string]types.MessageAttributeValue) int {
+	size := len(body)
+
+	for name, value := range attributes {
+		size += len(name) + len(aws.ToString(value.DataType)) + len(aws.ToString(value.StringValue)) + len(value.BinaryValue)
+	}
+
+	return size
+}
+
+// validateAttribute checks a single message attribute against the SQS rules.
+func validateAttribute(name string, value types.MessageAttributeValue) error {
+	if reason := invalidAttributeName(name); reason != "" {
+		return &ErrInvalidMessageAttribute{Name: name, Reason: reason}
+	}
+
+	dataType := aws.ToString(value.DataType)
+	baseType, _, _ := strings.Cut(dataType, ".")
+
+	switch baseType {
+	case _attributeDataTypeString, _attributeDataTypeNumber:
+		if aws.ToString(value.StringValue) == "" {
+			return &ErrInvalidMessageAttribute{Name: name, Reason: baseType + " attributes require a non-empty StringValue"}
+		}
+	case _attributeDataTypeBinary:
+		if len(value.BinaryValue) == 0 {
+			return &ErrInvalidMessageAttribute{Name: name, Reason: "Binary attributes require a non-empty BinaryValue"}
+		}
+	default:
+		return &ErrInvalidMessageAttribute{Name: name, Reason: fmt.Sprintf("unsupported data type %q", dataType)}
+	}
+
+	return nil
+}
+
+// invalidAttributeName returns why name is not a valid attribute name, or "" if it is.
+func invalidAttributeName(name string) string {
+	switch {
+	case name == "":
+		return "name is empty"
+	case len(name) > _maxAttributeNameLength:
+		return fmt.Sprintf("name longer than %d characters", _maxAttributeNameLength)
+	case strings.HasPrefix(name, ".") || strings.HasSuffix(name, "."):
+		return "name can't start or end with a period"
+	case strings.Contains(name, ".."):
+		return "name can't contain consecutive periods"
+	}
+
+	for _, prefix := range _reservedAttributePrefixes {
+		if strings.HasPrefix(strings.ToLower(name), prefix) {
+			return "names starting with AWS. or Amazon. are reserved"
+		}
+	}
+
+	for _, r := range name {
+		isAlphanumeric := (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+		if !isAlphanumeric && r != '-' && r != '_' && r != '.' {
+			return fmt.Sprintf("invalid character %q", r)
+		}
+	}
+
+	return ""
+}
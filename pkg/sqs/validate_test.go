@@ -0,0 +1,81 @@
+package sqs
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+func TestValidateSendInputSize(t *testing.T) {
+	body := strings.Repeat("x", _maxMessageSizeBytes-10)
+	input := &sqs.SendMessageInput{
+		MessageBody:       aws.String(body),
+		MessageAttributes: map[string]types.MessageAttributeValue{"trace": stringAttribute("abcdef")},
+	}
+
+	err := validateSendInput(input)
+
+	var tooLarge *ErrMessageTooLarge
+	if !errors.As(err, &tooLarge) {
+		t.Fatalf("Expected ErrMessageTooLarge, got %v", err)
+	}
+	// body + "trace" + "String" + "abcdef"
+	if expected := len(body) + 5 + 6 + 6; tooLarge.Size != expected {
+		t.Errorf("Expected computed size %d, got %d", expected, tooLarge.Size)
+	}
+
+	input.MessageAttributes = nil
+	if err := validateSendInput(input); err != nil {
+		t.Errorf("Expected message within the limit to be valid, got %v", err)
+	}
+}
+
+func TestValidateSendInputAttributes(t *testing.T) {
+	tests := []struct {
+		name       string
+		attributes map[string]types.MessageAttributeValue
+		valid      bool
+	}{
+		{"valid string", map[string]types.MessageAttributeValue{"event.type": stringAttribute("created")}, true},
+		{"custom data type", map[string]types.MessageAttributeValue{"n": {DataType: aws.String("Number.int"), StringValue: aws.String("1")}}, true},
+		{"reserved prefix", map[string]types.MessageAttributeValue{"AWS.trace": stringAttribute("x")}, false},
+		{"consecutive periods", map[string]types.MessageAttributeValue{"a..b": stringAttribute("x")}, false},
+		{"invalid character", map[string]types.MessageAttributeValue{"a b": stringAttribute("x")}, false},
+		{"empty value", map[string]types.MessageAttributeValue{"a": stringAttribute("")}, false},
+		{"unknown data type", map[string]types.MessageAttributeValue{"a": {DataType: aws.String("Date"), StringValue: aws.String("x")}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSendInput(&sqs.SendMessageInput{MessageBody: aws.String("body"), MessageAttributes: tt.attributes})
+
+			var invalid *ErrInvalidMessageAttribute
+			if tt.valid && err != nil {
+				t.Errorf("Expected valid attributes, got %v", err)
+			}
+			if !tt.valid && !errors.As(err, &invalid) {
+				t.Errorf("Expected ErrInvalidMessageAttribute, got %v", err)
+			}
+		})
+	}
+}
+
+func TestSendMessageFailsFastOnInvalidMessage(t *testing.T) {
+	fake := &fakeSQS{}
+	client := newTestSQS(fake)
+
+	_, err := client.SendMessage(context.Background(), "queue", strings.Repeat("x", _maxMessageSizeBytes+1))
+
+	var tooLarge *ErrMessageTooLarge
+	if !errors.As(err, &tooLarge) {
+		t.Fatalf("Expected ErrMessageTooLarge, got %v", err)
+	}
+	if len(fake.sentMessages()) != 0 {
+		t.Error("Expected no API call for an invalid message")
+	}
+}
@@ -0,0 +1,49 @@
+package sqs
+
+import "math"
+
+// Variance configuration values
+const (
+	_spikeDeviations          = 3.0 // Standard deviations from the average within which an observation isn't a spike
+	_classConfidenceThreshold = 0.5 // Class confidence below which the volume class doesn't move to a neighbor
+)
+
+// updateVariance folds the deviation of an observation from the previous average into the
+// exponentially weighted variance, with the smoothing factor of the average update. Must
+// be called with the mutex held.
+func (a *arrakis) updateVariance(deviation, alpha float64) {
+	a.variance = (1 - alpha) * (a.variance + alpha*deviation*deviation)
+}
+
+// stdDev returns the exponentially weighted standard deviation of the message counts. Must
+// be called with the mutex held.
+func (a *arrakis) stdDev() float64 {
+	return math.Sqrt(a.variance)
+}
+
+// spikeLimit returns the largest increase over the average an observation may bring
+// before spike protection caps it: 200% of the average, or three standard deviations on
+// noisy queues, whose swings are normal traffic rather than spikes. Must be called with
+// the mutex held.
+func (a *arrakis) spikeLimit() float64 {
+	return math.Max(a.average*2, _spikeDeviations*a.stdDev())
+}
+
+// classConfidence returns how firmly the average sits in its volume class: its distance to
+// the nearest class threshold in standard deviations, capped at 1. An average less than
+// one standard deviation away from a threshold may well cross it with the next polls.
+// Must be called with the mutex held.
+func (a *arrakis) classConfidence() float64 {
+	stdDev := a.stdDev()
+	if stdDev == 0 || a.average == 0 {
+		return 1
+	}
+
+	scale := float64(a.config.batchSize()) / _defaultNumberOfMessages
+	distance := math.Inf(1)
+	for _, threshold := range []float64{_lowVolumeThreshold, _mediumVolumeThreshold, _highVolumeThreshold} {
+		distance = math.Min(distance, math.Abs(a.average-threshold*scale))
+	}
+
+	return math.Min(distance/stdDev, 1)
+}
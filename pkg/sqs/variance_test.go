@@ -0,0 +1,66 @@
+package sqs
+
+import "testing"
+
+func TestVarianceTracksNoise(t *testing.T) {
+	client := newTestSQS(&fakeSQS{})
+
+	steady := client.state("steady")
+	for range 20 {
+		steady.updateMessageCount(7)
+	}
+	if stats := steady.stats(); stats.StdDev > 0.5 || stats.ClassConfidence != 1 {
+		t.Errorf("Expected a steady queue to be quiet and confidently classified, got %+v", stats)
+	}
+
+	noisy := client.state("noisy")
+	for i := range 20 {
+		noisy.updateMessageCount(10 * (i % 2))
+	}
+	stats := noisy.stats()
+	if stats.StdDev < 2 || stats.ClassConfidence >= 1 {
+		t.Errorf("Expected a noisy queue with a low class confidence, got %+v", stats)
+	}
+
+	// A burst over 200% of the average, but within the usual swings of the noisy queue, is
+	// not a spike
+	burst := int(stats.Average + 2.8*stats.StdDev)
+	if float64(burst) <= 3*stats.Average {
+		t.Fatalf("Expected a burst over 200%% of the average %v, got %d", stats.Average, burst)
+	}
+	clamps := stats.Events.SpikeClamps
+	noisy.updateMessageCount(burst)
+	if got := noisy.stats().Events.SpikeClamps; got != clamps {
+		t.Errorf("Expected no spike clamp within the noise, got %d clamps instead of %d", got, clamps)
+	}
+}
+
+func TestClassHysteresis(t *testing.T) {
+	client := newTestSQS(&fakeSQS{})
+
+	steady := client.state("steady")
+	steady.seed(AdaptiveState{Average: 4})
+	transitions := steady.stats().Events.ClassTransitions
+	for range 20 {
+		steady.updateMessageCount(6)
+	}
+	if stats := steady.stats(); stats.VolumeClass != VolumeHigh || stats.Events.ClassTransitions != transitions+1 {
+		t.Errorf("Expected a steady queue to move to the high volume class, got %+v", stats)
+	}
+
+	// A noisy queue whose average swings around the medium/high threshold keeps its class
+	noisy := client.state("noisy")
+	noisy.seed(AdaptiveState{Average: 4})
+	transitions = noisy.stats().Events.ClassTransitions
+	crossed := false
+	for i := range 40 {
+		noisy.updateMessageCount([]int{2, 9}[i%2])
+		crossed = crossed || noisy.classify(noisy.currentAverage()) == VolumeHigh
+	}
+	if !crossed {
+		t.Fatal("Expected the noisy average to cross into the high volume class")
+	}
+	if stats := noisy.stats(); stats.VolumeClass != VolumeMedium || stats.Events.ClassTransitions != transitions {
+		t.Errorf("Expected a noisy queue to stay in the medium volume class, got %+v", stats)
+	}
+}
@@ -0,0 +1,296 @@
+package sqs
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SNS wake-up configuration values
+const (
+	_snsTypeSubscriptionConfirmation = "SubscriptionConfirmation" // Type of the first request of a subscription
+	_snsRequestTimeout               = 10 * time.Second           // Bound on fetching certificates and confirming subscriptions
+	_maxSNSRequestBytes              = 256 * 1024                 // Largest SNS request accepted (SNS messages are up to 256 KiB)
+)
+
+// snsHostPattern matches the hosts SNS signs certificates and subscription URLs from.
+var snsHostPattern = regexp.MustCompile(`^sns\.[a-z0-9-]+\.amazonaws\.com(\.cn)?$`)
+
+// Wake ends the idle backoff sleep of the consumer (see WithIdleBackoff) so it polls right
+// away, typically because a producer signalled new work. The backoff starts over from its
+// shortest sleep if the queue turns out to be empty. Wake is a no-op without idle backoff,
+// as the consumer is then always long polling, and is safe to call from any goroutine.
+func (c *Consumer) Wake() {
+	c.idle.wake()
+}
+
+// wake interrupts the current or next sleep of the backoff.
+func (b *idleBackoff) wake() {
+	if b == nil {
+		return
+	}
+
+	select {
+	case b.woken <- struct{}{}:
+	default:
+	}
+}
+
+// snsWakeRequest is an SNS HTTP(S) delivery, a notification or a subscription confirmation.
+type snsWakeRequest struct {
+	Type             string  `json:"Type"`
+	MessageID        string  `json:"MessageId"`
+	Token            string  `json:"Token"`
+	TopicArn         string  `json:"TopicArn"`
+	Subject          *string `json:"Subject"`
+	Message          string  `json:"Message"`
+	SubscribeURL     string  `json:"SubscribeURL"`
+	Timestamp        string  `json:"Timestamp"`
+	SignatureVersion string  `json:"SignatureVersion"`
+	Signature        string  `json:"Signature"`
+	SigningCertURL   string  `json:"SigningCertURL"`
+}
+
+// SNSWakeHandler is an http.Handler receiving the notifications of an SNS topic over an
+// HTTP(S) subscription and waking consumers with every notification. See NewSNSWakeHandler.
+type SNSWakeHandler struct {
+	topicArn  string
+	consumers []*Consumer
+	client    *http.Client
+
+	// certificate fetches signing certificates; replaced in tests
+	certificate func(certURL string) (*x509.Certificate, error)
+
+	mu    sync.Mutex
+	certs map[string]*x509.Certificate
+}
+
+// NewSNSWakeHandler creates the endpoint of an SNS HTTP(S) subscription that wakes
+// consumers (see Consumer.Wake) whenever a message is published to the topic. Producers
+// publish a notification after sending messages, so consumers sleeping in a long idle
+// backoff pick the work up at once: idle queues then cost close to nothing while traffic
+// resuming is handled with close to no latency.
+//
+// The handler confirms the subscription when SNS asks for it and ignores requests from
+// other topics. Every request must carry a valid SNS signature, checked against the
+// certificate SNS publishes for it.
+//
+// Parameters:
+//   - topicArn: ARN of the topic the handler is subscribed to
+//   - consumers: The consumers to wake
+//
+// Returns:
+//   - *SNSWakeHandler: An http.Handler to mount on the subscribed endpoint
+//
+// Example:
+//
+//	consumer := sqs.NewConsumer(client, queueURL, handler, sqs.WithIdleBackoff(15*time.Minute, 0))
+//	http.Handle("/arrakis/wake", sqs.NewSNSWakeHandler(wakeTopicArn, consumer))
+func NewSNSWakeHandler(topicArn string, consumers ...*Consumer) *SNSWakeHandler {
+	h := &SNSWakeHandler{
+		topicArn:  topicArn,
+		consumers: consumers,
+		client:    &http.Client{Timeout: _snsRequestTimeout},
+		certs:     map[string]*x509.Certificate{},
+	}
+	h.certificate = h.fetchCertificate
+
+	return h
+}
+
+// ServeHTTP verifies an SNS request and confirms the subscription or wakes the consumers.
+func (h *SNSWakeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var request snsWakeRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, _maxSNSRequestBytes)).Decode(&request); err != nil {
+		http.Error(w, "invalid SNS request", http.StatusBadRequest)
+		return
+	}
+
+	if request.TopicArn != h.topicArn {
+		http.Error(w, "unexpected topic", http.StatusForbidden)
+		return
+	}
+
+	if err := h.verify(request); err != nil {
+		http.Error(w, "invalid SNS signature", http.StatusForbidden)
+		return
+	}
+
+	switch request.Type {
+	case _snsTypeSubscriptionConfirmation:
+		if err := h.confirm(r, request.SubscribeURL); err != nil {
+			http.Error(w, "subscription confirmation failed", http.StatusBadGateway)
+			return
+		}
+	case _snsTypeNotification:
+		for _, consumer := range h.consumers {
+			consumer.Wake()
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// confirm confirms a subscription by visiting its SubscribeURL.
+func (h *SNSWakeHandler) confirm(r *http.Request, subscribeURL string) error {
+	if !isSNSURL(subscribeURL) {
+		return fmt.Errorf("subscribe URL %q is not an SNS endpoint", subscribeURL)
+	}
+
+	request, err := http.NewRequestWithContext(r.Context(), http.MethodGet, subscribeURL, nil)
+	if err != nil {
+		return err
+	}
+
+	response, err := h.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("subscription confirmation returned %s", response.Status)
+	}
+
+	return nil
+}
+
+// verify checks the signature of an SNS request.
+func (h *SNSWakeHandler) verify(request snsWakeRequest) error {
+	var hash crypto.Hash
+	switch request.SignatureVersion {
+	case "1":
+		hash = crypto.SHA1
+	case "2":
+		hash = crypto.SHA256
+	default:
+		return fmt.Errorf("unsupported signature version %q", request.SignatureVersion)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(request.Signature)
+	if err != nil {
+		return err
+	}
+
+	cert, err := h.certificate(request.SigningCertURL)
+	if err != nil {
+		return err
+	}
+
+	key, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return errors.New("signing certificate has no RSA key")
+	}
+
+	return rsa.VerifyPKCS1v15(key, hash, digest(hash, snsStringToSign(request)), signature)
+}
+
+// fetchCertificate downloads, or returns from the cache, the signing certificate of SNS.
+func (h *SNSWakeHandler) fetchCertificate(certURL string) (*x509.Certificate, error) {
+	if !isSNSURL(certURL) {
+		return nil, fmt.Errorf("signing certificate URL %q is not an SNS endpoint", certURL)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if cert, ok := h.certs[certURL]; ok {
+		return cert, nil
+	}
+
+	response, err := h.client.Get(certURL)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	raw, err := io.ReadAll(io.LimitReader(response.Body, _maxSNSRequestBytes))
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, errors.New("signing certificate is not PEM encoded")
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	h.certs[certURL] = cert
+
+	return cert, nil
+}
+
+// snsStringToSign builds the string SNS signs for a request: name and value of the signed
+// fields, one per line, in alphabetical order of the names.
+func snsStringToSign(request snsWakeRequest) string {
+	var b strings.Builder
+	field := func(name, value string) {
+		b.WriteString(name + "\n" + value + "\n")
+	}
+
+	field("Message", request.Message)
+	field("MessageId", request.MessageID)
+
+	if request.Type == _snsTypeNotification {
+		if request.Subject != nil {
+			field("Subject", *request.Subject)
+		}
+	} else {
+		field("SubscribeURL", request.SubscribeURL)
+	}
+
+	field("Timestamp", request.Timestamp)
+
+	if request.Type != _snsTypeNotification {
+		field("Token", request.Token)
+	}
+
+	field("TopicArn", request.TopicArn)
+	field("Type", request.Type)
+
+	return b.String()
+}
+
+// digest hashes data with SHA1 (signature version 1) or SHA256 (signature version 2).
+func digest(hash crypto.Hash, data string) []byte {
+	if hash == crypto.SHA1 {
+		sum := sha1.Sum([]byte(data))
+		return sum[:]
+	}
+
+	sum := sha256.Sum256([]byte(data))
+	return sum[:]
+}
+
+// isSNSURL reports whether rawURL is an HTTPS URL of an SNS endpoint.
+func isSNSURL(rawURL string) bool {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+
+	return parsed.Scheme == "https" && snsHostPattern.MatchString(parsed.Hostname())
+}
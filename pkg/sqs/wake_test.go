@@ -0,0 +1,119 @@
+package sqs
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConsumerWake(t *testing.T) {
+	fake := &fakeSQS{}
+	consumer := NewConsumer(newTestSQS(fake), "queue", HandlerFunc(func(ctx context.Context, msg Message) error {
+		return nil
+	}), WithIdleBackoff(time.Hour, 0))
+
+	var woken time.Time
+	runConsumer(t, consumer, func() bool {
+		fake.mu.Lock()
+		receives := len(fake.receiveInputs)
+		fake.mu.Unlock()
+
+		// The first empty receive puts the consumer to sleep for a second
+		if receives == 1 && woken.IsZero() {
+			time.Sleep(10 * time.Millisecond)
+			fake.push(testMessage("m1", ""))
+			woken = time.Now()
+			consumer.Wake()
+		}
+		return len(fake.deletedHandles()) == 1
+	})
+
+	if elapsed := time.Since(woken); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected the woken consumer to poll right away, took %s", elapsed)
+	}
+}
+
+// signedSNSRequest builds an SNS notification signed with key, as SNS signature version 2.
+func signedSNSRequest(t *testing.T, key *rsa.PrivateKey, request snsWakeRequest) string {
+	t.Helper()
+
+	request.SignatureVersion = "2"
+	request.SigningCertURL = "https://sns.us-east-1.amazonaws.com/cert.pem"
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest(crypto.SHA256, snsStringToSign(request)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	request.Signature = base64.StdEncoding.EncodeToString(signature)
+
+	body, _ := json.Marshal(request)
+	return string(body)
+}
+
+func TestSNSWakeHandler(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	consumer := NewConsumer(newTestSQS(&fakeSQS{}), "queue", HandlerFunc(func(ctx context.Context, msg Message) error {
+		return nil
+	}), WithIdleBackoff(time.Hour, 0))
+
+	topicArn := "arn:aws:sns:us-east-1:123456789012:wake"
+	handler := NewSNSWakeHandler(topicArn, consumer)
+	handler.certificate = func(string) (*x509.Certificate, error) {
+		return &x509.Certificate{PublicKey: &key.PublicKey}, nil
+	}
+
+	post := func(body string) int {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/wake", strings.NewReader(body)))
+		return recorder.Code
+	}
+
+	notification := snsWakeRequest{Type: _snsTypeNotification, MessageID: "1", TopicArn: topicArn, Message: "orders", Timestamp: "2024-01-01T00:00:00.000Z"}
+	if code := post(signedSNSRequest(t, key, notification)); code != http.StatusOK || len(consumer.idle.woken) != 1 {
+		t.Fatalf("Expected the notification to wake the consumer, got %d", code)
+	}
+
+	forged := strings.Replace(signedSNSRequest(t, key, notification), `"orders"`, `"forged"`, 1)
+	if code := post(forged); code != http.StatusForbidden {
+		t.Errorf("Expected 403 for an invalid signature, got %d", code)
+	}
+
+	other := notification
+	other.TopicArn = "arn:aws:sns:us-east-1:123456789012:other"
+	if code := post(signedSNSRequest(t, key, other)); code != http.StatusForbidden {
+		t.Errorf("Expected 403 for another topic, got %d", code)
+	}
+
+	confirmation := snsWakeRequest{Type: _snsTypeSubscriptionConfirmation, MessageID: "2", TopicArn: topicArn, Token: "token", SubscribeURL: "https://attacker.example.com/confirm", Timestamp: "2024-01-01T00:00:00.000Z"}
+	if code := post(signedSNSRequest(t, key, confirmation)); code != http.StatusBadGateway {
+		t.Errorf("Expected subscribe URLs outside SNS not to be visited, got %d", code)
+	}
+}
+
+func TestIsSNSURL(t *testing.T) {
+	tests := map[string]bool{
+		"https://sns.us-east-1.amazonaws.com/SimpleNotificationService-1.pem": true,
+		"https://sns.cn-north-1.amazonaws.com.cn/?Action=ConfirmSubscription": true,
+		"http://sns.us-east-1.amazonaws.com/cert.pem":                         false,
+		"https://sns.us-east-1.amazonaws.com.attacker.example.com/cert.pem":   false,
+		"https://example.com/sns.us-east-1.amazonaws.com":                     false,
+	}
+
+	for rawURL, expected := range tests {
+		if got := isSNSURL(rawURL); got != expected {
+			t.Errorf("Expected isSNSURL(%q) = %v, got %v", rawURL, expected, got)
+		}
+	}
+}